@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/pdxmph/contacts-tui/internal/avatar"
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// runAvatars fetches avatars for contacts into the local cache
+func runAvatars(args []string) error {
+	fs := flag.NewFlagSet("avatars", flag.ExitOnError)
+	databasePath := fs.String("database", "", "Path to database file (overrides config)")
+	refresh := fs.Bool("refresh", false, "Fetch again even when an avatar is already cached")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: contacts-tui avatars [options]")
+		fmt.Fprintln(fs.Output(), "\nFetch avatars from Gravatar or Libravatar by hashed email address and")
+		fmt.Fprintln(fs.Output(), "cache them locally. Only the hash of each address is sent.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if *databasePath != "" {
+		cfg.Database.Path = *databasePath
+	}
+
+	fetcher, err := avatar.NewFetcher(cfg.Avatars)
+	if err != nil {
+		return err
+	}
+
+	database, err := db.Open(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	contacts, err := database.ListContacts()
+	if err != nil {
+		return err
+	}
+
+	summary := fetcher.FetchAll(contacts, *refresh)
+	fmt.Printf("Avatars: %s\n", summary)
+	for _, e := range summary.Errors {
+		fmt.Printf("  ✗ %s\n", e)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// exportBackup writes the whole database as a versioned JSON document to
+// path, or to stdout for "-"
+func exportBackup(cfg *config.Config, path string) error {
+	database, err := db.Open(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	backup, err := database.Backup()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding backup: %w", err)
+	}
+	data = append(data, '\n')
+
+	if path == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	path = config.ExpandPath(path)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	fmt.Printf("✓ Backed up %s to %s\n", describeBackup(backup), path)
+	return nil
+}
+
+// importBackup restores a JSON backup into the configured database, creating
+// it if needed. An existing database must not have any contacts yet.
+func importBackup(cfg *config.Config, path string) error {
+	data, err := os.ReadFile(config.ExpandPath(path))
+	if err != nil {
+		return fmt.Errorf("reading backup: %w", err)
+	}
+	var backup db.Backup
+	if err := json.Unmarshal(data, &backup); err != nil {
+		return fmt.Errorf("parsing backup: %w", err)
+	}
+
+	if _, err := os.Stat(cfg.Database.Path); os.IsNotExist(err) {
+		if err := db.Initialize(cfg.Database.Path); err != nil {
+			return err
+		}
+	}
+	database, err := db.Open(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if err := database.Restore(backup); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Restored %s into %s\n", describeBackup(backup), cfg.Database.Path)
+	return nil
+}
+
+// describeBackup counts what a backup holds
+func describeBackup(b db.Backup) string {
+	interactions := 0
+	for _, c := range b.Contacts {
+		interactions += len(c.Interactions)
+	}
+	return fmt.Sprintf("%d contacts, %d interactions and %d log entries", len(b.Contacts), interactions, len(b.Logs))
+}
+
+// runBackup copies the database file into the backup directory now
+func runBackup(args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	databasePath := fs.String("database", "", "Path to database file (overrides config)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: contacts-tui backup [options]")
+		fmt.Fprintln(fs.Output(), "\nCopy the database into backup_dir (see [retention]), removing the oldest")
+		fmt.Fprintln(fs.Output(), "copies past the number kept. Restore one with contacts-tui restore.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if *databasePath != "" {
+		cfg.Database.Path = *databasePath
+	}
+	if db.BackupDir() == "" {
+		return fmt.Errorf("backups are disabled; set backup_dir under [retention] in config.toml")
+	}
+
+	database, err := db.Open(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	path, err := database.BackupFile()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Backed up %s to %s\n", cfg.Database.Path, path)
+	return nil
+}
+
+// runRestore replaces the database with a copy made by backup, or lists the
+// copies there are
+func runRestore(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	databasePath := fs.String("database", "", "Path to database file (overrides config)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: contacts-tui restore [options] [file]")
+		fmt.Fprintln(fs.Output(), "\nReplace the database with a copy made by contacts-tui backup, before a")
+		fmt.Fprintln(fs.Output(), "migration or on the backup_interval schedule. The current database is")
+		fmt.Fprintln(fs.Output(), "backed up first. Without a file, list the copies there are.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if *databasePath != "" {
+		cfg.Database.Path = *databasePath
+	}
+
+	database, err := db.Open(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if fs.NArg() == 0 {
+		copies, err := database.BackupFiles()
+		if err != nil {
+			return err
+		}
+		if len(copies) == 0 {
+			fmt.Println("No backups yet")
+			return nil
+		}
+		for _, path := range copies {
+			info, err := os.Stat(path)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s  %s  %d KB\n", info.ModTime().Format("2006-01-02 15:04"), path, info.Size()/1024)
+		}
+		return nil
+	}
+
+	file := config.ExpandPath(fs.Arg(0))
+	if filepath.Ext(file) == ".json" {
+		return fmt.Errorf("%s is a JSON backup; restore it with contacts-tui -import-json", file)
+	}
+	if err := database.RestoreBackupFile(file); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Restored %s from %s\n", cfg.Database.Path, file)
+	return nil
+}
+
+// scheduledBackup copies the database when the last copy is older than the
+// configured backup_interval
+func scheduledBackup(database *db.DB, cfg *config.Config) error {
+	if cfg.Retention.BackupInterval == "" {
+		return nil
+	}
+	interval, err := time.ParseDuration(cfg.Retention.BackupInterval)
+	if err != nil {
+		return fmt.Errorf("parsing backup_interval: %w", err)
+	}
+	last, err := database.LastBackupFile()
+	if err != nil {
+		return err
+	}
+	if time.Since(last) < interval {
+		return nil
+	}
+	_, err = database.BackupFile()
+	return err
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pdxmph/contacts-tui/internal/batch"
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// runBatch applies maintenance commands read from stdin or a file
+func runBatch(args []string) error {
+	fs := flag.NewFlagSet("batch", flag.ExitOnError)
+	databasePath := fs.String("database", "", "Path to database file (overrides config)")
+	dryRun := fs.Bool("dry-run", false, "Check the commands without applying them")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: contacts-tui batch [options] [file]")
+		fmt.Fprintln(fs.Output(), "\nApply commands read from a file or stdin, one per line, in a single")
+		fmt.Fprintln(fs.Output(), "transaction; if any fails, nothing is changed. Contacts are given by")
+		fmt.Fprintln(fs.Output(), "label or id:")
+		fmt.Fprintln(fs.Output(), "\n  set-state @sarahc ping")
+		fmt.Fprintln(fs.Output(), "  contacted @sarahc [type] [notes...]")
+		fmt.Fprintln(fs.Output(), "  archive @sarahc [reason...]")
+		fmt.Fprintln(fs.Output(), "  add-note @sarahc <type> <notes...>")
+		fmt.Fprintln(fs.Output(), "\nLines may also be JSON objects, e.g.")
+		fmt.Fprintln(fs.Output(), `  {"op": "add-note", "contact": "@sarahc", "type": "email", "notes": "Sent the deck"}`)
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return fmt.Errorf("expected at most one file")
+	}
+
+	var r io.Reader = os.Stdin
+	if fs.NArg() == 1 && fs.Arg(0) != "-" {
+		f, err := os.Open(config.ExpandPath(fs.Arg(0)))
+		if err != nil {
+			return fmt.Errorf("opening commands: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+	commands, err := batch.Parse(r)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if *databasePath != "" {
+		cfg.Database.Path = *databasePath
+	}
+
+	database, err := db.Open(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if *dryRun {
+		if err := batch.Check(database, commands); err != nil {
+			return err
+		}
+		fmt.Printf("%d commands OK (dry run, nothing changed)\n", len(commands))
+		return nil
+	}
+
+	summary, err := batch.Apply(database, commands)
+	if err != nil {
+		return fmt.Errorf("%w (nothing was changed)", err)
+	}
+	fmt.Printf("✓ Applied %d commands: %s\n", summary.Total, summary)
+	return nil
+}
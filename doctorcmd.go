@@ -0,0 +1,116 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"golang.org/x/term"
+)
+
+// runDoctor checks the database for damage, orphaned rows and invalid
+// values, and offers to repair what it finds
+func runDoctor(args []string) error {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	databasePath := fs.String("database", "", "Path to database file (overrides config)")
+	fix := fs.Bool("fix", false, "Repair the problems found without asking")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: contacts-tui doctor [options]")
+		fmt.Fprintln(fs.Output(), "\nCheck the database's integrity, look for rows whose contact (or other")
+		fmt.Fprintln(fs.Output(), "parent row) is gone and for values the schema doesn't allow. Repairing")
+		fmt.Fprintln(fs.Output(), "backs up the database, rebuilds its indexes, resets invalid values and")
+		fmt.Fprintln(fs.Output(), "moves orphaned rows to a JSON file in deleted_dir (see [retention]).")
+		fmt.Fprintln(fs.Output(), "Exits with an error while problems remain, so it can run from cron.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if *databasePath != "" {
+		cfg.Database.Path = *databasePath
+	}
+
+	database, err := db.Open(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+	database.SetDeletedDir(cfg.Retention.DeletedDir)
+
+	problems, err := database.Diagnose()
+	if err != nil {
+		return err
+	}
+	if len(problems) == 0 {
+		fmt.Printf("✓ No problems found in %s\n", cfg.Database.Path)
+		return nil
+	}
+	printProblems(problems)
+
+	if !*fix {
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return fmt.Errorf("%d problems found; run contacts-tui doctor -fix to repair them", len(problems))
+		}
+		fmt.Print("\nRepair them? The database is backed up first. (y/N): ")
+		var response string
+		fmt.Scanln(&response)
+		if response != "y" && response != "Y" {
+			return fmt.Errorf("%d problems left unrepaired", len(problems))
+		}
+	}
+
+	file, err := database.Repair(problems)
+	if err != nil {
+		return err
+	}
+	if file != "" {
+		fmt.Printf("Saved orphaned rows to %s\n", file)
+	}
+
+	remaining, err := database.Diagnose()
+	if err != nil {
+		return err
+	}
+	if len(remaining) > 0 {
+		fmt.Println("\nStill found after repairing:")
+		printProblems(remaining)
+		return fmt.Errorf("%d problems couldn't be repaired; restore a backup with contacts-tui restore", len(remaining))
+	}
+	fmt.Printf("✓ Repaired %d problems\n", len(problems))
+	return nil
+}
+
+// printProblems lists problems grouped by kind
+func printProblems(problems []db.Problem) {
+	headings := []struct{ kind, title string }{
+		{db.ProblemDamaged, "Damage"},
+		{db.ProblemOrphan, "Orphaned rows"},
+		{db.ProblemInvalid, "Invalid values"},
+	}
+	for _, h := range headings {
+		var lines []string
+		for _, p := range problems {
+			if p.Kind != h.kind {
+				continue
+			}
+			if p.Table == "" {
+				lines = append(lines, "  "+p.Detail)
+			} else {
+				lines = append(lines, fmt.Sprintf("  %s row %d: %s", p.Table, p.RowID, p.Detail))
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		fmt.Printf("%s (%d):\n", h.title, len(lines))
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"golang.org/x/term"
+)
+
+// databaseKey returns the passphrase of an encrypted database, from
+// key_command or else typed at the terminal
+func databaseKey(cfg config.DatabaseConfig) (string, error) {
+	if cfg.KeyCommand != "" {
+		out, err := exec.Command("sh", "-c", cfg.KeyCommand).Output()
+		if err != nil {
+			return "", fmt.Errorf("running key_command: %w", err)
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+	return promptPassphrase(fmt.Sprintf("Passphrase for %s: ", cfg.Path))
+}
+
+// promptPassphrase reads a passphrase from the terminal without echoing it
+func promptPassphrase(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("no terminal to ask for the passphrase; set key_command under [database]")
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	passphrase, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(passphrase), nil
+}
+
+// runEncrypt encrypts the database with SQLCipher, or decrypts it
+func runEncrypt(args []string) error {
+	fs := flag.NewFlagSet("encrypt", flag.ExitOnError)
+	databasePath := fs.String("database", "", "Path to database file (overrides config)")
+	decrypt := fs.Bool("decrypt", false, "Decrypt the database instead")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: contacts-tui encrypt [options]")
+		fmt.Fprintln(fs.Output(), "\nEncrypt the database with a passphrase (from key_command, or typed in),")
+		fmt.Fprintln(fs.Output(), "then set encrypted = true under [database]. The unencrypted file is kept")
+		fmt.Fprintln(fs.Output(), "with an .unencrypted suffix; delete it once the TUI opens the database.")
+		fmt.Fprintln(fs.Output(), "Needs a build with SQLCipher (make build-sqlcipher).")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if *databasePath != "" {
+		cfg.Database.Path = *databasePath
+	}
+	if _, err := os.Stat(cfg.Database.Path); err != nil {
+		return err
+	}
+
+	var passphrase string
+	if cfg.Database.KeyCommand != "" || *decrypt {
+		passphrase, err = databaseKey(cfg.Database)
+	} else {
+		// Typed twice, since a typo would lock the database for good
+		passphrase, err = promptPassphrase("New passphrase: ")
+		if err == nil {
+			var again string
+			again, err = promptPassphrase("Passphrase again: ")
+			if err == nil && again != passphrase {
+				err = fmt.Errorf("passphrases don't match")
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	kept, err := db.ConvertEncryption(cfg.Database.Path, passphrase, !*decrypt)
+	if err != nil {
+		return err
+	}
+	if *decrypt {
+		fmt.Printf("✓ Decrypted %s; set encrypted = false under [database]\n", cfg.Database.Path)
+	} else {
+		fmt.Printf("✓ Encrypted %s; set encrypted = true under [database]\n", cfg.Database.Path)
+	}
+	fmt.Printf("  The original is kept as %s; delete it once the database opens\n", kept)
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/enrich"
+)
+
+// runEnrich fills in and normalizes companies from email domains
+func runEnrich(args []string) error {
+	fs := flag.NewFlagSet("enrich", flag.ExitOnError)
+	databasePath := fs.String("database", "", "Path to database file (overrides config)")
+	dryRun := fs.Bool("dry-run", false, "Show the changes without saving them")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: contacts-tui enrich [options]")
+		fmt.Fprintln(fs.Output(), "\nFill in blank companies, and normalize the spelling of existing ones,")
+		fmt.Fprintln(fs.Output(), "from contacts' email domains using the [enrich.domains] map in the config.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if *databasePath != "" {
+		cfg.Database.Path = *databasePath
+	}
+	if len(cfg.Enrich.Domains) == 0 {
+		return fmt.Errorf("no domains configured; add an [enrich.domains] section to the config")
+	}
+
+	database, err := db.Open(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	contacts, err := database.ListContacts()
+	if err != nil {
+		return err
+	}
+	changes := enrich.Companies(contacts, cfg.Enrich.Domains)
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	if len(changes) == 0 {
+		fmt.Println("No companies to fill in or normalize")
+		return nil
+	}
+	if *dryRun {
+		fmt.Printf("\n%d changes (dry run, nothing saved)\n", len(changes))
+		return nil
+	}
+
+	saved, err := enrich.Apply(database, changes)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n✓ Updated %d companies\n", saved)
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/escalation"
+	"github.com/pdxmph/contacts-tui/internal/notify"
+	"github.com/pdxmph/contacts-tui/internal/tasks"
+)
+
+// runEscalate applies reminder escalation steps to overdue contacts. It runs
+// even when automatic escalation is disabled in the config, so it can be
+// scheduled from cron instead.
+func runEscalate(args []string) error {
+	fs := flag.NewFlagSet("escalate", flag.ExitOnError)
+	databasePath := fs.String("database", "", "Path to database file (overrides config)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: contacts-tui escalate [options]")
+		fmt.Fprintln(fs.Output(), "\nPing, create tasks for and flag overdue contacts per the [escalation] rules,\nand nudge contacts who owe a reply per [waiting].")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	db.SetCadences(cfg.Relationships.Cadence, cfg.Relationships.DefaultCadence)
+	if *databasePath != "" {
+		cfg.Database.Path = *databasePath
+	}
+
+	database, err := db.Open(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	taskManager, err := tasks.NewManager(cfg.Tasks.Backend)
+	if err != nil {
+		return err
+	}
+	taskManager.MapProjects(cfg.Tasks.Projects, database.RelationshipTypeOf)
+
+	throttle, err := notify.NewThrottle(database, cfg.Notifications, time.Now())
+	if err != nil {
+		return err
+	}
+
+	summary, err := escalation.Run(database, taskManager.Backend(), cfg.Escalation, throttle)
+	if err != nil {
+		return err
+	}
+	if err := escalation.Nudge(database, taskManager.Backend(), cfg.Waiting, throttle, &summary); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Escalation: %s\n", summary)
+	for _, e := range summary.Errors {
+		fmt.Printf("  ✗ %s\n", e)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/report"
+)
+
+// Export formats
+const (
+	exportVCard = "vcf"
+	exportCSV   = "csv"
+)
+
+// runExport writes all or a filtered set of contacts to a file for moving
+// them to another system
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	databasePath := fs.String("database", "", "Path to database file (overrides config)")
+	filter := addContactFilterFlags(fs)
+	format := fs.String("format", "", "Output format: vcf or csv (default: from the -o extension, else vcf)")
+	output := fs.String("o", "", "Write the contacts to this file instead of stdout")
+	timeline := fs.String("timeline", "", "Export this contact's (label or id) full interaction timeline instead, as md or json")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: contacts-tui export [options]")
+		fmt.Fprintln(fs.Output(), "\nExport contacts as vCards for a phone or another CRM, or as CSV with their")
+		fmt.Fprintln(fs.Output(), "state and last-contacted dates for a spreadsheet. In vCards, label,")
+		fmt.Fprintln(fs.Output(), "relationship type, state and notes are kept in X-CONTACTS- properties, so")
+		fmt.Fprintln(fs.Output(), "importing the file again restores them.")
+		fmt.Fprintln(fs.Output(), "\nWith -timeline, export one contact's whole interaction history as Markdown")
+		fmt.Fprintln(fs.Output(), "(-format md, the default) or JSON (-format json) instead.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *format == "" {
+		*format = exportVCard
+		if *timeline != "" {
+			*format = report.TimelineMarkdown
+		}
+		switch ext := strings.ToLower(filepath.Ext(*output)); {
+		case ext == ".csv":
+			*format = exportCSV
+		case ext == ".json" && *timeline != "":
+			*format = report.TimelineJSON
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	db.SetCadences(cfg.Relationships.Cadence, cfg.Relationships.DefaultCadence)
+	if *databasePath != "" {
+		cfg.Database.Path = *databasePath
+	}
+
+	database, err := db.Open(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if *timeline != "" {
+		return exportTimeline(database, *timeline, *format, *output)
+	}
+
+	contacts, err := filter.query(database)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	switch *format {
+	case exportVCard:
+		if err = database.LoadValues(contacts); err == nil {
+			err = db.WriteVCards(&buf, contacts)
+		}
+	case exportCSV:
+		err = db.WriteCSV(&buf, contacts)
+	default:
+		err = fmt.Errorf("unknown export format %q (use %s or %s)", *format, exportVCard, exportCSV)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		os.Stdout.Write(buf.Bytes())
+		return nil
+	}
+	if err := os.WriteFile(config.ExpandPath(*output), buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("writing export: %w", err)
+	}
+	fmt.Printf("✓ Exported %d contacts to %s\n", len(contacts), *output)
+	return nil
+}
+
+// exportTimeline writes one contact's full interaction timeline as Markdown
+// or JSON
+func exportTimeline(database *db.DB, query, format, output string) error {
+	contact, err := database.GetContactByLabel(query)
+	if err == sql.ErrNoRows {
+		id, convErr := strconv.Atoi(query)
+		if convErr != nil {
+			return fmt.Errorf("no contact labelled %s", query)
+		}
+		contact, err = database.GetContact(id)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("no contact with id %d", id)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	data, err := report.Timeline(database, *contact, format)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		os.Stdout.Write(data)
+		return nil
+	}
+	if err := os.WriteFile(config.ExpandPath(output), data, 0644); err != nil {
+		return fmt.Errorf("writing timeline: %w", err)
+	}
+	fmt.Printf("✓ Exported %s's interactions to %s\n", contact.Name, output)
+	return nil
+}
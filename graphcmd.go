@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/report"
+)
+
+// runGraph exports the contact network for visualizing elsewhere
+func runGraph(args []string) error {
+	fs := flag.NewFlagSet("graph", flag.ExitOnError)
+	databasePath := fs.String("database", "", "Path to database file (overrides config)")
+	relType := fs.String("type", "", "Only include contacts of this relationship type")
+	locations := fs.Bool("locations", false, "Also link contacts who share a location")
+	archived := fs.Bool("archived", false, "Include archived contacts")
+	format := fs.String("format", report.FormatDOT, "Output format: dot or graphml")
+	output := fs.String("o", "", "Write the graph to this file instead of stdout")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: contacts-tui graph [options]")
+		fmt.Fprintln(fs.Output(), "\nExport the contact network: contacts grouped by relationship type and")
+		fmt.Fprintln(fs.Output(), "linked through the companies (and optionally locations) they share.")
+		fmt.Fprintln(fs.Output(), "Render DOT with Graphviz, e.g. contacts-tui graph | neato -Tsvg > net.svg,")
+		fmt.Fprintln(fs.Output(), "or open GraphML in a tool like Gephi or yEd.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if *databasePath != "" {
+		cfg.Database.Path = *databasePath
+	}
+
+	database, err := db.Open(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	contacts, err := database.FilterContacts(db.ContactFilter{RelationshipType: *relType, IncludeArchived: *archived})
+	if err != nil {
+		return err
+	}
+
+	graph := report.BuildGraph(contacts, *locations)
+	data, err := graph.Encode(*format)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		os.Stdout.Write(data)
+		return nil
+	}
+	if err := os.WriteFile(config.ExpandPath(*output), data, 0644); err != nil {
+		return fmt.Errorf("writing graph: %w", err)
+	}
+	fmt.Printf("✓ Wrote %d contacts and %d links to %s\n", len(contacts), len(graph.Edges), *output)
+	return nil
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/pdxmph/contacts-tui/internal/avatar"
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/enrich"
+	"github.com/pdxmph/contacts-tui/internal/importer"
+)
+
+// runImport imports contacts from a file in any registered format, such as
+// CSV or vCard
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	databasePath := fs.String("database", "", "Path to database file (overrides config)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: contacts-tui import [options] <file>")
+		fmt.Fprintf(fs.Output(), "\nImport contacts from a file (formats: %s). Contacts matching an\n", strings.Join(importer.Formats(), ", "))
+		fmt.Fprintln(fs.Output(), "existing label, email or name only fill in blank fields.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected one file to import")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if *databasePath != "" {
+		cfg.Database.Path = *databasePath
+	}
+
+	contacts, err := importer.ParseFile(config.ExpandPath(fs.Arg(0)))
+	if err != nil {
+		return err
+	}
+
+	database, err := db.Open(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+	if _, err := database.AllowRelationshipTypes(cfg.Relationships.Types); err != nil {
+		return err
+	}
+	if _, err := database.AllowStates(cfg.States.Names()); err != nil {
+		return err
+	}
+
+	progress, err := importer.Import(database, contacts, func(p importer.Progress) {
+		fmt.Printf("\r%d of %d contacts", p.Processed(), p.Total)
+	})
+	fmt.Println()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Contacts: %d created, %d updated, %d unchanged\n", progress.Created, progress.Updated, progress.Skipped)
+	afterImport(database, cfg)
+	for _, e := range progress.Errors {
+		fmt.Printf("  ✗ %s\n", e)
+	}
+	return nil
+}
+
+// afterImport runs the configured enrichment and avatar fetching once an
+// import has finished
+func afterImport(database *db.DB, cfg *config.Config) {
+	if enriched, err := enrich.AfterImport(database, cfg.Enrich); err != nil {
+		fmt.Printf("  ✗ enriching companies: %v\n", err)
+	} else if enriched > 0 {
+		fmt.Printf("✓ Companies: %d filled in or normalized from email domains\n", enriched)
+	}
+	if cfg.Avatars.Enabled {
+		if summary, err := avatar.AfterImport(database, cfg.Avatars); err != nil {
+			fmt.Printf("  ✗ fetching avatars: %v\n", err)
+		} else {
+			fmt.Printf("✓ Avatars: %s\n", summary)
+		}
+	}
+}
+
+// runImportInteractions backfills interaction history from a CSV or JSON file
+func runImportInteractions(args []string) error {
+	fs := flag.NewFlagSet("import-interactions", flag.ExitOnError)
+	databasePath := fs.String("database", "", "Path to database file (overrides config)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: contacts-tui import-interactions [options] <file.csv|file.json>")
+		fmt.Fprintln(fs.Output(), "\nBackfill interaction history. Each record needs a contact label and a date;")
+		fmt.Fprintln(fs.Output(), "type (default: manual) and notes are optional.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected one file to import")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if *databasePath != "" {
+		cfg.Database.Path = *databasePath
+	}
+
+	records, err := importer.ParseInteractionsFile(config.ExpandPath(fs.Arg(0)))
+	if err != nil {
+		return err
+	}
+
+	database, err := db.Open(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	progress, err := importer.ImportInteractions(database, records, func(p importer.Progress) {
+		fmt.Printf("\r%d of %d records", p.Processed(), p.Total)
+	})
+	fmt.Println()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Added %d interactions, skipped %d already present\n", progress.Created, progress.Skipped)
+	for _, e := range progress.Errors {
+		fmt.Printf("  ✗ %s\n", e)
+	}
+	return nil
+}
+
+// runImportMapped imports contacts and their interactions from an arbitrary
+// JSON export, using a mapping file to find the fields
+func runImportMapped(args []string) error {
+	fs := flag.NewFlagSet("import-mapped", flag.ExitOnError)
+	databasePath := fs.String("database", "", "Path to database file (overrides config)")
+	mappingPath := fs.String("mapping", "", "Mapping file describing the export (required)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: contacts-tui import-mapped -mapping <mapping.toml> [options] <export.json>")
+		fmt.Fprintln(fs.Output(), "\nImport contacts and interactions exported from another CRM. The mapping")
+		fmt.Fprintln(fs.Output(), "file says where contacts, their fields and their interactions are in the")
+		fmt.Fprintln(fs.Output(), "export; see docs/IMPORT_MAPPING.md.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 || *mappingPath == "" {
+		fs.Usage()
+		return fmt.Errorf("expected a mapping and one file to import")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if *databasePath != "" {
+		cfg.Database.Path = *databasePath
+	}
+
+	mapping, err := importer.LoadMapping(config.ExpandPath(*mappingPath))
+	if err != nil {
+		return err
+	}
+	records, err := importer.ParseMappedJSON(config.ExpandPath(fs.Arg(0)), mapping)
+	if err != nil {
+		return err
+	}
+
+	database, err := db.Open(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	contacts, interactions, err := importer.ImportMapped(database, records, func(p importer.Progress) {
+		fmt.Printf("\r%d of %d contacts", p.Processed(), p.Total)
+	})
+	fmt.Println()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Contacts: %d created, %d updated, %d unchanged\n", contacts.Created, contacts.Updated, contacts.Skipped)
+	fmt.Printf("✓ Interactions: %d added, %d skipped\n", interactions.Created, interactions.Skipped)
+	afterImport(database, cfg)
+	for _, e := range append(contacts.Errors, interactions.Errors...) {
+		fmt.Printf("  ✗ %s\n", e)
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package avatar
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// Avatar services, looked up by the SHA-256 hash of the email address
+var services = map[string]string{
+	"gravatar":   "https://gravatar.com/avatar/%s?d=404&s=200",
+	"libravatar": "https://seccdn.libravatar.org/avatar/%s?d=404&s=200",
+}
+
+// missingSuffix marks an address the service has no avatar for, so it is
+// not asked again on every run
+const missingSuffix = ".none"
+
+// maxSize caps the size of a downloaded avatar
+const maxSize = 1 << 20
+
+// Summary reports what a fetch run did
+type Summary struct {
+	Fetched int
+	Missing int // The service has no avatar for the address
+	Cached  int // Already fetched (or known missing) before this run
+	Errors  []string
+}
+
+// String describes the summary in one line
+func (s Summary) String() string {
+	return fmt.Sprintf("%d fetched, %d without an avatar, %d already cached, %d errors",
+		s.Fetched, s.Missing, s.Cached, len(s.Errors))
+}
+
+// Fetcher downloads avatars into a local cache
+type Fetcher struct {
+	url    string
+	dir    string
+	client *http.Client
+}
+
+// NewFetcher builds a fetcher for the configured service
+func NewFetcher(cfg config.AvatarsConfig) (*Fetcher, error) {
+	service := cfg.Service
+	if service == "" {
+		service = "gravatar"
+	}
+	url, ok := services[service]
+	if !ok {
+		return nil, fmt.Errorf("unknown avatar service %q (use gravatar or libravatar)", service)
+	}
+	if cfg.CacheDir == "" {
+		return nil, fmt.Errorf("no avatar cache_dir configured")
+	}
+	return &Fetcher{
+		url:    url,
+		dir:    cfg.CacheDir,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Hash returns the hash avatar services use for an email address
+func Hash(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(sum[:])
+}
+
+// CachedPath returns the cached avatar file for an email address, or "" if
+// none has been fetched
+func CachedPath(dir, email string) string {
+	if dir == "" || strings.TrimSpace(email) == "" {
+		return ""
+	}
+	base := filepath.Join(dir, Hash(email))
+	for _, ext := range []string{".jpg", ".png", ".gif"} {
+		if _, err := os.Stat(base + ext); err == nil {
+			return base + ext
+		}
+	}
+	return ""
+}
+
+// FetchAll fetches avatars for every contact with an email address. Avatars
+// already cached, and addresses known to have none, are skipped unless
+// refresh is set.
+func (f *Fetcher) FetchAll(contacts []db.Contact, refresh bool) Summary {
+	var summary Summary
+	if err := os.MkdirAll(f.dir, 0755); err != nil {
+		summary.Errors = append(summary.Errors, fmt.Sprintf("creating avatar cache: %v", err))
+		return summary
+	}
+
+	for _, c := range contacts {
+		if !c.Email.Valid || strings.TrimSpace(c.Email.String) == "" {
+			continue
+		}
+		hash := Hash(c.Email.String)
+		if !refresh && (CachedPath(f.dir, c.Email.String) != "" || exists(filepath.Join(f.dir, hash+missingSuffix))) {
+			summary.Cached++
+			continue
+		}
+
+		found, err := f.fetch(hash)
+		switch {
+		case err != nil:
+			summary.Errors = append(summary.Errors, fmt.Sprintf("%s: %v", c.Name, err))
+		case found:
+			summary.Fetched++
+		default:
+			summary.Missing++
+		}
+	}
+	return summary
+}
+
+// fetch downloads the avatar for a hash, reporting whether the service had one
+func (f *Fetcher) fetch(hash string) (bool, error) {
+	resp, err := f.client.Get(fmt.Sprintf(f.url, hash))
+	if err != nil {
+		return false, fmt.Errorf("fetching avatar: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		marker := filepath.Join(f.dir, hash+missingSuffix)
+		if err := os.WriteFile(marker, nil, 0644); err != nil {
+			return false, fmt.Errorf("recording missing avatar: %w", err)
+		}
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("fetching avatar: %s", resp.Status)
+	}
+
+	ext := ".jpg"
+	switch resp.Header.Get("Content-Type") {
+	case "image/png":
+		ext = ".png"
+	case "image/gif":
+		ext = ".gif"
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize))
+	if err != nil {
+		return false, fmt.Errorf("reading avatar: %w", err)
+	}
+
+	base := filepath.Join(f.dir, hash)
+	for _, old := range []string{".jpg", ".png", ".gif", missingSuffix} {
+		os.Remove(base + old)
+	}
+	if err := os.WriteFile(base+ext, data, 0644); err != nil {
+		return false, fmt.Errorf("saving avatar: %w", err)
+	}
+	return true, nil
+}
+
+// exists reports whether a file exists
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// AfterImport fetches avatars for all contacts when the config enables it
+func AfterImport(database *db.DB, cfg config.AvatarsConfig) (Summary, error) {
+	if !cfg.Enabled {
+		return Summary{}, nil
+	}
+	f, err := NewFetcher(cfg)
+	if err != nil {
+		return Summary{}, err
+	}
+	contacts, err := database.ListContacts()
+	if err != nil {
+		return Summary{}, fmt.Errorf("loading contacts: %w", err)
+	}
+	return f.FetchAll(contacts, false), nil
+}
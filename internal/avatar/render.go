@@ -0,0 +1,194 @@
+package avatar
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // Register decoders for the formats avatars come in
+	_ "image/jpeg"
+	"image/png"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Ways of drawing an avatar in the detail pane
+const (
+	DisplayAuto   = "auto"   // Pick from the terminal
+	DisplayKitty  = "kitty"  // Kitty graphics protocol, through Unicode placeholders
+	DisplayBlocks = "blocks" // Half-block characters in 24-bit color
+	DisplayText   = "text"   // The contact's initials
+	DisplayOff    = "off"
+)
+
+// DetectDisplay picks how to draw avatars in the terminal the TUI is running
+// in. Sixel images can't be kept in place while the screen is redrawn around
+// them, so terminals that only support sixel get half blocks.
+func DetectDisplay() string {
+	term := os.Getenv("TERM")
+	if os.Getenv("TMUX") == "" && (os.Getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" ||
+		term == "xterm-ghostty" || os.Getenv("TERM_PROGRAM") == "ghostty") {
+		return DisplayKitty
+	}
+	switch os.Getenv("COLORTERM") {
+	case "truecolor", "24bit":
+		return DisplayBlocks
+	}
+	return DisplayText
+}
+
+// Load decodes a JPEG, PNG or GIF avatar
+func Load(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return img, nil
+}
+
+// scale resizes an image to width x height by averaging the pixels each
+// new pixel covers
+func scale(img image.Image, width, height int) *image.NRGBA {
+	src := img.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := src.Min.Y + y*src.Dy()/height
+		y1 := max(src.Min.Y+(y+1)*src.Dy()/height, y0+1)
+		for x := 0; x < width; x++ {
+			x0 := src.Min.X + x*src.Dx()/width
+			x1 := max(src.Min.X+(x+1)*src.Dx()/width, x0+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := color.NRGBAModel.Convert(img.At(sx, sy)).(color.NRGBA)
+					r += uint64(c.R)
+					g += uint64(c.G)
+					b += uint64(c.B)
+					a += uint64(c.A)
+					n++
+				}
+			}
+			dst.SetNRGBA(x, y, color.NRGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(b / n), A: uint8(a / n)})
+		}
+	}
+	return dst
+}
+
+// Blocks draws an image cols cells wide and rows cells high with half-block
+// characters, two pixels to a cell. Transparent pixels are left in the
+// terminal's background color.
+func Blocks(img image.Image, cols, rows int) []string {
+	small := scale(img, cols, rows*2)
+	lines := make([]string, rows)
+	for row := 0; row < rows; row++ {
+		var b strings.Builder
+		for col := 0; col < cols; col++ {
+			top := small.NRGBAAt(col, row*2)
+			bottom := small.NRGBAAt(col, row*2+1)
+			switch {
+			case top.A < 128 && bottom.A < 128:
+				b.WriteString("\x1b[0m ")
+			case top.A < 128:
+				fmt.Fprintf(&b, "\x1b[0m\x1b[38;2;%d;%d;%dm▄", bottom.R, bottom.G, bottom.B)
+			case bottom.A < 128:
+				fmt.Fprintf(&b, "\x1b[0m\x1b[38;2;%d;%d;%dm▀", top.R, top.G, top.B)
+			default:
+				fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%dm\x1b[48;2;%d;%d;%dm▀",
+					top.R, top.G, top.B, bottom.R, bottom.G, bottom.B)
+			}
+		}
+		b.WriteString("\x1b[0m")
+		lines[row] = b.String()
+	}
+	return lines
+}
+
+// kittyPlaceholder is the character kitty replaces with a cell of an image
+const kittyPlaceholder = '\U0010EEEE'
+
+// kittyDiacritics number the rows and columns of placeholder cells, in the
+// order kitty's graphics protocol defines them
+var kittyDiacritics = []rune{
+	0x0305, 0x030D, 0x030E, 0x0310, 0x0312, 0x033D, 0x033E, 0x033F, 0x0346, 0x034A,
+	0x034B, 0x034C, 0x0350, 0x0351, 0x0352, 0x0357, 0x035B, 0x0363, 0x0364, 0x0365,
+	0x0366, 0x0367, 0x0368, 0x0369, 0x036A, 0x036B, 0x036C, 0x036D, 0x036E, 0x036F,
+}
+
+// MaxKittyCells is the most rows or columns a kitty avatar can span
+var MaxKittyCells = len(kittyDiacritics)
+
+// kittyChunk is the most base64 data kitty accepts in one escape sequence
+const kittyChunk = 4096
+
+// Kitty returns the escape sequences that send an image to a kitty terminal
+// as image id, and the lines of placeholder cells that show it cols cells
+// wide and rows cells high. Placeholders are ordinary text, so the image
+// moves with the layout around it; the sequences only need sending again
+// when the image changes.
+func Kitty(img image.Image, id uint32, cols, rows int) (string, []string, error) {
+	cols = min(cols, MaxKittyCells)
+	rows = min(rows, MaxKittyCells)
+	id &= 0xFFFFFF // The id is carried in a 24-bit color
+
+	// Cells are about twice as tall as they are wide
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, scale(img, cols*10, rows*20)); err != nil {
+		return "", nil, fmt.Errorf("encoding avatar: %w", err)
+	}
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	// q=2 keeps the terminal from answering, which would arrive as key presses
+	var seq strings.Builder
+	for first := true; first || data != ""; first = false {
+		chunk := data[:min(kittyChunk, len(data))]
+		data = data[len(chunk):]
+		more := 0
+		if data != "" {
+			more = 1
+		}
+		if first {
+			fmt.Fprintf(&seq, "\x1b_Ga=T,U=1,f=100,t=d,q=2,i=%d,c=%d,r=%d,m=%d;%s\x1b\\", id, cols, rows, more, chunk)
+		} else {
+			fmt.Fprintf(&seq, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+
+	lines := make([]string, rows)
+	for row := 0; row < rows; row++ {
+		var b strings.Builder
+		fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%dm", id>>16, id>>8&0xFF, id&0xFF)
+		for col := 0; col < cols; col++ {
+			b.WriteRune(kittyPlaceholder)
+			b.WriteRune(kittyDiacritics[row])
+			b.WriteRune(kittyDiacritics[col])
+		}
+		b.WriteString("\x1b[39m")
+		lines[row] = b.String()
+	}
+	return seq.String(), lines, nil
+}
+
+// Initials returns up to two initials of a name, for drawing an avatar as
+// text
+func Initials(name string) string {
+	var initials []rune
+	for _, word := range strings.Fields(name) {
+		r := []rune(word)[0]
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			initials = append(initials, unicode.ToUpper(r))
+		}
+	}
+	if len(initials) > 2 {
+		initials = []rune{initials[0], initials[len(initials)-1]}
+	}
+	return string(initials)
+}
@@ -0,0 +1,218 @@
+package batch
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// Command is one line of a batch: an action on a contact given by label
+// (with or without the @) or numeric id
+type Command struct {
+	Line    int    `json:"-"`
+	Action  string `json:"op"`
+	Contact string `json:"contact"`
+	State   string `json:"state,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Notes   string `json:"notes,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// Summary reports how many of each action a batch applied
+type Summary struct {
+	Counts map[string]int
+	Total  int
+}
+
+// String describes the summary in one line
+func (s Summary) String() string {
+	var parts []string
+	for _, action := range actions {
+		if n := s.Counts[action]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, action))
+		}
+	}
+	if len(parts) == 0 {
+		return "nothing to do"
+	}
+	return strings.Join(parts, ", ")
+}
+
+var actions = []string{db.BatchSetState, db.BatchContacted, db.BatchArchive, db.BatchAddNote}
+
+// Parse reads batch commands, one per line. A line is either a JSON object
+// such as {"op": "set-state", "contact": "@sarahc", "state": "ping"} or
+// words:
+//
+//	set-state <contact> <state>
+//	contacted <contact> [type] [notes...]
+//	archive <contact> [reason...]
+//	add-note <contact> <type> <notes...>
+//
+// Blank lines and lines starting with # are skipped.
+func Parse(r io.Reader) ([]Command, error) {
+	var commands []Command
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var c Command
+		if strings.HasPrefix(line, "{") {
+			if err := json.Unmarshal([]byte(line), &c); err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+		} else {
+			c = parseWords(strings.Fields(line))
+		}
+		c.Line = n
+		c.Action = strings.ToLower(strings.TrimSpace(c.Action))
+		c.State = strings.ToLower(strings.TrimSpace(c.State))
+		c.Type = strings.ToLower(strings.TrimSpace(c.Type))
+		if err := c.validate(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		commands = append(commands, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading commands: %w", err)
+	}
+	return commands, nil
+}
+
+// parseWords builds a command from the words of a plain text line
+func parseWords(words []string) Command {
+	var c Command
+	if len(words) > 0 {
+		c.Action = words[0]
+	}
+	if len(words) > 1 {
+		c.Contact = words[1]
+	}
+	rest := words[min(len(words), 2):]
+	switch strings.ToLower(c.Action) {
+	case db.BatchSetState:
+		c.State = strings.Join(rest, " ")
+	case db.BatchContacted, db.BatchAddNote:
+		if len(rest) > 0 {
+			c.Type = rest[0]
+			c.Notes = strings.Join(rest[1:], " ")
+		}
+	case db.BatchArchive:
+		c.Reason = strings.Join(rest, " ")
+	}
+	return c
+}
+
+// validate checks that a command has what its action needs
+func (c Command) validate() error {
+	if c.Contact == "" {
+		return fmt.Errorf("missing contact")
+	}
+	switch c.Action {
+	case db.BatchSetState:
+		if c.State == "" || strings.Contains(c.State, " ") {
+			return fmt.Errorf("set-state needs a single state")
+		}
+	case db.BatchAddNote:
+		if c.Type == "" || strings.TrimSpace(c.Notes) == "" {
+			return fmt.Errorf("add-note needs a type and notes")
+		}
+	case db.BatchContacted, db.BatchArchive:
+	default:
+		return fmt.Errorf("unknown action %q (use %s)", c.Action, strings.Join(actions, ", "))
+	}
+	return nil
+}
+
+// Apply resolves the commands' contacts and applies them in one transaction.
+// Nothing is changed if any contact is unknown or any command fails.
+func Apply(database *db.DB, commands []Command) (Summary, error) {
+	summary := Summary{Counts: make(map[string]int)}
+	ops, err := resolve(database, commands)
+	if err != nil {
+		return summary, err
+	}
+	if err := batchError(commands, database.ApplyBatch(ops)); err != nil {
+		return summary, err
+	}
+	for _, c := range commands {
+		summary.Counts[c.Action]++
+		summary.Total++
+	}
+	return summary, nil
+}
+
+// Check reports the first command that would fail, without changing anything
+func Check(database *db.DB, commands []Command) error {
+	ops, err := resolve(database, commands)
+	if err != nil {
+		return err
+	}
+	return batchError(commands, database.CheckBatch(ops))
+}
+
+// resolve turns commands into database operations, looking up their contacts
+func resolve(database *db.DB, commands []Command) ([]db.BatchOp, error) {
+	contacts, err := database.ListContacts()
+	if err != nil {
+		return nil, fmt.Errorf("loading contacts: %w", err)
+	}
+	byLabel := make(map[string]int)
+	byID := make(map[int]bool)
+	for _, c := range contacts {
+		byID[c.ID] = true
+		if c.Label.Valid && c.Label.String != "" {
+			byLabel[db.NormalizeLabel(c.Label.String)] = c.ID
+		}
+	}
+
+	ops := make([]db.BatchOp, len(commands))
+	for i, c := range commands {
+		id, ok := byLabel[db.NormalizeLabel(c.Contact)]
+		if !ok {
+			if n, err := strconv.Atoi(c.Contact); err == nil && byID[n] {
+				id, ok = n, true
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("line %d: no contact %q", c.Line, c.Contact)
+		}
+
+		op := db.BatchOp{
+			Action:          c.Action,
+			ContactID:       id,
+			State:           c.State,
+			InteractionType: c.Type,
+			Notes:           strings.TrimSpace(c.Notes),
+			Reason:          c.Reason,
+		}
+		if c.Action == db.BatchContacted {
+			if op.InteractionType == "" {
+				op.InteractionType = "manual"
+			}
+			if op.Notes == "" {
+				op.Notes = "Marked via batch"
+			}
+		}
+		ops[i] = op
+	}
+	return ops, nil
+}
+
+// batchError points a failed batch operation at its command's line
+func batchError(commands []Command, err error) error {
+	var batchErr *db.BatchError
+	if errors.As(err, &batchErr) {
+		return fmt.Errorf("line %d: %w", commands[batchErr.Index].Line, batchErr.Err)
+	}
+	return err
+}
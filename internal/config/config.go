@@ -4,20 +4,41 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 
 	"github.com/BurntSushi/toml"
 )
 
 // Config holds the application configuration
 type Config struct {
-	Database DatabaseConfig `toml:"database"`
-	Tasks    TasksConfig    `toml:"tasks"`
-	External ExternalConfig `toml:"external"`
+	Database      DatabaseConfig      `toml:"database"`
+	Tasks         TasksConfig         `toml:"tasks"`
+	External      ExternalConfig      `toml:"external"`
+	Sync          SyncConfig          `toml:"sync"`
+	Confirm       ConfirmConfig       `toml:"confirm"`
+	UI            UIConfig            `toml:"ui"`
+	Retention     RetentionConfig     `toml:"retention"`
+	Escalation    EscalationConfig    `toml:"escalation"`
+	Notifications NotificationConfig  `toml:"notifications"`
+	Scripting     ScriptingConfig     `toml:"scripting"`
+	Email         EmailConfig         `toml:"email"`
+	Messaging     MessagingConfig     `toml:"messaging"`
+	Dates         DatesConfig         `toml:"dates"`
+	Waiting       WaitingConfig       `toml:"waiting"`
+	Relationships RelationshipsConfig `toml:"relationships"`
+	Fields        FieldsConfig        `toml:"fields"`
+	States        StatesConfig        `toml:"states"`
+	Enrich        EnrichConfig        `toml:"enrich"`
+	Avatars       AvatarsConfig       `toml:"avatars"`
+	Theme         ThemeConfig         `toml:"theme"`
 }
 
 // DatabaseConfig holds database-related configuration
 type DatabaseConfig struct {
-	Path string `toml:"path"`
+	Path        string `toml:"path"`
+	Encrypted   bool   `toml:"encrypted"`    // Encrypted with SQLCipher; see contacts-tui encrypt
+	KeyCommand  string `toml:"key_command"`  // Command printing the passphrase; prompted for when empty
+	JournalMode string `toml:"journal_mode"` // "wal" (default) or "delete" for databases in synced folders
 }
 
 // TasksConfig holds task management configuration
@@ -26,6 +47,9 @@ type TasksConfig struct {
 	Things       ThingsConfig        `toml:"things"`
 	Dstask       DstaskConfig        `toml:"dstask"`
 	TaskWarrior  TaskWarriorConfig   `toml:"taskwarrior"`
+	// Projects maps a contact label (e.g. "@sarahc") or relationship type to
+	// the project (Things: list or area) its tasks are filed under
+	Projects     map[string]string   `toml:"projects"`
 }
 
 // ThingsConfig holds Things-specific configuration
@@ -50,12 +74,233 @@ type ExternalConfig struct {
 	NotesTUI bool `toml:"notes_tui"` // Enable notes-tui integration
 }
 
+// SyncConfig holds background sync configuration
+type SyncConfig struct {
+	Backend  string            `toml:"backend"`  // "git" or "carddav", or empty to disable sync
+	Interval string            `toml:"interval"` // How often to sync, e.g. "15m" (empty: on startup and Ctrl+S only)
+	Git      GitSyncConfig     `toml:"git"`
+	CardDAV  CardDAVSyncConfig `toml:"carddav"`
+}
+
+// CardDAVSyncConfig holds CardDAV sync configuration
+type CardDAVSyncConfig struct {
+	URL             string `toml:"url"`              // Address book collection URL
+	Username        string `toml:"username"`
+	Password        string `toml:"password"`         // App password; password_command keeps it out of the file
+	PasswordCommand string `toml:"password_command"` // Command printing the password, e.g. "pass show fastmail"
+	Conflicts       string `toml:"conflicts"`        // Side that wins when both changed a field: "local" (default) or "remote"
+}
+
+// GitSyncConfig holds git sync configuration
+type GitSyncConfig struct {
+	Remote string `toml:"remote"` // Remote to pull from and push to (default: "origin")
+	Branch string `toml:"branch"` // Branch to sync (default: the current branch)
+}
+
+// ConfirmConfig controls which actions ask for confirmation first
+type ConfirmConfig struct {
+	Bump           bool `toml:"bump"`            // Confirm before bumping (default: true)
+	Delete         bool `toml:"delete"`          // Confirm before deleting (default: true)
+	Archive        bool `toml:"archive"`         // Confirm before archiving or unarchiving (default: false)
+	TaskCompletion bool `toml:"task_completion"` // Ask for a completion note before completing a task (default: true)
+	ArchiveReason  bool `toml:"archive_reason"`  // Ask for an optional reason when archiving (default: true)
+}
+
+// UIConfig holds user interface behavior settings
+type UIConfig struct {
+	DeleteAction     string  `toml:"delete_action"`     // What D does: "archive" (default) or "delete"
+	CopyInteractions int     `toml:"copy_interactions"` // Recent interactions included when y copies a contact (default: 5)
+	FollowUpAlerts   bool    `toml:"follow_up_alerts"`  // List follow-ups and deadlines due at startup (default: true)
+	Dashboard        bool    `toml:"dashboard"`         // Start on the dashboard (default: false)
+	StarredFirst     bool    `toml:"starred_first"`     // List starred contacts before the rest (default: true)
+	Sort             string  `toml:"sort"`              // Contact list order: name (default), last_contacted, overdue, recent or state; set with O
+	Mouse            bool    `toml:"mouse"`             // Click and scroll with the mouse (default: true)
+	ListWidth        float64 `toml:"list_width"`        // Share of the window the contact list takes, 0.2 to 0.8 (default: 0.33); set with Ctrl+←/→
+	Layout           string  `toml:"layout"`            // "auto" (default): list and details side by side, one at a time below 90 columns; "split" or "single"
+	OpenCommand      string  `toml:"open_command"`      // Opens interaction attachments (default: open on macOS, xdg-open elsewhere)
+	ExportDir        string  `toml:"export_dir"`        // Where w and W in the interaction view write a contact's timeline (default: ~/Documents)
+}
+
+// RetentionConfig controls how long archived and deleted contacts, and
+// copies of the database, are kept
+type RetentionConfig struct {
+	ArchivedDays   int    `toml:"archived_days"`   // Purge contacts archived longer than this many days; 0 keeps them forever
+	DeletedDir     string `toml:"deleted_dir"`     // Contacts are saved here as JSON before permanent deletion; "" disables
+	TrashDays      int    `toml:"trash_days"`      // Purge contacts in the trash longer than this many days; 0 keeps them until purged by hand
+	BackupDir      string `toml:"backup_dir"`      // The database is copied here before migrations change it; "" disables
+	Backups        int    `toml:"backups"`         // How many of those copies to keep (default: 5; 0 keeps them all)
+	BackupInterval string `toml:"backup_interval"` // Also copy it at startup once the last copy is this old, e.g. "24h"
+}
+
+// EscalationConfig controls reminder escalation for overdue contacts
+type EscalationConfig struct {
+	Enabled bool                      `toml:"enabled"` // Apply escalation steps automatically (default: false)
+	Default EscalationRule            `toml:"default"`
+	Types   map[string]EscalationRule `toml:"types"` // Per relationship type rules, replacing the default
+}
+
+// EscalationRule sets how many times overdue a contact must be before each
+// escalation step happens; 0 disables a step
+type EscalationRule struct {
+	PingAt      float64 `toml:"ping_at"`      // Set state to ping (default: 1)
+	TaskAt      float64 `toml:"task_at"`      // Create a task (default: 2)
+	NeglectedAt float64 `toml:"neglected_at"` // Show in the neglected view (default: 3)
+}
+
+// NotificationConfig limits when and how often reminders fire
+type NotificationConfig struct {
+	QuietHours string `toml:"quiet_hours"` // No reminders in this local time window, e.g. "22:00-08:00"
+	MaxPerDay  int    `toml:"max_per_day"` // Most reminders to fire per day; 0 is unlimited
+}
+
+// ScriptingConfig locates the user script file
+type ScriptingConfig struct {
+	File string `toml:"file"` // Script filters, sort score and automations (default: ~/.config/contacts/scripts.toml)
+}
+
+// EmailConfig holds the mail command and per-state email templates
+type EmailConfig struct {
+	Command   string                   `toml:"command"`   // Mail command; {draft} is replaced with a draft file, otherwise a mailto: URL is appended
+	Log       bool                     `toml:"log"`       // Log an "email" interaction when the mail command succeeds (default: false)
+	Templates map[string]EmailTemplate `toml:"templates"` // Keyed by contact state, with "default" for any other state
+}
+
+// EmailTemplate is a Go text/template subject and body for an email draft
+type EmailTemplate struct {
+	Subject string `toml:"subject"`
+	Body    string `toml:"body"`
+}
+
+// MessagingConfig holds the command used to text contacts
+type MessagingConfig struct {
+	Command     string `toml:"command"`      // e.g. "signal-cli -a +15551234567 send -m {message} {phone}"; empty disables texting
+	CallCommand string `toml:"call_command"` // Starts a call; {phone} is replaced with the number, otherwise a tel: URL is appended (default: open_command)
+	LogCalls    bool   `toml:"log_calls"`    // Log a "call" interaction when the call command succeeds (default: false)
+}
+
+// DatesConfig controls reminders for contacts' important dates
+type DatesConfig struct {
+	RemindDays int `toml:"remind_days"` // Surface important dates this many days ahead (default: 14)
+}
+
+// WaitingConfig controls nudges for contacts who owe a reply
+type WaitingConfig struct {
+	NudgeAfterDays int `toml:"nudge_after_days"` // Create a nudge task after waiting this many days; 0 disables (default: 7)
+}
+
+// RelationshipsConfig lists the relationship types contacts can have and
+// how often each should be in touch
+type RelationshipsConfig struct {
+	Types          []string       `toml:"types"`           // In the order shown in the TUI; change with contacts-tui types
+	Cadence        map[string]int `toml:"cadence"`         // Days between contacts before a contact of a type is overdue
+	DefaultCadence int            `toml:"default_cadence"` // For types without a cadence (default: 60)
+}
+
+// FieldsConfig declares custom contact fields
+type FieldsConfig struct {
+	Custom []string `toml:"custom"` // Field names in the order shown, e.g. "GitHub handle"
+}
+
+// StatesConfig lists the states contacts can be in and restricts how they
+// move between them
+type StatesConfig struct {
+	// List defines the states in the order the state menu shows them,
+	// replacing the built-in ones. It must include "ok" and "ping".
+	List []StateConfig `toml:"list"`
+	// Transitions maps a state to the states it may change to. States not
+	// listed can change to any state; contacts without a state count as "ok".
+	Transitions map[string][]string `toml:"transitions"`
+}
+
+// StateConfig defines one contact state
+type StateConfig struct {
+	Name  string `toml:"name"`
+	Color string `toml:"color"` // ANSI number or hex color; empty for the default
+	Task  bool   `toml:"task"`  // Create a task when a contact moves to this state
+}
+
+// DefaultStates are the states used when the config doesn't list any
+var DefaultStates = []StateConfig{
+	{Name: "ping", Task: true},
+	{Name: "invite", Task: true},
+	{Name: "write", Task: true},
+	{Name: "followup", Task: true},
+	{Name: "sked", Task: true},
+	{Name: "notes", Task: true},
+	{Name: "scheduled", Task: true},
+	{Name: "timeout", Task: true},
+	{Name: "ok"},
+}
+
+// Defined returns the configured states, or the built-in ones when the
+// config doesn't list any
+func (s StatesConfig) Defined() []StateConfig {
+	if len(s.List) == 0 {
+		return DefaultStates
+	}
+	return s.List
+}
+
+// Names returns the names of the defined states, in order
+func (s StatesConfig) Names() []string {
+	var names []string
+	for _, state := range s.Defined() {
+		names = append(names, state.Name)
+	}
+	return names
+}
+
+// EnrichConfig maps email domains to company names for filling in and
+// normalizing contacts' companies
+type EnrichConfig struct {
+	AfterImport bool              `toml:"after_import"` // Enrich automatically after each import
+	Domains     map[string]string `toml:"domains"`      // Email domain to company name, e.g. "acme.com" = "Acme Corp"
+}
+
+// AvatarsConfig controls fetching avatars by hashed email address and how
+// avatars are drawn in the detail pane
+type AvatarsConfig struct {
+	Enabled  bool   `toml:"enabled"`   // Fetch avatars after imports (default: false)
+	Service  string `toml:"service"`   // "gravatar" or "libravatar" (default: "gravatar")
+	CacheDir string `toml:"cache_dir"` // Where fetched avatars are kept
+	Display  string `toml:"display"`   // "auto", "kitty", "blocks", "text" or "off" (default: "auto")
+	Height   int    `toml:"height"`    // Rows the avatar takes in the detail pane (default: 6)
+}
+
+// ThemeConfig picks the colors of the TUI: a built-in theme, with any of
+// its colors replaced. Colors are ANSI numbers ("214") or hex ("#ff8700").
+type ThemeConfig struct {
+	Name      string `toml:"name"`       // "auto" (default), "dark", "light" or "solarized"
+	Accent    string `toml:"accent"`     // Selected contact, states and highlights
+	Title     string `toml:"title"`      // Titles of forms and dialogs
+	Border    string `toml:"border"`     // Pane borders
+	Overlay   string `toml:"overlay"`    // Borders of forms and dialogs
+	Muted     string `toml:"muted"`      // Labels, hints and help text
+	Dim       string `toml:"dim"`        // Archived contacts and placeholders
+	Text      string `toml:"text"`       // Text typed into inputs
+	Danger    string `toml:"danger"`     // Overdue contacts and deletions
+	Success   string `toml:"success"`    // Ambient contacts
+	Warning   string `toml:"warning"`    // Triggered contacts and dates coming up
+	Link      string `toml:"link"`       // Links in notes
+	Code      string `toml:"code"`       // Code in notes
+	StatusBar string `toml:"status_bar"` // Background of the status bar
+
+	// Backgrounds of status bar messages, which are written in white
+	FlashSuccess string `toml:"flash_success"`
+	FlashError   string `toml:"flash_error"`
+	FlashInfo    string `toml:"flash_info"`
+}
+
+// DefaultRelationshipTypes are the relationship types of a new database
+var DefaultRelationshipTypes = []string{"work", "close", "family", "network", "social", "providers", "recruiters"}
+
 // Default returns the default configuration
 func Default() *Config {
 	homeDir, _ := os.UserHomeDir()
 	return &Config{
 		Database: DatabaseConfig{
-			Path: filepath.Join(homeDir, ".config", "contacts", "contacts.db"),
+			Path:        filepath.Join(homeDir, ".config", "contacts", "contacts.db"),
+			JournalMode: "wal",
 		},
 		Tasks: TasksConfig{
 			Backend: "", // Empty means auto-detect
@@ -69,17 +314,96 @@ func Default() *Config {
 		External: ExternalConfig{
 			NotesTUI: false, // Disabled by default
 		},
+		Sync: SyncConfig{
+			Backend: "", // Disabled by default
+			Git: GitSyncConfig{
+				Remote: "origin",
+			},
+		},
+		Confirm: ConfirmConfig{
+			Bump:           true,
+			Delete:         true,
+			Archive:        false,
+			TaskCompletion: true,
+			ArchiveReason:  true,
+		},
+		UI: UIConfig{
+			DeleteAction:     "archive",
+			CopyInteractions: 5,
+			FollowUpAlerts:   true,
+			StarredFirst:     true,
+			Sort:             "name",
+			Mouse:            true,
+			ListWidth:        0.33,
+			Layout:           "auto",
+			OpenCommand:      defaultOpenCommand(),
+			ExportDir:        filepath.Join(homeDir, "Documents"),
+		},
+		Retention: RetentionConfig{
+			TrashDays:  30,
+			DeletedDir: filepath.Join(homeDir, ".config", "contacts", "deleted"),
+			BackupDir:  filepath.Join(homeDir, ".config", "contacts", "backups"),
+			Backups:    5,
+		},
+		Escalation: EscalationConfig{
+			Enabled: false,
+			Default: EscalationRule{
+				PingAt:      1,
+				TaskAt:      2,
+				NeglectedAt: 3,
+			},
+		},
+		Scripting: ScriptingConfig{
+			File: filepath.Join(homeDir, ".config", "contacts", "scripts.toml"),
+		},
+		Dates: DatesConfig{
+			RemindDays: 14,
+		},
+		Waiting: WaitingConfig{
+			NudgeAfterDays: 7,
+		},
+		Relationships: RelationshipsConfig{
+			Types:          append([]string(nil), DefaultRelationshipTypes...),
+			Cadence:        map[string]int{"close": 30, "family": 30, "network": 90},
+			DefaultCadence: 60,
+		},
+		Avatars: AvatarsConfig{
+			Service:  "gravatar",
+			CacheDir: filepath.Join(homeDir, ".config", "contacts", "avatars"),
+			Display:  "auto",
+			Height:   6,
+		},
+		Email: EmailConfig{
+			Command: defaultOpenCommand(),
+			Templates: map[string]EmailTemplate{
+				"ping": {
+					Subject: "Checking in",
+					Body:    "Hi {{.FirstName}},\n\nIt's been a while, so I wanted to check in. How are things going?\n\n",
+				},
+				"default": {
+					Subject: "Hello",
+					Body:    "Hi {{.FirstName}},\n\n",
+				},
+			},
+		},
+	}
+}
+
+// Path returns the standard location of the config file
+func Path() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("getting home dir: %w", err)
 	}
+	return filepath.Join(homeDir, ".config", "contacts", "config.toml"), nil
 }
 
 // Load loads configuration from the standard location
 func Load() (*Config, error) {
-	homeDir, err := os.UserHomeDir()
+	configPath, err := Path()
 	if err != nil {
-		return nil, fmt.Errorf("getting home dir: %w", err)
+		return nil, err
 	}
-	
-	configPath := filepath.Join(homeDir, ".config", "contacts", "config.toml")
 	return LoadFrom(configPath)
 }
 
@@ -106,14 +430,38 @@ func LoadFrom(configPath string) (*Config, error) {
 	
 	// Expand home directory in paths
 	if cfg.Database.Path != "" {
-		cfg.Database.Path = expandPath(cfg.Database.Path)
+		cfg.Database.Path = ExpandPath(cfg.Database.Path)
+	}
+	if cfg.Retention.DeletedDir != "" {
+		cfg.Retention.DeletedDir = ExpandPath(cfg.Retention.DeletedDir)
+	}
+	if cfg.Retention.BackupDir != "" {
+		cfg.Retention.BackupDir = ExpandPath(cfg.Retention.BackupDir)
+	}
+	if cfg.Scripting.File != "" {
+		cfg.Scripting.File = ExpandPath(cfg.Scripting.File)
+	}
+	if cfg.UI.ExportDir != "" {
+		cfg.UI.ExportDir = ExpandPath(cfg.UI.ExportDir)
+	}
+	if cfg.Avatars.CacheDir != "" {
+		cfg.Avatars.CacheDir = ExpandPath(cfg.Avatars.CacheDir)
 	}
 	
 	return cfg, nil
 }
 
-// expandPath expands ~ to home directory
-func expandPath(path string) string {
+// defaultOpenCommand returns the system command that opens files and URLs,
+// including mailto: URLs
+func defaultOpenCommand() string {
+	if runtime.GOOS == "darwin" {
+		return "open"
+	}
+	return "xdg-open"
+}
+
+// ExpandPath expands ~ to home directory
+func ExpandPath(path string) string {
 	if len(path) > 0 && path[0] == '~' {
 		homeDir, _ := os.UserHomeDir()
 		return filepath.Join(homeDir, path[1:])
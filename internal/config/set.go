@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// writePath, when set, is where SetValue and SetNumber write instead of
+// the config file
+var writePath string
+
+// SetWritePath makes SetValue and SetNumber write to path instead of the
+// config file, so a scratch run can change settings without touching it
+func SetWritePath(path string) {
+	writePath = path
+}
+
+// SetValue sets a string setting in a section of the config file, such as
+// sort under [ui], leaving the rest of the file and its comments as they
+// are. The section is added if the file doesn't have it.
+func SetValue(section, key, value string) error {
+	return setSetting(section, key, strconv.Quote(value))
+}
+
+// SetNumber sets a number setting in a section of the config file, like
+// SetValue does for strings
+func SetNumber(section, key string, value float64) error {
+	return setSetting(section, key, strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// setSetting sets key to an already formatted TOML value
+func setSetting(section, key, value string) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if writePath != "" {
+		path = writePath
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading config: %w", err)
+	}
+
+	setting := key + " = " + value
+	keyLine := regexp.MustCompile(`^\s*` + regexp.QuoteMeta(key) + `\s*=`)
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(data) == 0 {
+		lines = nil
+	}
+
+	header := -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if header < 0 {
+			if trimmed == "["+section+"]" {
+				header = i
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "[") {
+			break // Next section
+		}
+		if keyLine.MatchString(line) {
+			lines[i] = setting
+			return writeConfig(path, lines)
+		}
+	}
+
+	if header < 0 {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "["+section+"]", setting)
+	} else {
+		lines = append(lines[:header+1], append([]string{setting}, lines[header+1:]...)...)
+	}
+	return writeConfig(path, lines)
+}
+
+// writeConfig writes the lines of the config file
+func writeConfig(path string, lines []string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	return nil
+}
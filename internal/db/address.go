@@ -0,0 +1,90 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Address is a contact's postal address
+type Address struct {
+	Street     string `json:"street,omitempty"`
+	City       string `json:"city,omitempty"`
+	Region     string `json:"region,omitempty"` // State, province or county
+	PostalCode string `json:"postal_code,omitempty"`
+	Country    string `json:"country,omitempty"`
+}
+
+// IsEmpty reports whether no part of the address is filled in
+func (a Address) IsEmpty() bool {
+	return a == Address{}
+}
+
+// Lines formats the address as it would be written on an envelope, e.g.
+// "12 Oak St" / "Portland, OR 97201" / "USA"
+func (a Address) Lines() []string {
+	var lines []string
+	if a.Street != "" {
+		lines = append(lines, a.Street)
+	}
+
+	locality := a.City
+	if a.Region != "" {
+		if locality != "" {
+			locality += ", "
+		}
+		locality += a.Region
+	}
+	if a.PostalCode != "" {
+		if locality != "" {
+			locality += " "
+		}
+		locality += a.PostalCode
+	}
+	if locality != "" {
+		lines = append(lines, locality)
+	}
+
+	if a.Country != "" {
+		lines = append(lines, a.Country)
+	}
+	return lines
+}
+
+// InLocation reports whether a contact's location, or the city, region or
+// country of its address, contains place, ignoring case
+func (c Contact) InLocation(place string) bool {
+	place = strings.ToLower(place)
+	for _, value := range []string{c.Location.String, c.Address.City, c.Address.Region, c.Address.Country} {
+		if strings.Contains(strings.ToLower(value), place) {
+			return true
+		}
+	}
+	return false
+}
+
+// trimmed returns the address with surrounding whitespace removed
+func (a Address) trimmed() Address {
+	return Address{
+		Street:     strings.TrimSpace(a.Street),
+		City:       strings.TrimSpace(a.City),
+		Region:     strings.TrimSpace(a.Region),
+		PostalCode: strings.TrimSpace(a.PostalCode),
+		Country:    strings.TrimSpace(a.Country),
+	}
+}
+
+// UpdateAddress replaces a contact's postal address; an empty address
+// clears it
+func (db *DB) UpdateAddress(contactID int, a Address) error {
+	a = a.trimmed()
+	_, err := db.pool().Exec(`
+		UPDATE contacts
+		SET street = ?, city = ?, region = ?, postal_code = ?, country = ?
+		WHERE id = ?
+	`, NewNullString(a.Street), NewNullString(a.City), NewNullString(a.Region),
+		NewNullString(a.PostalCode), NewNullString(a.Country), contactID)
+	if err != nil {
+		return fmt.Errorf("updating address: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AddAttachment attaches a file path or URL to an interaction
+func (db *DB) AddAttachment(interactionID int, target string) error {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return fmt.Errorf("a file path or URL is required")
+	}
+	_, err := db.pool().Exec(`INSERT OR IGNORE INTO interaction_attachments (interaction_id, target) VALUES (?, ?)`, interactionID, target)
+	if err != nil {
+		return fmt.Errorf("adding attachment: %w", err)
+	}
+	return nil
+}
+
+// RemoveAttachment takes a file path or URL off an interaction
+func (db *DB) RemoveAttachment(interactionID int, target string) error {
+	_, err := db.pool().Exec(`DELETE FROM interaction_attachments WHERE interaction_id = ? AND target = ?`, interactionID, target)
+	if err != nil {
+		return fmt.Errorf("removing attachment: %w", err)
+	}
+	return nil
+}
+
+// loadAttachments fills in the attachments of interactions, in the order
+// they were added. With contactID 0 the interactions may belong to anyone.
+func (db *DB) loadAttachments(logs []Log, contactID int) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	query := `SELECT interaction_id, target FROM interaction_attachments ORDER BY id`
+	var args []interface{}
+	if contactID != 0 {
+		query = `
+			SELECT interaction_id, target
+			FROM interaction_attachments
+			WHERE interaction_id IN (SELECT id FROM contact_interactions WHERE contact_id = ?)
+			ORDER BY id
+		`
+		args = append(args, contactID)
+	}
+	rows, err := db.pool().Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("querying attachments: %w", err)
+	}
+	defer rows.Close()
+
+	byInteraction := make(map[int][]string)
+	for rows.Next() {
+		var id int
+		var target string
+		if err := rows.Scan(&id, &target); err != nil {
+			return fmt.Errorf("scanning attachment: %w", err)
+		}
+		byInteraction[id] = append(byInteraction[id], target)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range logs {
+		logs[i].Attachments = byInteraction[logs[i].ID]
+	}
+	return nil
+}
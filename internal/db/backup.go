@@ -0,0 +1,352 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// BackupVersion is the version of the backup document written by Backup.
+// Restore accepts documents up to this version.
+const BackupVersion = 1
+
+// timestampLayout is how SQLite's CURRENT_TIMESTAMP writes times, in UTC
+const timestampLayout = "2006-01-02 15:04:05"
+
+// Backup is the whole database as one JSON document: every contact with its
+// interactions and important dates, the links between contacts, and the log
+// entries linked to them
+type Backup struct {
+	Version    int             `json:"version"`
+	ExportedAt time.Time       `json:"exported_at"`
+	Contacts   []ContactRecord `json:"contacts"`
+	Groups     []string        `json:"groups,omitempty"` // Every group, including empty ones
+	Links      []LinkRecord    `json:"links,omitempty"`
+	Logs       []LogRecord     `json:"logs"`
+}
+
+// LinkRecord is the JSON form of a link between two contacts
+type LinkRecord struct {
+	ContactID int    `json:"contact_id"`
+	OtherID   int    `json:"other_id"`
+	Kind      string `json:"kind"`
+}
+
+// LogRecord is the JSON form of a log entry and the contacts it mentions
+type LogRecord struct {
+	Content    string    `json:"content"`
+	ContactIDs []int     `json:"contact_ids,omitempty"` // IDs of the contacts in the backup
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Backup reads the whole database into a backup document
+func (db *DB) Backup() (Backup, error) {
+	backup := Backup{Version: BackupVersion, ExportedAt: time.Now().UTC(), Contacts: []ContactRecord{}, Logs: []LogRecord{}}
+
+	contacts, err := db.ListContacts()
+	if err != nil {
+		return backup, err
+	}
+	trashed, err := db.ListTrashed()
+	if err != nil {
+		return backup, err
+	}
+	contacts = append(contacts, trashed...)
+	interactions, err := db.ListInteractions()
+	if err != nil {
+		return backup, err
+	}
+	byContact := make(map[int][]Log)
+	for _, l := range interactions {
+		byContact[l.ContactID] = append(byContact[l.ContactID], l)
+	}
+
+	groups, err := db.ListGroups()
+	if err != nil {
+		return backup, err
+	}
+	for _, g := range groups {
+		backup.Groups = append(backup.Groups, g.Name)
+	}
+
+	sort.Slice(contacts, func(i, j int) bool { return contacts[i].ID < contacts[j].ID })
+	for _, c := range contacts {
+		dates, err := db.ListImportantDates(c.ID)
+		if err != nil {
+			return backup, err
+		}
+		emails, err := db.ListEmails(c.ID)
+		if err != nil {
+			return backup, err
+		}
+		phones, err := db.ListPhones(c.ID)
+		if err != nil {
+			return backup, err
+		}
+		handles, err := db.ListHandles(c.ID)
+		if err != nil {
+			return backup, err
+		}
+		fields, err := db.FieldValues(c.ID)
+		if err != nil {
+			return backup, err
+		}
+		record := NewContactRecord(c, byContact[c.ID])
+		record.ImportantDates = NewDateRecords(dates)
+		record.Emails = NewEmailRecords(emails)
+		record.Phones = NewPhoneRecords(phones)
+		record.Handles = NewHandleRecords(handles)
+		if len(fields) > 0 {
+			record.Fields = fields
+		}
+		backup.Contacts = append(backup.Contacts, record)
+	}
+
+	links, err := db.pool().Query(`SELECT contact_id, other_id, kind FROM contact_links ORDER BY id`)
+	if err != nil {
+		return backup, fmt.Errorf("querying links: %w", err)
+	}
+	defer links.Close()
+	for links.Next() {
+		var r LinkRecord
+		if err := links.Scan(&r.ContactID, &r.OtherID, &r.Kind); err != nil {
+			return backup, fmt.Errorf("scanning link: %w", err)
+		}
+		backup.Links = append(backup.Links, r)
+	}
+	if err := links.Err(); err != nil {
+		return backup, err
+	}
+
+	rows, err := db.pool().Query(`
+		SELECT l.id, l.content, l.created_at, l.updated_at, lc.contact_id
+		FROM logs l
+		LEFT JOIN log_contacts lc ON lc.log_id = l.id
+		ORDER BY l.created_at, l.id, lc.contact_id
+	`)
+	if err != nil {
+		return backup, fmt.Errorf("querying logs: %w", err)
+	}
+	defer rows.Close()
+
+	lastID := 0
+	for rows.Next() {
+		var id int
+		var r LogRecord
+		var contactID sql.NullInt64
+		if err := rows.Scan(&id, &r.Content, &r.CreatedAt, &r.UpdatedAt, &contactID); err != nil {
+			return backup, fmt.Errorf("scanning log: %w", err)
+		}
+		if id != lastID {
+			backup.Logs = append(backup.Logs, r)
+			lastID = id
+		}
+		if contactID.Valid {
+			last := &backup.Logs[len(backup.Logs)-1]
+			last.ContactIDs = append(last.ContactIDs, int(contactID.Int64))
+		}
+	}
+	return backup, rows.Err()
+}
+
+// Restore loads a backup document into an empty database in one
+// transaction, keeping contact IDs. Links to a sync server are not restored;
+// the next sync matches contacts to their cards again.
+func (db *DB) Restore(backup Backup) error {
+	if backup.Version < 1 || backup.Version > BackupVersion {
+		return fmt.Errorf("unsupported backup version %d (this build reads up to %d)", backup.Version, BackupVersion)
+	}
+
+	tx, err := db.pool().Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var count int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM contacts`).Scan(&count); err != nil {
+		return fmt.Errorf("counting contacts: %w", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("database already has %d contacts; restore into a new database", count)
+	}
+
+	for _, name := range backup.Groups {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO contact_groups (name) VALUES (?)`, name); err != nil {
+			return fmt.Errorf("group %s: %w", name, err)
+		}
+	}
+
+	for _, c := range backup.Contacts {
+		if err := restoreContact(tx, c); err != nil {
+			return fmt.Errorf("contact %d (%s): %w", c.ID, c.Name, err)
+		}
+	}
+
+	for _, l := range backup.Links {
+		_, err := tx.Exec(`INSERT OR IGNORE INTO contact_links (contact_id, other_id, kind) VALUES (?, ?, ?)`, l.ContactID, l.OtherID, l.Kind)
+		if err != nil {
+			return fmt.Errorf("link from contact %d to %d: %w", l.ContactID, l.OtherID, err)
+		}
+	}
+
+	for i, l := range backup.Logs {
+		result, err := tx.Exec(`INSERT INTO logs (content, created_at, updated_at) VALUES (?, ?, ?)`,
+			l.Content, l.CreatedAt.UTC().Format(timestampLayout), l.UpdatedAt.UTC().Format(timestampLayout))
+		if err != nil {
+			return fmt.Errorf("log %d: %w", i+1, err)
+		}
+		logID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("log %d: %w", i+1, err)
+		}
+		for _, contactID := range l.ContactIDs {
+			if _, err := tx.Exec(`INSERT INTO log_contacts (log_id, contact_id) VALUES (?, ?)`, logID, contactID); err != nil {
+				return fmt.Errorf("log %d: linking contact %d: %w", i+1, contactID, err)
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// restoreContact inserts a contact with its interactions and important dates
+func restoreContact(tx *sql.Tx, c ContactRecord) error {
+	var frequency interface{}
+	if c.CustomFrequencyDays != nil {
+		frequency = *c.CustomFrequencyDays
+	}
+	style := c.ContactStyle
+	if style == "" {
+		style = "periodic"
+	}
+	var address Address
+	if c.Address != nil {
+		address = c.Address.trimmed()
+	}
+	birthday, err := ParseBirthday(c.Birthday)
+	if err != nil {
+		return err
+	}
+
+	// The first email address and phone number are the primary ones. They are
+	// written with the contact, since updating it afterwards would reset its
+	// updated_at.
+	emails := c.Emails
+	if len(emails) == 0 && c.Email != "" {
+		emails = []EmailRecord{{Email: c.Email, Type: EmailPersonal}} // Backups from before email lists
+	}
+	email := c.Email
+	if len(emails) > 0 {
+		email = emails[0].Email
+	}
+	phones := c.Phones
+	if len(phones) == 0 && c.Phone != "" {
+		phones = []PhoneRecord{{Phone: c.Phone, Label: PhoneMobile}} // Backups from before phone lists
+	}
+	phone := c.Phone
+	if len(phones) > 0 {
+		phone = phones[0].Phone
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO contacts (
+			id, name, email, phone, company, location,
+			street, city, region, postal_code, country, birthday, avatar, timezone,
+			relationship_type, state, notes, label, basic_memory_url,
+			contacted_at, last_bump_date, bump_count, follow_up_date, deadline_date,
+			archived, archived_at, archive_reason,
+			contact_style, custom_frequency_days, escalation_level,
+			reminders_muted, starred, waiting_since, waiting_nudged, snooze_until, trashed_at,
+			created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		c.ID, c.Name, NewNullString(email), NewNullString(phone), NewNullString(c.Company), NewNullString(c.Location),
+		NewNullString(address.Street), NewNullString(address.City), NewNullString(address.Region),
+		NewNullString(address.PostalCode), NewNullString(address.Country), NewNullString(birthday), NewNullString(c.Avatar), NewNullString(c.Timezone),
+		c.RelationshipType, NewNullString(c.State), NewNullString(c.Notes), NewNullString(c.Label), NewNullString(c.BasicMemoryURL),
+		timestampValue(c.ContactedAt), timestampValue(c.LastBumpDate), c.BumpCount, dateValue(c.FollowUpDate), dateValue(c.DeadlineDate),
+		c.Archived, timestampValue(c.ArchivedAt), NewNullString(c.ArchiveReason),
+		style, frequency, c.EscalationLevel,
+		c.RemindersMuted, c.Starred, timestampValue(c.WaitingSince), c.WaitingNudged, timestampValue(c.SnoozeUntil), timestampValue(c.TrashedAt),
+		c.CreatedAt.UTC().Format(timestampLayout), c.UpdatedAt.UTC().Format(timestampLayout),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range c.Interactions {
+		result, err := tx.Exec(`
+			INSERT INTO contact_interactions (contact_id, interaction_date, interaction_type, notes, rating, duration_minutes)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, c.ID, i.Date.UTC().Format(timestampLayout), i.Type, NewNullString(i.Notes),
+			sql.NullInt64{Int64: i.Rating, Valid: i.Rating > 0},
+			sql.NullInt64{Int64: i.Minutes, Valid: i.Minutes > 0})
+		if err != nil {
+			return fmt.Errorf("interaction on %s: %w", i.Date.Format(dateLayout), err)
+		}
+		interactionID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("interaction on %s: %w", i.Date.Format(dateLayout), err)
+		}
+		for _, target := range i.Attachments {
+			_, err := tx.Exec(`INSERT OR IGNORE INTO interaction_attachments (interaction_id, target) VALUES (?, ?)`, interactionID, target)
+			if err != nil {
+				return fmt.Errorf("attachment %s: %w", target, err)
+			}
+		}
+	}
+
+	for _, e := range emails {
+		if err := emailList.insert(tx, c.ID, e.Email, e.Type); err != nil {
+			return fmt.Errorf("email %s: %w", e.Email, err)
+		}
+	}
+	for _, p := range phones {
+		if err := phoneList.insert(tx, c.ID, p.Phone, p.Label); err != nil {
+			return fmt.Errorf("phone %s: %w", p.Phone, err)
+		}
+	}
+	for _, h := range c.Handles {
+		if err := handleList.insert(tx, c.ID, h.Handle, h.Platform); err != nil {
+			return fmt.Errorf("handle %s: %w", h.Handle, err)
+		}
+	}
+
+	if err := setContactTags(tx, c.ID, c.Tags); err != nil {
+		return err
+	}
+	if err := setContactGroups(tx, c.ID, c.Groups); err != nil {
+		return err
+	}
+	if err := setFieldValues(tx, c.ID, c.Fields); err != nil {
+		return err
+	}
+
+	for _, d := range c.ImportantDates {
+		_, err := tx.Exec(`INSERT INTO important_dates (contact_id, label, date, recurring) VALUES (?, ?, ?, ?)`,
+			c.ID, d.Label, d.Date, d.Recurring)
+		if err != nil {
+			return fmt.Errorf("important date %s: %w", d.Label, err)
+		}
+	}
+	return nil
+}
+
+// timestampValue converts an optional time for a timestamp column
+func timestampValue(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(timestampLayout)
+}
+
+// dateValue converts an optional time for a date column
+func dateValue(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.Format(dateLayout)
+}
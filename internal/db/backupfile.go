@@ -0,0 +1,198 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Where copies of the database file are kept, and how many
+var (
+	backupDir  string // "" disables the copies
+	backupKeep int    // 0 keeps every copy
+)
+
+// SetBackups makes copies of the database file go into dir, keeping the
+// newest keep of them (0 keeps them all). Copies are made before migrations
+// and before relationship type and state changes rebuild the contacts
+// table, on a schedule and on request. An empty dir disables them.
+func SetBackups(dir string, keep int) {
+	backupDir = dir
+	backupKeep = keep
+}
+
+// BackupDir returns where copies of the database are kept, or "" if they
+// are disabled
+func BackupDir() string {
+	return backupDir
+}
+
+// backupPrefix is how the names of the database's copies start
+func (db *DB) backupPrefix() string {
+	return strings.TrimSuffix(filepath.Base(db.path), filepath.Ext(db.path)) + "-"
+}
+
+// BackupFile copies the database into the backup directory as
+// <name>-<timestamp>.db and removes the oldest copies past the number kept.
+// It returns the copy's path, or "" if copies are disabled.
+func (db *DB) BackupFile() (string, error) {
+	if backupDir == "" {
+		return "", nil
+	}
+	if err := os.MkdirAll(backupDir, 0700); err != nil {
+		return "", fmt.Errorf("creating backup directory: %w", err)
+	}
+
+	name := db.backupPrefix() + time.Now().Format("20060102-150405")
+	path := filepath.Join(backupDir, name+".db")
+	for n := 2; ; n++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			break
+		}
+		// Already copied this second
+		path = filepath.Join(backupDir, fmt.Sprintf("%s-%d.db", name, n))
+	}
+	// VACUUM INTO writes a consistent copy even while the database is open
+	if _, err := db.pool().Exec(`VACUUM INTO ?`, path); err != nil {
+		return "", fmt.Errorf("backing up database: %w", err)
+	}
+	log.Printf("Backed up database to %s", path)
+
+	if backupKeep <= 0 {
+		return path, nil
+	}
+	copies, err := db.BackupFiles()
+	if err != nil {
+		return "", err
+	}
+	for _, old := range copies[min(backupKeep, len(copies)):] {
+		if err := os.Remove(old); err != nil {
+			return "", fmt.Errorf("removing old backup: %w", err)
+		}
+	}
+	return path, nil
+}
+
+// BackupFiles lists the copies of the database, newest first
+func (db *DB) BackupFiles() ([]string, error) {
+	if backupDir == "" {
+		return nil, nil
+	}
+	copies, err := filepath.Glob(filepath.Join(backupDir, db.backupPrefix()+"*.db"))
+	if err != nil {
+		return nil, err
+	}
+	modified := make(map[string]time.Time)
+	for _, path := range copies {
+		if info, err := os.Stat(path); err == nil {
+			modified[path] = info.ModTime()
+		}
+	}
+	sort.SliceStable(copies, func(i, j int) bool {
+		if !modified[copies[i]].Equal(modified[copies[j]]) {
+			return modified[copies[i]].After(modified[copies[j]])
+		}
+		return copies[i] > copies[j]
+	})
+	return copies, nil
+}
+
+// LastBackupFile returns when the newest copy was made, or the zero time if
+// there is none
+func (db *DB) LastBackupFile() (time.Time, error) {
+	copies, err := db.BackupFiles()
+	if err != nil || len(copies) == 0 {
+		return time.Time{}, err
+	}
+	info, err := os.Stat(copies[0])
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
+// CopyFile writes a consistent copy of the database at src to dst,
+// including changes still in its write-ahead log, without migrating or
+// otherwise changing src
+func CopyFile(src, dst string) error {
+	if _, err := os.Stat(src); err != nil {
+		return err
+	}
+	conn, err := sql.Open(driverName, "file:"+src+"?mode=ro")
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", src, err)
+	}
+	defer conn.Close()
+	if _, err := conn.Exec(`VACUUM INTO ?`, dst); err != nil {
+		return fmt.Errorf("copying database: %w", err)
+	}
+	return nil
+}
+
+// RestoreBackupFile replaces the database with a copy, after copying the
+// current database so the restore can be undone. The copy is migrated if it
+// predates the current schema.
+func (db *DB) RestoreBackupFile(file string) error {
+	if err := checkBackupFile(file); err != nil {
+		return err
+	}
+	if _, err := db.BackupFile(); err != nil {
+		return err
+	}
+
+	src, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	tmp, err := os.CreateTemp(filepath.Dir(db.path), ".restore-*.db")
+	if err != nil {
+		return fmt.Errorf("restoring backup: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, src); err != nil {
+		tmp.Close()
+		return fmt.Errorf("restoring backup: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("restoring backup: %w", err)
+	}
+
+	return db.Exclusive(func() error {
+		if err := os.Rename(tmp.Name(), db.path); err != nil {
+			return fmt.Errorf("restoring backup: %w", err)
+		}
+		return nil
+	})
+}
+
+// checkBackupFile makes sure a file is an intact contacts database
+func checkBackupFile(file string) error {
+	if _, err := os.Stat(file); err != nil {
+		return err
+	}
+	conn, err := sql.Open(driverName, "file:"+file+"?mode=ro")
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", file, err)
+	}
+	defer conn.Close()
+
+	var result string
+	if err := conn.QueryRow(`PRAGMA quick_check`).Scan(&result); err != nil {
+		return fmt.Errorf("%s is not a database: %w", file, err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("%s is damaged: %s", file, result)
+	}
+	var count int
+	if err := conn.QueryRow(`SELECT COUNT(*) FROM contacts`).Scan(&count); err != nil {
+		return fmt.Errorf("%s is not a contacts database: %w", file, err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Batch actions
+const (
+	BatchSetState  = "set-state"
+	BatchContacted = "contacted"
+	BatchArchive   = "archive"
+	BatchAddNote   = "add-note"
+)
+
+// BatchOp is a single change applied by ApplyBatch
+type BatchOp struct {
+	Action          string
+	ContactID       int
+	State           string // For set-state
+	InteractionType string // For contacted and add-note
+	Notes           string // For contacted and add-note
+	Reason          string // For archive
+}
+
+// BatchError reports which operation of a batch failed
+type BatchError struct {
+	Index int // Position of the failed operation, from 0
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("operation %d: %v", e.Index+1, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyBatch applies the operations in order in a single transaction. If any
+// of them fails, none are applied.
+func (db *DB) ApplyBatch(ops []BatchOp) error {
+	return db.runBatch(ops, true)
+}
+
+// CheckBatch runs the operations as ApplyBatch would and rolls them back,
+// reporting the first that would fail
+func (db *DB) CheckBatch(ops []BatchOp) error {
+	return db.runBatch(ops, false)
+}
+
+// runBatch applies the operations in a transaction, committing it if asked
+func (db *DB) runBatch(ops []BatchOp, commit bool) error {
+	tx, err := db.pool().Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i, op := range ops {
+		if err := applyBatchOp(tx, op); err != nil {
+			return &BatchError{Index: i, Err: fmt.Errorf("%s: %w", op.Action, err)}
+		}
+	}
+	if !commit {
+		return nil
+	}
+	return tx.Commit()
+}
+
+// applyBatchOp performs one batch operation within a transaction
+func applyBatchOp(tx *sql.Tx, op BatchOp) error {
+	var res sql.Result
+	var err error
+	switch op.Action {
+	case BatchSetState:
+		res, err = tx.Exec(`UPDATE contacts SET state = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`, op.State, op.ContactID)
+	case BatchContacted:
+		res, err = tx.Exec(`UPDATE contacts SET contacted_at = CURRENT_TIMESTAMP WHERE id = ?`, op.ContactID)
+		if err == nil {
+			_, err = tx.Exec(`
+				INSERT INTO contact_interactions (contact_id, interaction_date, interaction_type, notes)
+				VALUES (?, CURRENT_TIMESTAMP, ?, ?)
+			`, op.ContactID, op.InteractionType, op.Notes)
+		}
+	case BatchArchive:
+		res, err = tx.Exec(`
+			UPDATE contacts
+			SET archived = 1,
+			    archived_at = CURRENT_TIMESTAMP,
+			    archive_reason = ?,
+			    updated_at = CURRENT_TIMESTAMP
+			WHERE id = ?
+		`, NewNullString(strings.TrimSpace(op.Reason)), op.ContactID)
+	case BatchAddNote:
+		if op.Notes == "" {
+			return fmt.Errorf("notes cannot be empty")
+		}
+		res, err = tx.Exec(`
+			INSERT INTO contact_interactions (contact_id, interaction_date, interaction_type, notes)
+			VALUES (?, CURRENT_TIMESTAMP, ?, ?)
+		`, op.ContactID, op.InteractionType, op.Notes)
+	default:
+		return fmt.Errorf("unknown action")
+	}
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("no contact with id %d", op.ContactID)
+	}
+	return nil
+}
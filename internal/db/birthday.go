@@ -0,0 +1,129 @@
+package db
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Birthdays are stored as YYYY-MM-DD, or as --MM-DD when the year is
+// unknown, which is how vCard writes a date without a year
+
+// birthdayLabel labels birthdays among upcoming dates
+const birthdayLabel = "Birthday"
+
+// ParseBirthday converts a birthday typed as YYYY-MM-DD, MM-DD or --MM-DD
+// (or vCard's YYYYMMDD and --MMDD) to its stored form. An empty string
+// means no birthday.
+func ParseBirthday(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", nil
+	}
+	for _, layout := range []string{"2006-01-02", "20060102"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Format(dateLayout), nil
+		}
+	}
+	for _, layout := range []string{"01-02", "0102"} {
+		// Parsed in a leap year so February 29 is accepted
+		if t, err := time.Parse("2006-"+layout, "2000-"+strings.TrimPrefix(s, "--")); err == nil {
+			return t.Format("--01-02"), nil
+		}
+	}
+	return "", fmt.Errorf("invalid birthday %q (use YYYY-MM-DD, or MM-DD if the year is unknown)", s)
+}
+
+// birthdayDate reads a stored birthday, reporting whether its year is known.
+// Birthdays without a year are dated in 2000.
+func birthdayDate(birthday string) (date time.Time, yearKnown, ok bool) {
+	if strings.HasPrefix(birthday, "--") {
+		t, err := time.ParseInLocation("2006-01-02", "2000-"+birthday[2:], time.Local)
+		return t, false, err == nil
+	}
+	t, err := time.ParseInLocation(dateLayout, birthday, time.Local)
+	return t, true, err == nil
+}
+
+// FormatBirthday describes a stored birthday, e.g. "Apr 12, 1980" or "Apr 12"
+func FormatBirthday(birthday string) string {
+	date, yearKnown, ok := birthdayDate(birthday)
+	switch {
+	case !ok:
+		return birthday
+	case yearKnown:
+		return date.Format("Jan 2, 2006")
+	default:
+		return date.Format("Jan 2")
+	}
+}
+
+// NextBirthday returns the contact's first birthday on or after from's day,
+// and false if no birthday is recorded. Birthdays on February 29 fall on
+// February 28 in other years.
+func (c Contact) NextBirthday(from time.Time) (time.Time, bool) {
+	date, _, ok := birthdayDate(c.Birthday.String)
+	if !c.Birthday.Valid || !ok {
+		return time.Time{}, false
+	}
+	return ImportantDate{Date: date, Recurring: true}.NextOccurrence(from)
+}
+
+// DaysUntilBirthday returns the number of days from from's day to the
+// contact's next birthday, or -1 if no birthday is recorded
+func (c Contact) DaysUntilBirthday(from time.Time) int {
+	next, ok := c.NextBirthday(from)
+	if !ok {
+		return -1
+	}
+	today := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	return int(math.Round(next.Sub(today).Hours() / 24))
+}
+
+// TurningAge returns the age the contact turns on their next birthday, and
+// false if the year they were born is unknown
+func (c Contact) TurningAge(from time.Time) (int, bool) {
+	date, yearKnown, ok := birthdayDate(c.Birthday.String)
+	next, upcoming := c.NextBirthday(from)
+	if !ok || !yearKnown || !upcoming {
+		return 0, false
+	}
+	return next.Year() - date.Year(), true
+}
+
+// upcomingBirthdays returns the birthdays of unarchived contacts falling on
+// or before horizon, as upcoming dates
+func (db *DB) upcomingBirthdays(now, horizon time.Time) ([]UpcomingDate, error) {
+	rows, err := db.pool().Query(`
+		SELECT id, name, birthday
+		FROM contacts
+		WHERE birthday IS NOT NULL AND (archived = 0 OR archived IS NULL) AND trashed_at IS NULL
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("querying birthdays: %w", err)
+	}
+	defer rows.Close()
+
+	var upcoming []UpcomingDate
+	for rows.Next() {
+		var c Contact
+		if err := rows.Scan(&c.ID, &c.Name, &c.Birthday); err != nil {
+			return nil, fmt.Errorf("scanning birthday: %w", err)
+		}
+		next, ok := c.NextBirthday(now)
+		if !ok || next.After(horizon) {
+			continue
+		}
+		date, yearKnown, _ := birthdayDate(c.Birthday.String)
+		if !yearKnown {
+			date = next // No age to count
+		}
+		upcoming = append(upcoming, UpcomingDate{
+			ImportantDate: ImportantDate{ContactID: c.ID, Label: birthdayLabel, Date: date, Recurring: true},
+			ContactName:   c.Name,
+			Next:          next,
+		})
+	}
+	return upcoming, rows.Err()
+}
@@ -0,0 +1,112 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// inTx runs fn in a transaction, committing if it succeeds. Bulk operations
+// use it so a change to many contacts lands all at once or not at all.
+func (db *DB) inTx(fn func(tx *sql.Tx) error) error {
+	tx, err := db.pool().Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+// execEach runs a statement once per contact ID in a transaction
+func (db *DB) execEach(ids []int, query string, args ...any) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return db.inTx(func(tx *sql.Tx) error {
+		stmt, err := tx.Prepare(query)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, id := range ids {
+			if _, err := stmt.Exec(append(args, id)...); err != nil {
+				return fmt.Errorf("contact %d: %w", id, err)
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateContactsState sets the state of several contacts at once
+func (db *DB) UpdateContactsState(ids []int, state string) error {
+	err := db.execEach(ids, `UPDATE contacts SET state = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`, state)
+	if err != nil {
+		return fmt.Errorf("updating contact states: %w", err)
+	}
+	return nil
+}
+
+// ArchiveContacts archives several contacts at once, with the same reason
+func (db *DB) ArchiveContacts(ids []int, reason string) error {
+	err := db.execEach(ids, `
+		UPDATE contacts 
+		SET archived = 1,
+		    archived_at = CURRENT_TIMESTAMP,
+		    archive_reason = ?,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, NewNullString(strings.TrimSpace(reason)))
+	if err != nil {
+		return fmt.Errorf("archiving contacts: %w", err)
+	}
+	return nil
+}
+
+// TagContacts adds a tag to several contacts at once, keeping their other
+// tags
+func (db *DB) TagContacts(ids []int, tag string) error {
+	tags := ParseTags(tag)
+	if len(tags) != 1 {
+		return fmt.Errorf("invalid tag %q", tag)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return db.inTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO tags (name) VALUES (?)`, tags[0]); err != nil {
+			return fmt.Errorf("adding tag %s: %w", tags[0], err)
+		}
+		stmt, err := tx.Prepare(`
+			INSERT OR IGNORE INTO contact_tags (contact_id, tag_id)
+			SELECT ?, id FROM tags WHERE name = ?
+		`)
+		if err != nil {
+			return fmt.Errorf("tagging contacts: %w", err)
+		}
+		defer stmt.Close()
+
+		for _, id := range ids {
+			if _, err := stmt.Exec(id, tags[0]); err != nil {
+				return fmt.Errorf("tagging contact %d with %s: %w", id, tags[0], err)
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteContacts moves several contacts to the trash at once
+func (db *DB) DeleteContacts(ids []int) error {
+	err := db.execEach(ids, `UPDATE contacts SET trashed_at = CURRENT_TIMESTAMP WHERE id = ?`)
+	if err != nil {
+		return fmt.Errorf("moving contacts to trash: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package db
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// ErrNoCipher is returned for an encrypted database when SQLite wasn't built
+// with SQLCipher. Stock SQLite ignores the key, which would leave the
+// database unencrypted.
+var ErrNoCipher = errors.New("this build of contacts-tui can't open encrypted databases; build it with make build-sqlcipher")
+
+// The passphrase of an encrypted database, asked for the first time it is
+// needed
+var (
+	keySource func() (string, error) // nil when the database isn't encrypted
+	key       string
+	keyErr    error
+	keyOnce   sync.Once
+)
+
+// SetKeySource marks the database as encrypted with SQLCipher. source is
+// called for the passphrase the first time the database is opened.
+func SetKeySource(source func() (string, error)) {
+	keySource = source
+}
+
+// passphrase returns the passphrase from the key source, asking only once
+func passphrase() (string, error) {
+	keyOnce.Do(func() {
+		key, keyErr = keySource()
+		if keyErr == nil && key == "" {
+			keyErr = errors.New("empty passphrase")
+		}
+	})
+	return key, keyErr
+}
+
+// unlock gives a new connection the passphrase, and checks that it opens
+// the database
+func unlock(conn *sqlite3.SQLiteConn) error {
+	if keySource == nil {
+		return nil
+	}
+	k, err := passphrase()
+	if err != nil {
+		return fmt.Errorf("reading database passphrase: %w", err)
+	}
+	if _, err := conn.Exec("PRAGMA key = "+quoteLiteral(k), nil); err != nil {
+		return fmt.Errorf("setting database key: %w", err)
+	}
+	if !hasCipher(conn) {
+		return ErrNoCipher
+	}
+	if _, err := conn.Exec("SELECT COUNT(*) FROM sqlite_master", nil); err != nil {
+		return errors.New("wrong passphrase, or the database isn't encrypted (see contacts-tui encrypt)")
+	}
+	return nil
+}
+
+// hasCipher reports whether SQLite was built with SQLCipher
+func hasCipher(conn *sqlite3.SQLiteConn) bool {
+	rows, err := conn.Query("PRAGMA cipher_version", nil)
+	if err != nil {
+		return false
+	}
+	defer rows.Close()
+	values := make([]driver.Value, len(rows.Columns()))
+	return rows.Next(values) != io.EOF
+}
+
+// quoteLiteral quotes a string for use in SQL
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// ConvertEncryption encrypts the database file at path with passphrase, or
+// decrypts it when encrypt is false. The original file is kept next to it
+// with an .unencrypted or .encrypted suffix, whose path is returned; delete
+// it once the converted database has been checked.
+func ConvertEncryption(path, passphrase string, encrypt bool) (string, error) {
+	if passphrase == "" {
+		return "", errors.New("empty passphrase")
+	}
+	// A plain connection, so the key source isn't asked
+	conn, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return "", fmt.Errorf("opening database: %w", err)
+	}
+	defer conn.Close()
+	// The key and the attached database belong to one connection
+	conn.SetMaxOpenConns(1)
+
+	var cipherVersion string
+	if err := conn.QueryRow("PRAGMA cipher_version").Scan(&cipherVersion); err != nil {
+		return "", ErrNoCipher
+	}
+
+	target, targetKey, kept := path+".encrypting", passphrase, path+".unencrypted"
+	if !encrypt {
+		target, targetKey, kept = path+".decrypting", "", path+".encrypted"
+		if _, err := conn.Exec("PRAGMA key = " + quoteLiteral(passphrase)); err != nil {
+			return "", fmt.Errorf("setting database key: %w", err)
+		}
+	}
+	var version int
+	if err := conn.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		if encrypt {
+			return "", fmt.Errorf("reading database: %w", err)
+		}
+		return "", errors.New("wrong passphrase, or the database isn't encrypted")
+	}
+
+	os.Remove(target)
+	if _, err := conn.Exec("ATTACH DATABASE ? AS converted KEY "+quoteLiteral(targetKey), target); err != nil {
+		return "", fmt.Errorf("creating converted database: %w", err)
+	}
+	if _, err := conn.Exec("SELECT sqlcipher_export('converted')"); err != nil {
+		os.Remove(target)
+		return "", fmt.Errorf("converting database: %w", err)
+	}
+	// sqlcipher_export leaves the schema version behind
+	if _, err := conn.Exec(fmt.Sprintf("PRAGMA converted.user_version = %d", version)); err != nil {
+		os.Remove(target)
+		return "", fmt.Errorf("converting database: %w", err)
+	}
+	if _, err := conn.Exec("DETACH DATABASE converted"); err != nil {
+		os.Remove(target)
+		return "", fmt.Errorf("converting database: %w", err)
+	}
+	conn.Close()
+
+	if err := os.Rename(path, kept); err != nil {
+		os.Remove(target)
+		return "", fmt.Errorf("keeping original database: %w", err)
+	}
+	if err := os.Rename(target, path); err != nil {
+		return "", fmt.Errorf("replacing database: %w", err)
+	}
+	return kept, nil
+}
@@ -0,0 +1,52 @@
+package db
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// csvHeader lists the columns written by WriteCSV. The contact fields use
+// the names the CSV importer reads, so an export can be imported again.
+var csvHeader = []string{
+	"name", "email", "phone", "company", "location",
+	"relationship_type", "state", "label", "notes",
+	"last_contacted", "last_bumped", "overdue", "archived",
+}
+
+// WriteCSV writes contacts as CSV with a header row. Dates are written as
+// YYYY-MM-DD and are blank when unset.
+func WriteCSV(w io.Writer, contacts []Contact) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return fmt.Errorf("writing CSV: %w", err)
+	}
+	for _, c := range contacts {
+		record := []string{
+			c.Name, c.Email.String, c.Phone.String, c.Company.String, c.Location.String,
+			c.RelationshipType, c.State.String, c.Label.String, c.Notes.String,
+			csvDate(c.ContactedAt.Time, c.ContactedAt.Valid),
+			csvDate(c.LastBumpDate.Time, c.LastBumpDate.Valid),
+			strconv.FormatBool(c.IsOverdue()),
+			strconv.FormatBool(c.Archived),
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("writing CSV: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("writing CSV: %w", err)
+	}
+	return nil
+}
+
+// csvDate formats a date for a CSV cell
+func csvDate(t time.Time, valid bool) string {
+	if !valid {
+		return ""
+	}
+	return t.Local().Format("2006-01-02")
+}
@@ -0,0 +1,230 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// dateLayout is how important dates are stored
+const dateLayout = "2006-01-02"
+
+// ImportantDate is a date worth remembering for a contact, such as a work
+// anniversary. Recurring dates come around every year; the others happen once.
+type ImportantDate struct {
+	ID        int
+	ContactID int
+	Label     string
+	Date      time.Time
+	Recurring bool
+}
+
+// UpcomingDate is the next occurrence of an important date
+type UpcomingDate struct {
+	ImportantDate
+	ContactName string
+	Next        time.Time
+}
+
+// NextOccurrence returns the first occurrence of the date on or after from's
+// day, and false if a one-off date has already passed. Recurring dates on
+// February 29 fall on February 28 in other years.
+func (d ImportantDate) NextOccurrence(from time.Time) (time.Time, bool) {
+	today := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	if !d.Recurring {
+		date := time.Date(d.Date.Year(), d.Date.Month(), d.Date.Day(), 0, 0, 0, 0, time.Local)
+		return date, !date.Before(today)
+	}
+
+	for year := today.Year(); ; year++ {
+		day := d.Date.Day()
+		if d.Date.Month() == time.February && day == 29 && !isLeapYear(year) {
+			day = 28
+		}
+		next := time.Date(year, d.Date.Month(), day, 0, 0, 0, 0, time.Local)
+		if !next.Before(today) {
+			return next, true
+		}
+	}
+}
+
+// isLeapYear reports whether year has a February 29
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// ListImportantDates returns a contact's important dates, soonest first
+func (db *DB) ListImportantDates(contactID int) ([]ImportantDate, error) {
+	rows, err := db.pool().Query(`
+		SELECT id, contact_id, label, date, recurring
+		FROM important_dates
+		WHERE contact_id = ?
+	`, contactID)
+	if err != nil {
+		return nil, fmt.Errorf("querying important dates: %w", err)
+	}
+	defer rows.Close()
+
+	dates, err := scanImportantDates(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sort.SliceStable(dates, func(i, j int) bool {
+		a, aOK := dates[i].NextOccurrence(now)
+		b, bOK := dates[j].NextOccurrence(now)
+		if aOK != bOK {
+			return aOK // Past one-off dates last
+		}
+		return a.Before(b)
+	})
+	return dates, nil
+}
+
+// Labels of the follow-up and deadline dates UpcomingDates includes
+const (
+	followUpLabel = "Follow-up"
+	deadlineLabel = "Deadline"
+)
+
+// UpcomingDates returns important dates, birthdays, follow-ups and deadlines
+// of unarchived contacts occurring in the given number of days from today,
+// soonest first
+func (db *DB) UpcomingDates(days int) ([]UpcomingDate, error) {
+	rows, err := db.pool().Query(`
+		SELECT d.id, d.contact_id, d.label, d.date, d.recurring, c.name
+		FROM important_dates d
+		JOIN contacts c ON c.id = d.contact_id
+		WHERE (c.archived = 0 OR c.archived IS NULL) AND c.trashed_at IS NULL
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("querying important dates: %w", err)
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	horizon := time.Date(now.Year(), now.Month(), now.Day()+days, 0, 0, 0, 0, time.Local)
+
+	var upcoming []UpcomingDate
+	for rows.Next() {
+		var u UpcomingDate
+		if err := rows.Scan(&u.ID, &u.ContactID, &u.Label, &u.Date, &u.Recurring, &u.ContactName); err != nil {
+			return nil, fmt.Errorf("scanning important date: %w", err)
+		}
+		u.Date = localDate(u.Date)
+		if next, ok := u.NextOccurrence(now); ok && !next.After(horizon) {
+			u.Next = next
+			upcoming = append(upcoming, u)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	birthdays, err := db.upcomingBirthdays(now, horizon)
+	if err != nil {
+		return nil, err
+	}
+	upcoming = append(upcoming, birthdays...)
+
+	followUps, err := db.upcomingFollowUps(now, horizon)
+	if err != nil {
+		return nil, err
+	}
+	upcoming = append(upcoming, followUps...)
+
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		return upcoming[i].Next.Before(upcoming[j].Next)
+	})
+	return upcoming, nil
+}
+
+// upcomingFollowUps returns the follow-up and deadline dates of unarchived
+// contacts falling from today to horizon, as upcoming dates. Ones already
+// past are left to the due follow-ups alert.
+func (db *DB) upcomingFollowUps(now, horizon time.Time) ([]UpcomingDate, error) {
+	rows, err := db.pool().Query(`
+		SELECT id, name, follow_up_date, deadline_date
+		FROM contacts
+		WHERE (follow_up_date IS NOT NULL OR deadline_date IS NOT NULL)
+		  AND (archived = 0 OR archived IS NULL) AND trashed_at IS NULL
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("querying follow-up dates: %w", err)
+	}
+	defer rows.Close()
+
+	var upcoming []UpcomingDate
+	for rows.Next() {
+		var c Contact
+		if err := rows.Scan(&c.ID, &c.Name, &c.FollowUpDate, &c.DeadlineDate); err != nil {
+			return nil, fmt.Errorf("scanning follow-up dates: %w", err)
+		}
+		for _, due := range []struct {
+			label string
+			date  sql.NullTime
+		}{{followUpLabel, c.FollowUpDate}, {deadlineLabel, c.DeadlineDate}} {
+			if !due.date.Valid {
+				continue
+			}
+			d := ImportantDate{ContactID: c.ID, Label: due.label, Date: localDate(due.date.Time)}
+			if next, ok := d.NextOccurrence(now); ok && !next.After(horizon) {
+				upcoming = append(upcoming, UpcomingDate{ImportantDate: d, ContactName: c.Name, Next: next})
+			}
+		}
+	}
+	return upcoming, rows.Err()
+}
+
+// scanImportantDates reads important date rows
+func scanImportantDates(rows *sql.Rows) ([]ImportantDate, error) {
+	var dates []ImportantDate
+	for rows.Next() {
+		var d ImportantDate
+		if err := rows.Scan(&d.ID, &d.ContactID, &d.Label, &d.Date, &d.Recurring); err != nil {
+			return nil, fmt.Errorf("scanning important date: %w", err)
+		}
+		d.Date = localDate(d.Date)
+		dates = append(dates, d)
+	}
+	return dates, rows.Err()
+}
+
+// localDate returns midnight local time on the calendar day of a stored date,
+// which the driver reads back as UTC
+func localDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// AddImportantDate adds an important date to a contact
+func (db *DB) AddImportantDate(contactID int, label string, date time.Time, recurring bool) error {
+	_, err := db.pool().Exec(`
+		INSERT INTO important_dates (contact_id, label, date, recurring)
+		VALUES (?, ?, ?, ?)
+	`, contactID, label, date.Format(dateLayout), recurring)
+	if err != nil {
+		return fmt.Errorf("adding important date: %w", err)
+	}
+	return nil
+}
+
+// UpdateImportantDate changes an important date
+func (db *DB) UpdateImportantDate(id int, label string, date time.Time, recurring bool) error {
+	_, err := db.pool().Exec(`
+		UPDATE important_dates SET label = ?, date = ?, recurring = ? WHERE id = ?
+	`, label, date.Format(dateLayout), recurring, id)
+	if err != nil {
+		return fmt.Errorf("updating important date: %w", err)
+	}
+	return nil
+}
+
+// DeleteImportantDate removes an important date
+func (db *DB) DeleteImportantDate(id int) error {
+	if _, err := db.pool().Exec(`DELETE FROM important_dates WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("deleting important date: %w", err)
+	}
+	return nil
+}
@@ -1,17 +1,89 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 )
 
+// driverName is the SQLite driver databases are opened with, which sets up
+// each connection with prepareConn
+const driverName = "sqlite3_contacts"
+
+// sqliteDriver is the driver registered as driverName
+var sqliteDriver = &sqlite3.SQLiteDriver{ConnectHook: prepareConn}
+
+func init() {
+	sql.Register(driverName, sqliteDriver)
+}
+
+// busyTimeout is how long a connection waits for another one to finish
+// writing before giving up with "database is locked"
+const busyTimeout = 5 * time.Second
+
+// journalMode is the SQLite journal mode databases are opened in
+var journalMode = "wal"
+
+// SetJournalMode sets the journal mode databases are opened in: "wal" lets
+// the TUI, a sync and other commands read while one of them writes, and
+// "delete" keeps every change in the database file itself
+func SetJournalMode(mode string) {
+	switch mode = strings.ToLower(mode); mode {
+	case "wal", "delete", "truncate", "persist":
+		journalMode = mode
+	}
+}
+
+// prepareConn sets up a new connection: unlocking an encrypted database,
+// waiting for other writers instead of failing, enforcing foreign keys and
+// switching to the configured journal mode, and adding the fold function
+// searches compare text with
+func prepareConn(conn *sqlite3.SQLiteConn) error {
+	if _, err := conn.Exec(fmt.Sprintf("PRAGMA busy_timeout = %d", busyTimeout.Milliseconds()), nil); err != nil {
+		return fmt.Errorf("setting busy timeout: %w", err)
+	}
+	if err := unlock(conn); err != nil {
+		return err
+	}
+	if _, err := conn.Exec("PRAGMA foreign_keys = ON", nil); err != nil {
+		return fmt.Errorf("enabling foreign keys: %w", err)
+	}
+	if err := conn.RegisterFunc("fold", fold, true); err != nil {
+		return fmt.Errorf("registering fold: %w", err)
+	}
+	// The mode can't change while another process has the database open,
+	// or on a read-only connection; it then stays as it is
+	conn.Exec("PRAGMA journal_mode = "+journalMode, nil)
+	return nil
+}
+
+// fold lowercases text in SQL the way the TUI does, for all of Unicode
+// rather than SQLite's lower() for ASCII; other values pass through
+func fold(value any) any {
+	if text, ok := value.(string); ok {
+		return strings.ToLower(text)
+	}
+	return value
+}
+
 // DB wraps the database connection
 type DB struct {
-	conn *sql.DB
+	mu          sync.RWMutex // Held by Exclusive, and by connector while opening a connection
+	connecting  atomic.Int32 // Connections waiting in connector for Exclusive to finish
+	conn        *sql.DB
+	path        string
+	deletedDir  string // Where contacts are saved before permanent deletion
+	fts         bool   // Notes are indexed with FTS5; search falls back to LIKE without it
+	defaultType string // Relationship type for contacts imported without one
 }
 
 // Open creates a new database connection
@@ -21,12 +93,8 @@ func Open(dbPath string) (*DB, error) {
 		return nil, fmt.Errorf("database not found at %s\nRun 'contacts-tui -init' to create it", dbPath)
 	}
 	
-	conn, err := sql.Open("sqlite3", dbPath)
-	if err != nil {
-		return nil, fmt.Errorf("opening database: %w", err)
-	}
-	
-	db := &DB{conn: conn}
+	db := &DB{path: dbPath}
+	db.conn = sql.OpenDB(connector{db})
 	
 	// Run any pending migrations
 	if err := db.RunMigrations(); err != nil {
@@ -38,55 +106,134 @@ func Open(dbPath string) (*DB, error) {
 
 // Close closes the database connection
 func (db *DB) Close() error {
-	return db.conn.Close()
-}// ListContacts returns all contacts ordered by name
-func (db *DB) ListContacts() ([]Contact, error) {
-	query := `
-		SELECT 
-			id, name, email, phone, company, 
-			relationship_type, state, notes, label,
-			basic_memory_url, contacted_at, last_bump_date, bump_count,
-			follow_up_date, deadline_date,
-			archived, archived_at,
-			contact_style, custom_frequency_days,
-			created_at, updated_at
-		FROM contacts
-		ORDER BY name
-	`
-	
-	rows, err := db.conn.Query(query)
-	if err != nil {
-		return nil, fmt.Errorf("querying contacts: %w", err)
+	return db.pool().Close()
+}
+
+// pool returns the connection pool. Statements wait for a connection
+// while Exclusive has the file.
+func (db *DB) pool() *sql.DB {
+	return db.conn
+}
+
+// connector opens the connections of a DB's pool, waiting while Exclusive
+// has the file
+type connector struct {
+	db *DB
+}
+
+// Connect opens a connection once no Exclusive is running
+func (c connector) Connect(ctx context.Context) (driver.Conn, error) {
+	c.db.connecting.Add(1)
+	c.db.mu.RLock()
+	defer c.db.mu.RUnlock()
+	c.db.connecting.Add(-1)
+	return sqliteDriver.Open(c.db.path)
+}
+
+// Driver returns the SQLite driver
+func (c connector) Driver() driver.Driver {
+	return sqliteDriver
+}
+
+// Path returns the path of the database file
+func (db *DB) Path() string {
+	return db.path
+}
+
+// Reopen closes and reopens the connection, picking up a database file
+// that was replaced on disk
+func (db *DB) Reopen() error {
+	return db.Exclusive(func() error { return nil })
+}
+
+// Exclusive runs fn with every connection closed, so fn can commit, copy
+// or replace the database file (e.g. a sync pulling a newer copy). Closing
+// the last connection writes the WAL back into the file. Statements
+// started meanwhile wait for fn to finish and then open the file afresh;
+// those already running are given busyTimeout to finish first.
+func (db *DB) Exclusive(fn func() error) error {
+	db.mu.Lock()
+	// Close the idle connections now, and those in use, such as an open
+	// transaction, as they are released
+	db.conn.SetMaxIdleConns(0)
+	deadline := time.Now().Add(busyTimeout)
+	for db.conn.Stats().OpenConnections > int(db.connecting.Load()) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
 	}
-	defer rows.Close()
 	
-	var contacts []Contact
-	for rows.Next() {
-		var c Contact
-		err := rows.Scan(
-			&c.ID, &c.Name, &c.Email, &c.Phone, &c.Company,
-			&c.RelationshipType, &c.State, &c.Notes, &c.Label,
-			&c.BasicMemoryURL, &c.ContactedAt, &c.LastBumpDate, &c.BumpCount,
-			&c.FollowUpDate, &c.DeadlineDate,
-			&c.Archived, &c.ArchivedAt,
-			&c.ContactStyle, &c.CustomFrequencyDays,
-			&c.CreatedAt, &c.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("scanning contact: %w", err)
-		}
-		
-		// Clean up the name field - remove newlines and trim whitespace
-		c.Name = strings.TrimSpace(strings.ReplaceAll(c.Name, "\n", " "))
-		
-		contacts = append(contacts, c)
-	}
+	fnErr := fn()
 	
-	return contacts, rows.Err()
+	db.conn.SetMaxIdleConns(maxIdleConns)
+	db.mu.Unlock()
+	
+	if err := db.RunMigrations(); err != nil {
+		return errors.Join(fnErr, fmt.Errorf("running migrations: %w", err))
+	}
+	return fnErr
+}
+
+// maxIdleConns is how many idle connections the pool keeps, as database/sql
+// does by default
+const maxIdleConns = 2
+
+// contactColumns lists the contact columns read by scanContact, in order
+const contactColumns = `
+	id, name, email, phone, company, location,
+	COALESCE(street, ''), COALESCE(city, ''), COALESCE(region, ''),
+	COALESCE(postal_code, ''), COALESCE(country, ''), birthday, avatar, timezone,
+	relationship_type, state, notes, label,
+	basic_memory_url, contacted_at, last_bump_date, bump_count,
+	follow_up_date, deadline_date,
+	archived, archived_at, archive_reason,
+	contact_style, custom_frequency_days, escalation_level,
+	reminders_muted, starred, waiting_since, waiting_nudged, snooze_until,
+	external_id, synced_at, trashed_at,
+	(SELECT GROUP_CONCAT(t.name, ' ') FROM contact_tags ct JOIN tags t ON t.id = ct.tag_id WHERE ct.contact_id = contacts.id),
+	(SELECT GROUP_CONCAT(g.name, char(31)) FROM group_members gm JOIN contact_groups g ON g.id = gm.group_id WHERE gm.contact_id = contacts.id),
+	(SELECT ci.interaction_type FROM contact_interactions ci WHERE ci.contact_id = contacts.id ORDER BY ci.interaction_date DESC, ci.id DESC LIMIT 1),
+	(SELECT CAST(strftime('%s', ci.interaction_date) AS INTEGER) FROM contact_interactions ci WHERE ci.contact_id = contacts.id ORDER BY ci.interaction_date DESC, ci.id DESC LIMIT 1),
+	created_at, updated_at`
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanContact reads a contact selected with contactColumns
+func scanContact(row rowScanner) (Contact, error) {
+	var c Contact
+	var tags, groups sql.NullString
+	var lastInteractionAt sql.NullInt64
+	err := row.Scan(
+		&c.ID, &c.Name, &c.Email, &c.Phone, &c.Company, &c.Location,
+		&c.Address.Street, &c.Address.City, &c.Address.Region,
+		&c.Address.PostalCode, &c.Address.Country, &c.Birthday, &c.Avatar, &c.Timezone,
+		&c.RelationshipType, &c.State, &c.Notes, &c.Label,
+		&c.BasicMemoryURL, &c.ContactedAt, &c.LastBumpDate, &c.BumpCount,
+		&c.FollowUpDate, &c.DeadlineDate,
+		&c.Archived, &c.ArchivedAt, &c.ArchiveReason,
+		&c.ContactStyle, &c.CustomFrequencyDays, &c.EscalationLevel,
+		&c.RemindersMuted, &c.Starred, &c.WaitingSince, &c.WaitingNudged, &c.SnoozeUntil,
+		&c.ExternalID, &c.SyncedAt, &c.TrashedAt,
+		&tags, &groups,
+		&c.LastInteractionType, &lastInteractionAt,
+		&c.CreatedAt, &c.UpdatedAt,
+	)
+	if lastInteractionAt.Valid {
+		c.LastInteractionAt = sql.NullTime{Time: time.Unix(lastInteractionAt.Int64, 0), Valid: true}
+	}
+	c.Tags = splitTags(tags.String)
+	c.Groups = splitGroups(groups.String)
+	return c, err
+}
+
+// ListContacts returns all contacts not in the trash, ordered by name
+func (db *DB) ListContacts() ([]Contact, error) {
+	return db.FilterContacts(ContactFilter{IncludeArchived: true})
 }
 // MarkContacted marks a contact as contacted with today's date
 func (db *DB) MarkContacted(contactID int, interactionType string, notes string) error {
-	tx, err := db.conn.Begin()
+	tx, err := db.pool().Begin()
 	if err != nil {
 		return fmt.Errorf("starting transaction: %w", err)
 	}
@@ -112,29 +259,26 @@ func (db *DB) MarkContacted(contactID int, interactionType string, notes string)
 
 // GetContact retrieves a single contact by ID
 func (db *DB) GetContact(id int) (*Contact, error) {
-	query := `
-		SELECT 
-			id, name, email, phone, company, 
-			relationship_type, state, notes, label,
-			basic_memory_url, contacted_at, last_bump_date, bump_count,
-			follow_up_date, deadline_date,
-			archived, archived_at,
-			contact_style, custom_frequency_days,
-			created_at, updated_at
-		FROM contacts
-		WHERE id = ?
-	`
+	query := `SELECT ` + contactColumns + ` FROM contacts WHERE id = ?`
 	
-	var c Contact
-	err := db.conn.QueryRow(query, id).Scan(
-		&c.ID, &c.Name, &c.Email, &c.Phone, &c.Company,
-		&c.RelationshipType, &c.State, &c.Notes, &c.Label,
-		&c.BasicMemoryURL, &c.ContactedAt, &c.LastBumpDate, &c.BumpCount,
-		&c.FollowUpDate, &c.DeadlineDate,
-		&c.Archived, &c.ArchivedAt,
-		&c.ContactStyle, &c.CustomFrequencyDays,
-		&c.CreatedAt, &c.UpdatedAt,
-	)
+	c, err := scanContact(db.pool().QueryRow(query, id))
+	if err != nil {
+		return nil, err
+	}
+	
+	return &c, nil
+}
+
+// GetContactByLabel retrieves a single contact by label, with or without
+// the leading @
+func (db *DB) GetContactByLabel(label string) (*Contact, error) {
+	label = strings.TrimSpace(label)
+	if label != "" && !strings.HasPrefix(label, "@") {
+		label = "@" + label
+	}
+	query := `SELECT ` + contactColumns + ` FROM contacts WHERE label = ? COLLATE NOCASE AND trashed_at IS NULL`
+	
+	c, err := scanContact(db.pool().QueryRow(query, label))
 	if err != nil {
 		return nil, err
 	}
@@ -145,7 +289,7 @@ func (db *DB) GetContact(id int) (*Contact, error) {
 // UpdateContactState updates the state of a contact
 func (db *DB) UpdateContactState(contactID int, state string) error {
 	query := `UPDATE contacts SET state = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
-	_, err := db.conn.Exec(query, state, contactID)
+	_, err := db.pool().Exec(query, state, contactID)
 	if err != nil {
 		return fmt.Errorf("updating contact state: %w", err)
 	}
@@ -155,24 +299,38 @@ func (db *DB) UpdateContactState(contactID int, state string) error {
 // UpdateContactLabel updates the label of a contact
 func (db *DB) UpdateContactLabel(contactID int, label string) error {
 	query := `UPDATE contacts SET label = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
-	_, err := db.conn.Exec(query, label, contactID)
+	_, err := db.pool().Exec(query, label, contactID)
 	if err != nil {
-		return fmt.Errorf("updating contact label: %w", err)
+		return fmt.Errorf("updating contact label: %w", db.labelError(err, contactID, label))
 	}
 	return nil
 }
 
-// AddInteractionNote adds a note without updating contacted_at
-func (db *DB) AddInteractionNote(contactID int, interactionType string, notes string) error {
+// UpdateContactCompany updates the company of a contact
+func (db *DB) UpdateContactCompany(contactID int, company string) error {
+	query := `UPDATE contacts SET company = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
+	_, err := db.pool().Exec(query, company, contactID)
+	if err != nil {
+		return fmt.Errorf("updating contact company: %w", err)
+	}
+	return nil
+}
+
+// AddInteractionNote adds a note without updating contacted_at. Rating is
+// the 1-5 energy rating of the interaction, or 0 if unrated; minutes is how
+// long the interaction took, or 0 if not tracked.
+func (db *DB) AddInteractionNote(contactID int, interactionType string, notes string, rating int, minutes int) error {
 	if notes == "" {
 		return fmt.Errorf("notes cannot be empty")
 	}
 	
 	query := `
-		INSERT INTO contact_interactions (contact_id, interaction_date, interaction_type, notes)
-		VALUES (?, CURRENT_TIMESTAMP, ?, ?)
+		INSERT INTO contact_interactions (contact_id, interaction_date, interaction_type, notes, rating, duration_minutes)
+		VALUES (?, CURRENT_TIMESTAMP, ?, ?, ?, ?)
 	`
-	_, err := db.conn.Exec(query, contactID, interactionType, notes)
+	_, err := db.pool().Exec(query, contactID, interactionType, notes,
+		sql.NullInt64{Int64: int64(rating), Valid: rating > 0},
+		sql.NullInt64{Int64: int64(minutes), Valid: minutes > 0})
 	if err != nil {
 		return fmt.Errorf("inserting interaction note: %w", err)
 	}
@@ -180,18 +338,96 @@ func (db *DB) AddInteractionNote(contactID int, interactionType string, notes st
 	return nil
 }
 
+// AddInteractionAt records an interaction that happened at the given time,
+// moving contacted_at forward if it is the most recent contact. It returns
+// false without adding anything if an identical interaction already exists.
+func (db *DB) AddInteractionAt(contactID int, at time.Time, interactionType string, notes string) (bool, error) {
+	// Store dates in the same format as CURRENT_TIMESTAMP so they compare correctly
+	date := at.UTC().Format("2006-01-02 15:04:05")
+	
+	tx, err := db.pool().Begin()
+	if err != nil {
+		return false, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	var exists int
+	err = tx.QueryRow(`
+		SELECT COUNT(*) FROM contact_interactions
+		WHERE contact_id = ? AND interaction_date = ? AND interaction_type = ? AND COALESCE(notes, '') = ?
+	`, contactID, date, interactionType, notes).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("checking for existing interaction: %w", err)
+	}
+	if exists > 0 {
+		return false, nil
+	}
+	
+	logQuery := `
+		INSERT INTO contact_interactions (contact_id, interaction_date, interaction_type, notes)
+		VALUES (?, ?, ?, ?)
+	`
+	if _, err := tx.Exec(logQuery, contactID, date, interactionType, NewNullString(notes)); err != nil {
+		return false, fmt.Errorf("inserting interaction log: %w", err)
+	}
+	
+	updateQuery := `
+		UPDATE contacts SET contacted_at = ?
+		WHERE id = ? AND (contacted_at IS NULL OR contacted_at < ?)
+	`
+	if _, err := tx.Exec(updateQuery, date, contactID, date); err != nil {
+		return false, fmt.Errorf("updating contact: %w", err)
+	}
+	
+	return true, tx.Commit()
+}
+
 // GetContactInteractions retrieves recent interaction logs for a contact
 func (db *DB) GetContactInteractions(contactID int, limit int) ([]Log, error) {
 	query := `
 		SELECT 
-			id, contact_id, interaction_date, interaction_type, notes, created_at
+			id, contact_id, interaction_date, interaction_type, notes, rating, duration_minutes, created_at
 		FROM contact_interactions
 		WHERE contact_id = ?
 		ORDER BY interaction_date DESC
 		LIMIT ?
 	`
 	
-	rows, err := db.conn.Query(query, contactID, limit)
+	rows, err := db.pool().Query(query, contactID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying interactions: %w", err)
+	}
+	defer rows.Close()
+	
+	var logs []Log
+	for rows.Next() {
+		var l Log
+		err := rows.Scan(
+			&l.ID, &l.ContactID, &l.InteractionDate, 
+			&l.InteractionType, &l.Notes, &l.Rating, &l.DurationMinutes, &l.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scanning log: %w", err)
+		}
+		logs = append(logs, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	
+	return logs, db.loadAttachments(logs, contactID)
+}
+
+// ListInteractions retrieves every interaction log, oldest first
+func (db *DB) ListInteractions() ([]Log, error) {
+	query := `
+		SELECT 
+			id, contact_id, interaction_date, interaction_type, notes, rating, duration_minutes, created_at
+		FROM contact_interactions
+		ORDER BY interaction_date
+	`
+	
+	rows, err := db.pool().Query(query)
 	if err != nil {
 		return nil, fmt.Errorf("querying interactions: %w", err)
 	}
@@ -202,17 +438,196 @@ func (db *DB) GetContactInteractions(contactID int, limit int) ([]Log, error) {
 		var l Log
 		err := rows.Scan(
 			&l.ID, &l.ContactID, &l.InteractionDate, 
-			&l.InteractionType, &l.Notes, &l.CreatedAt,
+			&l.InteractionType, &l.Notes, &l.Rating, &l.DurationMinutes, &l.CreatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scanning log: %w", err)
 		}
 		logs = append(logs, l)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 	
+	return logs, db.loadAttachments(logs, 0)
+}
+
+// RecentInteractions retrieves the latest interactions with contacts that
+// aren't in the trash, newest first
+func (db *DB) RecentInteractions(limit int) ([]Log, error) {
+	query := `
+		SELECT
+			i.id, i.contact_id, i.interaction_date, i.interaction_type, i.notes, i.rating, i.duration_minutes, i.created_at
+		FROM contact_interactions i
+		JOIN contacts c ON c.id = i.contact_id
+		WHERE c.trashed_at IS NULL
+		ORDER BY i.interaction_date DESC
+		LIMIT ?
+	`
+
+	rows, err := db.pool().Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying interactions: %w", err)
+	}
+	defer rows.Close()
+
+	var logs []Log
+	for rows.Next() {
+		var l Log
+		err := rows.Scan(
+			&l.ID, &l.ContactID, &l.InteractionDate,
+			&l.InteractionType, &l.Notes, &l.Rating, &l.DurationMinutes, &l.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scanning log: %w", err)
+		}
+		logs = append(logs, l)
+	}
 	return logs, rows.Err()
 }
 
+// CountInteractionsByType returns how many interactions of each type have
+// been logged
+func (db *DB) CountInteractionsByType() (map[string]int, error) {
+	rows, err := db.pool().Query(`SELECT interaction_type, COUNT(*) FROM contact_interactions GROUP BY interaction_type`)
+	if err != nil {
+		return nil, fmt.Errorf("counting interactions: %w", err)
+	}
+	defer rows.Close()
+	
+	counts := make(map[string]int)
+	for rows.Next() {
+		var interactionType string
+		var count int
+		if err := rows.Scan(&interactionType, &count); err != nil {
+			return nil, fmt.Errorf("scanning interaction count: %w", err)
+		}
+		counts[interactionType] = count
+	}
+	return counts, rows.Err()
+}
+
+// RatingSummary aggregates the energy ratings of a contact's interactions
+type RatingSummary struct {
+	Count         int     // Rated interactions
+	Average       float64 // Average rating over all rated interactions
+	RecentCount   int     // Rated interactions in the last 90 days
+	RecentAverage float64 // Average rating in the last 90 days
+}
+
+// GetRatingSummary aggregates the energy ratings of a contact's interactions
+func (db *DB) GetRatingSummary(contactID int) (RatingSummary, error) {
+	var s RatingSummary
+	var avg, recentAvg sql.NullFloat64
+	cutoff := time.Now().AddDate(0, 0, -90).UTC().Format("2006-01-02 15:04:05")
+	err := db.pool().QueryRow(`
+		SELECT
+			COUNT(rating), AVG(rating),
+			COUNT(CASE WHEN interaction_date >= ? THEN rating END),
+			AVG(CASE WHEN interaction_date >= ? THEN rating END)
+		FROM contact_interactions
+		WHERE contact_id = ? AND rating IS NOT NULL
+	`, cutoff, cutoff, contactID).Scan(&s.Count, &avg, &s.RecentCount, &recentAvg)
+	if err != nil {
+		return s, fmt.Errorf("summarizing ratings: %w", err)
+	}
+	s.Average = avg.Float64
+	s.RecentAverage = recentAvg.Float64
+	return s, nil
+}
+
+// DurationSummary totals the time spent in a contact's interactions
+type DurationSummary struct {
+	Count     int // Interactions with a duration
+	Minutes   int // Total minutes
+	MonthMins int // Minutes this calendar month
+}
+
+// GetDurationSummary totals the time spent in a contact's interactions
+func (db *DB) GetDurationSummary(contactID int) (DurationSummary, error) {
+	var s DurationSummary
+	month := time.Now().UTC().Format("2006-01")
+	err := db.pool().QueryRow(`
+		SELECT
+			COUNT(*), COALESCE(SUM(duration_minutes), 0),
+			COALESCE(SUM(CASE WHEN substr(interaction_date, 1, 7) = ? THEN duration_minutes END), 0)
+		FROM contact_interactions
+		WHERE contact_id = ? AND duration_minutes IS NOT NULL
+	`, month, contactID).Scan(&s.Count, &s.Minutes, &s.MonthMins)
+	if err != nil {
+		return s, fmt.Errorf("summarizing durations: %w", err)
+	}
+	return s, nil
+}
+
+// MonthDuration is the time spent with contacts in one month
+type MonthDuration struct {
+	Month        string // YYYY-MM
+	Minutes      int
+	Interactions int
+}
+
+// ContactDuration is the time spent with one contact
+type ContactDuration struct {
+	ContactID    int
+	Name         string
+	Minutes      int
+	Interactions int
+}
+
+// DurationsByMonth totals interaction time per month since a date, oldest
+// month first
+func (db *DB) DurationsByMonth(since time.Time) ([]MonthDuration, error) {
+	rows, err := db.pool().Query(`
+		SELECT substr(interaction_date, 1, 7) AS month, SUM(duration_minutes), COUNT(*)
+		FROM contact_interactions
+		WHERE duration_minutes IS NOT NULL AND interaction_date >= ?
+		GROUP BY month
+		ORDER BY month
+	`, since.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, fmt.Errorf("querying durations by month: %w", err)
+	}
+	defer rows.Close()
+
+	var months []MonthDuration
+	for rows.Next() {
+		var md MonthDuration
+		if err := rows.Scan(&md.Month, &md.Minutes, &md.Interactions); err != nil {
+			return nil, fmt.Errorf("scanning month duration: %w", err)
+		}
+		months = append(months, md)
+	}
+	return months, rows.Err()
+}
+
+// DurationsByContact totals interaction time per contact since a date, most
+// time first
+func (db *DB) DurationsByContact(since time.Time) ([]ContactDuration, error) {
+	rows, err := db.pool().Query(`
+		SELECT c.id, c.name, SUM(i.duration_minutes) AS minutes, COUNT(*)
+		FROM contact_interactions i
+		JOIN contacts c ON c.id = i.contact_id
+		WHERE i.duration_minutes IS NOT NULL AND i.interaction_date >= ?
+		GROUP BY c.id
+		ORDER BY minutes DESC, c.name
+	`, since.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, fmt.Errorf("querying durations by contact: %w", err)
+	}
+	defer rows.Close()
+
+	var contacts []ContactDuration
+	for rows.Next() {
+		var cd ContactDuration
+		if err := rows.Scan(&cd.ContactID, &cd.Name, &cd.Minutes, &cd.Interactions); err != nil {
+			return nil, fmt.Errorf("scanning contact duration: %w", err)
+		}
+		contacts = append(contacts, cd)
+	}
+	return contacts, rows.Err()
+}
+
 // UpdateContact updates all fields of a contact
 func (db *DB) UpdateContact(contact Contact) error {
 	query := `
@@ -221,34 +636,50 @@ func (db *DB) UpdateContact(contact Contact) error {
 		    email = ?, 
 		    phone = ?, 
 		    company = ?, 
+		    location = ?, 
 		    relationship_type = ?, 
 		    notes = ?, 
 		    label = ?,
+		    birthday = ?,
+		    avatar = ?,
+		    timezone = ?,
+		    follow_up_date = ?,
+		    deadline_date = ?,
 		    updated_at = CURRENT_TIMESTAMP
 		WHERE id = ?
 	`
 	
-	_, err := db.conn.Exec(query, 
+	_, err := db.pool().Exec(query, 
 		contact.Name,
 		contact.Email,
 		contact.Phone,
 		contact.Company,
+		contact.Location,
 		contact.RelationshipType,
 		contact.Notes,
 		contact.Label,
+		contact.Birthday,
+		contact.Avatar,
+		contact.Timezone,
+		dateValue(timePtr(contact.FollowUpDate)),
+		dateValue(timePtr(contact.DeadlineDate)),
 		contact.ID,
 	)
 	
 	if err != nil {
-		return fmt.Errorf("updating contact: %w", err)
+		return fmt.Errorf("updating contact: %w", db.labelError(err, contact.ID, contact.Label.String))
 	}
 	
-	return nil
+	// Keep the email and phone lists' primary entries in step with their columns
+	if err := emailList.setPrimary(db.pool(), contact.ID, contact.Email.String); err != nil {
+		return err
+	}
+	return phoneList.setPrimary(db.pool(), contact.ID, contact.Phone.String)
 }
 
 // BumpContact updates the bump date and increments bump count
 func (db *DB) BumpContact(contactID int) error {
-	tx, err := db.conn.Begin()
+	tx, err := db.pool().Begin()
 	if err != nil {
 		return fmt.Errorf("starting transaction: %w", err)
 	}
@@ -278,15 +709,16 @@ func (db *DB) BumpContact(contactID int) error {
 	return tx.Commit()
 }
 // ArchiveContact archives a contact
-func (db *DB) ArchiveContact(contactID int) error {
+func (db *DB) ArchiveContact(contactID int, reason string) error {
 	query := `
 		UPDATE contacts 
 		SET archived = 1,
 		    archived_at = CURRENT_TIMESTAMP,
+		    archive_reason = ?,
 		    updated_at = CURRENT_TIMESTAMP
 		WHERE id = ?
 	`
-	_, err := db.conn.Exec(query, contactID)
+	_, err := db.pool().Exec(query, NewNullString(strings.TrimSpace(reason)), contactID)
 	if err != nil {
 		return fmt.Errorf("archiving contact: %w", err)
 	}
@@ -299,30 +731,107 @@ func (db *DB) UnarchiveContact(contactID int) error {
 		UPDATE contacts 
 		SET archived = 0,
 		    archived_at = NULL,
+		    archive_reason = NULL,
 		    updated_at = CURRENT_TIMESTAMP
 		WHERE id = ?
 	`
-	_, err := db.conn.Exec(query, contactID)
+	_, err := db.pool().Exec(query, contactID)
 	if err != nil {
 		return fmt.Errorf("unarchiving contact: %w", err)
 	}
 	return nil
 }
 
-// DeleteContact permanently deletes a contact and all associated logs
-func (db *DB) DeleteContact(contactID int) error {
-	tx, err := db.conn.Begin()
+// PurgeContact permanently deletes a contact and all associated logs. When a
+// deleted directory is set, the contact is saved there as JSON first and the
+// delete is refused if that fails.
+func (db *DB) PurgeContact(contactID int) error {
+	if db.deletedDir != "" {
+		if _, err := db.saveDeleted(contactID); err != nil {
+			return fmt.Errorf("saving contact before delete: %w", err)
+		}
+	}
+	
+	tx, err := db.pool().Begin()
 	if err != nil {
 		return fmt.Errorf("starting transaction: %w", err)
 	}
 	defer tx.Rollback()
 	
-	// Delete interaction logs first (foreign key constraint)
+	// Delete interaction attachments and logs first (foreign key constraint)
+	_, err = tx.Exec(`
+		DELETE FROM interaction_attachments
+		WHERE interaction_id IN (SELECT id FROM contact_interactions WHERE contact_id = ?)
+	`, contactID)
+	if err != nil {
+		return fmt.Errorf("deleting interaction attachments: %w", err)
+	}
 	_, err = tx.Exec(`DELETE FROM contact_interactions WHERE contact_id = ?`, contactID)
 	if err != nil {
 		return fmt.Errorf("deleting interaction logs: %w", err)
 	}
 	
+	// Delete important dates
+	_, err = tx.Exec(`DELETE FROM important_dates WHERE contact_id = ?`, contactID)
+	if err != nil {
+		return fmt.Errorf("deleting important dates: %w", err)
+	}
+	
+	// Delete email addresses
+	_, err = tx.Exec(`DELETE FROM contact_emails WHERE contact_id = ?`, contactID)
+	if err != nil {
+		return fmt.Errorf("deleting emails: %w", err)
+	}
+	
+	// Delete phone numbers
+	_, err = tx.Exec(`DELETE FROM contact_phones WHERE contact_id = ?`, contactID)
+	if err != nil {
+		return fmt.Errorf("deleting phones: %w", err)
+	}
+	
+	// Delete social media handles
+	_, err = tx.Exec(`DELETE FROM contact_handles WHERE contact_id = ?`, contactID)
+	if err != nil {
+		return fmt.Errorf("deleting handles: %w", err)
+	}
+	
+	// Delete links to and from other contacts
+	_, err = tx.Exec(`DELETE FROM contact_links WHERE contact_id = ? OR other_id = ?`, contactID, contactID)
+	if err != nil {
+		return fmt.Errorf("deleting links: %w", err)
+	}
+	
+	// Delete tags, and any no other contact uses
+	_, err = tx.Exec(`DELETE FROM contact_tags WHERE contact_id = ?`, contactID)
+	if err != nil {
+		return fmt.Errorf("deleting tags: %w", err)
+	}
+	_, err = tx.Exec(`DELETE FROM tags WHERE id NOT IN (SELECT tag_id FROM contact_tags)`)
+	if err != nil {
+		return fmt.Errorf("deleting unused tags: %w", err)
+	}
+	
+	// Take the contact out of its groups
+	_, err = tx.Exec(`DELETE FROM group_members WHERE contact_id = ?`, contactID)
+	if err != nil {
+		return fmt.Errorf("deleting group memberships: %w", err)
+	}
+	
+	// Delete custom field values
+	_, err = tx.Exec(`DELETE FROM contact_field_values WHERE contact_id = ?`, contactID)
+	if err != nil {
+		return fmt.Errorf("deleting field values: %w", err)
+	}
+	
+	// Remember synced contacts so the deletion reaches the server
+	_, err = tx.Exec(`
+		INSERT OR IGNORE INTO sync_tombstones (external_id)
+		SELECT external_id FROM contacts WHERE id = ? AND source = ? AND external_id IS NOT NULL
+	`, contactID, SourceCardDAV)
+	if err != nil {
+		return fmt.Errorf("recording sync tombstone: %w", err)
+	}
+	
 	// Delete the contact
 	_, err = tx.Exec(`DELETE FROM contacts WHERE id = ?`, contactID)
 	if err != nil {
@@ -332,29 +841,153 @@ func (db *DB) DeleteContact(contactID int) error {
 	return tx.Commit()
 }
 
+// SetEscalationLevel records the reminder escalation step a contact has reached
+func (db *DB) SetEscalationLevel(contactID int, level int) error {
+	_, err := db.pool().Exec(`UPDATE contacts SET escalation_level = ? WHERE id = ?`, level, contactID)
+	if err != nil {
+		return fmt.Errorf("updating escalation level: %w", err)
+	}
+	return nil
+}
+
+// SetRemindersMuted sets whether a contact is left out of reminders
+func (db *DB) SetRemindersMuted(contactID int, muted bool) error {
+	_, err := db.pool().Exec(`UPDATE contacts SET reminders_muted = ? WHERE id = ?`, muted, contactID)
+	if err != nil {
+		return fmt.Errorf("updating reminders: %w", err)
+	}
+	return nil
+}
+
+// SetStarred stars or unstars a contact
+func (db *DB) SetStarred(contactID int, starred bool) error {
+	_, err := db.pool().Exec(`UPDATE contacts SET starred = ? WHERE id = ?`, starred, contactID)
+	if err != nil {
+		return fmt.Errorf("updating star: %w", err)
+	}
+	return nil
+}
+
+// SetWaiting marks a contact as owing a reply from now, or clears the mark
+func (db *DB) SetWaiting(contactID int, waiting bool) error {
+	query := `UPDATE contacts SET waiting_since = NULL, waiting_nudged = 0 WHERE id = ?`
+	if waiting {
+		query = `UPDATE contacts SET waiting_since = CURRENT_TIMESTAMP, waiting_nudged = 0 WHERE id = ?`
+	}
+	if _, err := db.pool().Exec(query, contactID); err != nil {
+		return fmt.Errorf("updating waiting on reply: %w", err)
+	}
+	return nil
+}
+
+// SnoozeContact keeps a contact from being overdue until a time, or ends
+// its snooze for the zero time
+func (db *DB) SnoozeContact(contactID int, until time.Time) error {
+	var value interface{}
+	if !until.IsZero() {
+		value = until.UTC().Format(timestampLayout)
+	}
+	if _, err := db.pool().Exec(`UPDATE contacts SET snooze_until = ? WHERE id = ?`, value, contactID); err != nil {
+		return fmt.Errorf("snoozing contact: %w", err)
+	}
+	return nil
+}
+
+// SetWaitingNudged records that a nudge task was created for a contact still
+// waiting on a reply
+func (db *DB) SetWaitingNudged(contactID int) error {
+	_, err := db.pool().Exec(`UPDATE contacts SET waiting_nudged = 1 WHERE id = ?`, contactID)
+	if err != nil {
+		return fmt.Errorf("updating waiting nudge: %w", err)
+	}
+	return nil
+}
+
+// RecordReminder records that a reminder fired for a contact, for throttling
+func (db *DB) RecordReminder(contactID int, kind string) error {
+	_, err := db.pool().Exec(`INSERT INTO reminders (contact_id, kind) VALUES (?, ?)`, contactID, kind)
+	if err != nil {
+		return fmt.Errorf("recording reminder: %w", err)
+	}
+	return nil
+}
+
+// CountRemindersSince returns how many reminders have fired since a time
+func (db *DB) CountRemindersSince(since time.Time) (int, error) {
+	var count int
+	err := db.pool().QueryRow(`SELECT COUNT(*) FROM reminders WHERE sent_at >= ?`,
+		since.UTC().Format("2006-01-02 15:04:05")).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("counting reminders: %w", err)
+	}
+	return count, nil
+}
+
+// ListArchivedBefore returns contacts that were archived before cutoff
+func (db *DB) ListArchivedBefore(cutoff time.Time) ([]Contact, error) {
+	contacts, err := db.ListContacts()
+	if err != nil {
+		return nil, err
+	}
+	
+	var expired []Contact
+	for _, c := range contacts {
+		if c.Archived && c.ArchivedAt.Valid && c.ArchivedAt.Time.Before(cutoff) {
+			expired = append(expired, c)
+		}
+	}
+	return expired, nil
+}
+
+// PurgeArchived permanently deletes the given archived contacts, returning
+// how many were removed
+func (db *DB) PurgeArchived(contacts []Contact) (int, error) {
+	purged := 0
+	for _, c := range contacts {
+		if err := db.PurgeContact(c.ID); err != nil {
+			return purged, fmt.Errorf("purging %s: %w", c.Name, err)
+		}
+		purged++
+	}
+	return purged, nil
+}
+
 // AddContact creates a new contact in the database
 func (db *DB) AddContact(contact Contact) (int64, error) {
 	query := `
 		INSERT INTO contacts (
-			name, email, phone, company, 
-			relationship_type, state, notes, label,
-			created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+			name, email, phone, company, location,
+			street, city, region, postal_code, country,
+			relationship_type, state, notes, label, birthday, avatar, timezone,
+			follow_up_date, deadline_date, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 	`
 	
-	result, err := db.conn.Exec(query,
+	address := contact.Address.trimmed()
+	result, err := db.pool().Exec(query,
 		contact.Name,
 		contact.Email,
 		contact.Phone,
 		contact.Company,
+		contact.Location,
+		NewNullString(address.Street),
+		NewNullString(address.City),
+		NewNullString(address.Region),
+		NewNullString(address.PostalCode),
+		NewNullString(address.Country),
 		contact.RelationshipType,
 		contact.State,
 		contact.Notes,
 		contact.Label,
+		contact.Birthday,
+		contact.Avatar,
+		contact.Timezone,
+		dateValue(timePtr(contact.FollowUpDate)),
+		dateValue(timePtr(contact.DeadlineDate)),
 	)
 	
 	if err != nil {
-		return 0, fmt.Errorf("inserting contact: %w", err)
+		return 0, fmt.Errorf("inserting contact: %w", db.labelError(err, 0, contact.Label.String))
 	}
 	
 	id, err := result.LastInsertId()
@@ -362,6 +995,23 @@ func (db *DB) AddContact(contact Contact) (int64, error) {
 		return 0, fmt.Errorf("getting insert ID: %w", err)
 	}
 	
+	// A contact read from a card brings every address and number, with
+	// their types
+	if len(contact.Emails) > 0 {
+		if _, err := emailList.addMissing(db.pool(), int(id), contact.Emails); err != nil {
+			return id, err
+		}
+	} else if err := emailList.setPrimary(db.pool(), int(id), contact.Email.String); err != nil {
+		return id, err
+	}
+	if len(contact.Phones) > 0 {
+		if _, err := phoneList.addMissing(db.pool(), int(id), contact.Phones); err != nil {
+			return id, err
+		}
+	} else if err := phoneList.setPrimary(db.pool(), int(id), contact.Phone.String); err != nil {
+		return id, err
+	}
+	
 	return id, nil
 }
 
@@ -372,7 +1022,7 @@ func (db *DB) UpdateInteraction(interactionID int, interactionType string, notes
 		SET interaction_type = ?, notes = ?
 		WHERE id = ?
 	`
-	_, err := db.conn.Exec(query, interactionType, notes, interactionID)
+	_, err := db.pool().Exec(query, interactionType, notes, interactionID)
 	if err != nil {
 		return fmt.Errorf("updating interaction: %w", err)
 	}
@@ -381,12 +1031,19 @@ func (db *DB) UpdateInteraction(interactionID int, interactionType string, notes
 
 // DeleteInteraction deletes an interaction by ID
 func (db *DB) DeleteInteraction(interactionID int) error {
-	query := `DELETE FROM contact_interactions WHERE id = ?`
-	_, err := db.conn.Exec(query, interactionID)
+	tx, err := db.pool().Begin()
 	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	if _, err := tx.Exec(`DELETE FROM interaction_attachments WHERE interaction_id = ?`, interactionID); err != nil {
+		return fmt.Errorf("deleting attachments: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM contact_interactions WHERE id = ?`, interactionID); err != nil {
 		return fmt.Errorf("deleting interaction: %w", err)
 	}
-	return nil
+	return tx.Commit()
 }
 
 // UpdateContactStyle updates the contact style and custom frequency
@@ -402,7 +1059,7 @@ func (db *DB) UpdateContactStyle(contactID int, style string, customFrequencyDay
 		args = []interface{}{style, contactID}
 	}
 	
-	_, err := db.conn.Exec(query, args...)
+	_, err := db.pool().Exec(query, args...)
 	if err != nil {
 		return fmt.Errorf("updating contact style: %w", err)
 	}
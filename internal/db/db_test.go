@@ -0,0 +1,114 @@
+package db
+
+import (
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// openFixtures opens a fixtures database in a temporary directory
+func openFixtures(t *testing.T) *DB {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	path := filepath.Join(dir, "contacts.db")
+	if err := CreateFixturesDatabase(path); err != nil {
+		t.Fatalf("creating fixtures: %v", err)
+	}
+	database, err := Open(path)
+	if err != nil {
+		t.Fatalf("opening fixtures: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+// TestExclusiveWhileQuerying runs queries on several goroutines while
+// Exclusive closes the file again and again; they must wait for it rather
+// than fail with "sql: database is closed"
+func TestExclusiveWhileQuerying(t *testing.T) {
+	database := openFixtures(t)
+
+	stop := make(chan struct{})
+	var queries atomic.Int64
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				contacts, err := database.ListContacts()
+				if err == nil && len(contacts) > 0 {
+					_, err = database.GetContactInteractions(contacts[0].ID, 5)
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+				queries.Add(1)
+			}
+		}()
+	}
+
+	for i := 0; i < 10; i++ {
+		err := database.Exclusive(func() error {
+			if open := database.conn.Stats().OpenConnections - int(database.connecting.Load()); open > 0 {
+				t.Errorf("%d connections still open in Exclusive", open)
+			}
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Exclusive: %v", err)
+		}
+	}
+	close(stop)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("query during Exclusive: %v", err)
+	}
+	if queries.Load() == 0 {
+		t.Error("no queries ran alongside Exclusive")
+	}
+}
+
+// TestStatementWaitsForExclusive runs a statement on a pool fetched before
+// Exclusive closed the file, as methods running several statements do
+func TestStatementWaitsForExclusive(t *testing.T) {
+	database := openFixtures(t)
+
+	conn := database.pool()
+	started := make(chan struct{})
+	var finished atomic.Bool
+	done := make(chan error)
+	go func() {
+		done <- database.Exclusive(func() error {
+			close(started)
+			time.Sleep(50 * time.Millisecond)
+			finished.Store(true)
+			return nil
+		})
+	}()
+
+	<-started
+	var count int
+	if err := conn.QueryRow(`SELECT COUNT(*) FROM contacts`).Scan(&count); err != nil {
+		t.Errorf("statement during Exclusive: %v", err)
+	}
+	if !finished.Load() {
+		t.Error("statement ran while Exclusive had the file")
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Exclusive: %v", err)
+	}
+}
@@ -0,0 +1,354 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Kinds of problem Diagnose finds
+const (
+	ProblemDamaged = "damaged" // Reported by PRAGMA integrity_check
+	ProblemOrphan  = "orphan"  // Row pointing at a row that no longer exists
+	ProblemInvalid = "invalid" // Value its column's CHECK constraint doesn't allow
+)
+
+// Problem is something wrong with the database
+type Problem struct {
+	Kind   string
+	Table  string // "" for damage not tied to a table
+	RowID  int64  // 0 when not tied to a row
+	Column string // The invalid column, for ProblemInvalid
+	Detail string
+	reset  any // What Repair sets an invalid value to
+}
+
+// valueCheck finds rows breaking a CHECK constraint, and what to set the
+// column to instead
+type valueCheck struct {
+	table, column string
+	invalid       string // WHERE clause matching the bad rows
+	args          []any
+	reset         any
+}
+
+// Diagnose checks the database for damage, rows left behind by rows they
+// belong to (interactions of deleted contacts and the like) and values the
+// schema's CHECK constraints don't allow, which can get in through older
+// versions, imports or editing the database by hand
+func (db *DB) Diagnose() ([]Problem, error) {
+	checks, err := db.valueChecks()
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []Problem
+	rows, err := db.pool().Query(`PRAGMA integrity_check`)
+	if err != nil {
+		return nil, fmt.Errorf("checking integrity: %w", err)
+	}
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("checking integrity: %w", err)
+		}
+		// CHECK failures in checked tables are reported row by row below
+		if line == "ok" || checkedTable(checks, strings.TrimPrefix(line, "CHECK constraint failed in ")) {
+			continue
+		}
+		problems = append(problems, Problem{Kind: ProblemDamaged, Detail: line})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("checking integrity: %w", err)
+	}
+
+	orphans, err := db.orphans()
+	if err != nil {
+		return nil, err
+	}
+	problems = append(problems, orphans...)
+
+	for _, check := range checks {
+		invalid, err := db.invalidValues(check)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, invalid...)
+	}
+
+	conflicts, err := db.labelConflicts()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range conflicts {
+		// The oldest contact keeps the label
+		for i, id := range c.ids[1:] {
+			problems = append(problems, Problem{
+				Kind:   ProblemInvalid,
+				Table:  "contacts",
+				RowID:  int64(id),
+				Column: "label",
+				Detail: fmt.Sprintf("label %q is also %s's (%s)", c.label, c.names[0], c.names[i+1]),
+			})
+		}
+	}
+	return problems, nil
+}
+
+// checkedTable reports whether Diagnose checks the values of a table itself
+func checkedTable(checks []valueCheck, table string) bool {
+	for _, check := range checks {
+		if check.table == table {
+			return true
+		}
+	}
+	return false
+}
+
+// valueChecks lists the CHECK constraints Diagnose looks for rows breaking.
+// The relationship types and states allowed come from the contacts table's
+// current definition, since the config can change them.
+func (db *DB) valueChecks() ([]valueCheck, error) {
+	var createSQL string
+	if err := db.pool().QueryRow(`SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'contacts'`).Scan(&createSQL); err != nil {
+		return nil, fmt.Errorf("reading contacts table definition: %w", err)
+	}
+
+	var checks []valueCheck
+	for _, column := range []string{"relationship_type", "state"} {
+		allowed := checkTypes(checkConstraint(column).FindString(createSQL))
+		if len(allowed) == 0 {
+			continue
+		}
+		check := valueCheck{table: "contacts", column: column}
+		switch {
+		case column == "relationship_type" && containsType(allowed, "network"):
+			check.reset = "network"
+		case column == "relationship_type":
+			check.reset = allowed[0]
+		case containsType(allowed, "ok"):
+			check.reset = "ok"
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(allowed)), ", ")
+		check.invalid = column + ` NOT IN (` + placeholders + `)`
+		if column == "relationship_type" {
+			check.invalid = column + ` IS NULL OR ` + check.invalid
+		}
+		for _, value := range allowed {
+			check.args = append(check.args, value)
+		}
+		checks = append(checks, check)
+	}
+
+	checks = append(checks,
+		valueCheck{table: "contact_interactions", column: "rating", invalid: `rating NOT BETWEEN 1 AND 5`},
+		valueCheck{table: "contact_interactions", column: "duration_minutes", invalid: `duration_minutes <= 0`},
+	)
+	return checks, nil
+}
+
+// invalidValues finds the rows breaking a CHECK constraint
+func (db *DB) invalidValues(check valueCheck) ([]Problem, error) {
+	rows, err := db.pool().Query(`SELECT rowid, `+check.column+` FROM `+check.table+` WHERE `+check.invalid, check.args...)
+	if err != nil {
+		return nil, fmt.Errorf("checking %s.%s: %w", check.table, check.column, err)
+	}
+	defer rows.Close()
+
+	var problems []Problem
+	for rows.Next() {
+		var id int64
+		var value any
+		if err := rows.Scan(&id, &value); err != nil {
+			return nil, fmt.Errorf("checking %s.%s: %w", check.table, check.column, err)
+		}
+		shown := fmt.Sprint(value)
+		switch v := value.(type) {
+		case nil:
+			shown = "NULL"
+		case string:
+			shown = fmt.Sprintf("%q", v)
+		case []byte:
+			shown = fmt.Sprintf("%q", v)
+		}
+		problems = append(problems, Problem{
+			Kind:   ProblemInvalid,
+			Table:  check.table,
+			RowID:  id,
+			Column: check.column,
+			Detail: fmt.Sprintf("%s %s isn't allowed", check.column, shown),
+			reset:  check.reset,
+		})
+	}
+	return problems, rows.Err()
+}
+
+// orphans finds rows whose foreign keys point at rows that don't exist
+func (db *DB) orphans() ([]Problem, error) {
+	rows, err := db.pool().Query(`PRAGMA foreign_key_check`)
+	if err != nil {
+		return nil, fmt.Errorf("checking foreign keys: %w", err)
+	}
+	type violation struct {
+		table, parent string
+		rowID         sql.NullInt64
+		fkID          int
+	}
+	var violations []violation
+	for rows.Next() {
+		var v violation
+		if err := rows.Scan(&v.table, &v.rowID, &v.parent, &v.fkID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("checking foreign keys: %w", err)
+		}
+		violations = append(violations, v)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("checking foreign keys: %w", err)
+	}
+
+	var problems []Problem
+	for _, v := range violations {
+		var column string
+		if err := db.pool().QueryRow(`SELECT "from" FROM pragma_foreign_key_list(?) WHERE id = ?`, v.table, v.fkID).Scan(&column); err != nil {
+			return nil, fmt.Errorf("reading foreign keys of %s: %w", v.table, err)
+		}
+		var value sql.NullString
+		if err := db.pool().QueryRow(`SELECT `+column+` FROM `+v.table+` WHERE rowid = ?`, v.rowID.Int64).Scan(&value); err != nil {
+			return nil, fmt.Errorf("reading %s row %d: %w", v.table, v.rowID.Int64, err)
+		}
+		problems = append(problems, Problem{
+			Kind:   ProblemOrphan,
+			Table:  v.table,
+			RowID:  v.rowID.Int64,
+			Detail: fmt.Sprintf("%s %s has no %s row", column, value.String, v.parent),
+		})
+	}
+	return problems, nil
+}
+
+// Repair fixes the problems Diagnose found, after backing up the database.
+// Indexes are rebuilt for damage, invalid values are reset to the default
+// (or cleared, as for labels other contacts have), and orphaned rows are quarantined: saved as JSON in the
+// deleted directory, then deleted. It returns the quarantine file, or ""
+// if nothing was quarantined. Damage REINDEX can't fix stays; run Diagnose
+// again to see what is left.
+func (db *DB) Repair(problems []Problem) (string, error) {
+	if len(problems) == 0 {
+		return "", nil
+	}
+	if _, err := db.BackupFile(); err != nil {
+		return "", err
+	}
+
+	quarantine := make(map[string][]map[string]any)
+	for _, p := range problems {
+		if p.Kind != ProblemOrphan {
+			continue
+		}
+		row, err := db.rowValues(p.Table, p.RowID)
+		if err != nil {
+			return "", err
+		}
+		quarantine[p.Table] = append(quarantine[p.Table], row)
+	}
+	var file string
+	if len(quarantine) > 0 {
+		var err error
+		if file, err = db.saveQuarantine(quarantine); err != nil {
+			return "", err
+		}
+	}
+
+	tx, err := db.pool().Begin()
+	if err != nil {
+		return "", fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	reindexed := false
+	for _, p := range problems {
+		switch p.Kind {
+		case ProblemDamaged:
+			if !reindexed {
+				if _, err := tx.Exec(`REINDEX`); err != nil {
+					return "", fmt.Errorf("rebuilding indexes: %w", err)
+				}
+				reindexed = true
+			}
+		case ProblemOrphan:
+			if _, err := tx.Exec(`DELETE FROM `+p.Table+` WHERE rowid = ?`, p.RowID); err != nil {
+				return "", fmt.Errorf("deleting %s row %d: %w", p.Table, p.RowID, err)
+			}
+		case ProblemInvalid:
+			if _, err := tx.Exec(`UPDATE `+p.Table+` SET `+p.Column+` = ? WHERE rowid = ?`, p.reset, p.RowID); err != nil {
+				return "", fmt.Errorf("fixing %s row %d: %w", p.Table, p.RowID, err)
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("committing repairs: %w", err)
+	}
+	return file, nil
+}
+
+// rowValues reads a whole row by column name
+func (db *DB) rowValues(table string, rowID int64) (map[string]any, error) {
+	rows, err := db.pool().Query(`SELECT * FROM `+table+` WHERE rowid = ?`, rowID)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s row %d: %w", table, rowID, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	if !rows.Next() {
+		return nil, fmt.Errorf("%s row %d not found", table, rowID)
+	}
+	if err := rows.Scan(pointers...); err != nil {
+		return nil, fmt.Errorf("reading %s row %d: %w", table, rowID, err)
+	}
+
+	row := make(map[string]any, len(columns))
+	for i, column := range columns {
+		if b, ok := values[i].([]byte); ok {
+			values[i] = string(b)
+		}
+		row[column] = values[i]
+	}
+	return row, nil
+}
+
+// saveQuarantine writes quarantined rows, by table, to the deleted
+// directory
+func (db *DB) saveQuarantine(rows map[string][]map[string]any) (string, error) {
+	if db.deletedDir == "" {
+		return "", fmt.Errorf("no deleted_dir to quarantine rows in; set one under [retention]")
+	}
+	data, err := json.MarshalIndent(rows, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encoding quarantined rows: %w", err)
+	}
+	if err := os.MkdirAll(db.deletedDir, 0755); err != nil {
+		return "", fmt.Errorf("creating deleted directory: %w", err)
+	}
+	path := filepath.Join(db.deletedDir, time.Now().Format("20060102-150405")+"-quarantine.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("writing %s: %w", path, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,195 @@
+package db
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// Duplicate is an existing contact that may be the same person as a new one
+type Duplicate struct {
+	Contact Contact
+	Reason  string // Which field matched: "name", "similar name", "email" or "phone"
+}
+
+// PossibleDuplicates returns the contacts that share a name, email address
+// or phone number with candidate, or have a very similar name. Names are
+// compared ignoring case and spacing, phone numbers by their last ten digits.
+func PossibleDuplicates(contacts []Contact, candidate Contact) []Duplicate {
+	name := normalizeName(candidate.Name)
+	email := strings.ToLower(strings.TrimSpace(candidate.Email.String))
+	phone := phoneKey(candidate.Phone.String)
+
+	var matches []Duplicate
+	for _, c := range contacts {
+		if c.ID == candidate.ID {
+			continue
+		}
+		switch {
+		case name != "" && normalizeName(c.Name) == name:
+			matches = append(matches, Duplicate{Contact: c, Reason: "name"})
+		case email != "" && strings.ToLower(strings.TrimSpace(c.Email.String)) == email:
+			matches = append(matches, Duplicate{Contact: c, Reason: "email"})
+		case phone != "" && phoneKey(c.Phone.String) == phone:
+			matches = append(matches, Duplicate{Contact: c, Reason: "phone"})
+		case similarNames(normalizeName(c.Name), name):
+			matches = append(matches, Duplicate{Contact: c, Reason: "similar name"})
+		}
+	}
+	return matches
+}
+
+// similarNames reports whether two normalized names are probably the same
+// person: a typo or two apart, or the same first and last name with a
+// middle name or initial on one of them
+func similarNames(a, b string) bool {
+	if len(a) < 4 || len(b) < 4 {
+		return false
+	}
+	allowed := 1
+	if len(a) >= 10 && len(b) >= 10 {
+		allowed = 2
+	}
+	if editDistance(a, b) <= allowed {
+		return true
+	}
+
+	wa, wb := strings.Fields(a), strings.Fields(b)
+	if len(wa) < 2 || len(wb) < 2 || len(wa) == len(wb) {
+		return false
+	}
+	return wa[0] == wb[0] && wa[len(wa)-1] == wb[len(wb)-1]
+}
+
+// editDistance returns the number of single character insertions,
+// deletions and substitutions that turn a into b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
+
+// FillBlanks fills empty fields of existing from incoming, and its postal
+// address if it has none, reporting whether anything changed
+func FillBlanks(existing, incoming Contact) (Contact, bool) {
+	changed := false
+	fill := func(dst *sql.NullString, src sql.NullString) {
+		if !dst.Valid && src.Valid {
+			*dst = src
+			changed = true
+		}
+	}
+
+	fill(&existing.Email, incoming.Email)
+	fill(&existing.Phone, incoming.Phone)
+	fill(&existing.Company, incoming.Company)
+	fill(&existing.Location, incoming.Location)
+	fill(&existing.Notes, incoming.Notes)
+	fill(&existing.Label, incoming.Label)
+	fill(&existing.Birthday, incoming.Birthday)
+	fill(&existing.Avatar, incoming.Avatar)
+	fill(&existing.Timezone, incoming.Timezone)
+	if existing.Address.IsEmpty() && !incoming.Address.IsEmpty() {
+		existing.Address = incoming.Address
+		changed = true
+	}
+
+	return existing, changed
+}
+
+// MergeInto adds the details of a contact about to be created to an
+// existing contact instead: blank fields are filled in, notes are added
+// after the existing ones, email addresses or phone numbers not already
+// on the contact are added to its lists, and tags are combined.
+func (db *DB) MergeInto(existing, incoming Contact) error {
+	merged, _ := FillBlanks(existing, incoming)
+	if existing.Notes.Valid && incoming.Notes.Valid && !strings.Contains(existing.Notes.String, incoming.Notes.String) {
+		merged.Notes = NewNullString(existing.Notes.String + "\n\n" + incoming.Notes.String)
+	}
+	if _, err := db.AddValues(existing.ID, incoming); err != nil {
+		return err
+	}
+	if err := db.UpdateContact(merged); err != nil {
+		return err
+	}
+	if merged.Address != existing.Address {
+		if err := db.UpdateAddress(existing.ID, merged.Address); err != nil {
+			return err
+		}
+	}
+
+	if len(incoming.Tags) > 0 {
+		return db.SetContactTags(existing.ID, append(existing.Tags, incoming.Tags...))
+	}
+	return nil
+}
+
+// AddValues adds the email addresses and phone numbers of incoming that a
+// contact doesn't have yet to its lists, with their types, and reports
+// whether any were added. A contact without a list of addresses or numbers
+// brings its primary one.
+func (db *DB) AddValues(contactID int, incoming Contact) (bool, error) {
+	emails := incoming.Emails
+	if len(emails) == 0 && incoming.Email.Valid {
+		emails = []ContactValue{{Value: incoming.Email.String}}
+	}
+	phones := incoming.Phones
+	if len(phones) == 0 && incoming.Phone.Valid {
+		phones = []ContactValue{{Value: incoming.Phone.String}}
+	}
+
+	addedEmails, err := emailList.addMissing(db.pool(), contactID, emails)
+	if err != nil {
+		return addedEmails, err
+	}
+	addedPhones, err := phoneList.addMissing(db.pool(), contactID, phones)
+	return addedEmails || addedPhones, err
+}
+
+// hasValue reports whether a list of email addresses or phone numbers
+// holds a value, comparing phone numbers by their digits
+func hasValue(values []ContactValue, value string, phone bool) bool {
+	for _, v := range values {
+		if strings.EqualFold(v.Value, value) || (phone && phoneKey(value) != "" && phoneKey(v.Value) == phoneKey(value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeName lowercases a name and collapses its whitespace
+func normalizeName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// phoneKey returns the last ten digits of a phone number, or "" when it has
+// too few digits to compare
+func phoneKey(phone string) string {
+	var digits []rune
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits = append(digits, r)
+		}
+	}
+	if len(digits) < 7 {
+		return ""
+	}
+	if len(digits) > 10 {
+		digits = digits[len(digits)-10:]
+	}
+	return string(digits)
+}
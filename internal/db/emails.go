@@ -0,0 +1,53 @@
+package db
+
+import "strings"
+
+// Email address types
+const (
+	EmailPersonal = "personal"
+	EmailWork     = "work"
+)
+
+// EmailTypes lists the email address types in the order they are offered
+var EmailTypes = []string{EmailPersonal, EmailWork}
+
+// emailList is the contact_emails table, whose primary address is kept in
+// the email column of contacts
+var emailList = valueList{
+	table:    "contact_emails",
+	column:   "email",
+	noun:     "email address",
+	types:    EmailTypes,
+	validate: func(email string) bool { return strings.Contains(email, "@") },
+	primary:  true,
+}
+
+// ListEmails returns a contact's email addresses, primary first
+func (db *DB) ListEmails(contactID int) ([]ContactValue, error) {
+	return emailList.list(db.pool(), contactID)
+}
+
+// AddEmail adds an email address after a contact's existing ones
+func (db *DB) AddEmail(contactID int, email, emailType string) error {
+	return emailList.add(db.pool(), contactID, email, emailType)
+}
+
+// UpdateEmail changes an email address and its type
+func (db *DB) UpdateEmail(emailID int, email, emailType string) error {
+	return emailList.update(db.pool(), emailID, email, emailType)
+}
+
+// DeleteEmail removes an email address; the next one becomes primary if
+// the primary address is removed
+func (db *DB) DeleteEmail(emailID int) error {
+	return emailList.remove(db.pool(), emailID)
+}
+
+// MakePrimaryEmail moves an email address to the front of its contact's list
+func (db *DB) MakePrimaryEmail(emailID int) error {
+	contactID, err := emailList.contactOf(db.pool(), emailID)
+	if err != nil {
+		return err
+	}
+	return emailList.makePrimary(db.pool(), contactID, emailID)
+}
@@ -0,0 +1,279 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ContactRecord is the JSON form of a contact and its interaction history
+type ContactRecord struct {
+	ID                  int                 `json:"id"`
+	Name                string              `json:"name"`
+	Email               string              `json:"email,omitempty"`
+	Phone               string              `json:"phone,omitempty"`
+	Company             string              `json:"company,omitempty"`
+	Location            string              `json:"location,omitempty"`
+	Address             *Address            `json:"address,omitempty"`
+	Birthday            string              `json:"birthday,omitempty"` // YYYY-MM-DD, or --MM-DD without a year
+	Avatar              string              `json:"avatar,omitempty"`   // Image file path
+	Timezone            string              `json:"timezone,omitempty"` // IANA time zone
+	Tags                []string            `json:"tags,omitempty"`
+	Groups              []string            `json:"groups,omitempty"`
+	Fields              map[string]string   `json:"fields,omitempty"` // Custom field values by field name
+	RelationshipType    string              `json:"relationship_type"`
+	State               string              `json:"state,omitempty"`
+	Notes               string              `json:"notes,omitempty"`
+	Label               string              `json:"label,omitempty"`
+	BasicMemoryURL      string              `json:"basic_memory_url,omitempty"`
+	ContactedAt         *time.Time          `json:"contacted_at,omitempty"`
+	LastBumpDate        *time.Time          `json:"last_bump_date,omitempty"`
+	BumpCount           int                 `json:"bump_count"`
+	FollowUpDate        *time.Time          `json:"follow_up_date,omitempty"`
+	DeadlineDate        *time.Time          `json:"deadline_date,omitempty"`
+	Archived            bool                `json:"archived"`
+	ArchivedAt          *time.Time          `json:"archived_at,omitempty"`
+	ArchiveReason       string              `json:"archive_reason,omitempty"`
+	ContactStyle        string              `json:"contact_style"`
+	CustomFrequencyDays *int64              `json:"custom_frequency_days,omitempty"`
+	EscalationLevel     int                 `json:"escalation_level,omitempty"`
+	RemindersMuted      bool                `json:"reminders_muted,omitempty"`
+	Starred             bool                `json:"starred,omitempty"`
+	WaitingSince        *time.Time          `json:"waiting_since,omitempty"`
+	WaitingNudged       bool                `json:"waiting_nudged,omitempty"`
+	SnoozeUntil         *time.Time          `json:"snooze_until,omitempty"`
+	ExternalID          string              `json:"external_id,omitempty"`
+	TrashedAt           *time.Time          `json:"trashed_at,omitempty"`
+	CreatedAt           time.Time           `json:"created_at"`
+	UpdatedAt           time.Time           `json:"updated_at"`
+	Interactions        []InteractionRecord `json:"interactions"`
+	ImportantDates      []DateRecord        `json:"important_dates,omitempty"`
+	Emails              []EmailRecord       `json:"emails,omitempty"`
+	Phones              []PhoneRecord       `json:"phones,omitempty"`
+	Handles             []HandleRecord      `json:"handles,omitempty"`
+}
+
+// InteractionRecord is the JSON form of an interaction log entry
+type InteractionRecord struct {
+	Date        time.Time `json:"date"`
+	Type        string    `json:"type"`
+	Notes       string    `json:"notes,omitempty"`
+	Rating      int64     `json:"rating,omitempty"`
+	Minutes     int64     `json:"duration_minutes,omitempty"` // How long the interaction took
+	Attachments []string  `json:"attachments,omitempty"`      // File paths or URLs
+}
+
+// DateRecord is the JSON form of an important date
+type DateRecord struct {
+	Label     string `json:"label"`
+	Date      string `json:"date"` // YYYY-MM-DD
+	Recurring bool   `json:"recurring"`
+}
+
+// EmailRecord is the JSON form of an email address
+type EmailRecord struct {
+	Email string `json:"email"`
+	Type  string `json:"type"`
+}
+
+// NewEmailRecords builds the JSON form of email addresses
+func NewEmailRecords(emails []ContactValue) []EmailRecord {
+	var records []EmailRecord
+	for _, e := range emails {
+		records = append(records, EmailRecord{Email: e.Value, Type: e.Type})
+	}
+	return records
+}
+
+// PhoneRecord is the JSON form of a phone number
+type PhoneRecord struct {
+	Phone string `json:"phone"`
+	Label string `json:"label"`
+}
+
+// NewPhoneRecords builds the JSON form of phone numbers
+func NewPhoneRecords(phones []ContactValue) []PhoneRecord {
+	var records []PhoneRecord
+	for _, p := range phones {
+		records = append(records, PhoneRecord{Phone: p.Value, Label: p.Type})
+	}
+	return records
+}
+
+// HandleRecord is the JSON form of a social media handle
+type HandleRecord struct {
+	Handle   string `json:"handle"`
+	Platform string `json:"platform"`
+}
+
+// NewHandleRecords builds the JSON form of social media handles
+func NewHandleRecords(handles []ContactValue) []HandleRecord {
+	var records []HandleRecord
+	for _, h := range handles {
+		records = append(records, HandleRecord{Handle: h.Value, Platform: h.Type})
+	}
+	return records
+}
+
+// NewDateRecords builds the JSON form of important dates
+func NewDateRecords(dates []ImportantDate) []DateRecord {
+	var records []DateRecord
+	for _, d := range dates {
+		records = append(records, DateRecord{Label: d.Label, Date: d.Date.Format(dateLayout), Recurring: d.Recurring})
+	}
+	return records
+}
+
+// NewContactRecord builds the JSON form of a contact and its interactions
+func NewContactRecord(c Contact, logs []Log) ContactRecord {
+	r := ContactRecord{
+		ID:               c.ID,
+		Name:             c.Name,
+		Email:            c.Email.String,
+		Phone:            c.Phone.String,
+		Company:          c.Company.String,
+		Location:         c.Location.String,
+		Birthday:         c.Birthday.String,
+		Avatar:           c.Avatar.String,
+		Timezone:         c.Timezone.String,
+		Tags:             c.Tags,
+		Groups:           c.Groups,
+		RelationshipType: c.RelationshipType,
+		State:            c.State.String,
+		Notes:            c.Notes.String,
+		Label:            c.Label.String,
+		BasicMemoryURL:   c.BasicMemoryURL.String,
+		ContactedAt:      timePtr(c.ContactedAt),
+		LastBumpDate:     timePtr(c.LastBumpDate),
+		BumpCount:        c.BumpCount,
+		FollowUpDate:     timePtr(c.FollowUpDate),
+		DeadlineDate:     timePtr(c.DeadlineDate),
+		Archived:         c.Archived,
+		ArchivedAt:       timePtr(c.ArchivedAt),
+		ArchiveReason:    c.ArchiveReason.String,
+		ContactStyle:     c.ContactStyle,
+		EscalationLevel:  c.EscalationLevel,
+		RemindersMuted:   c.RemindersMuted,
+		Starred:          c.Starred,
+		WaitingSince:     timePtr(c.WaitingSince),
+		WaitingNudged:    c.WaitingNudged,
+		SnoozeUntil:      timePtr(c.SnoozeUntil),
+		ExternalID:       c.ExternalID.String,
+		TrashedAt:        timePtr(c.TrashedAt),
+		CreatedAt:        c.CreatedAt,
+		UpdatedAt:        c.UpdatedAt,
+		Interactions:     []InteractionRecord{},
+	}
+	if c.CustomFrequencyDays.Valid {
+		days := c.CustomFrequencyDays.Int64
+		r.CustomFrequencyDays = &days
+	}
+	if !c.Address.IsEmpty() {
+		address := c.Address
+		r.Address = &address
+	}
+	for _, l := range logs {
+		r.Interactions = append(r.Interactions, InteractionRecord{
+			Date:        l.InteractionDate,
+			Type:        l.InteractionType,
+			Notes:       l.Notes.String,
+			Rating:      l.Rating.Int64,
+			Minutes:     l.DurationMinutes.Int64,
+			Attachments: l.Attachments,
+		})
+	}
+	return r
+}
+
+// timePtr converts a nullable time for JSON output
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// SetDeletedDir sets the directory where contacts are saved as JSON before
+// they are permanently deleted. An empty dir disables saving.
+func (db *DB) SetDeletedDir(dir string) {
+	db.deletedDir = dir
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// FullContactRecord builds the JSON form of a contact with its full
+// interaction history, important dates, email addresses, phone numbers,
+// handles and custom fields
+func (db *DB) FullContactRecord(contact Contact) (ContactRecord, error) {
+	logs, err := db.GetContactInteractions(contact.ID, -1) // -1: no limit
+	if err != nil {
+		return ContactRecord{}, err
+	}
+
+	dates, err := db.ListImportantDates(contact.ID)
+	if err != nil {
+		return ContactRecord{}, err
+	}
+
+	emails, err := db.ListEmails(contact.ID)
+	if err != nil {
+		return ContactRecord{}, err
+	}
+
+	phones, err := db.ListPhones(contact.ID)
+	if err != nil {
+		return ContactRecord{}, err
+	}
+
+	handles, err := db.ListHandles(contact.ID)
+	if err != nil {
+		return ContactRecord{}, err
+	}
+
+	fields, err := db.FieldValues(contact.ID)
+	if err != nil {
+		return ContactRecord{}, err
+	}
+
+	record := NewContactRecord(contact, logs)
+	record.ImportantDates = NewDateRecords(dates)
+	record.Emails = NewEmailRecords(emails)
+	record.Phones = NewPhoneRecords(phones)
+	record.Handles = NewHandleRecords(handles)
+	if len(fields) > 0 {
+		record.Fields = fields
+	}
+	return record, nil
+}
+
+// saveDeleted writes a contact and its full interaction history to the
+// deleted directory, returning the file written
+func (db *DB) saveDeleted(contactID int) (string, error) {
+	contact, err := db.GetContact(contactID)
+	if err != nil {
+		return "", err
+	}
+	record, err := db.FullContactRecord(*contact)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encoding contact: %w", err)
+	}
+
+	if err := os.MkdirAll(db.deletedDir, 0755); err != nil {
+		return "", fmt.Errorf("creating deleted directory: %w", err)
+	}
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(strings.ToLower(contact.Name), "-"), "-")
+	path := filepath.Join(db.deletedDir, fmt.Sprintf("%s-%d-%s.json", time.Now().Format("20060102-150405"), contact.ID, name))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("writing %s: %w", path, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldValues returns a contact's custom field values keyed by field name.
+// Fields since removed from the config are included.
+func (db *DB) FieldValues(contactID int) (map[string]string, error) {
+	rows, err := db.pool().Query(`
+		SELECT f.name, v.value
+		FROM contact_field_values v
+		JOIN custom_fields f ON f.id = v.field_id
+		WHERE v.contact_id = ?
+	`, contactID)
+	if err != nil {
+		return nil, fmt.Errorf("querying field values: %w", err)
+	}
+	defer rows.Close()
+
+	values := make(map[string]string)
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, fmt.Errorf("scanning field value: %w", err)
+		}
+		values[name] = value
+	}
+	return values, rows.Err()
+}
+
+// SetFieldValues sets custom field values of a contact by field name. A
+// blank value clears the field; fields not in values are left alone.
+func (db *DB) SetFieldValues(contactID int, values map[string]string) error {
+	tx, err := db.pool().Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setFieldValues(tx, contactID, values); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// setFieldValues sets custom field values, creating any fields that are new
+func setFieldValues(conn execer, contactID int, values map[string]string) error {
+	for name, value := range values {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			_, err := conn.Exec(`
+				DELETE FROM contact_field_values
+				WHERE contact_id = ? AND field_id = (SELECT id FROM custom_fields WHERE name = ?)
+			`, contactID, name)
+			if err != nil {
+				return fmt.Errorf("clearing %s: %w", name, err)
+			}
+			continue
+		}
+		if _, err := conn.Exec(`INSERT OR IGNORE INTO custom_fields (name) VALUES (?)`, name); err != nil {
+			return fmt.Errorf("adding field %s: %w", name, err)
+		}
+		_, err := conn.Exec(`
+			INSERT OR REPLACE INTO contact_field_values (contact_id, field_id, value)
+			SELECT ?, id, ? FROM custom_fields WHERE name = ?
+		`, contactID, value, name)
+		if err != nil {
+			return fmt.Errorf("setting %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ContactFilter narrows the contacts FilterContacts returns. Empty fields
+// match every contact; trashed contacts never match.
+type ContactFilter struct {
+	RelationshipType string
+	State            string
+	Location         string // Location or address city, region or country contains this, ignoring case
+	Text             string // A search as typed in the TUI's filter; see ParseSearch
+	IncludeArchived  bool
+	Limit            int // 0 for no limit
+	Offset           int
+}
+
+// likePattern matches text anywhere in a value with LIKE ... ESCAPE '\'
+func likePattern(text string) string {
+	return "%" + likeEscaper.Replace(text) + "%"
+}
+
+// prefixPattern matches values starting with text with LIKE ... ESCAPE '\'
+func prefixPattern(text string) string {
+	return likeEscaper.Replace(text) + "%"
+}
+
+// where builds the filter's WHERE clause. Type and state are compared
+// exactly so their indexes are used. Text is compared after fold, so case
+// is ignored beyond the ASCII letters LIKE ignores it for, as in the TUI.
+func (f ContactFilter) where() (string, []any, error) {
+	conditions := []string{"trashed_at IS NULL"}
+	var args []any
+	if !f.IncludeArchived {
+		conditions = append(conditions, "(archived = 0 OR archived IS NULL)")
+	}
+	if f.RelationshipType != "" {
+		conditions = append(conditions, "relationship_type = ?")
+		args = append(args, f.RelationshipType)
+	}
+	if f.State != "" {
+		conditions = append(conditions, "state = ?")
+		args = append(args, f.State)
+	}
+	search := ParseSearch(f.Text)
+	for _, place := range []string{f.Location, search.Location} {
+		if place == "" {
+			continue
+		}
+		pattern := likePattern(strings.ToLower(place))
+		conditions = append(conditions, `(fold(location) LIKE ? ESCAPE '\' OR fold(city) LIKE ? ESCAPE '\'
+			OR fold(region) LIKE ? ESCAPE '\' OR fold(country) LIKE ? ESCAPE '\')`)
+		args = append(args, pattern, pattern, pattern, pattern)
+	}
+	for _, word := range search.Words {
+		pattern := likePattern(word)
+		conditions = append(conditions, `(fold(name) LIKE ? ESCAPE '\' OR fold(label) LIKE ? ESCAPE '\'
+			OR fold(company) LIKE ? ESCAPE '\' OR fold(location) LIKE ? ESCAPE '\' OR `+tagCondition+`)`)
+		args = append(args, pattern, pattern, pattern, pattern, pattern)
+	}
+	for _, tag := range search.Tags {
+		conditions = append(conditions, tagCondition)
+		args = append(args, prefixPattern(tag))
+	}
+	for _, term := range search.Terms {
+		condition, termArgs, err := termCondition(term)
+		if err != nil {
+			return "", nil, err
+		}
+		if term.Negate {
+			condition = "NOT " + condition
+		}
+		conditions = append(conditions, condition)
+		args = append(args, termArgs...)
+	}
+	return strings.Join(conditions, " AND "), args, nil
+}
+
+// tagCondition matches contacts carrying a tag that matches its pattern
+const tagCondition = `EXISTS (SELECT 1 FROM contact_tags ct JOIN tags t ON t.id = ct.tag_id
+	WHERE ct.contact_id = contacts.id AND fold(t.name) LIKE ? ESCAPE '\')`
+
+// termCondition builds the condition that matches a field:value term the
+// way the TUI's filter does. It is never NULL, so it can be negated.
+func termCondition(term SearchTerm) (string, []any, error) {
+	switch term.Field {
+	case "name", "label", "company", "email", "phone", "notes": // Named after their columns
+		return "fold(COALESCE(" + term.Field + `, '')) LIKE ? ESCAPE '\'`, []any{likePattern(term.Value)}, nil
+	case "state": // Contacts without one are ok
+		return `fold(COALESCE(NULLIF(state, ''), 'ok')) LIKE ? ESCAPE '\'`, []any{prefixPattern(term.Value)}, nil
+	case "type":
+		return `fold(relationship_type) LIKE ? ESCAPE '\'`, []any{prefixPattern(term.Value)}, nil
+	case "style":
+		return `fold(COALESCE(contact_style, '')) LIKE ? ESCAPE '\'`, []any{prefixPattern(term.Value)}, nil
+	case "group":
+		return `EXISTS (SELECT 1 FROM group_members gm JOIN contact_groups g ON g.id = gm.group_id
+			WHERE gm.contact_id = contacts.id AND fold(g.name) LIKE ? ESCAPE '\')`, []any{prefixPattern(term.Value)}, nil
+	case "tag":
+		return tagCondition, []any{prefixPattern(term.Value)}, nil
+	case "starred":
+		return "COALESCE(starred = 1, 0)", nil, nil
+	case "muted":
+		return "COALESCE(reminders_muted = 1, 0)", nil, nil
+	case "waiting":
+		return "waiting_since IS NOT NULL", nil, nil
+	case "snoozed":
+		return "COALESCE(snooze_until > ?, 0)", []any{time.Now().UTC().Format(timestampLayout)}, nil
+	}
+	// Whether a contact is overdue depends on the reminder settings
+	return "", nil, fmt.Errorf("%s: can only be searched for in the TUI", term.Field)
+}
+
+// FilterContacts returns the contacts matching a filter, ordered by name,
+// doing the filtering and paging in the database
+func (db *DB) FilterContacts(f ContactFilter) ([]Contact, error) {
+	where, args, err := f.where()
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + contactColumns + ` FROM contacts WHERE ` + where + ` ORDER BY name`
+	if f.Limit > 0 || f.Offset > 0 {
+		limit := f.Limit
+		if limit <= 0 {
+			limit = -1 // No limit, for an offset alone
+		}
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, f.Offset)
+	}
+
+	rows, err := db.pool().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying contacts: %w", err)
+	}
+	defer rows.Close()
+
+	var contacts []Contact
+	for rows.Next() {
+		c, err := scanContact(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning contact: %w", err)
+		}
+
+		// Clean up the name field - remove newlines and trim whitespace
+		c.Name = strings.TrimSpace(strings.ReplaceAll(c.Name, "\n", " "))
+
+		contacts = append(contacts, c)
+	}
+	return contacts, rows.Err()
+}
+
+// CountContacts returns how many contacts match a filter, ignoring its
+// limit and offset, for paging through them
+func (db *DB) CountContacts(f ContactFilter) (int, error) {
+	where, args, err := f.where()
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	if err := db.pool().QueryRow(`SELECT COUNT(*) FROM contacts WHERE `+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting contacts: %w", err)
+	}
+	return count, nil
+}
@@ -246,7 +246,7 @@ func CreateFixturesDatabase(dbPath string) error {
 		}
 		
 		// Use AddInteractionNote method instead of AddLog
-		if err := database.AddInteractionNote(contactID, log.interactionType, log.notes); err != nil {
+		if err := database.AddInteractionNote(contactID, log.interactionType, log.notes, 0, 0); err != nil {
 			return fmt.Errorf("adding interaction note for %s: %w", log.contactName, err)
 		}
 	}
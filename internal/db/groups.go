@@ -0,0 +1,172 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// groupSeparator separates the group names selected with contactColumns;
+// names may hold spaces and commas
+const groupSeparator = "\x1f"
+
+// Group is a named set of contacts, e.g. "book club"
+type Group struct {
+	ID      int
+	Name    string
+	Members int
+}
+
+// InGroup reports whether a contact belongs to a group, ignoring case
+func (c Contact) InGroup(name string) bool {
+	for _, g := range c.Groups {
+		if strings.EqualFold(g, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitGroups reads the group names selected with contactColumns
+func splitGroups(s string) []string {
+	if s == "" {
+		return nil
+	}
+	groups := strings.Split(s, groupSeparator)
+	sort.Slice(groups, func(i, j int) bool { return strings.ToLower(groups[i]) < strings.ToLower(groups[j]) })
+	return groups
+}
+
+// cleanGroupName trims a group name and rejects empty ones
+func cleanGroupName(name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return "", fmt.Errorf("group name is required")
+	}
+	return name, nil
+}
+
+// ListGroups returns every group with its number of members, by name
+func (db *DB) ListGroups() ([]Group, error) {
+	rows, err := db.pool().Query(`
+		SELECT g.id, g.name, COUNT(c.id)
+		FROM contact_groups g
+		LEFT JOIN group_members gm ON gm.group_id = g.id
+		LEFT JOIN contacts c ON c.id = gm.contact_id AND c.trashed_at IS NULL
+		GROUP BY g.id
+		ORDER BY g.name COLLATE NOCASE
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("querying groups: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []Group
+	for rows.Next() {
+		var g Group
+		if err := rows.Scan(&g.ID, &g.Name, &g.Members); err != nil {
+			return nil, fmt.Errorf("scanning group: %w", err)
+		}
+		groups = append(groups, g)
+	}
+	return groups, rows.Err()
+}
+
+// CreateGroup adds an empty group, returning its ID
+func (db *DB) CreateGroup(name string) (int, error) {
+	name, err := cleanGroupName(name)
+	if err != nil {
+		return 0, err
+	}
+	if db.groupExists(name, 0) {
+		return 0, fmt.Errorf("a group named %q already exists", name)
+	}
+	result, err := db.pool().Exec(`INSERT INTO contact_groups (name) VALUES (?)`, name)
+	if err != nil {
+		return 0, fmt.Errorf("creating group: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("creating group: %w", err)
+	}
+	return int(id), nil
+}
+
+// RenameGroup changes a group's name
+func (db *DB) RenameGroup(groupID int, name string) error {
+	name, err := cleanGroupName(name)
+	if err != nil {
+		return err
+	}
+	if db.groupExists(name, groupID) {
+		return fmt.Errorf("a group named %q already exists", name)
+	}
+	if _, err := db.pool().Exec(`UPDATE contact_groups SET name = ? WHERE id = ?`, name, groupID); err != nil {
+		return fmt.Errorf("renaming group: %w", err)
+	}
+	return nil
+}
+
+// groupExists reports whether a group other than exceptID has a name
+func (db *DB) groupExists(name string, exceptID int) bool {
+	var count int
+	db.pool().QueryRow(`SELECT COUNT(*) FROM contact_groups WHERE name = ? AND id != ?`, name, exceptID).Scan(&count)
+	return count > 0
+}
+
+// DeleteGroup removes a group; its members are kept
+func (db *DB) DeleteGroup(groupID int) error {
+	tx, err := db.pool().Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM group_members WHERE group_id = ?`, groupID); err != nil {
+		return fmt.Errorf("deleting group members: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM contact_groups WHERE id = ?`, groupID); err != nil {
+		return fmt.Errorf("deleting group: %w", err)
+	}
+	return tx.Commit()
+}
+
+// AddToGroup makes a contact a member of a group
+func (db *DB) AddToGroup(groupID, contactID int) error {
+	_, err := db.pool().Exec(`INSERT OR IGNORE INTO group_members (group_id, contact_id) VALUES (?, ?)`, groupID, contactID)
+	if err != nil {
+		return fmt.Errorf("adding to group: %w", err)
+	}
+	return nil
+}
+
+// RemoveFromGroup takes a contact out of a group
+func (db *DB) RemoveFromGroup(groupID, contactID int) error {
+	_, err := db.pool().Exec(`DELETE FROM group_members WHERE group_id = ? AND contact_id = ?`, groupID, contactID)
+	if err != nil {
+		return fmt.Errorf("removing from group: %w", err)
+	}
+	return nil
+}
+
+// setContactGroups adds a contact to groups by name, creating any that are
+// new
+func setContactGroups(conn execer, contactID int, groups []string) error {
+	for _, name := range groups {
+		name, err := cleanGroupName(name)
+		if err != nil {
+			continue
+		}
+		if _, err := conn.Exec(`INSERT OR IGNORE INTO contact_groups (name) VALUES (?)`, name); err != nil {
+			return fmt.Errorf("adding group %s: %w", name, err)
+		}
+		_, err = conn.Exec(`
+			INSERT OR IGNORE INTO group_members (group_id, contact_id)
+			SELECT id, ? FROM contact_groups WHERE name = ?
+		`, contactID, name)
+		if err != nil {
+			return fmt.Errorf("adding to group %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package db
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Social media platforms
+const (
+	HandleMastodon  = "mastodon"
+	HandleBluesky   = "bluesky"
+	HandleLinkedIn  = "linkedin"
+	HandleGitHub    = "github"
+	HandleX         = "x"
+	HandleInstagram = "instagram"
+	HandleThreads   = "threads"
+	HandleWebsite   = "website"
+)
+
+// HandlePlatforms lists the social media platforms in the order they are
+// offered
+var HandlePlatforms = []string{
+	HandleMastodon, HandleBluesky, HandleLinkedIn, HandleGitHub,
+	HandleX, HandleInstagram, HandleThreads, HandleWebsite,
+}
+
+// handleList is the contact_handles table. Handles have no primary entry.
+var handleList = valueList{
+	table:    "contact_handles",
+	column:   "handle",
+	noun:     "handle",
+	types:    HandlePlatforms,
+	validate: func(handle string) bool { return !strings.ContainsAny(handle, " \t\n") },
+}
+
+// ListHandles returns a contact's social media handles
+func (db *DB) ListHandles(contactID int) ([]ContactValue, error) {
+	return handleList.list(db.pool(), contactID)
+}
+
+// AddHandle adds a social media handle after a contact's existing ones
+func (db *DB) AddHandle(contactID int, handle, platform string) error {
+	return handleList.add(db.pool(), contactID, handle, platform)
+}
+
+// UpdateHandle changes a handle and its platform
+func (db *DB) UpdateHandle(handleID int, handle, platform string) error {
+	return handleList.update(db.pool(), handleID, handle, platform)
+}
+
+// DeleteHandle removes a social media handle
+func (db *DB) DeleteHandle(handleID int) error {
+	return handleList.remove(db.pool(), handleID)
+}
+
+// ProfileURL returns the web address of a handle's profile. Handles that
+// are already URLs are returned as they are; Mastodon handles need their
+// server, as in @name@example.social.
+func ProfileURL(platform, handle string) string {
+	handle = strings.TrimSpace(handle)
+	if strings.Contains(handle, "://") {
+		return handle
+	}
+	name := strings.TrimPrefix(handle, "@")
+	switch platform {
+	case HandleMastodon:
+		user, server, ok := strings.Cut(name, "@")
+		if !ok {
+			return ""
+		}
+		return "https://" + server + "/@" + url.PathEscape(user)
+	case HandleBluesky:
+		return "https://bsky.app/profile/" + url.PathEscape(name)
+	case HandleLinkedIn:
+		name = strings.TrimPrefix(name, "in/")
+		return "https://www.linkedin.com/in/" + url.PathEscape(name)
+	case HandleGitHub:
+		return "https://github.com/" + url.PathEscape(name)
+	case HandleX:
+		return "https://x.com/" + url.PathEscape(name)
+	case HandleInstagram:
+		return "https://www.instagram.com/" + url.PathEscape(name)
+	case HandleThreads:
+		return "https://www.threads.net/@" + url.PathEscape(name)
+	case HandleWebsite:
+		return "https://" + handle
+	}
+	return ""
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// historyColumns are the contact columns whose changes are recorded in
+// contact_history. Bookkeeping columns that change on their own, like
+// contacted_at, bump counts and sync state, are left out.
+var historyColumns = []string{
+	"name", "label", "email", "phone", "company", "location", "notes",
+	"relationship_type", "state", "follow_up_date", "deadline_date",
+	"contact_style", "custom_frequency_days", "reminders_muted", "starred", "snooze_until",
+	"archived", "archive_reason", "trashed_at", "basic_memory_url",
+	"street", "city", "region", "postal_code", "country", "birthday", "avatar", "timezone",
+}
+
+// HistoryEntry is one recorded change to a contact field
+type HistoryEntry struct {
+	ID        int
+	ContactID int
+	Field     string // Column name
+	OldValue  sql.NullString
+	NewValue  sql.NullString
+	ChangedAt time.Time
+}
+
+// historyTrigger returns the trigger that records each change to a contact
+// column in historyColumns
+func historyTrigger() string {
+	var inserts []string
+	for _, column := range historyColumns {
+		inserts = append(inserts, fmt.Sprintf(`INSERT INTO contact_history (contact_id, field, old_value, new_value)
+	SELECT NEW.id, '%[1]s', OLD.%[1]s, NEW.%[1]s WHERE OLD.%[1]s IS NOT NEW.%[1]s;`, column))
+	}
+	return "CREATE TRIGGER history_contact_update AFTER UPDATE ON contacts\nBEGIN\n\t" +
+		strings.Join(inserts, "\n\t") + "\nEND"
+}
+
+// ContactHistory returns the recorded changes to a contact, newest first
+func (db *DB) ContactHistory(contactID int) ([]HistoryEntry, error) {
+	rows, err := db.pool().Query(`
+		SELECT id, contact_id, field, old_value, new_value, changed_at
+		FROM contact_history
+		WHERE contact_id = ?
+		ORDER BY changed_at DESC, id DESC
+	`, contactID)
+	if err != nil {
+		return nil, fmt.Errorf("querying contact history: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []HistoryEntry
+	for rows.Next() {
+		var e HistoryEntry
+		if err := rows.Scan(&e.ID, &e.ContactID, &e.Field, &e.OldValue, &e.NewValue, &e.ChangedAt); err != nil {
+			return nil, fmt.Errorf("scanning history entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
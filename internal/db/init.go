@@ -23,7 +23,7 @@ func Initialize(dbPath string) error {
 	}
 	
 	// Create database file
-	db, err := sql.Open("sqlite3", dbPath)
+	db, err := sql.Open(driverName, dbPath)
 	if err != nil {
 		return fmt.Errorf("creating database: %w", err)
 	}
@@ -40,6 +40,15 @@ CREATE TABLE IF NOT EXISTS contacts (
     email TEXT,
     phone TEXT,
     company TEXT,
+    location TEXT,
+    street TEXT,
+    city TEXT,
+    region TEXT,
+    postal_code TEXT,
+    country TEXT,
+    birthday TEXT,
+    avatar TEXT,
+    timezone TEXT,
     notes TEXT,
     relationship_type TEXT CHECK (relationship_type IN ('close', 'family', 'network', 'social', 'providers', 'recruiters', 'work')) NOT NULL DEFAULT 'network',
     contacted_at DATE,
@@ -57,9 +66,26 @@ CREATE TABLE IF NOT EXISTS contacts (
     -- Archive functionality columns
     archived BOOLEAN DEFAULT 0,
     archived_at TIMESTAMP,
+    archive_reason TEXT,
+    -- Trash column; deleted contacts are kept until purged
+    trashed_at TIMESTAMP,
     -- Contact style columns
     contact_style TEXT DEFAULT 'periodic',
-    custom_frequency_days INTEGER
+    custom_frequency_days INTEGER,
+    -- Reminder escalation column
+    escalation_level INTEGER DEFAULT 0,
+    -- Reminder participation column
+    reminders_muted BOOLEAN DEFAULT 0,
+    -- Starred contacts are kept near the top of the list
+    starred BOOLEAN DEFAULT 0,
+    -- Waiting on reply columns
+    waiting_since TIMESTAMP,
+    waiting_nudged BOOLEAN DEFAULT 0,
+    -- Snooze column; hides the contact from the overdue view until then
+    snooze_until TIMESTAMP,
+    -- CardDAV sync columns
+    external_etag TEXT,
+    synced_card TEXT
 );
 
 CREATE TABLE IF NOT EXISTS contact_interactions (
@@ -68,10 +94,138 @@ CREATE TABLE IF NOT EXISTS contact_interactions (
     interaction_type TEXT NOT NULL,
     interaction_date DATE NOT NULL,
     notes TEXT,
+    rating INTEGER CHECK (rating BETWEEN 1 AND 5),
+    duration_minutes INTEGER CHECK (duration_minutes > 0),
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (contact_id) REFERENCES contacts (id) ON DELETE CASCADE
 );
 
+CREATE TABLE IF NOT EXISTS reminders (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    contact_id INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    sent_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (contact_id) REFERENCES contacts (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS important_dates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    contact_id INTEGER NOT NULL,
+    label TEXT NOT NULL,
+    date DATE NOT NULL,
+    recurring BOOLEAN DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (contact_id) REFERENCES contacts (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS contact_emails (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    contact_id INTEGER NOT NULL,
+    email TEXT NOT NULL,
+    type TEXT NOT NULL DEFAULT 'personal',
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (contact_id) REFERENCES contacts (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS contact_phones (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    contact_id INTEGER NOT NULL,
+    phone TEXT NOT NULL,
+    type TEXT NOT NULL DEFAULT 'mobile',
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (contact_id) REFERENCES contacts (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS contact_handles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    contact_id INTEGER NOT NULL,
+    handle TEXT NOT NULL,
+    type TEXT NOT NULL DEFAULT 'mastodon',
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (contact_id) REFERENCES contacts (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS contact_links (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    contact_id INTEGER NOT NULL,
+    other_id INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (contact_id, other_id, kind),
+    FOREIGN KEY (contact_id) REFERENCES contacts (id) ON DELETE CASCADE,
+    FOREIGN KEY (other_id) REFERENCES contacts (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS contact_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    contact_id INTEGER NOT NULL,
+    field TEXT NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    changed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (contact_id) REFERENCES contacts (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS tags (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS contact_tags (
+    contact_id INTEGER NOT NULL,
+    tag_id INTEGER NOT NULL,
+    PRIMARY KEY (contact_id, tag_id),
+    FOREIGN KEY (contact_id) REFERENCES contacts (id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS contact_groups (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS group_members (
+    group_id INTEGER NOT NULL,
+    contact_id INTEGER NOT NULL,
+    PRIMARY KEY (group_id, contact_id),
+    FOREIGN KEY (group_id) REFERENCES contact_groups (id) ON DELETE CASCADE,
+    FOREIGN KEY (contact_id) REFERENCES contacts (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS custom_fields (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS contact_field_values (
+    contact_id INTEGER NOT NULL,
+    field_id INTEGER NOT NULL,
+    value TEXT NOT NULL,
+    PRIMARY KEY (contact_id, field_id),
+    FOREIGN KEY (contact_id) REFERENCES contacts (id) ON DELETE CASCADE,
+    FOREIGN KEY (field_id) REFERENCES custom_fields (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS interaction_attachments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    interaction_id INTEGER NOT NULL,
+    target TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (interaction_id, target),
+    FOREIGN KEY (interaction_id) REFERENCES contact_interactions (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS sync_tombstones (
+    external_id TEXT PRIMARY KEY,
+    deleted_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
 CREATE TABLE IF NOT EXISTS logs (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     content TEXT NOT NULL,
@@ -92,9 +246,20 @@ CREATE INDEX IF NOT EXISTS idx_contacts_relationship_type ON contacts (relations
 CREATE INDEX IF NOT EXISTS idx_contacts_contacted_at ON contacts (contacted_at);
 CREATE INDEX IF NOT EXISTS idx_contacts_state ON contacts (state);
 CREATE INDEX IF NOT EXISTS idx_contacts_label ON contacts (label);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_contacts_label_unique ON contacts (label COLLATE NOCASE) WHERE label IS NOT NULL AND label != '' AND trashed_at IS NULL;
 CREATE INDEX IF NOT EXISTS idx_contacts_relationship_contacted ON contacts(relationship_type, contacted_at);
 CREATE INDEX IF NOT EXISTS idx_contacts_search ON contacts(name, email, company, label);
 CREATE INDEX IF NOT EXISTS idx_interactions_contact_date ON contact_interactions(contact_id, interaction_date DESC);
+CREATE INDEX IF NOT EXISTS idx_reminders_sent_at ON reminders (sent_at);
+CREATE INDEX IF NOT EXISTS idx_important_dates_contact ON important_dates (contact_id);
+CREATE INDEX IF NOT EXISTS idx_contact_emails_contact ON contact_emails (contact_id, position);
+CREATE INDEX IF NOT EXISTS idx_contact_phones_contact ON contact_phones (contact_id, position);
+CREATE INDEX IF NOT EXISTS idx_contact_handles_contact ON contact_handles (contact_id, position);
+CREATE INDEX IF NOT EXISTS idx_contact_links_other ON contact_links (other_id);
+CREATE INDEX IF NOT EXISTS idx_contact_history_contact ON contact_history (contact_id, changed_at);
+CREATE INDEX IF NOT EXISTS idx_contact_tags_tag ON contact_tags (tag_id);
+CREATE INDEX IF NOT EXISTS idx_group_members_contact ON group_members (contact_id);
+CREATE INDEX IF NOT EXISTS idx_interaction_attachments_interaction ON interaction_attachments (interaction_id);
 CREATE INDEX IF NOT EXISTS idx_logs_content ON logs(content);
 CREATE INDEX IF NOT EXISTS idx_log_contacts_contact ON log_contacts(contact_id);
 CREATE INDEX IF NOT EXISTS idx_logs_created_at ON logs(created_at DESC);
@@ -117,5 +282,10 @@ END;`
 		return fmt.Errorf("creating schema: %w", err)
 	}
 	
+	// A new database has nothing to back up before migrating
+	if _, err := db.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, schemaVersion)); err != nil {
+		return fmt.Errorf("recording schema version: %w", err)
+	}
+	
 	return nil
 }
@@ -0,0 +1,159 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogEntry is a journal entry, kept in the logs table, and the contacts it
+// mentions
+type LogEntry struct {
+	ID         int
+	Content    string
+	ContactIDs []int
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// mentionPattern matches @-mentions of contact labels, but not the middle
+// of an email address
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])(@[A-Za-z0-9][\w.-]*)`)
+
+// Mentions returns the @labels mentioned in a journal entry, in order and
+// without repeats
+func Mentions(content string) []string {
+	var labels []string
+	seen := make(map[string]bool)
+	for _, m := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		label := strings.TrimRight(m[1], ".-")
+		if key := strings.ToLower(label); !seen[key] {
+			seen[key] = true
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// ResolveMentions finds the contacts a journal entry mentions by label. It
+// returns their IDs and the mentions that match no contact.
+func (db *DB) ResolveMentions(content string) ([]int, []string, error) {
+	var ids []int
+	var unknown []string
+	for _, label := range Mentions(content) {
+		c, err := db.GetContactByLabel(label)
+		if err == sql.ErrNoRows {
+			unknown = append(unknown, label)
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("looking up %s: %w", label, err)
+		}
+		ids = append(ids, c.ID)
+	}
+	return ids, unknown, nil
+}
+
+// SaveLog adds a journal entry (id 0) or replaces one's content, linking it
+// to the given contacts. It returns the entry's ID.
+func (db *DB) SaveLog(id int, content string, contactIDs []int) (int, error) {
+	tx, err := db.pool().Begin()
+	if err != nil {
+		return 0, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if id == 0 {
+		result, err := tx.Exec(`INSERT INTO logs (content) VALUES (?)`, content)
+		if err != nil {
+			return 0, fmt.Errorf("adding journal entry: %w", err)
+		}
+		newID, err := result.LastInsertId()
+		if err != nil {
+			return 0, err
+		}
+		id = int(newID)
+	} else {
+		if _, err := tx.Exec(`UPDATE logs SET content = ? WHERE id = ?`, content, id); err != nil {
+			return 0, fmt.Errorf("updating journal entry: %w", err)
+		}
+		if _, err := tx.Exec(`DELETE FROM log_contacts WHERE log_id = ?`, id); err != nil {
+			return 0, fmt.Errorf("unlinking journal entry: %w", err)
+		}
+	}
+
+	for _, contactID := range contactIDs {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO log_contacts (log_id, contact_id) VALUES (?, ?)`, id, contactID); err != nil {
+			return 0, fmt.Errorf("linking journal entry: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("committing journal entry: %w", err)
+	}
+	return id, nil
+}
+
+// DeleteLog removes a journal entry
+func (db *DB) DeleteLog(id int) error {
+	if _, err := db.pool().Exec(`DELETE FROM logs WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("deleting journal entry: %w", err)
+	}
+	return nil
+}
+
+// ListLogs returns the newest journal entries, or all of them for a limit
+// of -1
+func (db *DB) ListLogs(limit int) ([]LogEntry, error) {
+	return db.queryLogs(`
+		SELECT l.id, l.content, l.created_at, l.updated_at, GROUP_CONCAT(lc.contact_id)
+		FROM logs l
+		LEFT JOIN log_contacts lc ON lc.log_id = l.id
+		GROUP BY l.id
+		ORDER BY l.created_at DESC, l.id DESC
+		LIMIT ?
+	`, limit)
+}
+
+// ContactLogs returns the newest journal entries mentioning a contact, or
+// all of them for a limit of -1
+func (db *DB) ContactLogs(contactID, limit int) ([]LogEntry, error) {
+	return db.queryLogs(`
+		SELECT l.id, l.content, l.created_at, l.updated_at, GROUP_CONCAT(lc.contact_id)
+		FROM logs l
+		LEFT JOIN log_contacts lc ON lc.log_id = l.id
+		WHERE l.id IN (SELECT log_id FROM log_contacts WHERE contact_id = ?)
+		GROUP BY l.id
+		ORDER BY l.created_at DESC, l.id DESC
+		LIMIT ?
+	`, contactID, limit)
+}
+
+// queryLogs reads journal entries with their contact IDs
+func (db *DB) queryLogs(query string, args ...any) ([]LogEntry, error) {
+	rows, err := db.pool().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying journal: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []LogEntry
+	for rows.Next() {
+		var e LogEntry
+		var contactIDs sql.NullString
+		if err := rows.Scan(&e.ID, &e.Content, &e.CreatedAt, &e.UpdatedAt, &contactIDs); err != nil {
+			return nil, fmt.Errorf("scanning journal entry: %w", err)
+		}
+		if contactIDs.Valid {
+			for _, s := range strings.Split(contactIDs.String, ",") {
+				if id, err := strconv.Atoi(s); err == nil {
+					e.ContactIDs = append(e.ContactIDs, id)
+				}
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,89 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// ErrLabelTaken is returned when a contact is given a label another contact
+// not in the trash already has. Labels are compared ignoring case.
+var ErrLabelTaken = errors.New("label already in use")
+
+// NormalizeLabel lowercases a label and makes sure it starts with @, for
+// matching labels as written in files against contacts' labels
+func NormalizeLabel(label string) string {
+	label = strings.ToLower(strings.TrimSpace(label))
+	if label != "" && !strings.HasPrefix(label, "@") {
+		label = "@" + label
+	}
+	return label
+}
+
+// labelIndexSQL makes labels unique among contacts not in the trash
+const labelIndexSQL = `CREATE UNIQUE INDEX IF NOT EXISTS idx_contacts_label_unique ON contacts (label COLLATE NOCASE)
+	WHERE label IS NOT NULL AND label != '' AND trashed_at IS NULL`
+
+// labelError turns a write rejected by the unique label index into
+// ErrLabelTaken, naming the contact that has the label. Other errors are
+// returned as they are.
+func (db *DB) labelError(err error, contactID int, label string) error {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) || sqliteErr.ExtendedCode != sqlite3.ErrConstraintUnique ||
+		!strings.Contains(sqliteErr.Error(), "contacts.label") {
+		return err
+	}
+	var name string
+	if db.pool().QueryRow(`
+		SELECT name FROM contacts
+		WHERE label = ? COLLATE NOCASE AND id != ? AND trashed_at IS NULL
+	`, label, contactID).Scan(&name) != nil {
+		return fmt.Errorf("%w: %s", ErrLabelTaken, label)
+	}
+	return fmt.Errorf("%w: %s is %s's label", ErrLabelTaken, label, name)
+}
+
+// labelConflict is a label several contacts not in the trash share
+type labelConflict struct {
+	label string
+	ids   []int
+	names []string
+}
+
+// labelConflicts finds labels shared by several contacts not in the trash,
+// which keep the unique label index from being created. Contacts are listed
+// oldest first.
+func (db *DB) labelConflicts() ([]labelConflict, error) {
+	rows, err := db.pool().Query(`
+		SELECT id, name, label FROM contacts
+		WHERE label IS NOT NULL AND label != '' AND trashed_at IS NULL
+		  AND label COLLATE NOCASE IN (
+			SELECT label FROM contacts
+			WHERE label IS NOT NULL AND label != '' AND trashed_at IS NULL
+			GROUP BY label COLLATE NOCASE HAVING COUNT(*) > 1
+		  )
+		ORDER BY LOWER(label), id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("checking for shared labels: %w", err)
+	}
+	defer rows.Close()
+
+	var conflicts []labelConflict
+	for rows.Next() {
+		var id int
+		var name, label string
+		if err := rows.Scan(&id, &name, &label); err != nil {
+			return nil, fmt.Errorf("checking for shared labels: %w", err)
+		}
+		if n := len(conflicts); n == 0 || !strings.EqualFold(conflicts[n-1].label, label) {
+			conflicts = append(conflicts, labelConflict{label: label})
+		}
+		c := &conflicts[len(conflicts)-1]
+		c.ids = append(c.ids, id)
+		c.names = append(c.names, name)
+	}
+	return conflicts, rows.Err()
+}
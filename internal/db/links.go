@@ -0,0 +1,121 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LinkKind is a way two contacts can be related, described from each side
+type LinkKind struct {
+	Name    string // Stored in contact_links, e.g. "reports-to"
+	Label   string // From the contact that links, e.g. "reports to"
+	Inverse string // From the contact linked to, e.g. "manages"
+}
+
+// LinkKinds lists the kinds of links in the order they are offered
+var LinkKinds = []LinkKind{
+	{Name: "spouse", Label: "spouse of", Inverse: "spouse of"},
+	{Name: "partner", Label: "partner of", Inverse: "partner of"},
+	{Name: "reports-to", Label: "reports to", Inverse: "manages"},
+	{Name: "introduced-by", Label: "introduced by", Inverse: "introduced"},
+	{Name: "parent", Label: "parent of", Inverse: "child of"},
+	{Name: "sibling", Label: "sibling of", Inverse: "sibling of"},
+	{Name: "colleague", Label: "colleague of", Inverse: "colleague of"},
+	{Name: "friend", Label: "friend of", Inverse: "friend of"},
+}
+
+// findLinkKind looks up a kind of link by name
+func findLinkKind(name string) (LinkKind, bool) {
+	for _, k := range LinkKinds {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	return LinkKind{}, false
+}
+
+// ContactLink is a link between two contacts, seen from one of them
+type ContactLink struct {
+	ID        int
+	OtherID   int
+	OtherName string
+	Kind      string // Name of the LinkKind
+	Label     string // How the contact is related to the other, e.g. "reports to"
+}
+
+// AddLink links two contacts: contactID is the kind's Label of otherID, as
+// in "contactID reports to otherID". Adding a link twice does nothing.
+func (db *DB) AddLink(contactID, otherID int, kind string) error {
+	if _, ok := findLinkKind(kind); !ok {
+		names := make([]string, len(LinkKinds))
+		for i, k := range LinkKinds {
+			names[i] = k.Name
+		}
+		return fmt.Errorf("unknown link kind %q (use %s)", kind, strings.Join(names, ", "))
+	}
+	if contactID == otherID {
+		return fmt.Errorf("a contact can't be linked to itself")
+	}
+	_, err := db.pool().Exec(`INSERT OR IGNORE INTO contact_links (contact_id, other_id, kind) VALUES (?, ?, ?)`, contactID, otherID, kind)
+	if err != nil {
+		return fmt.Errorf("adding link: %w", err)
+	}
+	return nil
+}
+
+// DeleteLink removes a link between two contacts
+func (db *DB) DeleteLink(linkID int) error {
+	if _, err := db.pool().Exec(`DELETE FROM contact_links WHERE id = ?`, linkID); err != nil {
+		return fmt.Errorf("deleting link: %w", err)
+	}
+	return nil
+}
+
+// ListLinks returns the links of a contact in both directions, labeled from
+// its side, leaving out contacts in the trash
+func (db *DB) ListLinks(contactID int) ([]ContactLink, error) {
+	rows, err := db.pool().Query(`
+		SELECT l.id, c.id, c.name, l.kind, 1
+		FROM contact_links l
+		JOIN contacts c ON c.id = l.other_id
+		WHERE l.contact_id = ? AND c.trashed_at IS NULL
+		UNION ALL
+		SELECT l.id, c.id, c.name, l.kind, 0
+		FROM contact_links l
+		JOIN contacts c ON c.id = l.contact_id
+		WHERE l.other_id = ? AND c.trashed_at IS NULL
+	`, contactID, contactID)
+	if err != nil {
+		return nil, fmt.Errorf("querying links: %w", err)
+	}
+	defer rows.Close()
+
+	var links []ContactLink
+	for rows.Next() {
+		var l ContactLink
+		var outgoing bool
+		if err := rows.Scan(&l.ID, &l.OtherID, &l.OtherName, &l.Kind, &outgoing); err != nil {
+			return nil, fmt.Errorf("scanning link: %w", err)
+		}
+		l.Label = l.Kind
+		if kind, ok := findLinkKind(l.Kind); ok {
+			l.Label = kind.Inverse
+			if outgoing {
+				l.Label = kind.Label
+			}
+		}
+		links = append(links, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(links, func(i, j int) bool {
+		if links[i].Label != links[j].Label {
+			return links[i].Label < links[j].Label
+		}
+		return links[i].OtherName < links[j].OtherName
+	})
+	return links, nil
+}
@@ -1,12 +1,25 @@
 package db
 
 import (
+	"database/sql"
 	"fmt"
 	"log"
+	"strings"
 )
 
 // RunMigrations applies any pending database migrations
 func (db *DB) RunMigrations() error {
+	// Back up the database before a newer schema changes it
+	outdated, err := db.schemaOutdated()
+	if err != nil {
+		return err
+	}
+	if outdated {
+		if _, err := db.BackupFile(); err != nil {
+			return fmt.Errorf("backing up before migrating: %w", err)
+		}
+	}
+	
 	// Run bump columns migration
 	if err := db.runBumpMigration(); err != nil {
 		return err
@@ -22,13 +35,154 @@ func (db *DB) RunMigrations() error {
 		return err
 	}
 	
-	return nil
+	// Run escalation level migration
+	if err := db.runEscalationMigration(); err != nil {
+		return err
+	}
+	
+	// Run reminders table migration
+	if err := db.runRemindersMigration(); err != nil {
+		return err
+	}
+	
+	// Run reminders muted migration
+	if err := db.runRemindersMutedMigration(); err != nil {
+		return err
+	}
+	
+	// Run important dates table migration
+	if err := db.runImportantDatesMigration(); err != nil {
+		return err
+	}
+	
+	// Run interaction rating migration
+	if err := db.runInteractionRatingMigration(); err != nil {
+		return err
+	}
+	
+	// Run waiting on reply migration
+	if err := db.runWaitingMigration(); err != nil {
+		return err
+	}
+	
+	// Run archive reason migration
+	if err := db.runArchiveReasonMigration(); err != nil {
+		return err
+	}
+	
+	// Run interaction duration migration
+	if err := db.runInteractionDurationMigration(); err != nil {
+		return err
+	}
+	
+	// Run location migration
+	if err := db.runLocationMigration(); err != nil {
+		return err
+	}
+	
+	// Run sync state migration
+	if err := db.runSyncMigration(); err != nil {
+		return err
+	}
+	
+	// Run contact emails migration
+	if err := db.runContactEmailsMigration(); err != nil {
+		return err
+	}
+	
+	// Run contact phones migration
+	if err := db.runContactPhonesMigration(); err != nil {
+		return err
+	}
+	
+	// Run postal address migration
+	if err := db.runAddressMigration(); err != nil {
+		return err
+	}
+	
+	// Run birthday migration
+	if err := db.runBirthdayMigration(); err != nil {
+		return err
+	}
+	
+	// Run tags migration
+	if err := db.runTagsMigration(); err != nil {
+		return err
+	}
+	
+	// Run groups migration
+	if err := db.runGroupsMigration(); err != nil {
+		return err
+	}
+	
+	// Run custom fields migration
+	if err := db.runCustomFieldsMigration(); err != nil {
+		return err
+	}
+	
+	// Run trash migration
+	if err := db.runTrashMigration(); err != nil {
+		return err
+	}
+	
+	// Run avatar migration
+	if err := db.runAvatarMigration(); err != nil {
+		return err
+	}
+	
+	// Run social media handles migration
+	if err := db.runHandlesMigration(); err != nil {
+		return err
+	}
+	
+	// Run contact links migration
+	if err := db.runLinksMigration(); err != nil {
+		return err
+	}
+	
+	// Run interaction attachments migration
+	if err := db.runAttachmentsMigration(); err != nil {
+		return err
+	}
+	
+	// Run full-text search migration
+	if err := db.runSearchMigration(); err != nil {
+		return err
+	}
+	
+	// Run snooze migration
+	if err := db.runSnoozeMigration(); err != nil {
+		return err
+	}
+	
+	// Run time zone migration
+	if err := db.runTimezoneMigration(); err != nil {
+		return err
+	}
+	
+	// Run starred migration
+	if err := db.runStarredMigration(); err != nil {
+		return err
+	}
+	
+	// Run unique label migration
+	if err := db.runLabelUniqueMigration(); err != nil {
+		return err
+	}
+	
+	// Run contact history migration. It stays last, since its trigger
+	// names every column it records.
+	if err := db.runHistoryMigration(); err != nil {
+		return err
+	}
+	
+	return db.markMigrated()
 }
 
 func (db *DB) runBumpMigration() error {
 	// Check if bump columns exist
 	var count int
-	err := db.conn.QueryRow(`
+	err := db.pool().QueryRow(`
 		SELECT COUNT(*) 
 		FROM pragma_table_info('contacts') 
 		WHERE name IN ('last_bump_date', 'bump_count')
@@ -42,7 +196,7 @@ func (db *DB) runBumpMigration() error {
 	if count < 2 {
 		log.Println("Running migration: Adding bump functionality columns...")
 		
-		tx, err := db.conn.Begin()
+		tx, err := db.pool().Begin()
 		if err != nil {
 			return fmt.Errorf("starting transaction: %w", err)
 		}
@@ -73,7 +227,7 @@ func (db *DB) runBumpMigration() error {
 func (db *DB) runArchiveMigration() error {
 	// Check if archive columns exist
 	var count int
-	err := db.conn.QueryRow(`
+	err := db.pool().QueryRow(`
 		SELECT COUNT(*) 
 		FROM pragma_table_info('contacts') 
 		WHERE name IN ('archived', 'archived_at')
@@ -87,7 +241,7 @@ func (db *DB) runArchiveMigration() error {
 	if count < 2 {
 		log.Println("Running migration: Adding archive functionality columns...")
 		
-		tx, err := db.conn.Begin()
+		tx, err := db.pool().Begin()
 		if err != nil {
 			return fmt.Errorf("starting transaction: %w", err)
 		}
@@ -118,7 +272,7 @@ func (db *DB) runArchiveMigration() error {
 func (db *DB) runContactStyleMigration() error {
 	// Check if contact style columns exist
 	var count int
-	err := db.conn.QueryRow(`
+	err := db.pool().QueryRow(`
 		SELECT COUNT(*) 
 		FROM pragma_table_info('contacts') 
 		WHERE name IN ('contact_style', 'custom_frequency_days')
@@ -132,7 +286,7 @@ func (db *DB) runContactStyleMigration() error {
 	if count < 2 {
 		log.Println("Running migration: Adding contact style columns...")
 		
-		tx, err := db.conn.Begin()
+		tx, err := db.pool().Begin()
 		if err != nil {
 			return fmt.Errorf("starting transaction: %w", err)
 		}
@@ -158,4 +312,1395 @@ func (db *DB) runContactStyleMigration() error {
 	}
 	
 	return nil
-}
\ No newline at end of file
+}
+
+func (db *DB) runEscalationMigration() error {
+	// Check if escalation column exists
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM pragma_table_info('contacts') 
+		WHERE name = 'escalation_level'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for escalation column: %w", err)
+	}
+	
+	// If column doesn't exist, add it
+	if count < 1 {
+		log.Println("Running migration: Adding escalation level column...")
+		
+		tx, err := db.pool().Begin()
+		if err != nil {
+			return fmt.Errorf("starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+		
+		_, err = tx.Exec(`ALTER TABLE contacts ADD COLUMN escalation_level INTEGER DEFAULT 0`)
+		if err != nil && err.Error() != "duplicate column name: escalation_level" {
+			return fmt.Errorf("adding escalation_level column: %w", err)
+		}
+		
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing escalation migration: %w", err)
+		}
+		
+		log.Println("Escalation migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runRemindersMigration() error {
+	// Check if reminders table exists
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM sqlite_master 
+		WHERE type = 'table' AND name = 'reminders'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for reminders table: %w", err)
+	}
+	
+	// If table doesn't exist, create it
+	if count < 1 {
+		log.Println("Running migration: Adding reminders table...")
+		
+		tx, err := db.pool().Begin()
+		if err != nil {
+			return fmt.Errorf("starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+		
+		_, err = tx.Exec(`
+			CREATE TABLE IF NOT EXISTS reminders (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				contact_id INTEGER NOT NULL,
+				kind TEXT NOT NULL,
+				sent_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (contact_id) REFERENCES contacts (id) ON DELETE CASCADE
+			)
+		`)
+		if err != nil {
+			return fmt.Errorf("creating reminders table: %w", err)
+		}
+		
+		_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_reminders_sent_at ON reminders (sent_at)`)
+		if err != nil {
+			return fmt.Errorf("creating reminders index: %w", err)
+		}
+		
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing reminders migration: %w", err)
+		}
+		
+		log.Println("Reminders migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runRemindersMutedMigration() error {
+	// Check if reminders muted column exists
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM pragma_table_info('contacts') 
+		WHERE name = 'reminders_muted'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for reminders_muted column: %w", err)
+	}
+	
+	// If column doesn't exist, add it
+	if count < 1 {
+		log.Println("Running migration: Adding reminders muted column...")
+		
+		tx, err := db.pool().Begin()
+		if err != nil {
+			return fmt.Errorf("starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+		
+		_, err = tx.Exec(`ALTER TABLE contacts ADD COLUMN reminders_muted BOOLEAN DEFAULT 0`)
+		if err != nil && err.Error() != "duplicate column name: reminders_muted" {
+			return fmt.Errorf("adding reminders_muted column: %w", err)
+		}
+		
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing reminders muted migration: %w", err)
+		}
+		
+		log.Println("Reminders muted migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runImportantDatesMigration() error {
+	// Check if important dates table exists
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM sqlite_master 
+		WHERE type = 'table' AND name = 'important_dates'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for important_dates table: %w", err)
+	}
+	
+	// If table doesn't exist, create it
+	if count < 1 {
+		log.Println("Running migration: Adding important dates table...")
+		
+		tx, err := db.pool().Begin()
+		if err != nil {
+			return fmt.Errorf("starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+		
+		_, err = tx.Exec(`
+			CREATE TABLE IF NOT EXISTS important_dates (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				contact_id INTEGER NOT NULL,
+				label TEXT NOT NULL,
+				date DATE NOT NULL,
+				recurring BOOLEAN DEFAULT 1,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (contact_id) REFERENCES contacts (id) ON DELETE CASCADE
+			)
+		`)
+		if err != nil {
+			return fmt.Errorf("creating important_dates table: %w", err)
+		}
+		
+		_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_important_dates_contact ON important_dates (contact_id)`)
+		if err != nil {
+			return fmt.Errorf("creating important_dates index: %w", err)
+		}
+		
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing important dates migration: %w", err)
+		}
+		
+		log.Println("Important dates migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runInteractionRatingMigration() error {
+	// Check if rating column exists
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM pragma_table_info('contact_interactions') 
+		WHERE name = 'rating'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for rating column: %w", err)
+	}
+	
+	// If column doesn't exist, add it
+	if count < 1 {
+		log.Println("Running migration: Adding interaction rating column...")
+		
+		tx, err := db.pool().Begin()
+		if err != nil {
+			return fmt.Errorf("starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+		
+		_, err = tx.Exec(`ALTER TABLE contact_interactions ADD COLUMN rating INTEGER CHECK (rating BETWEEN 1 AND 5)`)
+		if err != nil && err.Error() != "duplicate column name: rating" {
+			return fmt.Errorf("adding rating column: %w", err)
+		}
+		
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing interaction rating migration: %w", err)
+		}
+		
+		log.Println("Interaction rating migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runWaitingMigration() error {
+	// Check if waiting columns exist
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM pragma_table_info('contacts') 
+		WHERE name IN ('waiting_since', 'waiting_nudged')
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for waiting columns: %w", err)
+	}
+	
+	// If columns don't exist, add them
+	if count < 2 {
+		log.Println("Running migration: Adding waiting on reply columns...")
+		
+		tx, err := db.pool().Begin()
+		if err != nil {
+			return fmt.Errorf("starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+		
+		_, err = tx.Exec(`ALTER TABLE contacts ADD COLUMN waiting_since TIMESTAMP`)
+		if err != nil && err.Error() != "duplicate column name: waiting_since" {
+			return fmt.Errorf("adding waiting_since column: %w", err)
+		}
+		
+		_, err = tx.Exec(`ALTER TABLE contacts ADD COLUMN waiting_nudged BOOLEAN DEFAULT 0`)
+		if err != nil && err.Error() != "duplicate column name: waiting_nudged" {
+			return fmt.Errorf("adding waiting_nudged column: %w", err)
+		}
+		
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing waiting migration: %w", err)
+		}
+		
+		log.Println("Waiting on reply migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runArchiveReasonMigration() error {
+	// Check if archive reason column exists
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM pragma_table_info('contacts') 
+		WHERE name = 'archive_reason'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for archive_reason column: %w", err)
+	}
+	
+	// If column doesn't exist, add it
+	if count < 1 {
+		log.Println("Running migration: Adding archive reason column...")
+		
+		tx, err := db.pool().Begin()
+		if err != nil {
+			return fmt.Errorf("starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+		
+		_, err = tx.Exec(`ALTER TABLE contacts ADD COLUMN archive_reason TEXT`)
+		if err != nil && err.Error() != "duplicate column name: archive_reason" {
+			return fmt.Errorf("adding archive_reason column: %w", err)
+		}
+		
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing archive reason migration: %w", err)
+		}
+		
+		log.Println("Archive reason migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runInteractionDurationMigration() error {
+	// Check if duration column exists
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM pragma_table_info('contact_interactions') 
+		WHERE name = 'duration_minutes'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for duration_minutes column: %w", err)
+	}
+	
+	// If column doesn't exist, add it
+	if count < 1 {
+		log.Println("Running migration: Adding interaction duration column...")
+		
+		tx, err := db.pool().Begin()
+		if err != nil {
+			return fmt.Errorf("starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+		
+		_, err = tx.Exec(`ALTER TABLE contact_interactions ADD COLUMN duration_minutes INTEGER CHECK (duration_minutes > 0)`)
+		if err != nil && err.Error() != "duplicate column name: duration_minutes" {
+			return fmt.Errorf("adding duration_minutes column: %w", err)
+		}
+		
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing interaction duration migration: %w", err)
+		}
+		
+		log.Println("Interaction duration migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runLocationMigration() error {
+	// Check if location column exists
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM pragma_table_info('contacts') 
+		WHERE name = 'location'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for location column: %w", err)
+	}
+	
+	// If column doesn't exist, add it
+	if count < 1 {
+		log.Println("Running migration: Adding location column...")
+		
+		tx, err := db.pool().Begin()
+		if err != nil {
+			return fmt.Errorf("starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+		
+		_, err = tx.Exec(`ALTER TABLE contacts ADD COLUMN location TEXT`)
+		if err != nil && err.Error() != "duplicate column name: location" {
+			return fmt.Errorf("adding location column: %w", err)
+		}
+		
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing location migration: %w", err)
+		}
+		
+		log.Println("Location migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runSyncMigration() error {
+	// Check if synced_card column exists
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM pragma_table_info('contacts') 
+		WHERE name = 'synced_card'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for synced_card column: %w", err)
+	}
+	
+	// If column doesn't exist, add the sync columns and tombstone table.
+	// Databases created from the original schema already have external_id
+	// and synced_at.
+	if count < 1 {
+		log.Println("Running migration: Adding sync columns...")
+		
+		tx, err := db.pool().Begin()
+		if err != nil {
+			return fmt.Errorf("starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+		
+		for _, column := range []string{
+			"external_id TEXT",
+			"source TEXT NOT NULL DEFAULT 'manual'",
+			"synced_at DATETIME",
+			"external_etag TEXT",
+			"synced_card TEXT",
+		} {
+			name := strings.Fields(column)[0]
+			var exists int
+			err := tx.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('contacts') WHERE name = ?`, name).Scan(&exists)
+			if err != nil {
+				return fmt.Errorf("checking for %s column: %w", name, err)
+			}
+			if exists > 0 {
+				continue
+			}
+			if _, err := tx.Exec(`ALTER TABLE contacts ADD COLUMN ` + column); err != nil {
+				return fmt.Errorf("adding %s column: %w", name, err)
+			}
+		}
+		
+		_, err = tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_contacts_external_id ON contacts (external_id)`)
+		if err != nil {
+			return fmt.Errorf("creating external_id index: %w", err)
+		}
+		
+		_, err = tx.Exec(`
+			CREATE TABLE IF NOT EXISTS sync_tombstones (
+				external_id TEXT PRIMARY KEY,
+				deleted_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)
+		`)
+		if err != nil {
+			return fmt.Errorf("creating sync_tombstones table: %w", err)
+		}
+		
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing sync migration: %w", err)
+		}
+		
+		log.Println("Sync migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runContactEmailsMigration() error {
+	// Check if contact emails table exists
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM sqlite_master 
+		WHERE type = 'table' AND name = 'contact_emails'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for contact_emails table: %w", err)
+	}
+	
+	// If table doesn't exist, create it and move each contact's email into it
+	if count < 1 {
+		log.Println("Running migration: Adding contact emails table...")
+		
+		tx, err := db.pool().Begin()
+		if err != nil {
+			return fmt.Errorf("starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+		
+		_, err = tx.Exec(`
+			CREATE TABLE IF NOT EXISTS contact_emails (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				contact_id INTEGER NOT NULL,
+				email TEXT NOT NULL,
+				type TEXT NOT NULL DEFAULT 'personal',
+				position INTEGER NOT NULL DEFAULT 0,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (contact_id) REFERENCES contacts (id) ON DELETE CASCADE
+			)
+		`)
+		if err != nil {
+			return fmt.Errorf("creating contact_emails table: %w", err)
+		}
+		
+		_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_contact_emails_contact ON contact_emails (contact_id, position)`)
+		if err != nil {
+			return fmt.Errorf("creating contact_emails index: %w", err)
+		}
+		
+		_, err = tx.Exec(`
+			INSERT INTO contact_emails (contact_id, email, type, position)
+			SELECT id, TRIM(email), 'personal', 0
+			FROM contacts
+			WHERE email IS NOT NULL AND TRIM(email) != ''
+		`)
+		if err != nil {
+			return fmt.Errorf("copying emails: %w", err)
+		}
+		
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing contact emails migration: %w", err)
+		}
+		
+		log.Println("Contact emails migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runContactPhonesMigration() error {
+	// Check if contact phones table exists
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM sqlite_master 
+		WHERE type = 'table' AND name = 'contact_phones'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for contact_phones table: %w", err)
+	}
+	
+	// If table doesn't exist, create it and move each contact's phone number into it
+	if count < 1 {
+		log.Println("Running migration: Adding contact phones table...")
+		
+		tx, err := db.pool().Begin()
+		if err != nil {
+			return fmt.Errorf("starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+		
+		_, err = tx.Exec(`
+			CREATE TABLE IF NOT EXISTS contact_phones (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				contact_id INTEGER NOT NULL,
+				phone TEXT NOT NULL,
+				type TEXT NOT NULL DEFAULT 'mobile',
+				position INTEGER NOT NULL DEFAULT 0,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (contact_id) REFERENCES contacts (id) ON DELETE CASCADE
+			)
+		`)
+		if err != nil {
+			return fmt.Errorf("creating contact_phones table: %w", err)
+		}
+		
+		_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_contact_phones_contact ON contact_phones (contact_id, position)`)
+		if err != nil {
+			return fmt.Errorf("creating contact_phones index: %w", err)
+		}
+		
+		_, err = tx.Exec(`
+			INSERT INTO contact_phones (contact_id, phone, type, position)
+			SELECT id, TRIM(phone), 'mobile', 0
+			FROM contacts
+			WHERE phone IS NOT NULL AND TRIM(phone) != ''
+		`)
+		if err != nil {
+			return fmt.Errorf("copying phones: %w", err)
+		}
+		
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing contact phones migration: %w", err)
+		}
+		
+		log.Println("Contact phones migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runAddressMigration() error {
+	// Check if postal_code column exists
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM pragma_table_info('contacts') 
+		WHERE name = 'postal_code'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for postal_code column: %w", err)
+	}
+	
+	// If column doesn't exist, add the address columns
+	if count < 1 {
+		log.Println("Running migration: Adding address columns...")
+		
+		tx, err := db.pool().Begin()
+		if err != nil {
+			return fmt.Errorf("starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+		
+		for _, column := range []string{"street", "city", "region", "postal_code", "country"} {
+			_, err = tx.Exec(`ALTER TABLE contacts ADD COLUMN ` + column + ` TEXT`)
+			if err != nil && err.Error() != "duplicate column name: "+column {
+				return fmt.Errorf("adding %s column: %w", column, err)
+			}
+		}
+		
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing address migration: %w", err)
+		}
+		
+		log.Println("Address migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runBirthdayMigration() error {
+	// Check if birthday column exists
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM pragma_table_info('contacts') 
+		WHERE name = 'birthday'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for birthday column: %w", err)
+	}
+	
+	// If column doesn't exist, add it and move yearly important dates
+	// labeled "Birthday" into it
+	if count < 1 {
+		log.Println("Running migration: Adding birthday column...")
+		
+		tx, err := db.pool().Begin()
+		if err != nil {
+			return fmt.Errorf("starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+		
+		_, err = tx.Exec(`ALTER TABLE contacts ADD COLUMN birthday TEXT`)
+		if err != nil && err.Error() != "duplicate column name: birthday" {
+			return fmt.Errorf("adding birthday column: %w", err)
+		}
+		
+		const birthdays = `
+			FROM important_dates
+			WHERE LOWER(TRIM(label)) = 'birthday' AND recurring = 1
+		`
+		_, err = tx.Exec(`
+			UPDATE contacts
+			SET birthday = (
+				SELECT SUBSTR(date, 1, 10) ` + birthdays + ` AND contact_id = contacts.id
+				ORDER BY id LIMIT 1
+			)
+			WHERE id IN (SELECT contact_id ` + birthdays + `)
+		`)
+		if err != nil {
+			return fmt.Errorf("copying birthdays: %w", err)
+		}
+		
+		if _, err := tx.Exec(`DELETE ` + birthdays); err != nil {
+			return fmt.Errorf("removing birthday dates: %w", err)
+		}
+		
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing birthday migration: %w", err)
+		}
+		
+		log.Println("Birthday migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runTagsMigration() error {
+	// Check if tags table exists
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM sqlite_master 
+		WHERE type = 'table' AND name = 'contact_tags'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for contact_tags table: %w", err)
+	}
+	
+	// If table doesn't exist, create the tag tables
+	if count < 1 {
+		log.Println("Running migration: Adding tags tables...")
+		
+		tx, err := db.pool().Begin()
+		if err != nil {
+			return fmt.Errorf("starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+		
+		_, err = tx.Exec(`
+			CREATE TABLE IF NOT EXISTS tags (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)
+		`)
+		if err != nil {
+			return fmt.Errorf("creating tags table: %w", err)
+		}
+		
+		_, err = tx.Exec(`
+			CREATE TABLE IF NOT EXISTS contact_tags (
+				contact_id INTEGER NOT NULL,
+				tag_id INTEGER NOT NULL,
+				PRIMARY KEY (contact_id, tag_id),
+				FOREIGN KEY (contact_id) REFERENCES contacts (id) ON DELETE CASCADE,
+				FOREIGN KEY (tag_id) REFERENCES tags (id) ON DELETE CASCADE
+			)
+		`)
+		if err != nil {
+			return fmt.Errorf("creating contact_tags table: %w", err)
+		}
+		
+		_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_contact_tags_tag ON contact_tags (tag_id)`)
+		if err != nil {
+			return fmt.Errorf("creating contact_tags index: %w", err)
+		}
+		
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing tags migration: %w", err)
+		}
+		
+		log.Println("Tags migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runGroupsMigration() error {
+	// Check if group tables exist
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM sqlite_master 
+		WHERE type = 'table' AND name = 'group_members'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for group_members table: %w", err)
+	}
+	
+	// If table doesn't exist, create the group tables
+	if count < 1 {
+		log.Println("Running migration: Adding groups tables...")
+		
+		tx, err := db.pool().Begin()
+		if err != nil {
+			return fmt.Errorf("starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+		
+		_, err = tx.Exec(`
+			CREATE TABLE IF NOT EXISTS contact_groups (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)
+		`)
+		if err != nil {
+			return fmt.Errorf("creating contact_groups table: %w", err)
+		}
+		
+		_, err = tx.Exec(`
+			CREATE TABLE IF NOT EXISTS group_members (
+				group_id INTEGER NOT NULL,
+				contact_id INTEGER NOT NULL,
+				PRIMARY KEY (group_id, contact_id),
+				FOREIGN KEY (group_id) REFERENCES contact_groups (id) ON DELETE CASCADE,
+				FOREIGN KEY (contact_id) REFERENCES contacts (id) ON DELETE CASCADE
+			)
+		`)
+		if err != nil {
+			return fmt.Errorf("creating group_members table: %w", err)
+		}
+		
+		_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_group_members_contact ON group_members (contact_id)`)
+		if err != nil {
+			return fmt.Errorf("creating group_members index: %w", err)
+		}
+		
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing groups migration: %w", err)
+		}
+		
+		log.Println("Groups migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runCustomFieldsMigration() error {
+	// Check if custom field tables exist
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM sqlite_master 
+		WHERE type = 'table' AND name = 'contact_field_values'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for contact_field_values table: %w", err)
+	}
+	
+	// If table doesn't exist, create the custom field tables
+	if count < 1 {
+		log.Println("Running migration: Adding custom fields tables...")
+		
+		tx, err := db.pool().Begin()
+		if err != nil {
+			return fmt.Errorf("starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+		
+		_, err = tx.Exec(`
+			CREATE TABLE IF NOT EXISTS custom_fields (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)
+		`)
+		if err != nil {
+			return fmt.Errorf("creating custom_fields table: %w", err)
+		}
+		
+		_, err = tx.Exec(`
+			CREATE TABLE IF NOT EXISTS contact_field_values (
+				contact_id INTEGER NOT NULL,
+				field_id INTEGER NOT NULL,
+				value TEXT NOT NULL,
+				PRIMARY KEY (contact_id, field_id),
+				FOREIGN KEY (contact_id) REFERENCES contacts (id) ON DELETE CASCADE,
+				FOREIGN KEY (field_id) REFERENCES custom_fields (id) ON DELETE CASCADE
+			)
+		`)
+		if err != nil {
+			return fmt.Errorf("creating contact_field_values table: %w", err)
+		}
+		
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing custom fields migration: %w", err)
+		}
+		
+		log.Println("Custom fields migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runTrashMigration() error {
+	// Check if trashed_at column exists
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM pragma_table_info('contacts') 
+		WHERE name = 'trashed_at'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for trashed_at column: %w", err)
+	}
+	
+	// If column doesn't exist, add it
+	if count < 1 {
+		log.Println("Running migration: Adding trash column...")
+		
+		tx, err := db.pool().Begin()
+		if err != nil {
+			return fmt.Errorf("starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+		
+		_, err = tx.Exec(`ALTER TABLE contacts ADD COLUMN trashed_at TIMESTAMP`)
+		if err != nil && err.Error() != "duplicate column name: trashed_at" {
+			return fmt.Errorf("adding trashed_at column: %w", err)
+		}
+		
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing trash migration: %w", err)
+		}
+		
+		log.Println("Trash migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runAvatarMigration() error {
+	// Check if avatar column exists
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM pragma_table_info('contacts') 
+		WHERE name = 'avatar'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for avatar column: %w", err)
+	}
+	
+	// If column doesn't exist, add it
+	if count < 1 {
+		log.Println("Running migration: Adding avatar column...")
+		
+		tx, err := db.pool().Begin()
+		if err != nil {
+			return fmt.Errorf("starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+		
+		_, err = tx.Exec(`ALTER TABLE contacts ADD COLUMN avatar TEXT`)
+		if err != nil && err.Error() != "duplicate column name: avatar" {
+			return fmt.Errorf("adding avatar column: %w", err)
+		}
+		
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing avatar migration: %w", err)
+		}
+		
+		log.Println("Avatar migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runHandlesMigration() error {
+	// Check if contact handles table exists
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM sqlite_master 
+		WHERE type = 'table' AND name = 'contact_handles'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for contact_handles table: %w", err)
+	}
+	
+	// If table doesn't exist, create it
+	if count < 1 {
+		log.Println("Running migration: Adding social media handles table...")
+		
+		tx, err := db.pool().Begin()
+		if err != nil {
+			return fmt.Errorf("starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+		
+		_, err = tx.Exec(`
+			CREATE TABLE IF NOT EXISTS contact_handles (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				contact_id INTEGER NOT NULL,
+				handle TEXT NOT NULL,
+				type TEXT NOT NULL DEFAULT 'mastodon',
+				position INTEGER NOT NULL DEFAULT 0,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (contact_id) REFERENCES contacts (id) ON DELETE CASCADE
+			)
+		`)
+		if err != nil {
+			return fmt.Errorf("creating contact_handles table: %w", err)
+		}
+		
+		_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_contact_handles_contact ON contact_handles (contact_id, position)`)
+		if err != nil {
+			return fmt.Errorf("creating contact_handles index: %w", err)
+		}
+		
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing social media handles migration: %w", err)
+		}
+		
+		log.Println("Social media handles migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runLinksMigration() error {
+	// Check if contact links table exists
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM sqlite_master 
+		WHERE type = 'table' AND name = 'contact_links'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for contact_links table: %w", err)
+	}
+	
+	// If table doesn't exist, create it
+	if count < 1 {
+		log.Println("Running migration: Adding contact links table...")
+		
+		tx, err := db.pool().Begin()
+		if err != nil {
+			return fmt.Errorf("starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+		
+		_, err = tx.Exec(`
+			CREATE TABLE IF NOT EXISTS contact_links (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				contact_id INTEGER NOT NULL,
+				other_id INTEGER NOT NULL,
+				kind TEXT NOT NULL,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE (contact_id, other_id, kind),
+				FOREIGN KEY (contact_id) REFERENCES contacts (id) ON DELETE CASCADE,
+				FOREIGN KEY (other_id) REFERENCES contacts (id) ON DELETE CASCADE
+			)
+		`)
+		if err != nil {
+			return fmt.Errorf("creating contact_links table: %w", err)
+		}
+		
+		_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_contact_links_other ON contact_links (other_id)`)
+		if err != nil {
+			return fmt.Errorf("creating contact_links index: %w", err)
+		}
+		
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing contact links migration: %w", err)
+		}
+		
+		log.Println("Contact links migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runAttachmentsMigration() error {
+	// Check if interaction_attachments table exists
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM sqlite_master 
+		WHERE type = 'table' AND name = 'interaction_attachments'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for interaction_attachments table: %w", err)
+	}
+	
+	// If table doesn't exist, create it
+	if count < 1 {
+		log.Println("Running migration: Adding interaction attachments table...")
+		
+		tx, err := db.pool().Begin()
+		if err != nil {
+			return fmt.Errorf("starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+		
+		_, err = tx.Exec(`
+			CREATE TABLE IF NOT EXISTS interaction_attachments (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				interaction_id INTEGER NOT NULL,
+				target TEXT NOT NULL,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE (interaction_id, target),
+				FOREIGN KEY (interaction_id) REFERENCES contact_interactions (id) ON DELETE CASCADE
+			)
+		`)
+		if err != nil {
+			return fmt.Errorf("creating interaction_attachments table: %w", err)
+		}
+		
+		_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_interaction_attachments_interaction ON interaction_attachments (interaction_id)`)
+		if err != nil {
+			return fmt.Errorf("creating interaction_attachments index: %w", err)
+		}
+		
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing attachments migration: %w", err)
+		}
+		
+		log.Println("Attachments migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runSnoozeMigration() error {
+	// Check if snooze_until column exists
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM pragma_table_info('contacts') 
+		WHERE name = 'snooze_until'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for snooze_until column: %w", err)
+	}
+	
+	// If column doesn't exist, add it
+	if count < 1 {
+		log.Println("Running migration: Adding snooze column...")
+		
+		_, err = db.pool().Exec(`ALTER TABLE contacts ADD COLUMN snooze_until TIMESTAMP`)
+		if err != nil && err.Error() != "duplicate column name: snooze_until" {
+			return fmt.Errorf("adding snooze_until column: %w", err)
+		}
+		
+		log.Println("Snooze migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runTimezoneMigration() error {
+	// Check if timezone column exists
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM pragma_table_info('contacts') 
+		WHERE name = 'timezone'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for timezone column: %w", err)
+	}
+	
+	// If column doesn't exist, add it
+	if count < 1 {
+		log.Println("Running migration: Adding time zone column...")
+		
+		_, err = db.pool().Exec(`ALTER TABLE contacts ADD COLUMN timezone TEXT`)
+		if err != nil && err.Error() != "duplicate column name: timezone" {
+			return fmt.Errorf("adding timezone column: %w", err)
+		}
+		
+		log.Println("Time zone migration completed successfully")
+	}
+	
+	return nil
+}
+
+func (db *DB) runStarredMigration() error {
+	// Check if starred column exists
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM pragma_table_info('contacts') 
+		WHERE name = 'starred'
+	`).Scan(&count)
+	
+	if err != nil {
+		return fmt.Errorf("checking for starred column: %w", err)
+	}
+	
+	// If column doesn't exist, add it
+	if count < 1 {
+		log.Println("Running migration: Adding starred column...")
+		
+		_, err = db.pool().Exec(`ALTER TABLE contacts ADD COLUMN starred BOOLEAN DEFAULT 0`)
+		if err != nil && err.Error() != "duplicate column name: starred" {
+			return fmt.Errorf("adding starred column: %w", err)
+		}
+		
+		log.Println("Starred migration completed successfully")
+	}
+	
+	return nil
+}
+
+// runLabelUniqueMigration makes labels unique at the database level. Labels
+// already shared by several contacts are reported and the index is left for
+// a later start, once they are changed (contacts-tui doctor -fix can clear
+// the extra ones).
+func (db *DB) runLabelUniqueMigration() error {
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) FROM sqlite_master
+		WHERE type = 'index' AND name = 'idx_contacts_label_unique'
+	`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("checking for unique label index: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+	
+	conflicts, err := db.labelConflicts()
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		for _, c := range conflicts {
+			log.Printf("Label %s is shared by %s; labels can't be made unique until they differ", c.label, strings.Join(c.names, ", "))
+		}
+		return nil
+	}
+	
+	log.Println("Running migration: Making labels unique...")
+	if _, err := db.pool().Exec(labelIndexSQL); err != nil {
+		return fmt.Errorf("creating unique label index: %w", err)
+	}
+	log.Println("Unique label migration completed successfully")
+	return nil
+}
+
+// searchTriggers keep the full-text indexes of contact and interaction
+// notes current
+var searchTriggers = map[string]string{
+	"search_contact_insert": `
+		CREATE TRIGGER IF NOT EXISTS search_contact_insert AFTER INSERT ON contacts
+		WHEN NEW.notes IS NOT NULL AND NEW.notes != ''
+		BEGIN
+			INSERT INTO contact_notes_fts (rowid, notes) VALUES (NEW.id, NEW.notes);
+		END`,
+	"search_contact_update": `
+		CREATE TRIGGER IF NOT EXISTS search_contact_update AFTER UPDATE OF notes ON contacts
+		BEGIN
+			DELETE FROM contact_notes_fts WHERE rowid = OLD.id;
+			INSERT INTO contact_notes_fts (rowid, notes)
+			SELECT NEW.id, NEW.notes WHERE NEW.notes IS NOT NULL AND NEW.notes != '';
+		END`,
+	"search_contact_delete": `
+		CREATE TRIGGER IF NOT EXISTS search_contact_delete AFTER DELETE ON contacts
+		BEGIN
+			DELETE FROM contact_notes_fts WHERE rowid = OLD.id;
+		END`,
+	"search_interaction_insert": `
+		CREATE TRIGGER IF NOT EXISTS search_interaction_insert AFTER INSERT ON contact_interactions
+		WHEN NEW.notes IS NOT NULL AND NEW.notes != ''
+		BEGIN
+			INSERT INTO interaction_notes_fts (rowid, notes) VALUES (NEW.id, NEW.notes);
+		END`,
+	"search_interaction_update": `
+		CREATE TRIGGER IF NOT EXISTS search_interaction_update AFTER UPDATE OF notes ON contact_interactions
+		BEGIN
+			DELETE FROM interaction_notes_fts WHERE rowid = OLD.id;
+			INSERT INTO interaction_notes_fts (rowid, notes)
+			SELECT NEW.id, NEW.notes WHERE NEW.notes IS NOT NULL AND NEW.notes != '';
+		END`,
+	"search_interaction_delete": `
+		CREATE TRIGGER IF NOT EXISTS search_interaction_delete AFTER DELETE ON contact_interactions
+		BEGIN
+			DELETE FROM interaction_notes_fts WHERE rowid = OLD.id;
+		END`,
+}
+
+// runSearchMigration indexes contact and interaction notes for full-text
+// search when SQLite was built with FTS5 (go build -tags sqlite_fts5).
+// Without FTS5 the triggers that keep the indexes current are dropped, since
+// every write would fail on them, and the indexes are rebuilt the next time
+// a build with FTS5 opens the database.
+func (db *DB) runSearchMigration() error {
+	var available int
+	err := db.pool().QueryRow(`SELECT COUNT(*) FROM pragma_module_list WHERE name = 'fts5'`).Scan(&available)
+	if err != nil {
+		return fmt.Errorf("checking for fts5: %w", err)
+	}
+	
+	var triggers int
+	err = db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM sqlite_master 
+		WHERE type = 'trigger' AND name LIKE 'search\_%' ESCAPE '\'
+	`).Scan(&triggers)
+	if err != nil {
+		return fmt.Errorf("checking for search triggers: %w", err)
+	}
+	
+	db.fts = available > 0
+	if !db.fts {
+		if triggers > 0 {
+			log.Println("SQLite was built without FTS5: dropping the full-text search index triggers")
+			for name := range searchTriggers {
+				if _, err := db.pool().Exec(`DROP TRIGGER IF EXISTS ` + name); err != nil {
+					return fmt.Errorf("dropping %s: %w", name, err)
+				}
+			}
+		}
+		return nil
+	}
+	if triggers == len(searchTriggers) {
+		return nil
+	}
+	
+	log.Println("Running migration: Indexing notes for full-text search...")
+	
+	tx, err := db.pool().Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	statements := []string{
+		`CREATE VIRTUAL TABLE IF NOT EXISTS contact_notes_fts USING fts5(notes)`,
+		`CREATE VIRTUAL TABLE IF NOT EXISTS interaction_notes_fts USING fts5(notes)`,
+		`DELETE FROM contact_notes_fts`,
+		`DELETE FROM interaction_notes_fts`,
+		`INSERT INTO contact_notes_fts (rowid, notes)
+		 SELECT id, notes FROM contacts WHERE notes IS NOT NULL AND notes != ''`,
+		`INSERT INTO interaction_notes_fts (rowid, notes)
+		 SELECT id, notes FROM contact_interactions WHERE notes IS NOT NULL AND notes != ''`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("building search index: %w", err)
+		}
+	}
+	for name, stmt := range searchTriggers {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("creating %s: %w", name, err)
+		}
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing search migration: %w", err)
+	}
+	
+	log.Println("Search migration completed successfully")
+	return nil
+}
+
+// runHistoryMigration creates the contact_history table and the trigger
+// recording changes to contacts in it. The trigger is recreated whenever
+// the columns recorded change.
+func (db *DB) runHistoryMigration() error {
+	var count int
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*) 
+		FROM sqlite_master 
+		WHERE type = 'table' AND name = 'contact_history'
+	`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("checking for contact_history table: %w", err)
+	}
+	
+	var existing sql.NullString
+	err = db.pool().QueryRow(`SELECT sql FROM sqlite_master WHERE type = 'trigger' AND name = 'history_contact_update'`).Scan(&existing)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("checking for history trigger: %w", err)
+	}
+	trigger := historyTrigger()
+	if count > 0 && existing.String == trigger {
+		return nil
+	}
+	
+	log.Println("Running migration: Recording contact history...")
+	
+	tx, err := db.pool().Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS contact_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			contact_id INTEGER NOT NULL,
+			field TEXT NOT NULL,
+			old_value TEXT,
+			new_value TEXT,
+			changed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (contact_id) REFERENCES contacts (id) ON DELETE CASCADE
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("creating contact_history table: %w", err)
+	}
+	
+	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_contact_history_contact ON contact_history (contact_id, changed_at)`)
+	if err != nil {
+		return fmt.Errorf("creating contact_history index: %w", err)
+	}
+	
+	if _, err := tx.Exec(`DROP TRIGGER IF EXISTS history_contact_update`); err != nil {
+		return fmt.Errorf("dropping history trigger: %w", err)
+	}
+	if _, err := tx.Exec(trigger); err != nil {
+		return fmt.Errorf("creating history trigger: %w", err)
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing history migration: %w", err)
+	}
+	
+	log.Println("History migration completed successfully")
+	return nil
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"database/sql"
+	"math"
 	"time"
 )
 
@@ -10,8 +11,17 @@ type Contact struct {
 	ID                   int
 	Name                 string
 	Email                sql.NullString
+	Emails               []ContactValue // Every email address, primary first, when read from a card or loaded with LoadValues
 	Phone                sql.NullString
+	Phones               []ContactValue // Every phone number, likewise
 	Company              sql.NullString
+	Location             sql.NullString // City or area, e.g. "Seattle, WA"
+	Address              Address        // Postal address
+	Birthday             sql.NullString // YYYY-MM-DD, or --MM-DD if the year is unknown
+	Avatar               sql.NullString // Image file shown in the detail pane, instead of a fetched avatar
+	Timezone             sql.NullString // IANA time zone, e.g. America/New_York
+	Tags                 []string       // Free-form tags, without the #
+	Groups               []string       // Names of the groups the contact is in
 	RelationshipType     string
 	State                sql.NullString
 	Notes                sql.NullString
@@ -24,8 +34,20 @@ type Contact struct {
 	DeadlineDate         sql.NullTime
 	Archived             bool
 	ArchivedAt           sql.NullTime
+	ArchiveReason        sql.NullString
 	ContactStyle         string
 	CustomFrequencyDays  sql.NullInt64
+	EscalationLevel      int          // Highest reminder escalation step reached while overdue
+	RemindersMuted       bool         // Reference-only contact: never overdue or escalated
+	Starred              bool         // Kept near the top of the list
+	WaitingSince         sql.NullTime // When the contact started owing a reply
+	WaitingNudged        bool         // A nudge task was created for the current wait
+	SnoozeUntil          sql.NullTime // Not overdue before this time
+	ExternalID           sql.NullString // Address of the contact's card on the sync server
+	SyncedAt             sql.NullTime   // When the contact was last synced with the server
+	TrashedAt            sql.NullTime   // When the contact was deleted to the trash
+	LastInteractionType  sql.NullString // Type of the most recent logged interaction
+	LastInteractionAt    sql.NullTime   // When the most recent logged interaction happened
 	CreatedAt            time.Time
 	UpdatedAt            time.Time
 }
@@ -37,57 +59,126 @@ type Log struct {
 	InteractionDate time.Time
 	InteractionType string
 	Notes           sql.NullString
+	Rating          sql.NullInt64 // 1-5 energy rating; 5 is energizing, 1 is draining
+	DurationMinutes sql.NullInt64 // How long the interaction took
+	Attachments     []string      // File paths or URLs attached to the interaction
 	CreatedAt       time.Time
 }
 
 // IsOverdue checks if a contact is overdue based on relationship type and contact style
 func (c Contact) IsOverdue() bool {
+	return c.OverdueRatio() > 1
+}
+
+// CadenceDays returns how many days may pass between contacts before the
+// contact is overdue, or 0 if the contact is never overdue
+func (c Contact) CadenceDays() int {
 	// Archived contacts are never overdue
 	if c.Archived {
-		return false
+		return 0
+	}
+	
+	// Contacts with reminders muted are never overdue
+	if c.RemindersMuted {
+		return 0
+	}
+	
+	// Snoozed contacts aren't overdue until the snooze ends
+	if c.IsSnoozed() {
+		return 0
 	}
 	
 	// Ambient and triggered contacts are never overdue
 	if c.ContactStyle == "ambient" || c.ContactStyle == "triggered" {
-		return false
+		return 0
 	}
 	
-	// Get the most recent interaction date (either contacted or bumped)
-	var lastInteraction sql.NullTime
+	// Use custom frequency if set
+	if c.CustomFrequencyDays.Valid && c.CustomFrequencyDays.Int64 > 0 {
+		return int(c.CustomFrequencyDays.Int64)
+	}
 	
+	// Otherwise use the relationship type's cadence
+	return relationshipCadence(c.RelationshipType)
+}
+
+// IsSnoozed reports whether the contact is snoozed until a time still to come
+func (c Contact) IsSnoozed() bool {
+	return c.SnoozeUntil.Valid && time.Now().Before(c.SnoozeUntil.Time)
+}
+
+// WaitingDays returns how many days the contact has owed a reply, or -1 if
+// not waiting on one
+func (c Contact) WaitingDays() int {
+	if !c.WaitingSince.Valid {
+		return -1
+	}
+	return int(time.Since(c.WaitingSince.Time).Hours() / 24)
+}
+
+// MissingFields lists the profile fields a contact is missing, in the order
+// they count towards Completeness
+func (c Contact) MissingFields() []string {
+	var missing []string
+	if !c.Email.Valid || c.Email.String == "" {
+		missing = append(missing, "email")
+	}
+	if !c.Phone.Valid || c.Phone.String == "" {
+		missing = append(missing, "phone")
+	}
+	if !c.Label.Valid || c.Label.String == "" {
+		missing = append(missing, "label")
+	}
+	if c.RelationshipType == "" {
+		missing = append(missing, "relationship type")
+	}
+	if c.ContactStyle == "" {
+		missing = append(missing, "style")
+	}
+	if !c.LastInteraction().Valid {
+		missing = append(missing, "interaction")
+	}
+	return missing
+}
+
+// Completeness returns the percentage of profile fields that are filled in:
+// email, phone, label, relationship type, style and any interaction
+func (c Contact) Completeness() int {
+	const fields = 6
+	return (fields - len(c.MissingFields())) * 100 / fields
+}
+
+// LastInteraction returns the most recent contact or bump date
+func (c Contact) LastInteraction() sql.NullTime {
 	if c.ContactedAt.Valid && c.LastBumpDate.Valid {
 		// Use whichever is more recent
 		if c.ContactedAt.Time.After(c.LastBumpDate.Time) {
-			lastInteraction = c.ContactedAt
-		} else {
-			lastInteraction = c.LastBumpDate
+			return c.ContactedAt
 		}
-	} else if c.ContactedAt.Valid {
-		lastInteraction = c.ContactedAt
-	} else if c.LastBumpDate.Valid {
-		lastInteraction = c.LastBumpDate
+		return c.LastBumpDate
+	}
+	if c.ContactedAt.Valid {
+		return c.ContactedAt
+	}
+	return c.LastBumpDate
+}
+
+// OverdueRatio returns how many cadence periods have passed since the last
+// interaction: above 1 is overdue, 2 is twice overdue, and so on. Contacts
+// that are never overdue return 0; contacts never contacted return +Inf.
+func (c Contact) OverdueRatio() float64 {
+	cadence := c.CadenceDays()
+	if cadence == 0 {
+		return 0
 	}
 	
+	lastInteraction := c.LastInteraction()
 	if !lastInteraction.Valid {
-		return true // Never contacted or bumped
+		return math.Inf(1) // Never contacted or bumped
 	}
 	
 	daysSince := time.Since(lastInteraction.Time).Hours() / 24
-	
-	// Use custom frequency if set
-	if c.CustomFrequencyDays.Valid && c.CustomFrequencyDays.Int64 > 0 {
-		return daysSince > float64(c.CustomFrequencyDays.Int64)
-	}
-	
-	// Otherwise use relationship type defaults
-	switch c.RelationshipType {
-	case "close", "family":
-		return daysSince > 30
-	case "network":
-		return daysSince > 90
-	default:
-		return daysSince > 60
-	}
+	return daysSince / float64(cadence)
 }
 
 // NewNullString creates a sql.NullString from a string
@@ -0,0 +1,62 @@
+package db
+
+import "unicode"
+
+// Phone number labels
+const (
+	PhoneMobile = "mobile"
+	PhoneWork   = "work"
+	PhoneHome   = "home"
+)
+
+// PhoneTypes lists the phone number labels in the order they are offered
+var PhoneTypes = []string{PhoneMobile, PhoneWork, PhoneHome}
+
+// phoneList is the contact_phones table, whose primary number is kept in
+// the phone column of contacts
+var phoneList = valueList{
+	table:  "contact_phones",
+	column: "phone",
+	noun:   "phone number",
+	types:  PhoneTypes,
+	validate: func(phone string) bool {
+		for _, r := range phone {
+			if unicode.IsDigit(r) {
+				return true
+			}
+		}
+		return false
+	},
+	primary: true,
+	phone:   true,
+}
+
+// ListPhones returns a contact's phone numbers, primary first
+func (db *DB) ListPhones(contactID int) ([]ContactValue, error) {
+	return phoneList.list(db.pool(), contactID)
+}
+
+// AddPhone adds a phone number after a contact's existing ones
+func (db *DB) AddPhone(contactID int, phone, label string) error {
+	return phoneList.add(db.pool(), contactID, phone, label)
+}
+
+// UpdatePhone changes a phone number and its label
+func (db *DB) UpdatePhone(phoneID int, phone, label string) error {
+	return phoneList.update(db.pool(), phoneID, phone, label)
+}
+
+// DeletePhone removes a phone number; the next one becomes primary if the
+// primary number is removed
+func (db *DB) DeletePhone(phoneID int) error {
+	return phoneList.remove(db.pool(), phoneID)
+}
+
+// MakePrimaryPhone moves a phone number to the front of its contact's list
+func (db *DB) MakePrimaryPhone(phoneID int) error {
+	contactID, err := phoneList.contactOf(db.pool(), phoneID)
+	if err != nil {
+		return err
+	}
+	return phoneList.makePrimary(db.pool(), contactID, phoneID)
+}
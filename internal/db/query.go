@@ -0,0 +1,140 @@
+package db
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Search is a parsed search such as `sarah company:"acme corp" #mentor`, as
+// typed in the TUI's filter or given to FilterContacts as ContactFilter.Text
+type Search struct {
+	Words    []string     // Each found in the name, label, company, location or a tag
+	Terms    []SearchTerm // Each matched against the field it names
+	Tags     []string     // The contact carries a tag starting with each
+	Location string       // Found in the location or address, from location: or loc:
+}
+
+// SearchTerm is a field:value part of a search, such as state:ping or
+// -type:work
+type SearchTerm struct {
+	Field  string
+	Value  string // Lowercased, without quotes
+	Negate bool   // Written with a leading -, or a yes/no field set to no
+}
+
+// Text fields match anywhere in the field, while states, types, styles,
+// groups and tags match from the start, so results narrow as a word is
+// typed. Yes/no fields match when set, or when not set if negated.
+var (
+	searchTextFields   = []string{"name", "label", "company", "email", "phone", "notes"}
+	searchPrefixFields = []string{"state", "type", "style", "group", "tag"}
+	searchYesNoFields  = []string{"overdue", "starred", "waiting", "muted", "snoozed"}
+)
+
+// SearchFields lists every field a search term can name
+func SearchFields() []string {
+	var fields []string
+	fields = append(fields, searchTextFields...)
+	fields = append(fields, searchPrefixFields...)
+	return append(fields, searchYesNoFields...)
+}
+
+// locationPrefixes start the location part of a search
+var locationPrefixes = []string{"location:", "loc:"}
+
+// ParseSearch splits a search into its parts, ignoring case. Words with an
+// unknown field, like a time or a URL, are searched for as written.
+func ParseSearch(text string) Search {
+	var s Search
+	text, s.Tags = splitSearchTags(strings.ToLower(text))
+	text, s.Location = splitSearchLocation(text)
+
+	known := SearchFields()
+	for _, token := range searchTokens(text) {
+		field, value, ok := strings.Cut(token, ":")
+		negate := strings.HasPrefix(field, "-")
+		field = strings.TrimPrefix(field, "-")
+		if !ok || !slices.Contains(known, field) {
+			s.Words = append(s.Words, strings.ReplaceAll(token, `"`, ""))
+			continue
+		}
+		value = strings.Trim(value, `"`)
+		if value == "" {
+			continue // Still being typed
+		}
+		if field == "tag" {
+			value = strings.TrimLeft(value, "#")
+		}
+		if slices.Contains(searchYesNoFields, field) && !searchYes(value) {
+			negate = !negate
+		}
+		s.Terms = append(s.Terms, SearchTerm{Field: field, Value: value, Negate: negate})
+	}
+	return s
+}
+
+// searchYes reads the value of a yes/no field; anything but no, n, false
+// or 0 means yes
+func searchYes(value string) bool {
+	switch value {
+	case "no", "n", "false", "0":
+		return false
+	}
+	return true
+}
+
+// splitSearchTags splits a search such as "acme #mentor" into the rest of
+// the search and the tags contacts must all carry
+func splitSearchTags(text string) (rest string, tags []string) {
+	var words []string
+	for _, word := range strings.Fields(text) {
+		if tag := strings.TrimLeft(word, "#"); strings.HasPrefix(word, "#") && tag != "" {
+			tags = append(tags, tag)
+		} else {
+			words = append(words, word)
+		}
+	}
+	if len(tags) == 0 {
+		return text, nil
+	}
+	return strings.Join(words, " "), tags
+}
+
+// splitSearchLocation splits a search such as "acme location:new york" into
+// the rest of the search and the place to match against each contact's
+// location and address. Everything after the prefix is the place, so it may
+// contain spaces.
+func splitSearchLocation(text string) (rest, location string) {
+	for _, prefix := range locationPrefixes {
+		if i := strings.Index(text, prefix); i >= 0 && (i == 0 || text[i-1] == ' ') {
+			return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+len(prefix):])
+		}
+	}
+	return text, ""
+}
+
+// searchTokens splits a search at spaces outside double quotes
+func searchTokens(text string) []string {
+	var tokens []string
+	var token strings.Builder
+	quoted := false
+	for _, r := range text {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			token.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if token.Len() > 0 {
+				tokens = append(tokens, token.String())
+				token.Reset()
+			}
+		default:
+			token.WriteRune(r)
+		}
+	}
+	if token.Len() > 0 {
+		tokens = append(tokens, token.String())
+	}
+	return tokens
+}
@@ -0,0 +1,31 @@
+package db
+
+import "fmt"
+
+// schemaVersion is stored in the database's user_version once RunMigrations
+// has brought it up to date. Bump it when adding a migration, so databases
+// are backed up before the migration changes them.
+const schemaVersion = 31
+
+// schemaOutdated reports whether the database was last migrated by an older
+// version, or never
+func (db *DB) schemaOutdated() (bool, error) {
+	var version int
+	if err := db.pool().QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return false, fmt.Errorf("reading schema version: %w", err)
+	}
+	return version < schemaVersion, nil
+}
+
+// markMigrated records that the schema is up to date. A database migrated by
+// a newer version keeps its version.
+func (db *DB) markMigrated() error {
+	outdated, err := db.schemaOutdated()
+	if err != nil || !outdated {
+		return err
+	}
+	if _, err := db.pool().Exec(fmt.Sprintf(`PRAGMA user_version = %d`, schemaVersion)); err != nil {
+		return fmt.Errorf("recording schema version: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NoteMatch is a contact's notes or an interaction's notes found by
+// SearchNotes
+type NoteMatch struct {
+	ContactID     int
+	ContactName   string
+	InteractionID int       // 0 when the contact's own notes matched
+	Date          time.Time // When the interaction happened
+	Type          string    // Interaction type
+	Notes         string
+}
+
+// SearchNotes finds contact notes and interaction notes containing every
+// word of query, contact notes first and then interactions newest first.
+// With FTS5 words match the start of words in the notes ("kube" finds
+// "Kubernetes"); without it they match anywhere in the text. Contacts in
+// the trash are left out.
+func (db *DB) SearchNotes(query string, limit int) ([]NoteMatch, error) {
+	words := strings.Fields(query)
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	contactCond, interactionCond, args := db.searchConditions(words)
+
+	rows, err := db.pool().Query(`
+		SELECT c.id, c.name, c.notes
+		FROM contacts c
+		WHERE c.trashed_at IS NULL AND `+contactCond+`
+		ORDER BY c.name
+		LIMIT ?
+	`, append(args, limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("searching contact notes: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []NoteMatch
+	for rows.Next() {
+		var m NoteMatch
+		if err := rows.Scan(&m.ContactID, &m.ContactName, &m.Notes); err != nil {
+			return nil, fmt.Errorf("scanning contact notes: %w", err)
+		}
+		matches = append(matches, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = db.pool().Query(`
+		SELECT c.id, c.name, i.id, i.interaction_date, i.interaction_type, i.notes
+		FROM contact_interactions i
+		JOIN contacts c ON c.id = i.contact_id
+		WHERE c.trashed_at IS NULL AND `+interactionCond+`
+		ORDER BY i.interaction_date DESC
+		LIMIT ?
+	`, append(args, limit-len(matches))...)
+	if err != nil {
+		return nil, fmt.Errorf("searching interaction notes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m NoteMatch
+		var notes sql.NullString
+		if err := rows.Scan(&m.ContactID, &m.ContactName, &m.InteractionID, &m.Date, &m.Type, &notes); err != nil {
+			return nil, fmt.Errorf("scanning interaction notes: %w", err)
+		}
+		m.Notes = notes.String
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
+// searchConditions returns the WHERE conditions matching contact notes and
+// interaction notes against every word, using the FTS5 indexes if there
+// are any
+func (db *DB) searchConditions(words []string) (string, string, []interface{}) {
+	if db.fts {
+		terms := make([]string, len(words))
+		for i, w := range words {
+			terms[i] = `"` + strings.ReplaceAll(w, `"`, `""`) + `"*`
+		}
+		match := strings.Join(terms, " ")
+		return `c.id IN (SELECT rowid FROM contact_notes_fts WHERE contact_notes_fts MATCH ?)`,
+			`i.id IN (SELECT rowid FROM interaction_notes_fts WHERE interaction_notes_fts MATCH ?)`,
+			[]interface{}{match}
+	}
+
+	var contactConds, interactionConds []string
+	var args []interface{}
+	for _, w := range words {
+		contactConds = append(contactConds, `c.notes LIKE ? ESCAPE '\'`)
+		interactionConds = append(interactionConds, `i.notes LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+likeEscaper.Replace(w)+"%")
+	}
+	return strings.Join(contactConds, " AND "), strings.Join(interactionConds, " AND "), args
+}
+
+// likeEscaper escapes the wildcards in a LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
@@ -0,0 +1,38 @@
+package db
+
+import "fmt"
+
+// requiredStates must be in every state list: contacts rest in "ok", and
+// escalation moves overdue contacts to "ping"
+var requiredStates = []string{"ok", "ping"}
+
+// ValidateStates checks a list of contact states from the config
+func ValidateStates(states []string) error {
+	seen := make(map[string]bool)
+	for _, name := range states {
+		if !validTypeName.MatchString(name) {
+			return fmt.Errorf("invalid state %q (use lowercase letters, digits, - and _)", name)
+		}
+		if seen[name] {
+			return fmt.Errorf("state %q is listed twice", name)
+		}
+		seen[name] = true
+	}
+	for _, name := range requiredStates {
+		if !seen[name] {
+			return fmt.Errorf("the state list must include %q", name)
+		}
+	}
+	return nil
+}
+
+// AllowStates makes the state CHECK constraint allow the configured states.
+// As with relationship types, states contacts are still in stay allowed
+// when the config drops them, and are returned so the caller can point
+// them out.
+func (db *DB) AllowStates(states []string) ([]string, error) {
+	if err := ValidateStates(states); err != nil {
+		return nil, err
+	}
+	return db.allowValues("state", states)
+}
@@ -0,0 +1,142 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// ContactStats counts the contacts not in the trash
+type ContactStats struct {
+	Total    int            // Unarchived contacts
+	Archived int            // Archived contacts
+	Waiting  int            // Unarchived contacts who owe a reply
+	ByType   map[string]int // Unarchived contacts by relationship type
+	ByState  map[string]int // Unarchived contacts by state; no state counts as "ok"
+	Overdue  map[string]int // Overdue unarchived contacts by relationship type, with every type present
+}
+
+// MonthCount is how many interactions were logged in a month, and the time
+// recorded for them
+type MonthCount struct {
+	Month        string // YYYY-MM
+	Interactions int
+	Minutes      int
+}
+
+// ContactStats counts contacts by relationship type and state, and how many
+// are overdue or waiting on a reply. Overdue depends on the configured
+// cadences, so only the columns it needs are read for each contact; the
+// rest is counted by the database.
+func (db *DB) ContactStats() (ContactStats, error) {
+	stats := ContactStats{
+		ByType:  make(map[string]int),
+		ByState: make(map[string]int),
+		Overdue: make(map[string]int),
+	}
+	err := db.pool().QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(waiting_since IS NOT NULL), 0)
+		FROM contacts
+		WHERE trashed_at IS NULL AND (archived = 0 OR archived IS NULL)
+	`).Scan(&stats.Total, &stats.Waiting)
+	if err == nil {
+		err = db.pool().QueryRow(`SELECT COUNT(*) FROM contacts WHERE trashed_at IS NULL AND archived = 1`).Scan(&stats.Archived)
+	}
+	if err != nil {
+		return stats, fmt.Errorf("counting contacts: %w", err)
+	}
+
+	for _, count := range []struct {
+		column string
+		into   map[string]int
+	}{
+		{"relationship_type", stats.ByType},
+		{"COALESCE(NULLIF(state, ''), 'ok')", stats.ByState},
+	} {
+		rows, err := db.pool().Query(`
+			SELECT ` + count.column + `, COUNT(*)
+			FROM contacts
+			WHERE trashed_at IS NULL AND (archived = 0 OR archived IS NULL)
+			GROUP BY 1
+		`)
+		if err != nil {
+			return stats, fmt.Errorf("counting contacts: %w", err)
+		}
+		for rows.Next() {
+			var key string
+			var n int
+			if err := rows.Scan(&key, &n); err != nil {
+				rows.Close()
+				return stats, fmt.Errorf("scanning contact count: %w", err)
+			}
+			count.into[key] = n
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return stats, err
+		}
+	}
+
+	rows, err := db.pool().Query(`
+		SELECT relationship_type, contact_style, custom_frequency_days, reminders_muted,
+			snooze_until, contacted_at, last_bump_date
+		FROM contacts
+		WHERE trashed_at IS NULL AND (archived = 0 OR archived IS NULL)
+	`)
+	if err != nil {
+		return stats, fmt.Errorf("counting overdue contacts: %w", err)
+	}
+	defer rows.Close()
+	for t := range stats.ByType {
+		stats.Overdue[t] = 0
+	}
+	for rows.Next() {
+		var c Contact
+		if err := rows.Scan(&c.RelationshipType, &c.ContactStyle, &c.CustomFrequencyDays, &c.RemindersMuted,
+			&c.SnoozeUntil, &c.ContactedAt, &c.LastBumpDate); err != nil {
+			return stats, fmt.Errorf("scanning contact: %w", err)
+		}
+		if c.IsOverdue() {
+			stats.Overdue[c.RelationshipType]++
+		}
+	}
+	return stats, rows.Err()
+}
+
+// InteractionsPerMonth counts the interactions logged in each of the last
+// months, counting the current one, oldest first. Months without any are
+// included with zero counts.
+func (db *DB) InteractionsPerMonth(months int) ([]MonthCount, error) {
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local).AddDate(0, 1-months, 0)
+
+	counts := make([]MonthCount, months)
+	index := make(map[string]int, months)
+	for i := range counts {
+		counts[i].Month = start.AddDate(0, i, 0).Format("2006-01")
+		index[counts[i].Month] = i
+	}
+
+	rows, err := db.pool().Query(`
+		SELECT strftime('%Y-%m', interaction_date, 'localtime') AS month,
+			COUNT(*), COALESCE(SUM(duration_minutes), 0)
+		FROM contact_interactions
+		WHERE interaction_date >= ?
+		GROUP BY month
+	`, start.UTC().Format(timestampLayout))
+	if err != nil {
+		return nil, fmt.Errorf("counting interactions: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var month string
+		var c MonthCount
+		if err := rows.Scan(&month, &c.Interactions, &c.Minutes); err != nil {
+			return nil, fmt.Errorf("scanning interaction count: %w", err)
+		}
+		if i, ok := index[month]; ok {
+			c.Month = month
+			counts[i] = c
+		}
+	}
+	return counts, rows.Err()
+}
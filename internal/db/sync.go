@@ -0,0 +1,97 @@
+package db
+
+import (
+	"fmt"
+)
+
+// SourceCardDAV is the source of contacts linked to a card on a CardDAV
+// server; their external_id is the card's address
+const SourceCardDAV = "carddav"
+
+// SyncState is what a contact looked like when it was last synced with a
+// server card, used to tell local edits from remote ones
+type SyncState struct {
+	ContactID  int
+	ExternalID string // Address of the card on the server
+	ETag       string // Version of the card when last synced
+	Card       string // The contact's fields when last synced, as a vCard
+}
+
+// SyncStates returns the sync state of every contact linked to a server card,
+// keyed by external ID
+func (db *DB) SyncStates(source string) (map[string]SyncState, error) {
+	rows, err := db.pool().Query(`
+		SELECT id, external_id, COALESCE(external_etag, ''), COALESCE(synced_card, '')
+		FROM contacts
+		WHERE source = ? AND external_id IS NOT NULL
+	`, source)
+	if err != nil {
+		return nil, fmt.Errorf("querying sync states: %w", err)
+	}
+	defer rows.Close()
+
+	states := make(map[string]SyncState)
+	for rows.Next() {
+		var s SyncState
+		if err := rows.Scan(&s.ContactID, &s.ExternalID, &s.ETag, &s.Card); err != nil {
+			return nil, fmt.Errorf("scanning sync state: %w", err)
+		}
+		states[s.ExternalID] = s
+	}
+	return states, rows.Err()
+}
+
+// SetSyncState links a contact to a server card and records the card's
+// version and the contact's fields as of this sync
+func (db *DB) SetSyncState(source string, s SyncState) error {
+	_, err := db.pool().Exec(`
+		UPDATE contacts
+		SET source = ?, external_id = ?, external_etag = ?, synced_card = ?, synced_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, source, s.ExternalID, NewNullString(s.ETag), s.Card, s.ContactID)
+	if err != nil {
+		return fmt.Errorf("updating sync state: %w", err)
+	}
+	return nil
+}
+
+// ClearSyncState unlinks a contact from its server card
+func (db *DB) ClearSyncState(contactID int) error {
+	_, err := db.pool().Exec(`
+		UPDATE contacts
+		SET source = 'manual', external_id = NULL, external_etag = NULL, synced_card = NULL, synced_at = NULL
+		WHERE id = ?
+	`, contactID)
+	if err != nil {
+		return fmt.Errorf("clearing sync state: %w", err)
+	}
+	return nil
+}
+
+// SyncTombstones returns the external IDs of synced contacts deleted since
+// the last sync
+func (db *DB) SyncTombstones() ([]string, error) {
+	rows, err := db.pool().Query(`SELECT external_id FROM sync_tombstones ORDER BY deleted_at`)
+	if err != nil {
+		return nil, fmt.Errorf("querying sync tombstones: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning sync tombstone: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ClearSyncTombstone forgets a deleted contact once the server has caught up
+func (db *DB) ClearSyncTombstone(externalID string) error {
+	if _, err := db.pool().Exec(`DELETE FROM sync_tombstones WHERE external_id = ?`, externalID); err != nil {
+		return fmt.Errorf("clearing sync tombstone: %w", err)
+	}
+	return nil
+}
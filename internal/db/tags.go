@@ -0,0 +1,122 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Tag is a free-form tag and how many contacts carry it
+type Tag struct {
+	Name  string
+	Count int
+}
+
+// ParseTags reads tags typed as "#mentor #neighbor", "mentor, neighbor" or
+// any mix. The # is optional; duplicates are dropped ignoring case.
+func ParseTags(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ','
+	})
+	var tags []string
+	seen := make(map[string]bool)
+	for _, f := range fields {
+		tag := strings.TrimLeft(f, "#")
+		if tag == "" || seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// FormatTags writes tags as they are typed, e.g. "#mentor #neighbor"
+func FormatTags(tags []string) string {
+	var parts []string
+	for _, t := range tags {
+		parts = append(parts, "#"+t)
+	}
+	return strings.Join(parts, " ")
+}
+
+// HasTag reports whether a contact carries a tag, ignoring case
+func (c Contact) HasTag(tag string) bool {
+	for _, t := range c.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitTags reads the space-separated tags selected with contactColumns
+func splitTags(s string) []string {
+	tags := strings.Fields(s)
+	sort.Slice(tags, func(i, j int) bool { return strings.ToLower(tags[i]) < strings.ToLower(tags[j]) })
+	return tags
+}
+
+// SetContactTags replaces a contact's tags. Tags no contact uses any more
+// are removed.
+func (db *DB) SetContactTags(contactID int, tags []string) error {
+	tx, err := db.pool().Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setContactTags(tx, contactID, tags); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM tags WHERE id NOT IN (SELECT tag_id FROM contact_tags)`); err != nil {
+		return fmt.Errorf("removing unused tags: %w", err)
+	}
+	return tx.Commit()
+}
+
+// setContactTags replaces a contact's tags, creating any that are new
+func setContactTags(conn execer, contactID int, tags []string) error {
+	if _, err := conn.Exec(`DELETE FROM contact_tags WHERE contact_id = ?`, contactID); err != nil {
+		return fmt.Errorf("clearing tags: %w", err)
+	}
+	for _, tag := range ParseTags(strings.Join(tags, " ")) {
+		if _, err := conn.Exec(`INSERT OR IGNORE INTO tags (name) VALUES (?)`, tag); err != nil {
+			return fmt.Errorf("adding tag %s: %w", tag, err)
+		}
+		_, err := conn.Exec(`
+			INSERT OR IGNORE INTO contact_tags (contact_id, tag_id)
+			SELECT ?, id FROM tags WHERE name = ?
+		`, contactID, tag)
+		if err != nil {
+			return fmt.Errorf("tagging contact with %s: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+// ListTags returns every tag in use with its number of contacts, by name
+func (db *DB) ListTags() ([]Tag, error) {
+	rows, err := db.pool().Query(`
+		SELECT t.name, COUNT(ct.contact_id)
+		FROM tags t
+		JOIN contact_tags ct ON ct.tag_id = t.id
+		GROUP BY t.id
+		ORDER BY t.name COLLATE NOCASE
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("querying tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []Tag
+	for rows.Next() {
+		var t Tag
+		if err := rows.Scan(&t.Name, &t.Count); err != nil {
+			return nil, fmt.Errorf("scanning tag: %w", err)
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
@@ -0,0 +1,66 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// DeleteContact moves a contact to the trash. It disappears from the list
+// but keeps its history until it is restored or purged.
+func (db *DB) DeleteContact(contactID int) error {
+	_, err := db.pool().Exec(`UPDATE contacts SET trashed_at = CURRENT_TIMESTAMP WHERE id = ?`, contactID)
+	if err != nil {
+		return fmt.Errorf("moving contact to trash: %w", err)
+	}
+	return nil
+}
+
+// RestoreContact takes a contact out of the trash
+func (db *DB) RestoreContact(contactID int) error {
+	_, err := db.pool().Exec(`UPDATE contacts SET trashed_at = NULL WHERE id = ?`, contactID)
+	if err != nil {
+		var label string
+		db.pool().QueryRow(`SELECT COALESCE(label, '') FROM contacts WHERE id = ?`, contactID).Scan(&label)
+		return fmt.Errorf("restoring contact: %w", db.labelError(err, contactID, label))
+	}
+	return nil
+}
+
+// ListTrashed returns the contacts in the trash, most recently deleted first
+func (db *DB) ListTrashed() ([]Contact, error) {
+	rows, err := db.pool().Query(`SELECT ` + contactColumns + ` FROM contacts WHERE trashed_at IS NOT NULL ORDER BY trashed_at DESC, name`)
+	if err != nil {
+		return nil, fmt.Errorf("querying trash: %w", err)
+	}
+	defer rows.Close()
+
+	var contacts []Contact
+	for rows.Next() {
+		c, err := scanContact(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning contact: %w", err)
+		}
+		contacts = append(contacts, c)
+	}
+	return contacts, rows.Err()
+}
+
+// PurgeTrash permanently deletes the contacts trashed before cutoff,
+// returning how many were removed
+func (db *DB) PurgeTrash(cutoff time.Time) (int, error) {
+	trashed, err := db.ListTrashed()
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, c := range trashed {
+		if !c.TrashedAt.Time.Before(cutoff) {
+			continue
+		}
+		if err := db.PurgeContact(c.ID); err != nil {
+			return purged, fmt.Errorf("purging %s: %w", c.Name, err)
+		}
+		purged++
+	}
+	return purged, nil
+}
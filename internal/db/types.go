@@ -0,0 +1,343 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// checkConstraint matches the CHECK constraint limiting a contacts column to
+// a list of values in the contacts table definition
+func checkConstraint(column string) *regexp.Regexp {
+	return regexp.MustCompile(`CHECK\s*\(\s*` + column + `\s+IN\s*\([^)]*\)\s*\)`)
+}
+
+// validTypeName matches the relationship type names that can be stored
+var validTypeName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ValidateRelationshipType checks that a relationship type name can be used
+func ValidateRelationshipType(name string) error {
+	if !validTypeName.MatchString(name) {
+		return fmt.Errorf("invalid relationship type %q (use lowercase letters, digits, - and _)", name)
+	}
+	return nil
+}
+
+// Days that may pass between contacts before a contact is overdue, by
+// relationship type, for contacts without a custom frequency
+var (
+	cadenceDays        = map[string]int{"close": 30, "family": 30, "network": 90}
+	defaultCadenceDays = 60
+)
+
+// SetCadences sets how many days may pass between contacts of each
+// relationship type before they are overdue; types not listed, or listed
+// with 0, use defaultDays
+func SetCadences(days map[string]int, defaultDays int) {
+	cadenceDays = make(map[string]int, len(days))
+	for name, n := range days {
+		if n > 0 {
+			cadenceDays[name] = n
+		}
+	}
+	if defaultDays > 0 {
+		defaultCadenceDays = defaultDays
+	}
+}
+
+// relationshipCadence returns the overdue threshold for a relationship type
+func relationshipCadence(name string) int {
+	if days, ok := cadenceDays[name]; ok {
+		return days
+	}
+	return defaultCadenceDays
+}
+
+// AllowRelationshipTypes makes the relationship type CHECK constraint allow
+// the configured types, so types added to the config can be used. Types
+// that contacts still have are kept allowed even when the config no longer
+// lists them, so no contact is left with a type the table rejects; they are
+// returned so the caller can point out the mismatch. The table is only
+// rebuilt when the allowed types change. An empty list leaves the
+// constraint alone.
+func (db *DB) AllowRelationshipTypes(types []string) ([]string, error) {
+	if len(types) == 0 {
+		return nil, nil
+	}
+	for _, name := range types {
+		if err := ValidateRelationshipType(name); err != nil {
+			return nil, err
+		}
+	}
+	db.defaultType = types[0]
+	for _, name := range types {
+		if name == "network" {
+			db.defaultType = name
+		}
+	}
+
+	return db.allowValues("relationship_type", types)
+}
+
+// allowValues makes the CHECK constraint on a contacts column allow the
+// configured values plus any values contacts still have, rebuilding the
+// table only when that changes. It returns the values in use that aren't
+// configured.
+func (db *DB) allowValues(column string, configured []string) ([]string, error) {
+	rows, err := db.pool().Query(`SELECT DISTINCT ` + column + ` FROM contacts WHERE ` + column + ` IS NOT NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s values: %w", column, err)
+	}
+	var unlisted []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning %s value: %w", column, err)
+		}
+		if !containsType(configured, value) {
+			unlisted = append(unlisted, value)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Strings(unlisted)
+	allowed := append(append([]string(nil), configured...), unlisted...)
+
+	var createSQL string
+	if err := db.pool().QueryRow(`SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'contacts'`).Scan(&createSQL); err != nil {
+		return nil, fmt.Errorf("reading contacts table definition: %w", err)
+	}
+	check := checkConstraint(column).FindString(createSQL)
+	if check == "" || sameTypes(checkTypes(check), allowed) {
+		return unlisted, nil
+	}
+	if _, err := db.BackupFile(); err != nil {
+		return nil, err
+	}
+
+	if err := db.rebuildContacts(column, allowed, "", ""); err != nil {
+		return nil, err
+	}
+	return unlisted, nil
+}
+
+// DefaultRelationshipType returns the type given to contacts imported or
+// synced without one: "network" when it is allowed, else the first
+// configured type
+func (db *DB) DefaultRelationshipType() string {
+	if db.defaultType == "" {
+		return "network"
+	}
+	return db.defaultType
+}
+
+// checkTypes returns the values a CHECK constraint allows
+func checkTypes(check string) []string {
+	var types []string
+	for _, m := range regexp.MustCompile(`'((?:[^']|'')*)'`).FindAllStringSubmatch(check, -1) {
+		types = append(types, strings.ReplaceAll(m[1], "''", "'"))
+	}
+	return types
+}
+
+// sameTypes reports whether two lists hold the same values, in any order
+func sameTypes(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, name := range a {
+		if !containsType(b, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsType reports whether a list of types or states includes name
+func containsType(types []string, name string) bool {
+	for _, t := range types {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// RelationshipTypeCounts returns how many contacts have each relationship type
+func (db *DB) RelationshipTypeCounts() (map[string]int, error) {
+	rows, err := db.pool().Query(`SELECT relationship_type, COUNT(*) FROM contacts GROUP BY relationship_type`)
+	if err != nil {
+		return nil, fmt.Errorf("counting relationship types: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var name string
+		var count int
+		if err := rows.Scan(&name, &count); err != nil {
+			return nil, fmt.Errorf("scanning relationship type: %w", err)
+		}
+		counts[name] = count
+	}
+	return counts, rows.Err()
+}
+
+// RenameRelationshipType moves every contact of type from to type to, and
+// replaces the table's CHECK constraint so it allows exactly the given
+// types. Merging is a rename onto a type that is already in use. It returns
+// the number of contacts moved.
+func (db *DB) RenameRelationshipType(from, to string, allowed []string) (int, error) {
+	for _, name := range append([]string{to}, allowed...) {
+		if err := ValidateRelationshipType(name); err != nil {
+			return 0, err
+		}
+	}
+
+	var moved int
+	if err := db.pool().QueryRow(`SELECT COUNT(*) FROM contacts WHERE relationship_type = ?`, from).Scan(&moved); err != nil {
+		return 0, fmt.Errorf("counting contacts: %w", err)
+	}
+	if _, err := db.BackupFile(); err != nil {
+		return 0, err
+	}
+
+	if err := db.rebuildContacts("relationship_type", allowed, from, to); err != nil {
+		return 0, err
+	}
+	return moved, nil
+}
+
+// rebuildContacts runs rebuildContactsTable in a transaction. Foreign keys
+// are turned off meanwhile, since dropping the old table would otherwise
+// delete every contact's interactions, emails and other rows with it.
+func (db *DB) rebuildContacts(column string, allowed []string, from, to string) error {
+	ctx := context.Background()
+	conn, err := db.pool().Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("connecting to database: %w", err)
+	}
+	defer conn.Close()
+	// The pragma is ignored inside a transaction, so it goes first
+	if _, err := conn.ExecContext(ctx, `PRAGMA foreign_keys = OFF`); err != nil {
+		return fmt.Errorf("disabling foreign keys: %w", err)
+	}
+	defer conn.ExecContext(ctx, `PRAGMA foreign_keys = ON`)
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := rebuildContactsTable(tx, column, allowed, from, to); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing %s change: %w", column, err)
+	}
+	return nil
+}
+
+// rebuildContactsTable recreates the contacts table with the CHECK
+// constraint on column allowing the given values, copying every row across
+// with value from changed to value to. SQLite cannot alter a constraint in
+// place, so the table is copied, dropped and renamed, and its indexes
+// recreated.
+func rebuildContactsTable(tx *sql.Tx, column string, allowed []string, from, to string) error {
+	var createSQL string
+	if err := tx.QueryRow(`SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'contacts'`).Scan(&createSQL); err != nil {
+		return fmt.Errorf("reading contacts table definition: %w", err)
+	}
+
+	quoted := make([]string, len(allowed))
+	for i, name := range allowed {
+		quoted[i] = "'" + strings.ReplaceAll(name, "'", "''") + "'"
+	}
+	constraint := checkConstraint(column)
+	check := "CHECK (" + column + " IN (" + strings.Join(quoted, ", ") + "))"
+	if !constraint.MatchString(createSQL) {
+		// No constraint to update; just move the contacts
+		_, err := tx.Exec(`UPDATE contacts SET `+column+` = ? WHERE `+column+` = ?`, to, from)
+		if err != nil {
+			return fmt.Errorf("updating contacts: %w", err)
+		}
+		return nil
+	}
+	createSQL = constraint.ReplaceAllLiteralString(createSQL, check)
+	createSQL = regexp.MustCompile(`(?i)^CREATE TABLE\s+("?contacts"?)`).ReplaceAllLiteralString(createSQL, "CREATE TABLE contacts_rebuild")
+
+	var indexes []string
+	rows, err := tx.Query(`SELECT sql FROM sqlite_master WHERE type IN ('index', 'trigger') AND tbl_name = 'contacts' AND sql IS NOT NULL`)
+	if err != nil {
+		return fmt.Errorf("reading contacts indexes: %w", err)
+	}
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning index: %w", err)
+		}
+		indexes = append(indexes, s)
+	}
+	rows.Close()
+
+	var columns []string
+	rows, err = tx.Query(`SELECT name FROM pragma_table_info('contacts') ORDER BY cid`)
+	if err != nil {
+		return fmt.Errorf("reading contacts columns: %w", err)
+	}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning column: %w", err)
+		}
+		columns = append(columns, name)
+	}
+	rows.Close()
+
+	selects := make([]string, len(columns))
+	for i, name := range columns {
+		selects[i] = name
+		if name == column {
+			selects[i] = "CASE WHEN " + column + " = ? THEN ? ELSE " + column + " END"
+		}
+	}
+
+	if _, err := tx.Exec(createSQL); err != nil {
+		return fmt.Errorf("creating new contacts table: %w", err)
+	}
+	copySQL := fmt.Sprintf("INSERT INTO contacts_rebuild (%s) SELECT %s FROM contacts", strings.Join(columns, ", "), strings.Join(selects, ", "))
+	if _, err := tx.Exec(copySQL, from, to); err != nil {
+		return fmt.Errorf("copying contacts: %w", err)
+	}
+	if _, err := tx.Exec(`DROP TABLE contacts`); err != nil {
+		return fmt.Errorf("dropping old contacts table: %w", err)
+	}
+	if _, err := tx.Exec(`ALTER TABLE contacts_rebuild RENAME TO contacts`); err != nil {
+		return fmt.Errorf("renaming new contacts table: %w", err)
+	}
+	for _, s := range indexes {
+		if _, err := tx.Exec(s); err != nil {
+			return fmt.Errorf("recreating index: %w", err)
+		}
+	}
+	return nil
+}
+
+// RelationshipTypeOf returns the relationship type of the contact with a
+// label, or "" if there is none
+func (db *DB) RelationshipTypeOf(label string) string {
+	c, err := db.GetContactByLabel(label)
+	if err != nil {
+		return ""
+	}
+	return c.RelationshipType
+}
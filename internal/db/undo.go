@@ -0,0 +1,105 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Snapshot holds the parts of a contact that state changes, deletes,
+// archives, bumps and marking contacted change, so they can be undone
+type Snapshot struct {
+	ContactID         int
+	State             sql.NullString
+	ContactedAt       sql.NullTime
+	LastBumpDate      sql.NullTime
+	BumpCount         int
+	Archived          bool
+	ArchivedAt        sql.NullTime
+	ArchiveReason     sql.NullString
+	TrashedAt         sql.NullTime
+	LastInteractionID int // Newest interaction logged for the contact
+}
+
+// TakeSnapshot records a contact's undoable fields before a change
+func (db *DB) TakeSnapshot(contactID int) (Snapshot, error) {
+	c, err := db.GetContact(contactID)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	s := Snapshot{
+		ContactID:     c.ID,
+		State:         c.State,
+		ContactedAt:   c.ContactedAt,
+		LastBumpDate:  c.LastBumpDate,
+		BumpCount:     c.BumpCount,
+		Archived:      c.Archived,
+		ArchivedAt:    c.ArchivedAt,
+		ArchiveReason: c.ArchiveReason,
+		TrashedAt:     c.TrashedAt,
+	}
+	s.LastInteractionID, err = db.lastInteractionID(contactID)
+	return s, err
+}
+
+// lastInteractionID returns the ID of the newest interaction logged for a
+// contact, or 0 if there are none
+func (db *DB) lastInteractionID(contactID int) (int, error) {
+	var id int
+	err := db.pool().QueryRow(`SELECT COALESCE(MAX(id), 0) FROM contact_interactions WHERE contact_id = ?`, contactID).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("querying interactions: %w", err)
+	}
+	return id, nil
+}
+
+// RestoreSnapshot puts a contact back the way it was when before was taken.
+// Interactions logged by the change being undone, those after before's and
+// up to after's newest, are deleted; any logged since are kept.
+func (db *DB) RestoreSnapshot(before, after Snapshot) error {
+	tx, err := db.pool().Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE contacts
+		SET state = ?,
+		    contacted_at = ?,
+		    last_bump_date = ?,
+		    bump_count = ?,
+		    archived = ?,
+		    archived_at = ?,
+		    archive_reason = ?,
+		    trashed_at = ?
+		WHERE id = ?
+	`, before.State, timestampValue(timePtr(before.ContactedAt)), timestampValue(timePtr(before.LastBumpDate)),
+		before.BumpCount, before.Archived, timestampValue(timePtr(before.ArchivedAt)), before.ArchiveReason,
+		timestampValue(timePtr(before.TrashedAt)), before.ContactID)
+	if err != nil {
+		return fmt.Errorf("restoring contact: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("the contact has been purged")
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM interaction_attachments
+		WHERE interaction_id IN (
+			SELECT id FROM contact_interactions
+			WHERE contact_id = ? AND id > ? AND id <= ?
+		)
+	`, before.ContactID, before.LastInteractionID, after.LastInteractionID)
+	if err != nil {
+		return fmt.Errorf("deleting logged attachments: %w", err)
+	}
+	_, err = tx.Exec(`
+		DELETE FROM contact_interactions
+		WHERE contact_id = ? AND id > ? AND id <= ?
+	`, before.ContactID, before.LastInteractionID, after.LastInteractionID)
+	if err != nil {
+		return fmt.Errorf("deleting logged interactions: %w", err)
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,263 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// ContactValue is one entry of a contact's email addresses, phone numbers or
+// social media handles. Entries are kept in order; the first email address
+// and phone number are the primary ones, which are also kept in the
+// matching column of contacts.
+type ContactValue struct {
+	ID        int
+	ContactID int
+	Value     string
+	Type      string // e.g. "work"; see EmailTypes, PhoneTypes and HandlePlatforms
+	Position  int
+}
+
+// execer is satisfied by *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// valueList describes a table of ordered, typed contact values, whose first
+// entry may be copied to a column of contacts
+type valueList struct {
+	table    string // e.g. "contact_emails"
+	column   string // Value column, in the table and (with primary) contacts
+	noun     string // For messages, e.g. "email address"
+	types    []string
+	validate func(value string) bool
+	primary  bool // The first entry is copied to the column of contacts
+	phone    bool // Entries are phone numbers, compared by their digits
+}
+
+// list returns a contact's entries, primary first
+func (l valueList) list(conn *sql.DB, contactID int) ([]ContactValue, error) {
+	rows, err := conn.Query(fmt.Sprintf(`
+		SELECT id, contact_id, %s, type, position
+		FROM %s
+		WHERE contact_id = ?
+		ORDER BY position, id
+	`, l.column, l.table), contactID)
+	if err != nil {
+		return nil, fmt.Errorf("querying %ss: %w", l.column, err)
+	}
+	defer rows.Close()
+
+	var values []ContactValue
+	for rows.Next() {
+		var v ContactValue
+		if err := rows.Scan(&v.ID, &v.ContactID, &v.Value, &v.Type, &v.Position); err != nil {
+			return nil, fmt.Errorf("scanning %s: %w", l.column, err)
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
+
+// add appends an entry to a contact's list
+func (l valueList) add(conn *sql.DB, contactID int, value, valueType string) error {
+	value, valueType, err := l.clean(value, valueType)
+	if err != nil {
+		return err
+	}
+	if err := l.insert(conn, contactID, value, valueType); err != nil {
+		return err
+	}
+	return l.syncPrimary(conn, contactID)
+}
+
+// update changes an entry and its type
+func (l valueList) update(conn *sql.DB, id int, value, valueType string) error {
+	value, valueType, err := l.clean(value, valueType)
+	if err != nil {
+		return err
+	}
+	contactID, err := l.contactOf(conn, id)
+	if err != nil {
+		return err
+	}
+	if _, err := conn.Exec(fmt.Sprintf(`UPDATE %s SET %s = ?, type = ? WHERE id = ?`, l.table, l.column), value, valueType, id); err != nil {
+		return fmt.Errorf("updating %s: %w", l.column, err)
+	}
+	return l.syncPrimary(conn, contactID)
+}
+
+// remove deletes an entry; the next one becomes primary if the primary
+// entry is removed
+func (l valueList) remove(conn *sql.DB, id int) error {
+	contactID, err := l.contactOf(conn, id)
+	if err != nil {
+		return err
+	}
+	if _, err := conn.Exec(fmt.Sprintf(`DELETE FROM %s WHERE id = ?`, l.table), id); err != nil {
+		return fmt.Errorf("deleting %s: %w", l.column, err)
+	}
+	return l.syncPrimary(conn, contactID)
+}
+
+// makePrimary moves an entry to the front of its contact's list
+func (l valueList) makePrimary(conn execer, contactID, id int) error {
+	_, err := conn.Exec(fmt.Sprintf(`
+		UPDATE %[1]s
+		SET position = (SELECT MIN(position) - 1 FROM %[1]s WHERE contact_id = ?)
+		WHERE id = ?
+	`, l.table), contactID, id)
+	if err != nil {
+		return fmt.Errorf("updating %s: %w", l.column, err)
+	}
+	return l.syncPrimary(conn, contactID)
+}
+
+// setPrimary makes value a contact's primary entry after its column was
+// edited: an entry already on the list moves to the front, otherwise it
+// replaces the primary entry. An empty value removes the primary entry.
+func (l valueList) setPrimary(conn execer, contactID int, value string) error {
+	value = strings.TrimSpace(value)
+
+	var primaryID int
+	var primary string
+	err := conn.QueryRow(fmt.Sprintf(`
+		SELECT id, %s FROM %s WHERE contact_id = ? ORDER BY position, id LIMIT 1
+	`, l.column, l.table), contactID).Scan(&primaryID, &primary)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("querying primary %s: %w", l.column, err)
+	}
+
+	switch {
+	case strings.EqualFold(primary, value):
+		return nil
+	case value == "":
+		_, err = conn.Exec(fmt.Sprintf(`DELETE FROM %s WHERE id = ?`, l.table), primaryID)
+	default:
+		var existingID int
+		err = conn.QueryRow(fmt.Sprintf(`
+			SELECT id FROM %s WHERE contact_id = ? AND %s = ? COLLATE NOCASE
+		`, l.table, l.column), contactID, value).Scan(&existingID)
+		switch {
+		case err == nil:
+			return l.makePrimary(conn, contactID, existingID)
+		case err != sql.ErrNoRows:
+		case primaryID != 0:
+			_, err = conn.Exec(fmt.Sprintf(`UPDATE %s SET %s = ? WHERE id = ?`, l.table, l.column), value, primaryID)
+		default:
+			err = l.insert(conn, contactID, value, l.types[0])
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("updating primary %s: %w", l.column, err)
+	}
+	return l.syncPrimary(conn, contactID)
+}
+
+// addMissing appends the entries of values that aren't on a contact's list
+// yet, in order and keeping their types where they are known, and reports
+// whether any were added. Like setPrimary it takes values as they come from
+// a contact's fields or an import, without validating them.
+func (l valueList) addMissing(conn *sql.DB, contactID int, values []ContactValue) (bool, error) {
+	existing, err := l.list(conn, contactID)
+	if err != nil {
+		return false, err
+	}
+
+	added := false
+	for _, v := range values {
+		value := strings.TrimSpace(v.Value)
+		if value == "" || hasValue(existing, value, l.phone) {
+			continue
+		}
+		valueType := l.types[0]
+		for _, t := range l.types {
+			if strings.EqualFold(t, strings.TrimSpace(v.Type)) {
+				valueType = t
+			}
+		}
+		if err := l.insert(conn, contactID, value, valueType); err != nil {
+			return added, err
+		}
+		existing = append(existing, ContactValue{Value: value, Type: valueType})
+		added = true
+	}
+	if !added {
+		return false, nil
+	}
+	return true, l.syncPrimary(conn, contactID)
+}
+
+// insert adds an entry at the end of a contact's list
+func (l valueList) insert(conn execer, contactID int, value, valueType string) error {
+	_, err := conn.Exec(fmt.Sprintf(`
+		INSERT INTO %[1]s (contact_id, %[2]s, type, position)
+		VALUES (?, ?, ?, (SELECT COALESCE(MAX(position), -1) + 1 FROM %[1]s WHERE contact_id = ?))
+	`, l.table, l.column), contactID, value, valueType, contactID)
+	if err != nil {
+		return fmt.Errorf("adding %s: %w", l.column, err)
+	}
+	return nil
+}
+
+// syncPrimary copies a contact's primary entry to its column of contacts
+func (l valueList) syncPrimary(conn execer, contactID int) error {
+	if !l.primary {
+		return nil
+	}
+	_, err := conn.Exec(fmt.Sprintf(`
+		UPDATE contacts
+		SET %[2]s = (SELECT %[2]s FROM %[1]s WHERE contact_id = ? ORDER BY position, id LIMIT 1)
+		WHERE id = ?
+	`, l.table, l.column), contactID, contactID)
+	if err != nil {
+		return fmt.Errorf("updating primary %s: %w", l.column, err)
+	}
+	return nil
+}
+
+// contactOf returns the contact an entry belongs to
+func (l valueList) contactOf(conn execer, id int) (int, error) {
+	var contactID int
+	if err := conn.QueryRow(fmt.Sprintf(`SELECT contact_id FROM %s WHERE id = ?`, l.table), id).Scan(&contactID); err != nil {
+		return 0, fmt.Errorf("finding %s: %w", l.column, err)
+	}
+	return contactID, nil
+}
+
+// clean validates a value and its type, defaulting to the first type
+func (l valueList) clean(value, valueType string) (string, string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || !l.validate(value) {
+		return "", "", fmt.Errorf("invalid %s %q", l.noun, value)
+	}
+	valueType = strings.ToLower(strings.TrimSpace(valueType))
+	if valueType == "" {
+		valueType = l.types[0]
+	}
+	for _, t := range l.types {
+		if t == valueType {
+			return value, valueType, nil
+		}
+	}
+	return "", "", fmt.Errorf("unknown %s type %q (use %s)", l.column, valueType, strings.Join(l.types, ", "))
+}
+
+// LoadValues fills in the Emails and Phones of contacts, for writing them
+// out with every address and number
+func (db *DB) LoadValues(contacts []Contact) error {
+	for i := range contacts {
+		emails, err := db.ListEmails(contacts[i].ID)
+		if err != nil {
+			return err
+		}
+		phones, err := db.ListPhones(contacts[i].ID)
+		if err != nil {
+			return err
+		}
+		contacts[i].Emails = emails
+		contacts[i].Phones = phones
+	}
+	return nil
+}
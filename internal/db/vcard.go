@@ -0,0 +1,173 @@
+package db
+
+import (
+	"bufio"
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// vCard properties carrying fields that have no standard equivalent, so a
+// round trip through a phone or another CRM keeps them
+const (
+	VCardLabel = "X-CONTACTS-LABEL"
+	VCardType  = "X-CONTACTS-TYPE"
+	VCardState = "X-CONTACTS-STATE"
+	VCardNotes = "X-CONTACTS-NOTES"
+)
+
+// vcardLineLength is the longest a vCard content line may be, in octets,
+// before it is folded
+const vcardLineLength = 75
+
+// WriteVCards writes contacts as vCard 3.0 cards. Every email address in
+// Emails and phone number in Phones is written with its type, the primary
+// ones marked PREF; without them the primary address or number alone is
+// written. The postal address is written as a structured ADR, or without
+// one the location as the city of an address. Label, relationship type,
+// state and notes also go in X-CONTACTS- properties.
+func WriteVCards(w io.Writer, contacts []Contact) error {
+	var lines []string
+	for _, c := range contacts {
+		lines = append(lines, VCardLines(c)...)
+	}
+	return WriteVCardLines(w, lines)
+}
+
+// WriteVCardLines writes unfolded content lines, folding long ones
+func WriteVCardLines(w io.Writer, lines []string) error {
+	bw := bufio.NewWriter(w)
+	for _, line := range lines {
+		writeFolded(bw, line)
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("writing vCards: %w", err)
+	}
+	return nil
+}
+
+// VCardLines returns the unfolded content lines of a contact's card
+func VCardLines(c Contact) []string {
+	lines := []string{
+		"BEGIN:VCARD",
+		"VERSION:3.0",
+		fmt.Sprintf("UID:contacts-tui-%d", c.ID),
+		"FN:" + vcardEscape(c.Name),
+		"N:" + vcardName(c.Name),
+	}
+	add := func(prop string, value sql.NullString) {
+		if value.Valid && strings.TrimSpace(value.String) != "" {
+			lines = append(lines, prop+":"+vcardEscape(value.String))
+		}
+	}
+	emails := c.Emails
+	if len(emails) == 0 && c.Email.Valid && strings.TrimSpace(c.Email.String) != "" {
+		emails = []ContactValue{{Value: c.Email.String}}
+	}
+	for i, e := range emails {
+		prop := "EMAIL;TYPE=INTERNET"
+		switch e.Type {
+		case EmailWork:
+			prop += ",WORK"
+		case EmailPersonal:
+			prop += ",HOME"
+		}
+		if i == 0 && len(emails) > 1 {
+			prop += ",PREF"
+		}
+		lines = append(lines, prop+":"+vcardEscape(e.Value))
+	}
+	phones := c.Phones
+	if len(phones) == 0 && c.Phone.Valid && strings.TrimSpace(c.Phone.String) != "" {
+		phones = []ContactValue{{Value: c.Phone.String}}
+	}
+	for i, p := range phones {
+		prop := "TEL;TYPE=VOICE"
+		switch p.Type {
+		case PhoneMobile:
+			prop = "TEL;TYPE=CELL"
+		case PhoneWork:
+			prop = "TEL;TYPE=WORK"
+		case PhoneHome:
+			prop = "TEL;TYPE=HOME"
+		}
+		if i == 0 && len(phones) > 1 {
+			prop += ",PREF"
+		}
+		lines = append(lines, prop+":"+vcardEscape(p.Value))
+	}
+	add("ORG", c.Company)
+	add("BDAY", c.Birthday)
+	if a := c.Address.trimmed(); !a.IsEmpty() {
+		lines = append(lines, "ADR;TYPE=HOME:;;"+vcardEscape(a.Street)+";"+vcardEscape(a.City)+";"+
+			vcardEscape(a.Region)+";"+vcardEscape(a.PostalCode)+";"+vcardEscape(a.Country))
+	} else if c.Location.Valid && strings.TrimSpace(c.Location.String) != "" {
+		lines = append(lines, "ADR:;;;"+vcardEscape(c.Location.String)+";;;")
+	}
+	add("NOTE", c.Notes)
+	add(VCardLabel, c.Label)
+	add(VCardType, NewNullString(c.RelationshipType))
+	add(VCardState, c.State)
+	add(VCardNotes, c.Notes)
+	if !c.UpdatedAt.IsZero() {
+		lines = append(lines, "REV:"+c.UpdatedAt.UTC().Format("20060102T150405Z"))
+	}
+	return append(lines, "END:VCARD")
+}
+
+// ShareVCard returns a card with only what a contact would share in
+// person: name, email, phone and company, without notes, state or any of
+// this app's own properties. It is kept short to fit in a QR code.
+func ShareVCard(c Contact) string {
+	lines := []string{
+		"BEGIN:VCARD",
+		"VERSION:3.0",
+		"FN:" + vcardEscape(c.Name),
+		"N:" + vcardName(c.Name),
+	}
+	add := func(prop string, value sql.NullString) {
+		if value.Valid && strings.TrimSpace(value.String) != "" {
+			lines = append(lines, prop+":"+vcardEscape(value.String))
+		}
+	}
+	add("TEL", c.Phone)
+	add("EMAIL", c.Email)
+	add("ORG", c.Company)
+	lines = append(lines, "END:VCARD")
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+// vcardName builds a structured N value from a display name, taking the
+// last word as the family name
+func vcardName(name string) string {
+	words := strings.Fields(name)
+	if len(words) < 2 {
+		return vcardEscape(name) + ";;;;"
+	}
+	family := words[len(words)-1]
+	given := strings.Join(words[:len(words)-1], " ")
+	return vcardEscape(family) + ";" + vcardEscape(given) + ";;;"
+}
+
+// vcardEscape escapes a text value
+func vcardEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\r\n", `\n`, "\n", `\n`, ",", `\,`, ";", `\;`).Replace(s)
+}
+
+// writeFolded writes a content line, folding it onto continuation lines
+// so no line is longer than vcardLineLength octets
+func writeFolded(w *bufio.Writer, line string) {
+	limit := vcardLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = vcardLineLength - 1 // The leading space counts
+	}
+	w.WriteString(line + "\r\n")
+}
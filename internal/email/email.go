@@ -0,0 +1,145 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// Draft is a composed email ready to hand to the mail command
+type Draft struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// TemplateData is the contact data available to email templates
+type TemplateData struct {
+	Name      string
+	FirstName string
+	Email     string
+	Company   string
+	Label     string
+	State     string
+	DaysSince int // Days since last contact; -1 if never
+}
+
+// TemplateFor returns the template for a state, falling back to the
+// "default" template
+func TemplateFor(cfg config.EmailConfig, state string) (config.EmailTemplate, bool) {
+	if tmpl, ok := cfg.Templates[state]; ok {
+		return tmpl, true
+	}
+	tmpl, ok := cfg.Templates["default"]
+	return tmpl, ok
+}
+
+// Compose fills in a template for a contact
+func Compose(c db.Contact, tmpl config.EmailTemplate) (Draft, error) {
+	if !c.Email.Valid || c.Email.String == "" {
+		return Draft{}, fmt.Errorf("%s has no email address", c.Name)
+	}
+
+	firstName := c.Name
+	if fields := strings.Fields(c.Name); len(fields) > 0 {
+		firstName = fields[0]
+	}
+
+	data := TemplateData{
+		Name:      c.Name,
+		FirstName: firstName,
+		Email:     c.Email.String,
+		Company:   c.Company.String,
+		Label:     c.Label.String,
+		State:     c.State.String,
+		DaysSince: -1,
+	}
+	if last := c.LastInteraction(); last.Valid {
+		data.DaysSince = int(time.Since(last.Time).Hours() / 24)
+	}
+
+	subject, err := render("subject", tmpl.Subject, data)
+	if err != nil {
+		return Draft{}, err
+	}
+	body, err := render("body", tmpl.Body, data)
+	if err != nil {
+		return Draft{}, err
+	}
+	return Draft{To: c.Email.String, Subject: subject, Body: body}, nil
+}
+
+// render executes a single template string
+func render(name, text string, data TemplateData) (string, error) {
+	t, err := template.New(name).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing %s template: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("rendering %s template: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// MailtoURL returns the draft as a mailto: URL
+func (d Draft) MailtoURL() string {
+	query := url.Values{}
+	if d.Subject != "" {
+		query.Set("subject", d.Subject)
+	}
+	if d.Body != "" {
+		query.Set("body", d.Body)
+	}
+	if len(query) == 0 {
+		return "mailto:" + d.To
+	}
+	// mailto expects %20 rather than + for spaces
+	return "mailto:" + d.To + "?" + strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+// Command builds the mail command for a draft. A {draft} argument is replaced
+// with the path of a file holding the draft as a message with To and Subject
+// headers, which cleanup removes; otherwise the mailto: URL is appended.
+func Command(command string, d Draft) (cmd *exec.Cmd, cleanup func(), err error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, nil, fmt.Errorf("no mail command configured")
+	}
+
+	cleanup = func() {}
+	usesDraft := false
+	for _, arg := range args {
+		if strings.Contains(arg, "{draft}") {
+			usesDraft = true
+		}
+	}
+
+	if usesDraft {
+		f, err := os.CreateTemp("", "contacts-draft-*.eml")
+		if err != nil {
+			return nil, nil, fmt.Errorf("creating draft: %w", err)
+		}
+		fmt.Fprintf(f, "To: %s\nSubject: %s\n\n%s", d.To, d.Subject, d.Body)
+		if err := f.Close(); err != nil {
+			os.Remove(f.Name())
+			return nil, nil, fmt.Errorf("writing draft: %w", err)
+		}
+		for i, arg := range args {
+			args[i] = strings.ReplaceAll(arg, "{draft}", f.Name())
+		}
+		cleanup = func() { os.Remove(f.Name()) }
+	} else {
+		args = append(args, d.MailtoURL())
+	}
+
+	return exec.Command(args[0], args[1:]...), cleanup, nil
+}
@@ -0,0 +1,113 @@
+package enrich
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// Change is a company filled in or normalized for a contact
+type Change struct {
+	Contact db.Contact
+	Company string // The company to set
+	Filled  bool   // The contact had no company; otherwise it was normalized
+}
+
+// String describes the change in one line
+func (c Change) String() string {
+	if c.Filled {
+		return fmt.Sprintf("%s: company set to %q (from %s)", c.Contact.Name, c.Company, c.Contact.Email.String)
+	}
+	return fmt.Sprintf("%s: company %q normalized to %q", c.Contact.Name, c.Contact.Company.String, c.Company)
+}
+
+// Companies works out the company changes for contacts whose email domain is
+// in the domain map. Blank companies are filled in; companies that only
+// differ from the mapped name in case, spacing or punctuation, or that are
+// the bare domain name (e.g. "acme" for acme.com), are normalized to it.
+// Other companies are left alone.
+func Companies(contacts []db.Contact, domains map[string]string) []Change {
+	if len(domains) == 0 {
+		return nil
+	}
+	lookup := make(map[string]string, len(domains))
+	for domain, company := range domains {
+		lookup[strings.ToLower(strings.TrimSpace(domain))] = strings.TrimSpace(company)
+	}
+
+	var changes []Change
+	for _, c := range contacts {
+		domain, company, ok := companyFor(c.Email.String, lookup)
+		if !ok || company == "" {
+			continue
+		}
+		current := strings.TrimSpace(c.Company.String)
+		switch {
+		case current == "":
+			changes = append(changes, Change{Contact: c, Company: company, Filled: true})
+		case current == company:
+			// Already normalized
+		case key(current) == key(company) || key(current) == key(strings.SplitN(domain, ".", 2)[0]):
+			changes = append(changes, Change{Contact: c, Company: company})
+		}
+	}
+	return changes
+}
+
+// Apply stores company changes, returning how many were saved
+func Apply(database *db.DB, changes []Change) (int, error) {
+	for i, change := range changes {
+		if err := database.UpdateContactCompany(change.Contact.ID, change.Company); err != nil {
+			return i, fmt.Errorf("%s: %w", change.Contact.Name, err)
+		}
+	}
+	return len(changes), nil
+}
+
+// AfterImport runs the enrichment pass when the config asks for it after
+// imports, returning how many contacts were changed
+func AfterImport(database *db.DB, cfg config.EnrichConfig) (int, error) {
+	if !cfg.AfterImport || len(cfg.Domains) == 0 {
+		return 0, nil
+	}
+	contacts, err := database.ListContacts()
+	if err != nil {
+		return 0, fmt.Errorf("loading contacts: %w", err)
+	}
+	return Apply(database, Companies(contacts, cfg.Domains))
+}
+
+// companyFor looks up the company for an email address, trying the full
+// domain and then each parent domain, so mail.acme.com matches acme.com
+func companyFor(email string, lookup map[string]string) (domain, company string, ok bool) {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return "", "", false
+	}
+	domain = strings.ToLower(strings.TrimSpace(email[at+1:]))
+	for d := domain; d != ""; {
+		if company, ok := lookup[d]; ok {
+			return d, company, true
+		}
+		dot := strings.Index(d, ".")
+		if dot < 0 {
+			break
+		}
+		d = d[dot+1:]
+	}
+	return domain, "", false
+}
+
+// key reduces a company name to lowercase letters and digits for comparison
+func key(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
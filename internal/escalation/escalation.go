@@ -0,0 +1,214 @@
+package escalation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/notify"
+	"github.com/pdxmph/contacts-tui/internal/tasks"
+)
+
+// Escalation steps, in the order they are reached as a contact grows more overdue
+const (
+	LevelNone = iota
+	LevelPing
+	LevelTask
+	LevelNeglected
+)
+
+// Summary reports what an escalation run did
+type Summary struct {
+	Pinged       int
+	TasksCreated int
+	Neglected    int
+	Reset        int
+	Nudged       int  // Nudge tasks created for contacts who owe a reply
+	Deferred     int  // Contacts whose reminders were held back by quiet hours or the daily limit
+	Quiet        bool // The run happened during quiet hours
+	Errors       []string
+}
+
+// String describes the summary in one line
+func (s Summary) String() string {
+	str := fmt.Sprintf("%d pinged, %d tasks created, %d newly neglected", s.Pinged, s.TasksCreated, s.Neglected)
+	if s.Nudged > 0 {
+		str += fmt.Sprintf(", %d nudged", s.Nudged)
+	}
+	if s.Deferred > 0 {
+		reason := "daily limit"
+		if s.Quiet {
+			reason = "quiet hours"
+		}
+		str += fmt.Sprintf(", %d deferred (%s)", s.Deferred, reason)
+	}
+	return str
+}
+
+// RuleFor returns the escalation rule for a relationship type
+func RuleFor(cfg config.EscalationConfig, relationshipType string) config.EscalationRule {
+	if rule, ok := cfg.Types[relationshipType]; ok {
+		return rule
+	}
+	return cfg.Default
+}
+
+// Level returns the highest escalation step a contact has reached. Contacts
+// that have never been contacted have no cadence to measure against, so they
+// are not escalated.
+func Level(c db.Contact, rule config.EscalationRule) int {
+	ratio := c.OverdueRatio()
+	if ratio == 0 || math.IsInf(ratio, 1) {
+		return LevelNone
+	}
+
+	level := LevelNone
+	for step, at := range []float64{LevelPing: rule.PingAt, LevelTask: rule.TaskAt, LevelNeglected: rule.NeglectedAt} {
+		if at > 0 && ratio >= at {
+			level = step
+		}
+	}
+	return level
+}
+
+// IsNeglected reports whether a contact is overdue enough for the neglected view
+func IsNeglected(c db.Contact, cfg config.EscalationConfig) bool {
+	rule := RuleFor(cfg, c.RelationshipType)
+	return rule.NeglectedAt > 0 && Level(c, rule) == LevelNeglected
+}
+
+// Run applies escalation steps to every overdue contact. Each step happens
+// once per overdue period: the level reached is stored on the contact and
+// reset when the contact is no longer overdue. Reminders the throttle holds
+// back are left for a later run, most overdue contacts first.
+func Run(database *db.DB, backend tasks.Backend, cfg config.EscalationConfig, throttle *notify.Throttle) (Summary, error) {
+	summary := Summary{Quiet: throttle.Quiet()}
+
+	contacts, err := database.ListContacts()
+	if err != nil {
+		return summary, fmt.Errorf("loading contacts: %w", err)
+	}
+	sort.SliceStable(contacts, func(i, j int) bool {
+		return contacts[i].OverdueRatio() > contacts[j].OverdueRatio()
+	})
+
+	for _, c := range contacts {
+		if c.Archived {
+			continue
+		}
+
+		level := Level(c, RuleFor(cfg, c.RelationshipType))
+		if level == c.EscalationLevel {
+			continue
+		}
+		if level < c.EscalationLevel {
+			// Contacted again (or the rules changed); start over
+			if err := database.SetEscalationLevel(c.ID, level); err != nil {
+				summary.Errors = append(summary.Errors, fmt.Sprintf("%s: %v", c.Name, err))
+			} else if level == LevelNone {
+				summary.Reset++
+			}
+			continue
+		}
+
+		reached := c.EscalationLevel
+		for step := reached + 1; step <= level; step++ {
+			if step != LevelNeglected && !throttle.Allow() {
+				summary.Deferred++
+				break
+			}
+			fired, err := applyStep(database, backend, c, step, &summary)
+			if err != nil {
+				summary.Errors = append(summary.Errors, fmt.Sprintf("%s: %v", c.Name, err))
+			} else if fired != "" {
+				if err := throttle.Record(c.ID, fired); err != nil {
+					summary.Errors = append(summary.Errors, fmt.Sprintf("%s: %v", c.Name, err))
+				}
+			}
+			reached = step
+		}
+		if reached == c.EscalationLevel {
+			continue
+		}
+		if err := database.SetEscalationLevel(c.ID, reached); err != nil {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("%s: %v", c.Name, err))
+		}
+	}
+
+	return summary, nil
+}
+
+// Nudge creates a "nudge" task for each contact that has owed a reply for
+// longer than the configured period. Each wait is nudged once; contacts
+// without a label or task backend are left waiting.
+func Nudge(database *db.DB, backend tasks.Backend, cfg config.WaitingConfig, throttle *notify.Throttle, summary *Summary) error {
+	if cfg.NudgeAfterDays <= 0 || backend == nil || !backend.IsEnabled() {
+		return nil
+	}
+
+	contacts, err := database.ListContacts()
+	if err != nil {
+		return fmt.Errorf("loading contacts: %w", err)
+	}
+	sort.SliceStable(contacts, func(i, j int) bool {
+		return contacts[i].WaitingDays() > contacts[j].WaitingDays()
+	})
+
+	for _, c := range contacts {
+		if c.Archived || c.WaitingNudged || c.WaitingDays() < cfg.NudgeAfterDays {
+			continue
+		}
+		if !c.Label.Valid || c.Label.String == "" {
+			continue
+		}
+		if !throttle.Allow() {
+			summary.Deferred++
+			continue
+		}
+		if err := backend.CreateContactTask(c.Name, "nudge", c.Label.String); err != nil {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("%s: creating nudge task: %v", c.Name, err))
+			continue
+		}
+		if err := database.SetWaitingNudged(c.ID); err != nil {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("%s: %v", c.Name, err))
+		}
+		if err := throttle.Record(c.ID, "nudge"); err != nil {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("%s: %v", c.Name, err))
+		}
+		summary.Nudged++
+	}
+	return nil
+}
+
+// applyStep performs a single escalation step for a contact, returning the
+// kind of reminder that fired, if any
+func applyStep(database *db.DB, backend tasks.Backend, c db.Contact, step int, summary *Summary) (string, error) {
+	switch step {
+	case LevelPing:
+		// Only contacts without an active state are moved to ping
+		if c.State.Valid && c.State.String != "ok" {
+			return "", nil
+		}
+		if err := database.UpdateContactState(c.ID, "ping"); err != nil {
+			return "", err
+		}
+		summary.Pinged++
+		return "ping", nil
+
+	case LevelTask:
+		if backend == nil || !backend.IsEnabled() || !c.Label.Valid || c.Label.String == "" {
+			return "", nil
+		}
+		if err := backend.CreateContactTask(c.Name, "ping", c.Label.String); err != nil {
+			return "", fmt.Errorf("creating task: %w", err)
+		}
+		summary.TasksCreated++
+		return "task", nil
+
+	case LevelNeglected:
+		summary.Neglected++
+	}
+	return "", nil
+}
@@ -0,0 +1,93 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// csvColumns maps accepted header names to contact fields
+var csvColumns = map[string]string{
+	"name":              "name",
+	"full name":         "name",
+	"email":             "email",
+	"phone":             "phone",
+	"company":           "company",
+	"organization":      "company",
+	"location":          "location",
+	"city":              "location",
+	"relationship_type": "relationship_type",
+	"type":              "relationship_type",
+	"state":             "state",
+	"notes":             "notes",
+	"label":             "label",
+}
+
+// ParseCSV reads contacts from a CSV file with a header row.
+// Unknown columns are ignored; a name column is required.
+func ParseCSV(r io.Reader) ([]db.Contact, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, h := range header {
+		key := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		if field, ok := csvColumns[key]; ok {
+			columns[field] = i
+		}
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, fmt.Errorf("missing name column")
+	}
+
+	var contacts []db.Contact
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading row: %w", err)
+		}
+
+		get := func(field string) string {
+			if i, ok := columns[field]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		label := get("label")
+		if label != "" && !strings.HasPrefix(label, "@") {
+			label = "@" + label
+		}
+
+		contacts = append(contacts, db.Contact{
+			Name:             get("name"),
+			Email:            db.NewNullString(get("email")),
+			Phone:            db.NewNullString(get("phone")),
+			Company:          db.NewNullString(get("company")),
+			Location:         db.NewNullString(get("location")),
+			RelationshipType: strings.ToLower(get("relationship_type")),
+			State:            db.NewNullString(strings.ToLower(get("state"))),
+			Notes:            db.NewNullString(get("notes")),
+			Label:            db.NewNullString(label),
+		})
+	}
+
+	return contacts, nil
+}
+
+// Register the CSV parser
+func init() {
+	Register("csv", ParseCSV)
+}
@@ -0,0 +1,210 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// Progress reports the state of an import run
+type Progress struct {
+	Total   int
+	Created int
+	Updated int
+	Skipped int
+	Errors  []string
+}
+
+// Processed returns how many records have been handled so far
+func (p Progress) Processed() int {
+	return p.Created + p.Updated + p.Skipped + len(p.Errors)
+}
+
+// Parser reads contacts from an import file
+type Parser func(r io.Reader) ([]db.Contact, error)
+
+var (
+	mu      sync.RWMutex
+	parsers = make(map[string]Parser)
+)
+
+// Register adds a parser for a file extension (without the dot)
+func Register(ext string, parser Parser) {
+	mu.Lock()
+	defer mu.Unlock()
+	parsers[strings.ToLower(ext)] = parser
+}
+
+// Formats returns the registered file extensions in alphabetical order
+func Formats() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	formats := make([]string, 0, len(parsers))
+	for ext := range parsers {
+		formats = append(formats, ext)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// ParseFile parses an import file, choosing the parser by extension
+func ParseFile(path string) ([]db.Contact, error) {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+
+	mu.RLock()
+	parser, ok := parsers[ext]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported import format %q", ext)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening import file: %w", err)
+	}
+	defer f.Close()
+
+	contacts, err := parser(f)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filepath.Base(path), err)
+	}
+	return contacts, nil
+}
+
+// Import writes contacts to the database, calling report after each record.
+// Contacts matching an existing label, email or name only fill in blank
+// fields and add email addresses they don't have yet, so an import never
+// overwrites data that was edited locally.
+func Import(database *db.DB, contacts []db.Contact, report func(Progress)) (Progress, error) {
+	idx, err := newContactIndex(database)
+	if err != nil {
+		return Progress{}, err
+	}
+
+	progress := Progress{Total: len(contacts)}
+	for n, c := range contacts {
+		idx.upsert(database, c, n, &progress)
+		if report != nil {
+			report(progress)
+		}
+	}
+
+	return progress, nil
+}
+
+// contactIndex finds existing contacts by label, email or name while importing
+type contactIndex struct {
+	existing []db.Contact
+	byLabel  map[string]int
+	byEmail  map[string]int
+	byName   map[string]int
+}
+
+// newContactIndex indexes the contacts already in the database
+func newContactIndex(database *db.DB) (*contactIndex, error) {
+	existing, err := database.ListContacts()
+	if err != nil {
+		return nil, fmt.Errorf("loading existing contacts: %w", err)
+	}
+
+	idx := &contactIndex{
+		byLabel: make(map[string]int),
+		byEmail: make(map[string]int),
+		byName:  make(map[string]int),
+	}
+	for _, c := range existing {
+		idx.add(c)
+	}
+	return idx, nil
+}
+
+// add indexes a contact
+func (idx *contactIndex) add(c db.Contact) {
+	idx.existing = append(idx.existing, c)
+	i := len(idx.existing) - 1
+	idx.byName[strings.ToLower(c.Name)] = i
+	if c.Label.Valid && c.Label.String != "" {
+		idx.byLabel[strings.ToLower(c.Label.String)] = i
+	}
+	if c.Email.Valid && c.Email.String != "" {
+		idx.byEmail[strings.ToLower(c.Email.String)] = i
+	}
+}
+
+// upsert adds a contact or fills in the blanks of the existing contact it
+// matches, counting the outcome in progress. It returns the contact's ID,
+// or 0 if the record failed.
+func (idx *contactIndex) upsert(database *db.DB, c db.Contact, n int, progress *Progress) int {
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Name == "" {
+		progress.Errors = append(progress.Errors, fmt.Sprintf("record %d: missing name", n+1))
+		return 0
+	}
+	if c.RelationshipType == "" {
+		c.RelationshipType = database.DefaultRelationshipType()
+	}
+	if !c.State.Valid {
+		c.State = db.NewNullString("ok")
+	}
+
+	i, found := -1, false
+	if c.Label.Valid {
+		i, found = idx.byLabel[strings.ToLower(c.Label.String)]
+	}
+	if !found && c.Email.Valid {
+		i, found = idx.byEmail[strings.ToLower(c.Email.String)]
+	}
+	if !found {
+		i, found = idx.byName[strings.ToLower(c.Name)]
+	}
+
+	if found {
+		merged, changed := db.FillBlanks(idx.existing[i], c)
+		added, err := database.AddValues(merged.ID, c)
+		if err != nil {
+			progress.Errors = append(progress.Errors, fmt.Sprintf("%s: %v", c.Name, err))
+			return 0
+		}
+		if !changed && !added {
+			progress.Skipped++
+		} else if !changed {
+			progress.Updated++
+		} else if err := database.UpdateContact(merged); err != nil {
+			progress.Errors = append(progress.Errors, fmt.Sprintf("%s: %v", c.Name, err))
+			return 0
+		} else if err := updateAddress(database, idx.existing[i], merged); err != nil {
+			progress.Errors = append(progress.Errors, fmt.Sprintf("%s: %v", c.Name, err))
+			return 0
+		} else {
+			idx.existing[i] = merged
+			progress.Updated++
+		}
+		return merged.ID
+	}
+
+	id, err := database.AddContact(c)
+	if err != nil {
+		progress.Errors = append(progress.Errors, fmt.Sprintf("%s: %v", c.Name, err))
+		return 0
+	}
+	c.ID = int(id)
+	idx.add(c)
+	progress.Created++
+	return c.ID
+}
+
+// updateAddress writes the postal address FillBlanks filled in, which
+// UpdateContact leaves alone
+func updateAddress(database *db.DB, existing, merged db.Contact) error {
+	if merged.Address == existing.Address {
+		return nil
+	}
+	return database.UpdateAddress(merged.ID, merged.Address)
+}
@@ -0,0 +1,196 @@
+package importer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// Interaction is a historical interaction to backfill
+type Interaction struct {
+	Label string    `json:"label"`
+	Date  time.Time `json:"-"`
+	Type  string    `json:"type"`
+	Notes string    `json:"notes"`
+}
+
+// interactionTimeLayouts are the formats accepted in interaction files for
+// a date with a time of day
+var interactionTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// interactionDayLayouts are the formats accepted for a date alone
+var interactionDayLayouts = []string{
+	"2006-01-02",
+	"01/02/2006",
+	"1/2/2006",
+}
+
+// parseInteractionDate parses a date in any of the accepted layouts. A date
+// alone is taken to be at noon, so it stays on the same day in UTC, which
+// interactions are stored and shown in.
+func parseInteractionDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range interactionTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	for _, layout := range interactionDayLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t.Add(12 * time.Hour), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q (use YYYY-MM-DD)", s)
+}
+
+// ParseInteractionsFile reads interactions from a CSV or JSON file
+func ParseInteractionsFile(path string) ([]Interaction, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening import file: %w", err)
+	}
+	defer f.Close()
+
+	var records []Interaction
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		records, err = parseInteractionsCSV(f)
+	case ".json":
+		records, err = parseInteractionsJSON(f)
+	default:
+		return nil, fmt.Errorf("unsupported interaction format %q (use .csv or .json)", strings.TrimPrefix(ext, "."))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filepath.Base(path), err)
+	}
+	return records, nil
+}
+
+// parseInteractionsCSV reads interactions from a CSV file with a header row
+// containing label, date, type and notes columns
+func parseInteractionsCSV(r io.Reader) ([]Interaction, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, h := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))] = i
+	}
+	for _, required := range []string{"label", "date"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing %s column", required)
+		}
+	}
+
+	var records []Interaction
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading row: %w", err)
+		}
+
+		get := func(field string) string {
+			if i, ok := columns[field]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+
+		date, err := parseInteractionDate(get("date"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		records = append(records, Interaction{
+			Label: get("label"),
+			Date:  date,
+			Type:  get("type"),
+			Notes: get("notes"),
+		})
+	}
+	return records, nil
+}
+
+// parseInteractionsJSON reads interactions from a JSON array of objects
+// with label, date, type and notes fields
+func parseInteractionsJSON(r io.Reader) ([]Interaction, error) {
+	var raw []struct {
+		Interaction
+		Date string `json:"date"`
+	}
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	records := make([]Interaction, 0, len(raw))
+	for i, item := range raw {
+		date, err := parseInteractionDate(item.Date)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", i+1, err)
+		}
+		item.Interaction.Date = date
+		records = append(records, item.Interaction)
+	}
+	return records, nil
+}
+
+// ImportInteractions adds historical interactions to their contacts, matched
+// by label, calling report after each record. Interactions already present
+// are skipped, so a file can safely be imported twice.
+func ImportInteractions(database *db.DB, records []Interaction, report func(Progress)) (Progress, error) {
+	contacts, err := database.ListContacts()
+	if err != nil {
+		return Progress{}, fmt.Errorf("loading contacts: %w", err)
+	}
+
+	byLabel := make(map[string]int)
+	for _, c := range contacts {
+		if c.Label.Valid && c.Label.String != "" {
+			byLabel[db.NormalizeLabel(c.Label.String)] = c.ID
+		}
+	}
+
+	progress := Progress{Total: len(records)}
+	for n, r := range records {
+		contactID, ok := byLabel[db.NormalizeLabel(r.Label)]
+		interactionType := strings.ToLower(strings.TrimSpace(r.Type))
+		if interactionType == "" {
+			interactionType = "manual"
+		}
+
+		if !ok {
+			progress.Errors = append(progress.Errors, fmt.Sprintf("record %d: no contact with label %q", n+1, r.Label))
+		} else if added, err := database.AddInteractionAt(contactID, r.Date, interactionType, r.Notes); err != nil {
+			progress.Errors = append(progress.Errors, fmt.Sprintf("record %d: %v", n+1, err))
+		} else if added {
+			progress.Created++
+		} else {
+			progress.Skipped++
+		}
+
+		if report != nil {
+			report(progress)
+		}
+	}
+
+	return progress, nil
+}
@@ -0,0 +1,261 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// Mapping describes how to read contacts and their interactions from an
+// arbitrary JSON export. Paths are dot-separated object keys and array
+// indexes, e.g. "emails.0.address"; several paths joined with " + " are
+// concatenated with spaces, e.g. "first_name + last_name".
+type Mapping struct {
+	Records      string            `toml:"records"` // Path to the array of contacts; "" for a top-level array
+	Fields       map[string]string `toml:"fields"`  // Contact field -> source path
+	Interactions struct {
+		Path  string `toml:"path"`  // Path to the array of interactions inside each contact
+		Date  string `toml:"date"`  // Date or Unix timestamp
+		Type  string `toml:"type"`  // Interaction type (default: manual)
+		Notes string `toml:"notes"` // Interaction notes
+	} `toml:"interactions"`
+}
+
+// mappedFields are the contact fields a mapping can fill
+var mappedFields = []string{"name", "email", "phone", "company", "location", "relationship_type", "state", "notes", "label"}
+
+// MappedRecord is a contact read through a mapping, with its interactions
+type MappedRecord struct {
+	Contact      db.Contact
+	Interactions []Interaction
+}
+
+// LoadMapping reads a TOML mapping file
+func LoadMapping(path string) (*Mapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading mapping: %w", err)
+	}
+
+	var m Mapping
+	meta, err := toml.Decode(string(data), &m)
+	if err != nil {
+		return nil, fmt.Errorf("parsing mapping: %w", err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf("mapping: unknown setting %q", undecoded[0].String())
+	}
+	for field := range m.Fields {
+		if !isMappedField(field) {
+			return nil, fmt.Errorf("mapping: unknown contact field %q (use %s)", field, strings.Join(mappedFields, ", "))
+		}
+	}
+	if m.Fields["name"] == "" {
+		return nil, fmt.Errorf("mapping: fields.name is required")
+	}
+	if m.Interactions.Path != "" && m.Interactions.Date == "" {
+		return nil, fmt.Errorf("mapping: interactions.date is required with interactions.path")
+	}
+	return &m, nil
+}
+
+// isMappedField reports whether field is a contact field a mapping can fill
+func isMappedField(field string) bool {
+	for _, f := range mappedFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseMappedJSON reads contacts and interactions from a JSON file using a mapping
+func ParseMappedJSON(path string, m *Mapping) ([]MappedRecord, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening import file: %w", err)
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing JSON: %w", err)
+	}
+
+	items, err := arrayAt(doc, m.Records)
+	if err != nil {
+		return nil, fmt.Errorf("records: %w", err)
+	}
+
+	records := make([]MappedRecord, 0, len(items))
+	for n, item := range items {
+		get := func(field string) string { return valueAt(item, m.Fields[field]) }
+
+		label := get("label")
+		if label != "" && !strings.HasPrefix(label, "@") {
+			label = "@" + label
+		}
+		record := MappedRecord{Contact: db.Contact{
+			Name:             get("name"),
+			Email:            db.NewNullString(get("email")),
+			Phone:            db.NewNullString(get("phone")),
+			Company:          db.NewNullString(get("company")),
+			Location:         db.NewNullString(get("location")),
+			RelationshipType: strings.ToLower(get("relationship_type")),
+			State:            db.NewNullString(strings.ToLower(get("state"))),
+			Notes:            db.NewNullString(get("notes")),
+			Label:            db.NewNullString(label),
+		}}
+
+		if m.Interactions.Path != "" {
+			logs, err := arrayAt(item, m.Interactions.Path)
+			if err != nil {
+				return nil, fmt.Errorf("record %d: interactions: %w", n+1, err)
+			}
+			for i, l := range logs {
+				date, err := parseMappedDate(l, m.Interactions.Date)
+				if err != nil {
+					return nil, fmt.Errorf("record %d, interaction %d: %w", n+1, i+1, err)
+				}
+				record.Interactions = append(record.Interactions, Interaction{
+					Date:  date,
+					Type:  valueAt(l, m.Interactions.Type),
+					Notes: valueAt(l, m.Interactions.Notes),
+				})
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// lookup follows a dot-separated path through decoded JSON
+func lookup(v interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return v, true
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			next, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// arrayAt returns the array at path; a missing or null value is an empty array
+func arrayAt(v interface{}, path string) ([]interface{}, error) {
+	found, ok := lookup(v, path)
+	if !ok || found == nil {
+		return nil, nil
+	}
+	items, ok := found.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%q is not an array", path)
+	}
+	return items, nil
+}
+
+// valueAt returns the text at a path, or the texts at several paths joined
+// with " + ", separated by spaces. Missing values are empty.
+func valueAt(v interface{}, path string) string {
+	if path == "" {
+		return ""
+	}
+	var parts []string
+	for _, p := range strings.Split(path, "+") {
+		found, ok := lookup(v, strings.TrimSpace(p))
+		if !ok {
+			continue
+		}
+		if text := strings.TrimSpace(scalarText(found)); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// scalarText formats a JSON scalar as text; objects and arrays are empty
+func scalarText(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(x)
+	default:
+		return ""
+	}
+}
+
+// parseMappedDate reads a date string or a Unix timestamp in seconds or
+// milliseconds
+func parseMappedDate(v interface{}, path string) (time.Time, error) {
+	found, ok := lookup(v, path)
+	if !ok || found == nil {
+		return time.Time{}, fmt.Errorf("missing date %q", path)
+	}
+	if ts, ok := found.(float64); ok {
+		if ts > 1e12 {
+			return time.UnixMilli(int64(ts)), nil
+		}
+		return time.Unix(int64(ts), 0), nil
+	}
+	return parseInteractionDate(scalarText(found))
+}
+
+// ImportMapped writes mapped contacts the same way Import does, then adds
+// their interactions the way ImportInteractions does, calling report after
+// each contact. It returns the progress for contacts and for interactions.
+func ImportMapped(database *db.DB, records []MappedRecord, report func(Progress)) (contacts, interactions Progress, err error) {
+	idx, err := newContactIndex(database)
+	if err != nil {
+		return contacts, interactions, err
+	}
+
+	contacts.Total = len(records)
+	for n, r := range records {
+		interactions.Total += len(r.Interactions)
+		contactID := idx.upsert(database, r.Contact, n, &contacts)
+		for _, l := range r.Interactions {
+			if contactID == 0 {
+				interactions.Skipped++
+				continue
+			}
+			interactionType := strings.ToLower(strings.TrimSpace(l.Type))
+			if interactionType == "" {
+				interactionType = "manual"
+			}
+			if added, err := database.AddInteractionAt(contactID, l.Date, interactionType, l.Notes); err != nil {
+				interactions.Errors = append(interactions.Errors, fmt.Sprintf("%s: %v", r.Contact.Name, err))
+			} else if added {
+				interactions.Created++
+			} else {
+				interactions.Skipped++
+			}
+		}
+
+		if report != nil {
+			report(contacts)
+		}
+	}
+
+	return contacts, interactions, nil
+}
@@ -0,0 +1,389 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"mime/quotedprintable"
+	"strings"
+
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// vcardProperty is one content line of a vCard, e.g.
+// EMAIL;TYPE=work,pref:sarah@example.com
+type vcardProperty struct {
+	Name   string
+	Params map[string][]string
+	Value  string
+}
+
+// pref reports whether a property is marked as preferred, in either the
+// vCard 3.0 (TYPE=pref) or 4.0 (PREF=1) style
+func (p vcardProperty) pref() bool {
+	if len(p.Params["PREF"]) > 0 {
+		return true
+	}
+	for _, t := range p.Params["TYPE"] {
+		if strings.EqualFold(t, "pref") {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseVCard reads contacts from a vCard file holding any number of cards.
+// FN (or N), EMAIL, TEL, ORG, ADR, BDAY and NOTE fill in the name, email,
+// phone, company, postal address and location, birthday and notes. Every email address and
+// phone number is kept with its type, the preferred one, or else the first,
+// as the primary one.
+// The X-CONTACTS- properties written by db.WriteVCards restore the label,
+// relationship type, state and notes.
+func ParseVCard(r io.Reader) ([]db.Contact, error) {
+	lines, err := UnfoldVCard(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var contacts []db.Contact
+	var card []vcardProperty
+	inCard := false
+	for n, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		prop, err := parseVCardLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+		switch {
+		case prop.Name == "BEGIN" && strings.EqualFold(prop.Value, "VCARD"):
+			inCard = true
+			card = nil
+		case prop.Name == "END" && strings.EqualFold(prop.Value, "VCARD"):
+			if !inCard {
+				return nil, fmt.Errorf("line %d: END:VCARD without BEGIN:VCARD", n+1)
+			}
+			contacts = append(contacts, vcardContact(card))
+			inCard = false
+		case inCard:
+			card = append(card, prop)
+		}
+	}
+	if inCard {
+		return nil, fmt.Errorf("card not closed with END:VCARD")
+	}
+	if len(contacts) == 0 {
+		return nil, fmt.Errorf("no vCards found")
+	}
+	return contacts, nil
+}
+
+// unfoldVCard splits a vCard file into logical lines, joining folded
+// continuation lines and vCard 2.1 quoted-printable soft line breaks
+func UnfoldVCard(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if len(lines) == 0 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		last := len(lines) - 1
+		switch {
+		case last >= 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")):
+			lines[last] += line[1:]
+		case last >= 0 && isQuotedPrintable(lines[last]) && strings.HasSuffix(lines[last], "="):
+			lines[last] = strings.TrimSuffix(lines[last], "=") + line
+		default:
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading vCard: %w", err)
+	}
+	return lines, nil
+}
+
+// isQuotedPrintable reports whether a raw content line is quoted-printable
+func isQuotedPrintable(line string) bool {
+	head, _, _ := strings.Cut(line, ":")
+	return strings.Contains(strings.ToUpper(head), "QUOTED-PRINTABLE")
+}
+
+// parseVCardLine splits a content line into its name, parameters and value
+func parseVCardLine(line string) (vcardProperty, error) {
+	head, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return vcardProperty{}, fmt.Errorf("missing ':' in %q", line)
+	}
+
+	parts := strings.Split(head, ";")
+	name := strings.ToUpper(parts[0])
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:] // Drop the group, as in item1.EMAIL
+	}
+
+	prop := vcardProperty{Name: name, Params: make(map[string][]string), Value: value}
+	for _, param := range parts[1:] {
+		key, val, ok := strings.Cut(param, "=")
+		if !ok {
+			// vCard 2.1 bare parameters such as ;WORK;PREF;QUOTED-PRINTABLE
+			key, val = "TYPE", param
+			if strings.EqualFold(param, "QUOTED-PRINTABLE") {
+				key = "ENCODING"
+			}
+		}
+		key = strings.ToUpper(key)
+		for _, v := range strings.Split(strings.Trim(val, `"`), ",") {
+			prop.Params[key] = append(prop.Params[key], v)
+		}
+	}
+
+	for _, enc := range prop.Params["ENCODING"] {
+		if strings.EqualFold(enc, "QUOTED-PRINTABLE") {
+			decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(prop.Value)))
+			if err != nil {
+				return vcardProperty{}, fmt.Errorf("decoding %s: %w", name, err)
+			}
+			prop.Value = string(decoded)
+		}
+	}
+	return prop, nil
+}
+
+// VCardProperty returns the name of a content line's property, without
+// its group, and its value as ParseVCard reads it: the first ORG component,
+// the location of an ADR, a TEL number without "tel:", and so on
+func VCardProperty(line string) (name, value string, err error) {
+	prop, err := parseVCardLine(line)
+	if err != nil {
+		return "", "", err
+	}
+	switch prop.Name {
+	case "N":
+		value = nameFromN(prop.Value)
+	case "TEL":
+		value = strings.TrimPrefix(vcardText(prop.Value), "tel:")
+	case "ORG":
+		value = vcardComponents(prop.Value)[0]
+	case "ADR":
+		value = locationFromADR(prop.Value)
+	default:
+		value = vcardText(prop.Value)
+	}
+	return prop.Name, strings.TrimSpace(value), nil
+}
+
+// vcardContact builds a contact from the properties of one card
+func vcardContact(card []vcardProperty) db.Contact {
+	var name, email, phone, company, location, notes string
+	var label, relType, state, xNotes, birthday string
+	var emails, phones []db.ContactValue
+	var emailPref, phonePref bool
+	var address db.Address
+	for _, p := range card {
+		switch p.Name {
+		case "FN":
+			name = vcardText(p.Value)
+		case "N":
+			if name == "" {
+				name = nameFromN(p.Value)
+			}
+		case "EMAIL":
+			e := db.ContactValue{Value: strings.TrimSpace(vcardText(p.Value)), Type: emailType(p)}
+			if e.Value == "" {
+				continue
+			}
+			emails = addVCardValue(emails, e, p.pref() && !emailPref)
+			emailPref = emailPref || p.pref()
+		case "TEL":
+			tel := db.ContactValue{Value: strings.TrimSpace(strings.TrimPrefix(vcardText(p.Value), "tel:")), Type: phoneType(p)}
+			if tel.Value == "" {
+				continue
+			}
+			phones = addVCardValue(phones, tel, p.pref() && !phonePref)
+			phonePref = phonePref || p.pref()
+		case "ORG":
+			if company == "" {
+				company = vcardComponents(p.Value)[0]
+			}
+		case "ADR":
+			if location == "" && address.IsEmpty() {
+				location = locationFromADR(p.Value)
+				address = addressFromADR(p.Value)
+			}
+		case "BDAY":
+			// Dates only; a time of birth is dropped
+			value, _, _ := strings.Cut(vcardText(p.Value), "T")
+			birthday, _ = db.ParseBirthday(value)
+		case "NOTE":
+			if notes != "" {
+				notes += "\n"
+			}
+			notes += vcardText(p.Value)
+		case db.VCardLabel:
+			label = strings.TrimSpace(vcardText(p.Value))
+		case db.VCardType:
+			relType = strings.ToLower(strings.TrimSpace(vcardText(p.Value)))
+		case db.VCardState:
+			state = strings.ToLower(strings.TrimSpace(vcardText(p.Value)))
+		case db.VCardNotes:
+			xNotes = vcardText(p.Value)
+		}
+	}
+	if xNotes != "" {
+		notes = xNotes
+	}
+	if len(emails) > 0 {
+		email = emails[0].Value
+	}
+	if len(phones) > 0 {
+		phone = phones[0].Value
+	}
+	if label != "" && !strings.HasPrefix(label, "@") {
+		label = "@" + label
+	}
+
+	return db.Contact{
+		Name:             strings.TrimSpace(name),
+		Email:            db.NewNullString(email),
+		Emails:           emails,
+		Phone:            db.NewNullString(phone),
+		Phones:           phones,
+		Company:          db.NewNullString(strings.TrimSpace(company)),
+		Location:         db.NewNullString(strings.TrimSpace(location)),
+		Address:          address,
+		RelationshipType: relType,
+		State:            db.NewNullString(state),
+		Notes:            db.NewNullString(strings.TrimSpace(notes)),
+		Label:            db.NewNullString(label),
+		Birthday:         db.NewNullString(birthday),
+	}
+}
+
+// emailType returns the type of an EMAIL property: work for TYPE=work,
+// otherwise personal
+func emailType(p vcardProperty) string {
+	for _, t := range p.Params["TYPE"] {
+		if strings.EqualFold(t, "work") {
+			return db.EmailWork
+		}
+	}
+	return db.EmailPersonal
+}
+
+// phoneType returns the label of a TEL property: work or home for
+// TYPE=work or TYPE=home, otherwise mobile
+func phoneType(p vcardProperty) string {
+	for _, t := range p.Params["TYPE"] {
+		switch strings.ToLower(t) {
+		case "work":
+			return db.PhoneWork
+		case "home":
+			return db.PhoneHome
+		}
+	}
+	return db.PhoneMobile
+}
+
+// addVCardValue adds an email address or phone number to those read from
+// a card, in front of them if it is the preferred one
+func addVCardValue(values []db.ContactValue, v db.ContactValue, pref bool) []db.ContactValue {
+	if pref {
+		return append([]db.ContactValue{v}, values...)
+	}
+	return append(values, v)
+}
+
+// nameFromN builds a display name from a structured N value
+// (family;given;additional;prefix;suffix)
+func nameFromN(value string) string {
+	c := vcardComponents(value)
+	for len(c) < 5 {
+		c = append(c, "")
+	}
+	var parts []string
+	for _, p := range []string{c[3], c[1], c[2], c[0], c[4]} {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// locationFromADR builds a location from a structured ADR value
+// (pobox;extended;street;locality;region;code;country): the city and
+// region, or the country when neither is given
+func locationFromADR(value string) string {
+	c := vcardComponents(value)
+	for len(c) < 7 {
+		c = append(c, "")
+	}
+	var parts []string
+	for _, p := range []string{c[3], c[4]} {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if len(parts) == 0 {
+		return strings.TrimSpace(c[6])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// addressFromADR builds a postal address from a structured ADR value, with
+// the extended address (an apartment or suite) after the street
+func addressFromADR(value string) db.Address {
+	c := vcardComponents(value)
+	for len(c) < 7 {
+		c = append(c, "")
+	}
+	for i := range c {
+		c[i] = strings.TrimSpace(c[i])
+	}
+	street := c[2]
+	if c[1] != "" {
+		if street != "" {
+			street += ", "
+		}
+		street += c[1]
+	}
+	return db.Address{Street: street, City: c[3], Region: c[4], PostalCode: c[5], Country: c[6]}
+}
+
+// vcardComponents splits a structured value on unescaped semicolons and
+// unescapes each component
+func vcardComponents(value string) []string {
+	var parts []string
+	var b strings.Builder
+	escaped := false
+	for _, r := range value {
+		switch {
+		case escaped:
+			b.WriteRune('\\')
+			b.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ';':
+			parts = append(parts, vcardText(b.String()))
+			b.Reset()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return append(parts, vcardText(b.String()))
+}
+
+// vcardText unescapes a text value
+func vcardText(value string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\:`, ":", `\\`, `\`).Replace(value)
+}
+
+// Register the vCard parser
+func init() {
+	Register("vcf", ParseVCard)
+	Register("vcard", ParseVCard)
+}
@@ -0,0 +1,244 @@
+package importer_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/importer"
+)
+
+// openDatabase opens an empty database in a temporary directory
+func openDatabase(t *testing.T) *db.DB {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	path := filepath.Join(dir, "contacts.db")
+	if err := db.Initialize(path); err != nil {
+		t.Fatalf("creating database: %v", err)
+	}
+	database, err := db.Open(path)
+	if err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+// importCards parses cards and imports them, returning the contacts read
+func importCards(t *testing.T, database *db.DB, cards string) []db.Contact {
+	t.Helper()
+	contacts, err := importer.ParseVCard(strings.NewReader(cards))
+	if err != nil {
+		t.Fatalf("parsing cards: %v", err)
+	}
+	progress, err := importer.Import(database, contacts, nil)
+	if err != nil {
+		t.Fatalf("importing cards: %v", err)
+	}
+	if len(progress.Errors) > 0 {
+		t.Fatalf("importing cards: %v", progress.Errors)
+	}
+	return contacts
+}
+
+// findContact returns the contact with a name
+func findContact(t *testing.T, database *db.DB, name string) db.Contact {
+	t.Helper()
+	contacts, err := database.ListContacts()
+	if err != nil {
+		t.Fatalf("listing contacts: %v", err)
+	}
+	for _, c := range contacts {
+		if c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("no contact named %s", name)
+	return db.Contact{}
+}
+
+// exportCards writes every contact in the database as vCards
+func exportCards(t *testing.T, database *db.DB) string {
+	t.Helper()
+	contacts, err := database.ListContacts()
+	if err != nil {
+		t.Fatalf("listing contacts: %v", err)
+	}
+	if err := database.LoadValues(contacts); err != nil {
+		t.Fatalf("loading values: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := db.WriteVCards(&buf, contacts); err != nil {
+		t.Fatalf("writing cards: %v", err)
+	}
+	return buf.String()
+}
+
+// assertValues fails unless values holds want, as "value type" pairs in order
+func assertValues(t *testing.T, what string, values []db.ContactValue, want ...string) {
+	t.Helper()
+	var got []string
+	for _, v := range values {
+		got = append(got, v.Value+" "+v.Type)
+	}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("%s: got %q, want %q", what, got, want)
+	}
+}
+
+func TestVCardEmailsRoundTrip(t *testing.T) {
+	database := openDatabase(t)
+	importCards(t, database, "BEGIN:VCARD\r\n"+
+		"VERSION:3.0\r\n"+
+		"FN:Jane Doe\r\n"+
+		"EMAIL;TYPE=work:jane@work.com\r\n"+
+		"EMAIL;TYPE=home:jane@home.com\r\n"+
+		"item1.EMAIL;TYPE=INTERNET,pref:jane@example.com\r\n"+
+		"END:VCARD\r\n")
+
+	contact := findContact(t, database, "Jane Doe")
+	if contact.Email.String != "jane@example.com" {
+		t.Errorf("primary email: got %q, want the preferred jane@example.com", contact.Email.String)
+	}
+	emails, err := database.ListEmails(contact.ID)
+	if err != nil {
+		t.Fatalf("listing emails: %v", err)
+	}
+	assertValues(t, "imported emails", emails,
+		"jane@example.com personal", "jane@work.com work", "jane@home.com personal")
+
+	exported := exportCards(t, database)
+	for _, line := range []string{
+		"EMAIL;TYPE=INTERNET,HOME,PREF:jane@example.com",
+		"EMAIL;TYPE=INTERNET,WORK:jane@work.com",
+		"EMAIL;TYPE=INTERNET,HOME:jane@home.com",
+	} {
+		if !strings.Contains(exported, line+"\r\n") {
+			t.Errorf("export is missing %s:\n%s", line, exported)
+		}
+	}
+
+	// Importing the export into a fresh database gives the same list
+	again := openDatabase(t)
+	importCards(t, again, exported)
+	contact = findContact(t, again, "Jane Doe")
+	emails, err = again.ListEmails(contact.ID)
+	if err != nil {
+		t.Fatalf("listing emails: %v", err)
+	}
+	assertValues(t, "re-imported emails", emails,
+		"jane@example.com personal", "jane@work.com work", "jane@home.com personal")
+}
+
+func TestVCardImportAddsMissingValues(t *testing.T) {
+	database := openDatabase(t)
+	importCards(t, database, "BEGIN:VCARD\r\nFN:Jane Doe\r\n"+
+		"EMAIL:jane@home.com\r\nTEL:503-555-0100\r\nEND:VCARD\r\n")
+	importCards(t, database, "BEGIN:VCARD\r\nFN:Jane Doe\r\n"+
+		"EMAIL;TYPE=work:jane@work.com\r\nEMAIL:JANE@home.com\r\n"+
+		"TEL;TYPE=work:(503) 555-0100\r\nTEL;TYPE=work:(555) 222-2222\r\nEND:VCARD\r\n")
+
+	contact := findContact(t, database, "Jane Doe")
+	emails, err := database.ListEmails(contact.ID)
+	if err != nil {
+		t.Fatalf("listing emails: %v", err)
+	}
+	assertValues(t, "emails", emails, "jane@home.com personal", "jane@work.com work")
+
+	phones, err := database.ListPhones(contact.ID)
+	if err != nil {
+		t.Fatalf("listing phones: %v", err)
+	}
+	assertValues(t, "phones", phones, "503-555-0100 mobile", "(555) 222-2222 work")
+}
+
+func TestVCardPhonesRoundTrip(t *testing.T) {
+	database := openDatabase(t)
+	importCards(t, database, "BEGIN:VCARD\r\n"+
+		"VERSION:3.0\r\n"+
+		"FN:Jane Doe\r\n"+
+		"TEL;TYPE=cell:555-1111\r\n"+
+		"TEL;TYPE=work,voice:555-2222\r\n"+
+		"TEL;TYPE=HOME;TYPE=PREF:555-3333\r\n"+
+		"END:VCARD\r\n")
+
+	contact := findContact(t, database, "Jane Doe")
+	if contact.Phone.String != "555-3333" {
+		t.Errorf("primary phone: got %q, want the preferred 555-3333", contact.Phone.String)
+	}
+	phones, err := database.ListPhones(contact.ID)
+	if err != nil {
+		t.Fatalf("listing phones: %v", err)
+	}
+	assertValues(t, "imported phones", phones, "555-3333 home", "555-1111 mobile", "555-2222 work")
+
+	exported := exportCards(t, database)
+	for _, line := range []string{
+		"TEL;TYPE=HOME,PREF:555-3333",
+		"TEL;TYPE=CELL:555-1111",
+		"TEL;TYPE=WORK:555-2222",
+	} {
+		if !strings.Contains(exported, line+"\r\n") {
+			t.Errorf("export is missing %s:\n%s", line, exported)
+		}
+	}
+
+	again := openDatabase(t)
+	importCards(t, again, exported)
+	phones, err = again.ListPhones(findContact(t, again, "Jane Doe").ID)
+	if err != nil {
+		t.Fatalf("listing phones: %v", err)
+	}
+	assertValues(t, "re-imported phones", phones, "555-3333 home", "555-1111 mobile", "555-2222 work")
+}
+
+func TestVCardAddressRoundTrip(t *testing.T) {
+	database := openDatabase(t)
+	importCards(t, database, "BEGIN:VCARD\r\n"+
+		"FN:Jane Doe\r\n"+
+		"ADR;TYPE=home:;Apt 4;12 Main St;Portland;OR;97201;USA\r\n"+
+		"ADR;TYPE=work:;;1 Office Park;Salem;OR;97301;USA\r\n"+
+		"END:VCARD\r\n"+
+		"BEGIN:VCARD\r\n"+
+		"FN:John Roe\r\n"+
+		"ADR:;;;;;;Canada\r\n"+
+		"END:VCARD\r\n")
+
+	want := db.Address{Street: "12 Main St, Apt 4", City: "Portland", Region: "OR", PostalCode: "97201", Country: "USA"}
+	jane := findContact(t, database, "Jane Doe")
+	if jane.Address != want {
+		t.Errorf("address: got %+v, want %+v", jane.Address, want)
+	}
+	if jane.Location.String != "Portland, OR" {
+		t.Errorf("location: got %q, want Portland, OR", jane.Location.String)
+	}
+	if john := findContact(t, database, "John Roe"); john.Location.String != "Canada" {
+		t.Errorf("location from a country alone: got %q, want Canada", john.Location.String)
+	}
+
+	exported := exportCards(t, database)
+	if line := "ADR;TYPE=HOME:;;12 Main St\\, Apt 4;Portland;OR;97201;USA\r\n"; !strings.Contains(exported, line) {
+		t.Errorf("export is missing %s:\n%s", line, exported)
+	}
+
+	again := openDatabase(t)
+	importCards(t, again, exported)
+	if got := findContact(t, again, "Jane Doe").Address; got != want {
+		t.Errorf("re-imported address: got %+v, want %+v", got, want)
+	}
+}
+
+func TestVCardImportFillsMissingAddress(t *testing.T) {
+	database := openDatabase(t)
+	importCards(t, database, "BEGIN:VCARD\r\nFN:Jane Doe\r\nEMAIL:jane@home.com\r\nEND:VCARD\r\n")
+	importCards(t, database, "BEGIN:VCARD\r\nFN:Jane Doe\r\nADR:;;12 Main St;Portland;OR;97201;\r\nEND:VCARD\r\n")
+	importCards(t, database, "BEGIN:VCARD\r\nFN:Jane Doe\r\nADR:;;9 Elm St;Salem;OR;97301;\r\nEND:VCARD\r\n")
+
+	want := db.Address{Street: "12 Main St", City: "Portland", Region: "OR", PostalCode: "97201"}
+	if got := findContact(t, database, "Jane Doe").Address; got != want {
+		t.Errorf("address: got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package messaging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// NormalizePhone strips the spaces and punctuation people type in phone
+// numbers, keeping digits and a leading +
+func NormalizePhone(phone string) string {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(phone) {
+		if (r >= '0' && r <= '9') || (r == '+' && i == 0) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Send sends a text message by running command with {phone} and {message}
+// arguments filled in. When the command has no {message} argument, the
+// message is written to its standard input instead.
+func Send(ctx context.Context, command, phone, message string) error {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return fmt.Errorf("no messaging command configured")
+	}
+	number := NormalizePhone(phone)
+	if number == "" {
+		return fmt.Errorf("invalid phone number %q", phone)
+	}
+
+	usesMessage := false
+	for i, arg := range args {
+		if strings.Contains(arg, "{message}") {
+			usesMessage = true
+		}
+		arg = strings.ReplaceAll(arg, "{phone}", number)
+		args[i] = strings.ReplaceAll(arg, "{message}", message)
+	}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	if !usesMessage {
+		cmd.Stdin = strings.NewReader(message)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %s", args[0], msg)
+		}
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	return nil
+}
+
+// CallCommand builds the command that starts a call to phone. A {phone}
+// argument is replaced with the number; otherwise a tel: URL is appended,
+// for commands like open or xdg-open.
+func CallCommand(command, phone string) (*exec.Cmd, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("no call command configured")
+	}
+	number := NormalizePhone(phone)
+	if number == "" {
+		return nil, fmt.Errorf("invalid phone number %q", phone)
+	}
+
+	usesPhone := false
+	for i, arg := range args {
+		if strings.Contains(arg, "{phone}") {
+			usesPhone = true
+		}
+		args[i] = strings.ReplaceAll(arg, "{phone}", number)
+	}
+	if !usesPhone {
+		args = append(args, "tel:"+number)
+	}
+	return exec.Command(args[0], args[1:]...), nil
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// ContentType is the Prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// metric is one metric family with its samples, keyed by label value
+type metric struct {
+	name    string
+	help    string
+	kind    string // "gauge" or "counter"
+	label   string // Label name; "" for a single unlabeled sample
+	samples map[string]int
+}
+
+// Write reads the current counts from the database and writes them in the
+// Prometheus text format
+func Write(w io.Writer, database *db.DB) error {
+	stats, err := database.ContactStats()
+	if err != nil {
+		return err
+	}
+	interactions, err := database.CountInteractionsByType()
+	if err != nil {
+		return err
+	}
+
+	states := make(map[string]int)
+	for state, n := range stats.ByState {
+		if state != "ok" {
+			states[state] = n
+		}
+	}
+
+	total := metric{name: "contacts_total", help: "Contacts that are not archived, by relationship type.", kind: "gauge", label: "type", samples: stats.ByType}
+	archived := metric{name: "contacts_archived", help: "Archived contacts.", kind: "gauge", samples: map[string]int{"": stats.Archived}}
+	overdue := metric{name: "contacts_overdue", help: "Contacts overdue for contact, by relationship type.", kind: "gauge", label: "type", samples: stats.Overdue}
+	state := metric{name: "contacts_state", help: "Contacts in a state other than ok, by state.", kind: "gauge", label: "state", samples: states}
+	waiting := metric{name: "contacts_waiting", help: "Contacts who owe a reply.", kind: "gauge", samples: map[string]int{"": stats.Waiting}}
+	logged := metric{name: "contacts_interactions_total", help: "Interactions logged, by interaction type.", kind: "counter", label: "type", samples: interactions}
+
+	for _, m := range []metric{total, archived, overdue, state, waiting, logged} {
+		if err := m.write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// write writes a metric family with its samples in sorted label order
+func (m metric) write(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# HELP %s %s\n", m.name, m.help)
+	fmt.Fprintf(&b, "# TYPE %s %s\n", m.name, m.kind)
+
+	keys := make([]string, 0, len(m.samples))
+	for k := range m.samples {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if m.label == "" {
+			fmt.Fprintf(&b, "%s %d\n", m.name, m.samples[k])
+		} else {
+			fmt.Fprintf(&b, "%s{%s=%q} %d\n", m.name, m.label, k, m.samples[k])
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,102 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// Throttle decides whether a reminder may fire, honoring quiet hours and the
+// daily limit. A nil Throttle allows everything.
+type Throttle struct {
+	database   *db.DB
+	quiet      bool
+	maxPerDay  int
+	firedToday int
+}
+
+// NewThrottle builds a throttle for the current time from the notification
+// settings, counting reminders already fired today
+func NewThrottle(database *db.DB, cfg config.NotificationConfig, now time.Time) (*Throttle, error) {
+	t := &Throttle{database: database, maxPerDay: cfg.MaxPerDay}
+
+	if cfg.QuietHours != "" {
+		start, end, err := ParseQuietHours(cfg.QuietHours)
+		if err != nil {
+			return nil, err
+		}
+		t.quiet = inWindow(now.Hour()*60+now.Minute(), start, end)
+	}
+
+	if t.maxPerDay > 0 {
+		midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		count, err := database.CountRemindersSince(midnight)
+		if err != nil {
+			return nil, err
+		}
+		t.firedToday = count
+	}
+
+	return t, nil
+}
+
+// ParseQuietHours parses a "HH:MM-HH:MM" window into minutes after midnight.
+// The window may wrap past midnight, e.g. "22:00-08:00".
+func ParseQuietHours(s string) (start, end int, err error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid quiet_hours %q (use HH:MM-HH:MM)", s)
+	}
+	if start, err = parseClock(from); err != nil {
+		return 0, 0, fmt.Errorf("invalid quiet_hours %q: %w", s, err)
+	}
+	if end, err = parseClock(to); err != nil {
+		return 0, 0, fmt.Errorf("invalid quiet_hours %q: %w", s, err)
+	}
+	return start, end, nil
+}
+
+// parseClock parses an HH:MM time of day into minutes after midnight
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("bad time %q", strings.TrimSpace(s))
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// inWindow reports whether minute falls in [start, end), wrapping past midnight
+func inWindow(minute, start, end int) bool {
+	if start <= end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+// Quiet reports whether it is currently quiet hours
+func (t *Throttle) Quiet() bool {
+	return t != nil && t.quiet
+}
+
+// Allow reports whether another reminder may fire now
+func (t *Throttle) Allow() bool {
+	if t == nil {
+		return true
+	}
+	if t.quiet {
+		return false
+	}
+	return t.maxPerDay <= 0 || t.firedToday < t.maxPerDay
+}
+
+// Record counts a reminder that fired for a contact against the daily limit
+func (t *Throttle) Record(contactID int, kind string) error {
+	if t == nil {
+		return nil
+	}
+	t.firedToday++
+	return t.database.RecordReminder(contactID, kind)
+}
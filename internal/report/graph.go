@@ -0,0 +1,194 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// Graph formats
+const (
+	FormatDOT     = "dot"
+	FormatGraphML = "graphml"
+)
+
+// Kinds of graph node
+const (
+	NodeContact  = "contact"
+	NodeCompany  = "company"
+	NodeLocation = "location"
+)
+
+// GraphNode is a contact, or a company or location shared by contacts
+type GraphNode struct {
+	ID    string
+	Kind  string
+	Label string
+	Type  string // Relationship type, for contacts
+}
+
+// GraphEdge links a contact to a company or location it shares
+type GraphEdge struct {
+	From string
+	To   string
+}
+
+// Graph is the contact network: contacts linked through the companies and
+// locations they have in common
+type Graph struct {
+	Nodes []GraphNode
+	Edges []GraphEdge
+}
+
+// BuildGraph links contacts through companies and, if asked, locations that
+// at least two of them share. Names are compared ignoring case and spacing;
+// the first spelling seen labels the node.
+func BuildGraph(contacts []db.Contact, locations bool) Graph {
+	var g Graph
+	for _, c := range contacts {
+		g.Nodes = append(g.Nodes, GraphNode{
+			ID:    fmt.Sprintf("c%d", c.ID),
+			Kind:  NodeContact,
+			Label: c.Name,
+			Type:  c.RelationshipType,
+		})
+	}
+
+	g.link(contacts, NodeCompany, "co", func(c db.Contact) string { return c.Company.String })
+	if locations {
+		g.link(contacts, NodeLocation, "loc", func(c db.Contact) string { return c.Location.String })
+	}
+	return g
+}
+
+// link adds a node for each value of field shared by two or more contacts,
+// with an edge to each of them
+func (g *Graph) link(contacts []db.Contact, kind, prefix string, field func(db.Contact) string) {
+	labels := make(map[string]string)
+	members := make(map[string][]int)
+	var keys []string
+	for _, c := range contacts {
+		value := strings.Join(strings.Fields(field(c)), " ")
+		if value == "" {
+			continue
+		}
+		key := strings.ToLower(value)
+		if _, ok := labels[key]; !ok {
+			labels[key] = value
+			keys = append(keys, key)
+		}
+		members[key] = append(members[key], c.ID)
+	}
+	sort.Strings(keys)
+
+	n := 0
+	for _, key := range keys {
+		if len(members[key]) < 2 {
+			continue
+		}
+		n++
+		id := fmt.Sprintf("%s%d", prefix, n)
+		g.Nodes = append(g.Nodes, GraphNode{ID: id, Kind: kind, Label: labels[key]})
+		for _, contactID := range members[key] {
+			g.Edges = append(g.Edges, GraphEdge{From: fmt.Sprintf("c%d", contactID), To: id})
+		}
+	}
+}
+
+// Encode renders the graph as Graphviz DOT or GraphML
+func (g Graph) Encode(format string) ([]byte, error) {
+	switch format {
+	case FormatDOT:
+		return g.dot(), nil
+	case FormatGraphML:
+		return g.graphML()
+	default:
+		return nil, fmt.Errorf("unknown graph format %q (use %s or %s)", format, FormatDOT, FormatGraphML)
+	}
+}
+
+// dot renders the graph for Graphviz, with contacts clustered by
+// relationship type
+func (g Graph) dot() []byte {
+	var b bytes.Buffer
+	b.WriteString("graph contacts {\n")
+	b.WriteString("  graph [overlap=false, splines=true];\n")
+	b.WriteString("  node [shape=ellipse];\n")
+
+	byType := make(map[string][]GraphNode)
+	var types []string
+	for _, n := range g.Nodes {
+		if n.Kind != NodeContact {
+			continue
+		}
+		if _, ok := byType[n.Type]; !ok {
+			types = append(types, n.Type)
+		}
+		byType[n.Type] = append(byType[n.Type], n)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		fmt.Fprintf(&b, "  subgraph %s {\n", dotQuote("cluster_"+t))
+		fmt.Fprintf(&b, "    label=%s;\n", dotQuote(t))
+		for _, n := range byType[t] {
+			fmt.Fprintf(&b, "    %s [label=%s];\n", dotQuote(n.ID), dotQuote(n.Label))
+		}
+		b.WriteString("  }\n")
+	}
+
+	for _, n := range g.Nodes {
+		switch n.Kind {
+		case NodeCompany:
+			fmt.Fprintf(&b, "  %s [label=%s, shape=box];\n", dotQuote(n.ID), dotQuote(n.Label))
+		case NodeLocation:
+			fmt.Fprintf(&b, "  %s [label=%s, shape=diamond];\n", dotQuote(n.ID), dotQuote(n.Label))
+		}
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -- %s;\n", dotQuote(e.From), dotQuote(e.To))
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+// dotQuote quotes a DOT identifier
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ").Replace(s) + `"`
+}
+
+// graphML renders the graph as GraphML with the node kind, label and
+// relationship type as attributes
+func (g Graph) graphML() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	b.WriteString(`  <key id="label" for="node" attr.name="label" attr.type="string"/>` + "\n")
+	b.WriteString(`  <key id="kind" for="node" attr.name="kind" attr.type="string"/>` + "\n")
+	b.WriteString(`  <key id="type" for="node" attr.name="relationship_type" attr.type="string"/>` + "\n")
+	b.WriteString(`  <graph id="contacts" edgedefault="undirected">` + "\n")
+
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "    <node id=%q>\n", n.ID)
+		for _, data := range [][2]string{{"label", n.Label}, {"kind", n.Kind}, {"type", n.Type}} {
+			if data[1] == "" {
+				continue
+			}
+			fmt.Fprintf(&b, "      <data key=%q>", data[0])
+			if err := xml.EscapeText(&b, []byte(data[1])); err != nil {
+				return nil, fmt.Errorf("encoding graph: %w", err)
+			}
+			b.WriteString("</data>\n")
+		}
+		b.WriteString("    </node>\n")
+	}
+	for i, e := range g.Edges {
+		fmt.Fprintf(&b, "    <edge id=\"e%d\" source=%q target=%q/>\n", i+1, e.From, e.To)
+	}
+
+	b.WriteString("  </graph>\n</graphml>\n")
+	return b.Bytes(), nil
+}
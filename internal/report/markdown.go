@@ -0,0 +1,63 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// ContactMarkdown formats a contact, its important dates and the given
+// interactions as a Markdown block for pasting into notes and documents
+func ContactMarkdown(c db.Contact, logs []db.Log, dates []db.ImportantDate) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## %s\n\n", c.Name)
+	writeContactFields(&b, c, dates)
+
+	if len(logs) > 0 {
+		b.WriteString("\n### Recent interactions\n\n")
+		for _, l := range logs {
+			fmt.Fprintf(&b, "- %s (%s)", l.InteractionDate.Format("2006-01-02"), l.InteractionType)
+			if l.Notes.Valid && l.Notes.String != "" {
+				// Keep multi-line notes inside the list item
+				b.WriteString(": " + strings.ReplaceAll(strings.TrimSpace(l.Notes.String), "\n", "\n  "))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
+
+// writeContactFields writes a contact's details, important dates and notes
+func writeContactFields(b *strings.Builder, c db.Contact, dates []db.ImportantDate) {
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(b, "- **%s:** %s\n", name, value)
+		}
+	}
+	field("Label", c.Label.String)
+	field("Company", c.Company.String)
+	field("Location", c.Location.String)
+	field("Relationship", c.RelationshipType)
+	field("Email", c.Email.String)
+	field("Phone", c.Phone.String)
+	if c.State.Valid && c.State.String != "ok" {
+		field("State", c.State.String)
+	}
+	if last := c.LastInteraction(); last.Valid {
+		days := int(time.Since(last.Time).Hours() / 24)
+		field("Last contact", fmt.Sprintf("%s (%d days ago)", last.Time.Format("2006-01-02"), days))
+	} else {
+		field("Last contact", "never")
+	}
+	for _, d := range dates {
+		field(d.Label, d.Date.Format("2006-01-02"))
+	}
+
+	if c.Notes.Valid && strings.TrimSpace(c.Notes.String) != "" {
+		fmt.Fprintf(b, "\n### Notes\n\n%s\n", strings.TrimSpace(c.Notes.String))
+	}
+}
@@ -0,0 +1,116 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// sheetWidth is the line width of text contact sheets, which fits a printed
+// page in a monospaced font
+const sheetWidth = 72
+
+// Sheet formats
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+)
+
+// Sheet renders a contact sheet for printing or sharing in the given format
+func Sheet(contacts []db.Contact, title, format string, generated time.Time) (string, error) {
+	switch format {
+	case FormatText:
+		return textSheet(contacts, title, generated), nil
+	case FormatMarkdown:
+		return markdownSheet(contacts, title, generated), nil
+	default:
+		return "", fmt.Errorf("unknown sheet format %q (use %s or %s)", format, FormatText, FormatMarkdown)
+	}
+}
+
+// sheetFields returns the labelled fields shown for a contact on a sheet
+func sheetFields(c db.Contact) [][2]string {
+	var fields [][2]string
+	add := func(name, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			fields = append(fields, [2]string{name, value})
+		}
+	}
+	add("Company", c.Company.String)
+	add("Location", c.Location.String)
+	add("Email", c.Email.String)
+	add("Phone", c.Phone.String)
+	add("Type", c.RelationshipType)
+	return fields
+}
+
+// textSheet renders a plain text sheet with entries grouped by initial
+func textSheet(contacts []db.Contact, title string, generated time.Time) string {
+	var b strings.Builder
+
+	b.WriteString(strings.ToUpper(title) + "\n")
+	fmt.Fprintf(&b, "%d contacts, %s\n", len(contacts), generated.Format("January 2, 2006"))
+	b.WriteString(strings.Repeat("=", sheetWidth) + "\n\n")
+
+	var initial string
+	for _, c := range contacts {
+		if first := strings.ToUpper(firstLetter(c.Name)); first != initial {
+			initial = first
+			fmt.Fprintf(&b, "%s\n%s\n", initial, strings.Repeat("-", sheetWidth))
+		}
+
+		name := c.Name
+		if c.Label.Valid && c.Label.String != "" {
+			label := c.Label.String
+			if pad := sheetWidth - len([]rune(name)) - len([]rune(label)); pad > 1 {
+				name += strings.Repeat(" ", pad) + label
+			} else {
+				name += " " + label
+			}
+		}
+		b.WriteString(name + "\n")
+		for _, f := range sheetFields(c) {
+			fmt.Fprintf(&b, "    %-8s %s\n", f[0]+":", f[1])
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// markdownSheet renders a Markdown sheet, ready for conversion to PDF with
+// a tool like pandoc
+func markdownSheet(contacts []db.Contact, title string, generated time.Time) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	fmt.Fprintf(&b, "_%d contacts, %s_\n\n", len(contacts), generated.Format("January 2, 2006"))
+	b.WriteString("| Name | Company | Email | Phone | Type |\n")
+	b.WriteString("|------|---------|-------|-------|------|\n")
+	for _, c := range contacts {
+		name := c.Name
+		if c.Label.Valid && c.Label.String != "" {
+			name += " (" + c.Label.String + ")"
+		}
+		cells := []string{name, c.Company.String, c.Email.String, c.Phone.String, c.RelationshipType}
+		for i, cell := range cells {
+			cells[i] = strings.ReplaceAll(strings.TrimSpace(cell), "|", `\|`)
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	return b.String()
+}
+
+// firstLetter returns the first character of s, or "#" when it is not a letter
+func firstLetter(s string) string {
+	for _, r := range strings.TrimSpace(s) {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+			return string(r)
+		}
+		return "#"
+	}
+	return "#"
+}
@@ -0,0 +1,165 @@
+package report
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// Statistics export formats
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// MonthStats aggregates the interactions in a month and the state of the
+// contact list at its end
+type MonthStats struct {
+	Month        string         `json:"month"` // YYYY-MM
+	Interactions int            `json:"interactions"`
+	ByType       map[string]int `json:"by_type"`
+	Minutes      int            `json:"minutes"`  // Time recorded on interactions
+	Contacts     int            `json:"contacts"` // Unarchived contacts at month end
+	Overdue      int            `json:"overdue"`  // Of those, overdue at month end
+}
+
+// Stats is the aggregate statistics export
+type Stats struct {
+	Generated time.Time    `json:"generated"`
+	Types     []string     `json:"types"` // Interaction types seen, for the CSV columns
+	Months    []MonthStats `json:"months"`
+}
+
+// BuildStats aggregates interactions per month and type over the last
+// months (counting the current one). The overdue trend is reconstructed from
+// the interaction history and each contact's current cadence, so it reflects
+// today's settings rather than those in force at the time, and is approximate
+// for months before the latest contact.
+func BuildStats(contacts []db.Contact, logs []db.Log, months int, now time.Time) Stats {
+	stats := Stats{Generated: now}
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, 1-months, 0)
+
+	index := make(map[string]int, months)
+	for i := 0; i < months; i++ {
+		month := start.AddDate(0, i, 0)
+		index[month.Format("2006-01")] = i
+		stats.Months = append(stats.Months, MonthStats{Month: month.Format("2006-01"), ByType: map[string]int{}})
+	}
+
+	types := map[string]bool{}
+	byContact := map[int][]time.Time{}
+	for _, l := range logs {
+		byContact[l.ContactID] = append(byContact[l.ContactID], l.InteractionDate)
+		i, ok := index[l.InteractionDate.In(now.Location()).Format("2006-01")]
+		if !ok {
+			continue
+		}
+		m := &stats.Months[i]
+		m.Interactions++
+		m.ByType[l.InteractionType]++
+		m.Minutes += int(l.DurationMinutes.Int64)
+		types[l.InteractionType] = true
+	}
+	for t := range types {
+		stats.Types = append(stats.Types, t)
+	}
+	sort.Strings(stats.Types)
+
+	for i := range stats.Months {
+		end := start.AddDate(0, i+1, 0)
+		if end.After(now) {
+			end = now
+		}
+		for _, c := range contacts {
+			if c.CreatedAt.After(end) || (c.Archived && c.ArchivedAt.Valid && !c.ArchivedAt.Time.After(end)) {
+				continue
+			}
+			stats.Months[i].Contacts++
+			if overdueAt(c, byContact[c.ID], end) {
+				stats.Months[i].Overdue++
+			}
+		}
+	}
+	return stats
+}
+
+// overdueAt reports whether a contact was overdue at a time, given the dates
+// of its interactions
+func overdueAt(c db.Contact, dates []time.Time, at time.Time) bool {
+	c.Archived = false
+	cadence := c.CadenceDays()
+	if cadence == 0 {
+		return false
+	}
+	// Marking a contact as contacted moves contacted_at up to the
+	// interaction, so anything logged after it is a note that does not reset
+	// the clock. Earlier notes can't be told apart and count as contact.
+	var last time.Time
+	for _, d := range dates {
+		if !c.ContactedAt.Valid || d.After(c.ContactedAt.Time) {
+			continue
+		}
+		if !d.After(at) && d.After(last) {
+			last = d
+		}
+	}
+	for _, t := range []sql.NullTime{c.ContactedAt, c.LastBumpDate} {
+		if t.Valid && !t.Time.After(at) && t.Time.After(last) {
+			last = t.Time
+		}
+	}
+	if last.IsZero() {
+		return true
+	}
+	return at.Sub(last).Hours()/24 > float64(cadence)
+}
+
+// Encode writes the statistics in the given format
+func (s Stats) Encode(format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("encoding statistics: %w", err)
+		}
+		return append(data, '\n'), nil
+	case FormatCSV:
+		return s.csv()
+	default:
+		return nil, fmt.Errorf("unknown statistics format %q (use %s or %s)", format, FormatCSV, FormatJSON)
+	}
+}
+
+// csv writes one row per month, with a column per interaction type
+func (s Stats) csv() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	header := []string{"month", "interactions"}
+	for _, t := range s.Types {
+		header = append(header, "type_"+t)
+	}
+	header = append(header, "minutes", "contacts", "overdue")
+	w.Write(header)
+
+	for _, m := range s.Months {
+		row := []string{m.Month, strconv.Itoa(m.Interactions)}
+		for _, t := range s.Types {
+			row = append(row, strconv.Itoa(m.ByType[t]))
+		}
+		row = append(row, strconv.Itoa(m.Minutes), strconv.Itoa(m.Contacts), strconv.Itoa(m.Overdue))
+		w.Write(row)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("writing statistics: %w", err)
+	}
+	return buf.Bytes(), nil
+}
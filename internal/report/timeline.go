@@ -0,0 +1,101 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// Timeline export formats
+const (
+	TimelineMarkdown = "md"
+	TimelineJSON     = "json"
+)
+
+// Timeline exports a contact's full interaction history, oldest first, for
+// sharing or for keeping before the contact is deleted. The JSON form is the
+// one deleted contacts are saved in.
+func Timeline(database *db.DB, c db.Contact, format string) ([]byte, error) {
+	switch format {
+	case TimelineJSON:
+		record, err := database.FullContactRecord(c)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.MarshalIndent(record, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("encoding timeline: %w", err)
+		}
+		return append(data, '\n'), nil
+	case TimelineMarkdown:
+		logs, err := database.GetContactInteractions(c.ID, -1) // -1: no limit
+		if err != nil {
+			return nil, err
+		}
+		dates, err := database.ListImportantDates(c.ID)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(MarkdownTimeline(c, logs, dates)), nil
+	default:
+		return nil, fmt.Errorf("unknown timeline format %q (use %s or %s)", format, TimelineMarkdown, TimelineJSON)
+	}
+}
+
+// MarkdownTimeline formats a contact and all its interactions, oldest
+// first and grouped by year, as a Markdown document
+func MarkdownTimeline(c db.Contact, logs []db.Log, dates []db.ImportantDate) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", c.Name)
+	writeContactFields(&b, c, dates)
+
+	fmt.Fprintf(&b, "\n## Interactions (%d)\n", len(logs))
+	if len(logs) == 0 {
+		b.WriteString("\nNone recorded.\n")
+	}
+	year := 0
+	for i := len(logs) - 1; i >= 0; i-- {
+		l := logs[i]
+		if l.InteractionDate.Year() != year {
+			year = l.InteractionDate.Year()
+			fmt.Fprintf(&b, "\n### %d\n\n", year)
+		}
+
+		details := []string{l.InteractionType}
+		if l.Rating.Valid {
+			details = append(details, fmt.Sprintf("energy %d/5", l.Rating.Int64))
+		}
+		if l.DurationMinutes.Valid {
+			details = append(details, fmt.Sprintf("%d min", l.DurationMinutes.Int64))
+		}
+		fmt.Fprintf(&b, "- **%s** (%s)", l.InteractionDate.Format("2006-01-02 15:04"), strings.Join(details, ", "))
+		if l.Notes.Valid && l.Notes.String != "" {
+			// Keep multi-line notes inside the list item
+			b.WriteString(": " + strings.ReplaceAll(strings.TrimSpace(l.Notes.String), "\n", "\n  "))
+		}
+		b.WriteString("\n")
+		for _, a := range l.Attachments {
+			fmt.Fprintf(&b, "  - Attachment: %s\n", a)
+		}
+	}
+
+	fmt.Fprintf(&b, "\n_Exported %s_\n", time.Now().Format("2006-01-02"))
+	return b.String()
+}
+
+var unsafeNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// TimelineFileName names an exported timeline after the contact and today's
+// date
+func TimelineFileName(c db.Contact, format string) string {
+	name := strings.Trim(unsafeNameChars.ReplaceAllString(strings.ToLower(c.Name), "-"), "-")
+	if name == "" {
+		name = fmt.Sprintf("contact-%d", c.ID)
+	}
+	return fmt.Sprintf("%s-interactions-%s.%s", name, time.Now().Format("20060102"), format)
+}
@@ -0,0 +1,260 @@
+package scripting
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/tasks"
+)
+
+// Events that automations can run on
+const (
+	EventContacted = "contacted" // Marked as contacted
+	EventNote      = "note"      // Interaction note added
+	EventBumped    = "bumped"    // Bumped without contact
+	EventState     = "state"     // State changed by hand
+	EventArchived  = "archived"  // Archived or unarchived
+)
+
+var events = []string{EventContacted, EventNote, EventBumped, EventState, EventArchived}
+
+// Env is the contact data available to script expressions
+type Env struct {
+	Name         string  `expr:"name"`
+	Email        string  `expr:"email"`
+	Phone        string  `expr:"phone"`
+	Company      string  `expr:"company"`
+	Location     string  `expr:"location"`
+	Type         string  `expr:"type"`
+	State        string  `expr:"state"`
+	Label        string  `expr:"label"`
+	Notes        string  `expr:"notes"`
+	Style        string  `expr:"style"`
+	Archived     bool    `expr:"archived"`
+	Muted        bool    `expr:"muted"`
+	Starred      bool    `expr:"starred"`
+	Overdue      bool    `expr:"overdue"`
+	OverdueRatio float64 `expr:"overdue_ratio"` // Very large when never contacted
+	Cadence      int     `expr:"cadence"`       // Days between contacts; 0 if never overdue
+	DaysSince    int     `expr:"days_since"`    // Days since last contact or bump; -1 if never
+	Bumps        int     `expr:"bumps"`
+	WaitingDays  int     `expr:"waiting_days"` // Days waiting on their reply; -1 if not waiting
+	Completeness int     `expr:"completeness"` // Percentage of profile fields filled in
+}
+
+// NewEnv builds the expression environment for a contact
+func NewEnv(c db.Contact) Env {
+	env := Env{
+		Name:         c.Name,
+		Email:        c.Email.String,
+		Phone:        c.Phone.String,
+		Company:      c.Company.String,
+		Location:     c.Location.String,
+		Type:         c.RelationshipType,
+		State:        c.State.String,
+		Label:        c.Label.String,
+		Notes:        c.Notes.String,
+		Style:        c.ContactStyle,
+		Archived:     c.Archived,
+		Muted:        c.RemindersMuted,
+		Starred:      c.Starred,
+		Overdue:      c.IsOverdue(),
+		OverdueRatio: c.OverdueRatio(),
+		Cadence:      c.CadenceDays(),
+		DaysSince:    -1,
+		Bumps:        c.BumpCount,
+		WaitingDays:  c.WaitingDays(),
+		Completeness: c.Completeness(),
+	}
+	if math.IsInf(env.OverdueRatio, 1) {
+		env.OverdueRatio = math.MaxFloat32
+	}
+	if last := c.LastInteraction(); last.Valid {
+		env.DaysSince = int(time.Since(last.Time).Hours() / 24)
+	}
+	return env
+}
+
+// file is the layout of the user script file
+type file struct {
+	Filters     map[string]string `toml:"filters"`
+	Score       string            `toml:"score"`
+	Automations []automation      `toml:"automations"`
+}
+
+// automation runs actions when an event happens to a contact matching When
+type automation struct {
+	On         string `toml:"on"`          // Event name, e.g. "contacted"
+	When       string `toml:"when"`        // Condition expression; empty always matches
+	SetState   string `toml:"set_state"`   // Change the contact's state
+	CreateTask bool   `toml:"create_task"` // Create a task for the contact's (new) state
+	Message    string `toml:"message"`     // Show a message in the status bar
+
+	when *vm.Program
+}
+
+// Engine evaluates the filters, score and automations from a script file
+type Engine struct {
+	filterNames []string
+	filters     map[string]*vm.Program
+	score       *vm.Program
+	automations []automation
+}
+
+// Load compiles the script file at path. A missing file is not an error and
+// returns a nil Engine, which has no filters, score or automations.
+func Load(path string) (*Engine, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading script file: %w", err)
+	}
+
+	var f file
+	if _, err := toml.Decode(string(data), &f); err != nil {
+		return nil, fmt.Errorf("parsing script file: %w", err)
+	}
+
+	e := &Engine{filters: make(map[string]*vm.Program)}
+	for name, source := range f.Filters {
+		program, err := expr.Compile(source, expr.Env(Env{}), expr.AsBool())
+		if err != nil {
+			return nil, fmt.Errorf("filter %q: %w", name, err)
+		}
+		e.filters[name] = program
+		e.filterNames = append(e.filterNames, name)
+	}
+	sort.Strings(e.filterNames)
+
+	if f.Score != "" {
+		program, err := expr.Compile(f.Score, expr.Env(Env{}), expr.AsFloat64())
+		if err != nil {
+			return nil, fmt.Errorf("score: %w", err)
+		}
+		e.score = program
+	}
+
+	for i, a := range f.Automations {
+		if !isEvent(a.On) {
+			return nil, fmt.Errorf("automation %d: unknown event %q (use %s)", i+1, a.On, strings.Join(events, ", "))
+		}
+		if a.When != "" {
+			program, err := expr.Compile(a.When, expr.Env(Env{}), expr.AsBool())
+			if err != nil {
+				return nil, fmt.Errorf("automation %d: %w", i+1, err)
+			}
+			a.when = program
+		}
+		e.automations = append(e.automations, a)
+	}
+
+	return e, nil
+}
+
+// isEvent reports whether name is a known automation event
+func isEvent(name string) bool {
+	for _, e := range events {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterNames returns the names of the script filters in sorted order
+func (e *Engine) FilterNames() []string {
+	if e == nil {
+		return nil
+	}
+	return e.filterNames
+}
+
+// Match reports whether a contact passes the named filter. Contacts the
+// expression fails on do not match.
+func (e *Engine) Match(name string, c db.Contact) bool {
+	if e == nil || e.filters[name] == nil {
+		return true
+	}
+	out, err := expr.Run(e.filters[name], NewEnv(c))
+	if err != nil {
+		return false
+	}
+	return out.(bool)
+}
+
+// HasScore reports whether the script defines a sort score
+func (e *Engine) HasScore() bool {
+	return e != nil && e.score != nil
+}
+
+// Score returns the custom sort score for a contact; higher sorts first
+func (e *Engine) Score(c db.Contact) float64 {
+	if !e.HasScore() {
+		return 0
+	}
+	out, err := expr.Run(e.score, NewEnv(c))
+	if err != nil {
+		return 0
+	}
+	return out.(float64)
+}
+
+// Run applies the automations for an event to a contact, returning the
+// messages they produced. State changes made here do not trigger further
+// automations.
+func (e *Engine) Run(event string, database *db.DB, backend tasks.Backend, contactID int) ([]string, error) {
+	if e == nil {
+		return nil, nil
+	}
+
+	var messages []string
+	for _, a := range e.automations {
+		if a.On != event {
+			continue
+		}
+
+		contact, err := database.GetContact(contactID)
+		if err != nil {
+			return messages, fmt.Errorf("loading contact: %w", err)
+		}
+		if a.when != nil {
+			out, err := expr.Run(a.when, NewEnv(*contact))
+			if err != nil {
+				return messages, fmt.Errorf("automation on %s: %w", event, err)
+			}
+			if !out.(bool) {
+				continue
+			}
+		}
+
+		state := contact.State.String
+		if a.SetState != "" {
+			if err := database.UpdateContactState(contactID, a.SetState); err != nil {
+				return messages, err
+			}
+			state = a.SetState
+		}
+		if a.CreateTask && backend != nil && backend.IsEnabled() && contact.Label.Valid && contact.Label.String != "" {
+			if err := backend.CreateContactTask(contact.Name, state, contact.Label.String); err != nil {
+				return messages, fmt.Errorf("creating task: %w", err)
+			}
+		}
+		if a.Message != "" {
+			messages = append(messages, a.Message)
+		}
+	}
+	return messages, nil
+}
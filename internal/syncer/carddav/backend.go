@@ -0,0 +1,471 @@
+package carddav
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/syncer"
+)
+
+// Backend syncs contacts with an address book on a CardDAV server such as
+// Fastmail, Nextcloud or iCloud. Each contact is linked to a card through
+// its external_id; the card's ETag and the contact as last synced are kept
+// so edits on either side can be told apart and merged field by field.
+type Backend struct {
+	dbPath       string
+	cfg          config.CardDAVSyncConfig
+	types        []string // Relationship types from the config
+	states       []string // Contact states from the config
+	preferRemote bool
+}
+
+// NewBackend creates a new CardDAV sync backend
+func NewBackend(cfg *config.Config) syncer.Backend {
+	return &Backend{
+		dbPath:       cfg.Database.Path,
+		cfg:          cfg.Sync.CardDAV,
+		types:        cfg.Relationships.Types,
+		states:       cfg.States.Names(),
+		preferRemote: strings.EqualFold(cfg.Sync.CardDAV.Conflicts, "remote"),
+	}
+}
+
+// Name returns the backend identifier
+func (b *Backend) Name() string {
+	return "carddav"
+}
+
+// IsEnabled returns whether an address book URL is configured
+func (b *Backend) IsEnabled() bool {
+	return b.cfg.URL != ""
+}
+
+// stats counts what a sync run did
+type stats struct {
+	pulled, pushed    int // Existing contacts updated locally / on the server
+	added, created    int // Contacts added locally / on the server
+	archived, deleted int // Contacts removed on the other side
+	conflicts         int
+	errors            []string
+}
+
+// summary describes a sync run in one line
+func (s stats) summary(preferRemote bool) string {
+	var parts []string
+	count := func(n int, what string) {
+		if n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, what))
+		}
+	}
+	count(s.pulled, "updated here")
+	count(s.added, "added here")
+	count(s.archived, "archived (deleted on server)")
+	count(s.pushed, "updated on server")
+	count(s.created, "added to server")
+	count(s.deleted, "deleted from server")
+	if s.conflicts > 0 {
+		kept := "local"
+		if preferRemote {
+			kept = "server"
+		}
+		parts = append(parts, fmt.Sprintf("%d conflicting fields (kept %s)", s.conflicts, kept))
+	}
+	if len(s.errors) > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed (%s)", len(s.errors), s.errors[0]))
+	}
+	if len(parts) == 0 {
+		return "no changes"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// run holds the state of one sync run
+type run struct {
+	b        *Backend
+	client   *client
+	database *db.DB
+	stats    stats
+}
+
+// Sync deletes cards for contacts deleted here, merges every card on the
+// server with its contact, archives contacts whose card was deleted and
+// adds new local contacts to the server. Problems with single cards are
+// reported in the summary rather than stopping the run.
+func (b *Backend) Sync(ctx context.Context) (syncer.Result, error) {
+	var result syncer.Result
+
+	c, err := b.connect(ctx)
+	if err != nil {
+		return result, err
+	}
+	cards, err := c.list(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	database, err := db.Open(b.dbPath)
+	if err != nil {
+		return result, err
+	}
+	defer database.Close()
+	if _, err := database.AllowRelationshipTypes(b.types); err != nil {
+		return result, err
+	}
+	if _, err := database.AllowStates(b.states); err != nil {
+		return result, err
+	}
+
+	r := &run{b: b, client: c, database: database}
+	if err := r.sync(ctx, cards); err != nil {
+		return result, err
+	}
+
+	s := r.stats
+	result.Changed = s.pulled+s.added+s.archived > 0
+	result.Summary = s.summary(b.preferRemote)
+	return result, nil
+}
+
+// connect builds the client, reading the password if a command gives it
+func (b *Backend) connect(ctx context.Context) (*client, error) {
+	base, err := url.Parse(b.cfg.URL)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("invalid carddav url %q", b.cfg.URL)
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+
+	password := b.cfg.Password
+	if b.cfg.PasswordCommand != "" {
+		out, err := exec.CommandContext(ctx, "sh", "-c", b.cfg.PasswordCommand).Output()
+		if err != nil {
+			return nil, fmt.Errorf("running password_command: %w", err)
+		}
+		password = strings.TrimSpace(string(out))
+	}
+
+	return &client{
+		http:     &http.Client{Timeout: 30 * time.Second},
+		base:     base,
+		username: b.cfg.Username,
+		password: password,
+	}, nil
+}
+
+// sync exchanges changes for the cards listed on the server
+func (r *run) sync(ctx context.Context, cards []remoteCard) error {
+	states, err := r.database.SyncStates(db.SourceCardDAV)
+	if err != nil {
+		return err
+	}
+	contacts, err := r.database.ListContacts()
+	if err != nil {
+		return fmt.Errorf("loading contacts: %w", err)
+	}
+	byID := make(map[int]db.Contact, len(contacts))
+	for _, c := range contacts {
+		byID[c.ID] = c
+	}
+
+	remote := make(map[string]remoteCard, len(cards))
+	for _, card := range cards {
+		remote[card.Path] = card
+	}
+	sort.Slice(cards, func(i, j int) bool { return cards[i].Path < cards[j].Path })
+
+	// Contacts deleted here since the last sync
+	tombstones, err := r.database.SyncTombstones()
+	if err != nil {
+		return err
+	}
+	for _, path := range tombstones {
+		if card, ok := remote[path]; ok {
+			if err := r.client.remove(ctx, path, card.ETag); err != nil {
+				r.fail(path, err)
+				continue
+			}
+			delete(remote, path)
+			r.stats.deleted++
+		}
+		if err := r.database.ClearSyncTombstone(path); err != nil {
+			r.fail(path, err)
+		}
+	}
+
+	// Cards on the server
+	linked := make(map[int]bool)
+	for _, card := range cards {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, ok := remote[card.Path]; !ok {
+			continue
+		}
+		state, known := states[card.Path]
+		contact, exists := byID[state.ContactID]
+		switch {
+		case known && exists:
+			linked[contact.ID] = true
+			r.syncCard(ctx, card, state, contact)
+		case known:
+			// Linked to a contact in the trash; left alone until it is
+			// restored, or deleted from the server once it is purged
+		default:
+			if id := r.pullCard(ctx, card, contacts, linked); id != 0 {
+				linked[id] = true
+			}
+		}
+	}
+
+	// Cards deleted on the server. An empty listing is more likely a wrong
+	// URL than a deleted address book, so nothing is archived then.
+	if len(remote) == 0 && len(states) > 0 {
+		r.stats.errors = append(r.stats.errors, fmt.Sprintf("server listed no cards; left %d synced contacts alone", len(states)))
+	} else {
+		for path, state := range states {
+			if _, ok := remote[path]; ok {
+				continue
+			}
+			contact, exists := byID[state.ContactID]
+			if !exists {
+				continue
+			}
+			if !contact.Archived {
+				if err := r.database.ArchiveContact(contact.ID, "Deleted on CardDAV server"); err != nil {
+					r.fail(contact.Name, err)
+					continue
+				}
+				r.stats.archived++
+			}
+			if err := r.database.ClearSyncState(contact.ID); err != nil {
+				r.fail(contact.Name, err)
+			}
+			linked[contact.ID] = true
+		}
+	}
+
+	// New local contacts
+	for _, contact := range contacts {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if linked[contact.ID] || contact.Archived || contact.ExternalID.Valid {
+			continue
+		}
+		r.pushNew(ctx, contact)
+	}
+	return nil
+}
+
+// syncCard merges a card with the contact it is linked to
+func (r *run) syncCard(ctx context.Context, card remoteCard, state db.SyncState, contact db.Contact) {
+	baseContact, err := parseCard(state.Card)
+	if err != nil {
+		r.fail(contact.Name, err)
+		return
+	}
+	base := fieldsOf(baseContact)
+	local := fieldsOf(contact)
+	if local == base && card.ETag != "" && card.ETag == state.ETag {
+		return // Unchanged on both sides
+	}
+
+	text, etag, err := r.client.get(ctx, card.Path)
+	if err != nil {
+		r.fail(contact.Name, err)
+		return
+	}
+	if etag == "" {
+		etag = card.ETag
+	}
+	remoteContact, err := parseCard(text)
+	if err != nil {
+		r.fail(contact.Name, err)
+		return
+	}
+	r.reconcile(ctx, card.Path, text, etag, base, contact, fieldsOf(remoteContact))
+}
+
+// pullCard adds a card that is new on the server as a contact, or links it
+// to an unsynced contact with the same email or name. It returns the
+// contact's ID, or 0 if the card failed.
+func (r *run) pullCard(ctx context.Context, card remoteCard, contacts []db.Contact, linked map[int]bool) int {
+	text, etag, err := r.client.get(ctx, card.Path)
+	if err != nil {
+		r.fail(card.Path, err)
+		return 0
+	}
+	if etag == "" {
+		etag = card.ETag
+	}
+	remoteContact, err := parseCard(text)
+	if err != nil {
+		r.fail(card.Path, err)
+		return 0
+	}
+	if remoteContact.Name == "" {
+		return 0 // Groups and other cards without a name
+	}
+	remote := fieldsOf(remoteContact)
+
+	if match, ok := matchContact(remoteContact, contacts, linked); ok {
+		local := fieldsOf(match)
+		r.reconcile(ctx, card.Path, text, etag, linkBase(local, remote), match, remote)
+		return match.ID
+	}
+
+	c := remoteContact
+	if c.RelationshipType == "" {
+		c.RelationshipType = r.database.DefaultRelationshipType()
+	}
+	if !c.State.Valid {
+		c.State = db.NewNullString("ok")
+	}
+	if c.Label.Valid {
+		if _, err := r.database.GetContactByLabel(c.Label.String); err == nil {
+			c.Label = db.NewNullString("") // Already used by another contact
+		}
+	}
+	id, err := r.database.AddContact(c)
+	if err != nil {
+		r.fail(c.Name, err)
+		return 0
+	}
+	added, err := r.database.GetContact(int(id))
+	if err != nil {
+		r.fail(c.Name, err)
+		return 0
+	}
+	r.stats.added++
+
+	// Push back the fields given defaults here, such as the relationship type
+	r.reconcile(ctx, card.Path, text, etag, remote, *added, remote)
+	return added.ID
+}
+
+// matchContact finds the unsynced contact a new server card belongs to
+func matchContact(card db.Contact, contacts []db.Contact, linked map[int]bool) (db.Contact, bool) {
+	candidate := func(c db.Contact) bool {
+		return !linked[c.ID] && !c.ExternalID.Valid
+	}
+	if card.Email.Valid {
+		for _, c := range contacts {
+			if candidate(c) && c.Email.Valid && strings.EqualFold(c.Email.String, card.Email.String) {
+				return c, true
+			}
+		}
+	}
+	for _, c := range contacts {
+		if candidate(c) && strings.EqualFold(strings.TrimSpace(c.Name), card.Name) {
+			return c, true
+		}
+	}
+	return db.Contact{}, false
+}
+
+// reconcile merges a contact with its card, updates whichever side is
+// behind and records the result as the new base
+func (r *run) reconcile(ctx context.Context, path, text, etag string, base fields, contact db.Contact, remote fields) {
+	local := fieldsOf(contact)
+	merged, conflicts := merge(base, local, remote, r.b.preferRemote)
+	r.stats.conflicts += conflicts
+
+	result := merged.apply(contact)
+	if merged != local {
+		if err := r.database.UpdateContact(result); err != nil {
+			r.fail(contact.Name, err)
+			return
+		}
+		if merged[fieldState] != local[fieldState] && merged[fieldState] != "" {
+			if err := r.database.UpdateContactState(contact.ID, merged[fieldState]); err != nil {
+				r.fail(contact.Name, err)
+				return
+			}
+		}
+		r.stats.pulled++
+	}
+
+	if merged != remote {
+		lines, err := patchCard(text, remote, merged, result)
+		if err != nil {
+			r.fail(contact.Name, err)
+			return
+		}
+		etag, err = r.put(ctx, path, lines, etag)
+		if err != nil {
+			r.fail(contact.Name, err)
+			return
+		}
+		r.stats.pushed++
+	}
+
+	state := db.SyncState{ContactID: contact.ID, ExternalID: path, ETag: etag, Card: cardText(result)}
+	if err := r.database.SetSyncState(db.SourceCardDAV, state); err != nil {
+		r.fail(contact.Name, err)
+	}
+}
+
+// pushNew adds a local contact to the server
+func (r *run) pushNew(ctx context.Context, contact db.Contact) {
+	uid, err := newUID()
+	if err != nil {
+		r.fail(contact.Name, err)
+		return
+	}
+	path := r.client.base.Path + uid + ".vcf"
+	etag, err := r.put(ctx, path, newCard(contact, uid), "")
+	if err != nil {
+		r.fail(contact.Name, err)
+		return
+	}
+	r.stats.created++
+
+	state := db.SyncState{ContactID: contact.ID, ExternalID: path, ETag: etag, Card: cardText(contact)}
+	if err := r.database.SetSyncState(db.SourceCardDAV, state); err != nil {
+		r.fail(contact.Name, err)
+	}
+}
+
+// put stores card lines on the server
+func (r *run) put(ctx context.Context, path string, lines []string, etag string) (string, error) {
+	var b strings.Builder
+	if err := db.WriteVCardLines(&b, lines); err != nil {
+		return "", err
+	}
+	newETag, err := r.client.put(ctx, path, b.String(), etag)
+	if errors.Is(err, errPrecondition) {
+		return "", fmt.Errorf("%w; will retry next sync", err)
+	}
+	return newETag, err
+}
+
+// fail records a problem with one card or contact
+func (r *run) fail(what string, err error) {
+	r.stats.errors = append(r.stats.errors, fmt.Sprintf("%s: %v", what, err))
+}
+
+// newUID returns a random identifier for a new card
+func newUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating card id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Register the CardDAV sync backend
+func init() {
+	syncer.Register("carddav", NewBackend)
+}
@@ -0,0 +1,233 @@
+package carddav
+
+import (
+	"strings"
+
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/importer"
+)
+
+// Contact fields kept in sync with the server
+const (
+	fieldName = iota
+	fieldEmail
+	fieldPhone
+	fieldCompany
+	fieldLocation
+	fieldNotes
+	fieldLabel
+	fieldType
+	fieldState
+	fieldCount
+)
+
+// fieldProperties lists the vCard properties holding each field
+var fieldProperties = [fieldCount][]string{
+	fieldName:     {"FN", "N"},
+	fieldEmail:    {"EMAIL"},
+	fieldPhone:    {"TEL"},
+	fieldCompany:  {"ORG"},
+	fieldLocation: {"ADR"},
+	fieldNotes:    {"NOTE", db.VCardNotes},
+	fieldLabel:    {db.VCardLabel},
+	fieldType:     {db.VCardType},
+	fieldState:    {db.VCardState},
+}
+
+// multiValued reports whether a card may hold several values of a field,
+// of which only the one the contact uses is replaced
+func multiValued(field int) bool {
+	return field == fieldEmail || field == fieldPhone || field == fieldLocation
+}
+
+// fields are the synced values of a contact
+type fields [fieldCount]string
+
+// fieldsOf returns the synced values of a contact. Only the primary email
+// address and phone number are synced: the others on a card are kept as
+// they are by patchCard, and a card pulled as a new contact brings
+// them all, but later edits to them on either side aren't merged, since
+// fields hold one value each.
+func fieldsOf(c db.Contact) fields {
+	var f fields
+	f[fieldName] = c.Name
+	f[fieldEmail] = c.Email.String
+	f[fieldPhone] = c.Phone.String
+	f[fieldCompany] = c.Company.String
+	f[fieldLocation] = c.Location.String
+	f[fieldNotes] = c.Notes.String
+	f[fieldLabel] = c.Label.String
+	f[fieldType] = c.RelationshipType
+	f[fieldState] = c.State.String
+	for i := range f {
+		f[i] = strings.TrimSpace(f[i])
+	}
+	return f
+}
+
+// apply sets a contact's synced values
+func (f fields) apply(c db.Contact) db.Contact {
+	c.Name = f[fieldName]
+	c.Email = db.NewNullString(f[fieldEmail])
+	c.Phone = db.NewNullString(f[fieldPhone])
+	c.Company = db.NewNullString(f[fieldCompany])
+	c.Location = db.NewNullString(f[fieldLocation])
+	c.Notes = db.NewNullString(f[fieldNotes])
+	c.Label = db.NewNullString(f[fieldLabel])
+	c.RelationshipType = f[fieldType]
+	c.State = db.NewNullString(f[fieldState])
+	return c
+}
+
+// parseCard reads the contact held by a single card
+func parseCard(card string) (db.Contact, error) {
+	contacts, err := importer.ParseVCard(strings.NewReader(card))
+	if err != nil {
+		return db.Contact{}, err
+	}
+	if len(contacts) == 0 {
+		return db.Contact{}, nil
+	}
+	return contacts[0], nil
+}
+
+// merge combines both sides' edits since the base. A field changed on only
+// one side takes that side's value; a field changed differently on both is
+// a conflict, settled by preferRemote.
+func merge(base, local, remote fields, preferRemote bool) (merged fields, conflicts int) {
+	for i := range merged {
+		switch {
+		case local[i] == remote[i], remote[i] == base[i]:
+			merged[i] = local[i]
+		case local[i] == base[i]:
+			merged[i] = remote[i]
+		default:
+			conflicts++
+			merged[i] = local[i]
+			if preferRemote {
+				merged[i] = remote[i]
+			}
+		}
+	}
+	return merged, conflicts
+}
+
+// linkBase is the base for linking a local contact to a server card it
+// matches: local values win, but blanks are filled in from the server
+func linkBase(local, remote fields) fields {
+	base := remote
+	for i := range base {
+		if local[i] == "" {
+			base[i] = ""
+		}
+	}
+	return base
+}
+
+// cardLines returns the content lines of a contact's card as synced. Only
+// the location is synced, so the ADR carries it alone: a postal address
+// would read back as a location changed to its city and region.
+func cardLines(c db.Contact) []string {
+	c.Address = db.Address{}
+	return db.VCardLines(c)
+}
+
+// cardText returns a contact's card as stored for the sync base
+func cardText(c db.Contact) string {
+	return strings.Join(cardLines(c), "\r\n") + "\r\n"
+}
+
+// newCard returns the card for a contact created on the server. Notes are
+// only written as NOTE so edits made on a phone are not shadowed.
+func newCard(c db.Contact, uid string) []string {
+	var lines []string
+	for _, line := range cardLines(c) {
+		name, _, _ := importer.VCardProperty(line)
+		switch name {
+		case db.VCardNotes, "REV":
+			continue
+		case "UID":
+			line = "UID:" + uid
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// patchCard rewrites the fields that differ between remote and merged in a
+// server card, keeping everything else on it (photos, other addresses,
+// birthdays) as it was
+func patchCard(card string, remote, merged fields, c db.Contact) ([]string, error) {
+	lines, err := importer.UnfoldVCard(strings.NewReader(card))
+	if err != nil {
+		return nil, err
+	}
+	generated := cardLines(merged.apply(c))
+
+	// Notes go in NOTE alone, so cards still carrying an X-CONTACTS-NOTES
+	// from an export have their notes rewritten
+	rewriteNotes := false
+	for _, line := range lines {
+		if name, _, err := importer.VCardProperty(line); err == nil && name == db.VCardNotes {
+			rewriteNotes = true
+		}
+	}
+
+	for field := 0; field < fieldCount; field++ {
+		if remote[field] == merged[field] && !(field == fieldNotes && rewriteNotes) {
+			continue
+		}
+
+		// Drop the lines holding the old value, remembering where the first
+		// one was so the new value takes its place
+		at := -1
+		var kept []string
+		for _, line := range lines {
+			name, value, err := importer.VCardProperty(line)
+			drop := err == nil && holds(field, name)
+			if drop && multiValued(field) {
+				drop = strings.EqualFold(value, remote[field])
+			}
+			if err == nil && holds(field, name) && at < 0 {
+				at = len(kept)
+			}
+			if !drop {
+				kept = append(kept, line)
+			}
+		}
+		lines = kept
+
+		var added []string
+		for _, line := range generated {
+			name, _, _ := importer.VCardProperty(line)
+			if holds(field, name) && name != db.VCardNotes {
+				added = append(added, line)
+			}
+		}
+		if at < 0 {
+			at = endOf(lines)
+		}
+		lines = append(lines[:at], append(added, lines[at:]...)...)
+	}
+	return lines, nil
+}
+
+// endOf returns the index of a card's END line, or the end of lines
+func endOf(lines []string) int {
+	for i := len(lines) - 1; i >= 0; i-- {
+		if name, value, err := importer.VCardProperty(lines[i]); err == nil && name == "END" && strings.EqualFold(value, "VCARD") {
+			return i
+		}
+	}
+	return len(lines)
+}
+
+// holds reports whether a property holds a field
+func holds(field int, name string) bool {
+	for _, p := range fieldProperties[field] {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
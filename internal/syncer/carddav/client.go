@@ -0,0 +1,200 @@
+package carddav
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// errPrecondition reports that a card changed on the server since it was
+// read, so a write was refused
+var errPrecondition = errors.New("card changed on the server")
+
+// remoteCard is a card listed in the address book
+type remoteCard struct {
+	Path string // Path of the card on the server, used as its external ID
+	ETag string
+}
+
+// client talks WebDAV to a CardDAV address book collection
+type client struct {
+	http     *http.Client
+	base     *url.URL
+	username string
+	password string
+}
+
+// propfindBody asks for the version and type of each resource
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:">
+  <d:prop>
+    <d:getetag/>
+    <d:resourcetype/>
+  </d:prop>
+</d:propfind>`
+
+// multistatus is the PROPFIND response; element names match in any namespace
+type multistatus struct {
+	Responses []struct {
+		Href     string `xml:"href"`
+		Propstat []struct {
+			Status string `xml:"status"`
+			Prop   struct {
+				ETag         string `xml:"getetag"`
+				ResourceType struct {
+					Collection *struct{} `xml:"collection"`
+				} `xml:"resourcetype"`
+			} `xml:"prop"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+// list returns the cards in the address book
+func (c *client) list(ctx context.Context) ([]remoteCard, error) {
+	req, err := c.request(ctx, "PROPFIND", c.base.Path, strings.NewReader(propfindBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Depth", "1")
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("listing address book: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("listing address book: %s", resp.Status)
+	}
+
+	var ms multistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("reading address book listing: %w", err)
+	}
+
+	var cards []remoteCard
+	for _, r := range ms.Responses {
+		ref, err := url.Parse(strings.TrimSpace(r.Href))
+		if err != nil {
+			continue
+		}
+		path := c.base.ResolveReference(ref).Path
+		if strings.TrimSuffix(path, "/") == strings.TrimSuffix(c.base.Path, "/") {
+			continue // The collection itself
+		}
+
+		card := remoteCard{Path: path}
+		collection := false
+		for _, ps := range r.Propstat {
+			if !strings.Contains(ps.Status, " 200 ") {
+				continue
+			}
+			if ps.Prop.ETag != "" {
+				card.ETag = ps.Prop.ETag
+			}
+			if ps.Prop.ResourceType.Collection != nil {
+				collection = true
+			}
+		}
+		if !collection {
+			cards = append(cards, card)
+		}
+	}
+	return cards, nil
+}
+
+// get fetches a card and its current version
+func (c *client) get(ctx context.Context, path string) (card, etag string, err error) {
+	req, err := c.request(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return "", "", err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("fetching %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("fetching %s: %s", path, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", "", fmt.Errorf("fetching %s: %w", path, err)
+	}
+	return string(body), resp.Header.Get("ETag"), nil
+}
+
+// put stores a card. With an etag the write only succeeds if the card is
+// still at that version; without one it only succeeds if the card is new.
+// It returns the card's new version, or "" if the server did not say.
+func (c *client) put(ctx context.Context, path, card, etag string) (string, error) {
+	req, err := c.request(ctx, http.MethodPut, path, strings.NewReader(card))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "text/vcard; charset=utf-8")
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	} else {
+		req.Header.Set("If-None-Match", "*")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("storing %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	switch {
+	case resp.StatusCode == http.StatusPreconditionFailed:
+		return "", errPrecondition
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return "", fmt.Errorf("storing %s: %s", path, resp.Status)
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// remove deletes a card; a card that is already gone is not an error
+func (c *client) remove(ctx context.Context, path, etag string) error {
+	req, err := c.request(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("deleting %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil
+	case resp.StatusCode == http.StatusPreconditionFailed:
+		return errPrecondition
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("deleting %s: %s", path, resp.Status)
+	}
+	return nil
+}
+
+// request builds an authenticated request for a path on the server
+func (c *client) request(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	u := c.base.ResolveReference(&url.URL{Path: path})
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("building %s request: %w", method, err)
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	return req, nil
+}
@@ -0,0 +1,190 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/syncer"
+)
+
+// Backend syncs the database file through the git repository containing it.
+// Local changes are committed, then rebased onto the remote and pushed.
+type Backend struct {
+	dir     string // Directory holding the database file
+	file    string // Database file name relative to dir
+	remote  string
+	branch  string
+	enabled bool
+	locker  syncer.Locker // The TUI's open database, if any
+}
+
+// NewBackend creates a new git sync backend for the configured database
+func NewBackend(cfg *config.Config) syncer.Backend {
+	b := &Backend{
+		dir:    filepath.Dir(cfg.Database.Path),
+		file:   filepath.Base(cfg.Database.Path),
+		remote: cfg.Sync.Git.Remote,
+		branch: cfg.Sync.Git.Branch,
+	}
+	if b.remote == "" {
+		b.remote = "origin"
+	}
+
+	if _, err := exec.LookPath("git"); err == nil {
+		out, err := b.git(context.Background(), "rev-parse", "--is-inside-work-tree")
+		b.enabled = err == nil && out == "true"
+	}
+
+	return b
+}
+
+// Name returns the backend identifier
+func (b *Backend) Name() string {
+	return "git"
+}
+
+// IsEnabled returns whether git is installed and the database is in a repository
+func (b *Backend) IsEnabled() bool {
+	return b.enabled
+}
+
+// SetLocker makes the git steps that read or replace the database file
+// take turns with the open database
+func (b *Backend) SetLocker(l syncer.Locker) {
+	b.locker = l
+}
+
+// exclusive runs fn with exclusive use of the database file. Without a
+// locker, as from the command line, nothing else in this process has it open.
+func (b *Backend) exclusive(fn func() error) error {
+	if b.locker == nil {
+		return fn()
+	}
+	return b.locker.Exclusive(fn)
+}
+
+// Sync commits local changes, pulls remote changes and pushes the result.
+// Fetching and pushing happen with the database in use; committing and
+// rebasing, which read and replace the file, happen while it is closed.
+func (b *Backend) Sync(ctx context.Context) (syncer.Result, error) {
+	var result syncer.Result
+	var done []string
+
+	_, err := b.git(ctx, "remote", "get-url", b.remote)
+	hasRemote := err == nil
+
+	branch := b.branch
+	var fetchErr error
+	if hasRemote {
+		if branch == "" {
+			current, err := b.git(ctx, "rev-parse", "--abbrev-ref", "HEAD")
+			if err != nil {
+				return result, err
+			}
+			branch = current
+		}
+		_, fetchErr = b.git(ctx, "fetch", b.remote, branch)
+	}
+
+	err = b.exclusive(func() error {
+		// Commit local changes to the database, if any
+		if _, err := b.git(ctx, "add", "--", b.file); err != nil {
+			return err
+		}
+		if _, err := b.git(ctx, "diff", "--cached", "--quiet", "--", b.file); err != nil {
+			if _, err := b.git(ctx, "commit", "-m", commitMessage(), "--", b.file); err != nil {
+				return err
+			}
+			done = append(done, "committed local changes")
+		}
+		if !hasRemote || fetchErr != nil {
+			return nil
+		}
+
+		before, err := b.git(ctx, "rev-parse", "HEAD:"+b.file)
+		if err != nil {
+			return err
+		}
+		if _, err := b.git(ctx, "rebase", "FETCH_HEAD"); err != nil {
+			// Leave the repository as it was rather than mid-rebase
+			b.git(context.Background(), "rebase", "--abort")
+			return fmt.Errorf("pulling from %s: %w (resolve manually in %s)", b.remote, err, b.dir)
+		}
+		after, err := b.git(ctx, "rev-parse", "HEAD:"+b.file)
+		if err != nil {
+			return err
+		}
+		if before != after {
+			result.Changed = true
+			done = append(done, "pulled remote changes")
+		}
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+	if fetchErr != nil {
+		return result, fmt.Errorf("pulling from %s: %w", b.remote, fetchErr)
+	}
+
+	// Without the remote there is nothing more to exchange
+	if !hasRemote {
+		if len(done) == 0 {
+			done = append(done, "no changes")
+		}
+		result.Summary = strings.Join(done, ", ")
+		return result, nil
+	}
+
+	if _, err := b.git(ctx, "push", b.remote, "HEAD:"+branch); err != nil {
+		return result, fmt.Errorf("pushing to %s: %w", b.remote, err)
+	}
+
+	if len(done) == 0 {
+		done = append(done, "up to date")
+	}
+	result.Summary = strings.Join(done, ", ")
+	return result, nil
+}
+
+// git runs a git command in the database directory, returning trimmed output
+func (b *Backend) git(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", b.dir}, args...)...)
+	output, err := cmd.CombinedOutput()
+	out := strings.TrimSpace(string(output))
+	if err != nil {
+		if out != "" {
+			return out, fmt.Errorf("git %s: %s", args[0], firstLine(out))
+		}
+		return out, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
+
+// commitMessage describes an automatic sync commit
+func commitMessage() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown host"
+	}
+	return fmt.Sprintf("contacts: sync from %s at %s", host, time.Now().Format("2006-01-02 15:04"))
+}
+
+// firstLine returns the first line of s
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// Register the git sync backend
+func init() {
+	syncer.Register("git", NewBackend)
+}
@@ -0,0 +1,97 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+)
+
+// Result describes the outcome of a sync run
+type Result struct {
+	// Changed reports whether the local database was modified by the sync
+	// and needs to be reloaded
+	Changed bool
+	// Summary is a short description of what the sync did
+	Summary string
+}
+
+// Backend defines the interface that all sync backends must implement
+type Backend interface {
+	// Name returns the backend identifier (e.g., "git")
+	Name() string
+
+	// IsEnabled checks if the backend is available and properly configured
+	IsEnabled() bool
+
+	// Sync exchanges changes with the remote. It runs in the background,
+	// so it must not touch the UI and should honor ctx cancellation.
+	Sync(ctx context.Context) (Result, error)
+}
+
+// Locker gives a backend exclusive use of the database file: nothing
+// writes to it while fn runs, and a file fn replaced is picked up afterwards.
+// *db.DB is one.
+type Locker interface {
+	Exclusive(fn func() error) error
+}
+
+// FileBackend is implemented by backends that commit or replace the
+// database file itself. The TUI hands them its open database so a sync
+// never reads the file mid-write, and nothing is written to a file the
+// sync has already replaced.
+type FileBackend interface {
+	Backend
+	SetLocker(l Locker)
+}
+
+// BackendFactory is a function that creates a new instance of a Backend
+type BackendFactory func(cfg *config.Config) Backend
+
+var (
+	mu       sync.RWMutex
+	backends = make(map[string]BackendFactory)
+)
+
+// Register adds a sync backend factory
+func Register(name string, factory BackendFactory) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, exists := backends[name]; exists {
+		return fmt.Errorf("sync backend %s already registered", name)
+	}
+
+	backends[name] = factory
+	return nil
+}
+
+// Create instantiates a sync backend by name
+func Create(name string, cfg *config.Config) (Backend, error) {
+	mu.RLock()
+	factory, exists := backends[name]
+	mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("sync backend %s not registered", name)
+	}
+
+	backend := factory(cfg)
+	if !backend.IsEnabled() {
+		return nil, fmt.Errorf("sync backend %s is not available", name)
+	}
+	return backend, nil
+}
+
+// List returns all registered sync backend names
+func List() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	return names
+}
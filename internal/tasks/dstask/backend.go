@@ -61,6 +61,12 @@ func (b *Backend) IsEnabled() bool {
 
 // CreateContactTask creates a dstask task for a contact state change
 func (b *Backend) CreateContactTask(contactName, state, label string) error {
+	return b.CreateContactTaskIn(contactName, state, label, "")
+}
+
+// CreateContactTaskIn creates a dstask task in a project other than the
+// configured one
+func (b *Backend) CreateContactTaskIn(contactName, state, label, project string) error {
 	if !b.enabled {
 		return fmt.Errorf("dstask not available")
 	}
@@ -79,7 +85,10 @@ func (b *Backend) CreateContactTask(contactName, state, label string) error {
 
 	// Create the task with label and state as tags, and project
 	// Using -- to ensure we don't get filtered by current context
-	args := []string{"add", "--", description, "+" + label, "+contact-" + state, "project:" + b.project}
+	if project == "" {
+		project = b.project
+	}
+	args := []string{"add", "--", description, "+" + label, "+contact-" + state, "project:" + project}
 	
 	cmd := exec.Command("dstask", args...)
 	output, err := cmd.CombinedOutput()
@@ -270,6 +279,8 @@ func formatTaskDescription(state, contactName string) string {
 		return fmt.Sprintf("Meeting scheduled with %s", contactName)
 	case "timeout":
 		return fmt.Sprintf("Check timeout status for %s", contactName)
+	case "nudge":
+		return fmt.Sprintf("Nudge %s for a reply", contactName)
 	default:
 		return fmt.Sprintf("%s: %s", strings.Title(state), contactName)
 	}
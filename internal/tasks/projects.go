@@ -0,0 +1,63 @@
+package tasks
+
+import "strings"
+
+// ProjectBackend is a Backend that can file a task somewhere other than its
+// default project: a TaskWarrior or dstask project, or a Things list or area
+type ProjectBackend interface {
+	Backend
+
+	// CreateContactTaskIn creates a contact task in the given project; an
+	// empty project means the backend's default
+	CreateContactTaskIn(contactName, state, label, project string) error
+}
+
+// ProjectFor returns the project mapped to a contact, looking up its label
+// first and then its relationship type, or "" when neither is mapped
+func ProjectFor(projects map[string]string, label, relationshipType string) string {
+	if label != "" {
+		if !strings.HasPrefix(label, "@") {
+			label = "@" + label
+		}
+		for key, project := range projects {
+			if strings.EqualFold(key, label) {
+				return project
+			}
+		}
+	}
+	if relationshipType != "" {
+		return projects[relationshipType]
+	}
+	return ""
+}
+
+// mappedBackend files contact tasks under the project mapped to each contact
+type mappedBackend struct {
+	Backend
+	projects map[string]string
+	typeOf   func(label string) string
+}
+
+// CreateContactTask creates the task in the contact's mapped project when the
+// backend supports projects
+func (b *mappedBackend) CreateContactTask(contactName, state, label string) error {
+	pb, ok := b.Backend.(ProjectBackend)
+	if !ok {
+		return b.Backend.CreateContactTask(contactName, state, label)
+	}
+	relationshipType := ""
+	if b.typeOf != nil {
+		relationshipType = b.typeOf(label)
+	}
+	return pb.CreateContactTaskIn(contactName, state, label, ProjectFor(b.projects, label, relationshipType))
+}
+
+// MapProjects files new contact tasks under the project mapped to each
+// contact's label or relationship type. typeOf looks up the relationship
+// type of the contact with a label.
+func (m *Manager) MapProjects(projects map[string]string, typeOf func(label string) string) {
+	if len(projects) == 0 {
+		return
+	}
+	m.backend = &mappedBackend{Backend: m.backend, projects: projects, typeOf: typeOf}
+}
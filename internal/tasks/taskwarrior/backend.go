@@ -59,6 +59,12 @@ func (b *Backend) IsEnabled() bool {
 
 // CreateContactTask creates a TaskWarrior task for a contact state change
 func (b *Backend) CreateContactTask(contactName, state, label string) error {
+	return b.CreateContactTaskIn(contactName, state, label, "")
+}
+
+// CreateContactTaskIn creates a TaskWarrior task in a project other than the
+// configured one
+func (b *Backend) CreateContactTaskIn(contactName, state, label, project string) error {
 	if !b.enabled {
 		return fmt.Errorf("TaskWarrior not available")
 	}
@@ -76,7 +82,10 @@ func (b *Backend) CreateContactTask(contactName, state, label string) error {
 	}
 
 	// Create the task with label as tag and project
-	args := []string{"add", description, "+" + label, "project:" + b.project}
+	if project == "" {
+		project = b.project
+	}
+	args := []string{"add", description, "+" + label, "project:" + project}
 	
 	cmd := exec.Command("task", args...)
 	output, err := cmd.CombinedOutput()
@@ -218,6 +227,8 @@ func formatTaskDescription(state, contactName string) string {
 		return fmt.Sprintf("Meeting scheduled with %s", contactName)
 	case "timeout":
 		return fmt.Sprintf("Check timeout status for %s", contactName)
+	case "nudge":
+		return fmt.Sprintf("Nudge %s for a reply", contactName)
 	default:
 		return fmt.Sprintf("%s: %s", strings.Title(state), contactName)
 	}
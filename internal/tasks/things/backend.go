@@ -28,8 +28,9 @@ type thingsTask struct {
 
 // Backend implements the tasks.Backend interface for Things 3
 type Backend struct {
-	enabled   bool
-	authToken string
+	enabled     bool
+	authToken   string
+	defaultList string
 }
 
 // NewBackend creates a new Things backend
@@ -41,6 +42,7 @@ func NewBackend() tasks.Backend {
 	// Load auth token from config if available
 	if cfg, err := config.Load(); err == nil {
 		backend.authToken = cfg.Tasks.Things.AuthToken
+		backend.defaultList = cfg.Tasks.Things.DefaultList
 	}
 	
 	return backend
@@ -58,6 +60,12 @@ func (b *Backend) IsEnabled() bool {
 
 // CreateContactTask creates a Things task for a contact state change
 func (b *Backend) CreateContactTask(contactName, state, label string) error {
+	return b.CreateContactTaskIn(contactName, state, label, "")
+}
+
+// CreateContactTaskIn creates a Things task in a list, project or area other
+// than the default list
+func (b *Backend) CreateContactTaskIn(contactName, state, label, list string) error {
 	if !b.enabled {
 		return fmt.Errorf("Things not available")
 	}
@@ -95,6 +103,12 @@ func (b *Backend) CreateContactTask(contactName, state, label string) error {
 	
 	thingsURL := fmt.Sprintf("things:///add?title=%s&tags=%s&auth-token=%s", 
 		titleParam, tagsParam, authParam)
+	if list == "" {
+		list = b.defaultList
+	}
+	if list != "" {
+		thingsURL += "&list=" + strings.ReplaceAll(url.QueryEscape(list), "+", "%20")
+	}
 	
 	// Open the URL to create the task
 	// Use -g flag to prevent Things from activating/coming to foreground
@@ -394,6 +408,8 @@ func formatTaskDescription(state, contactName string) string {
 		return fmt.Sprintf("Meeting scheduled with %s", contactName)
 	case "timeout":
 		return fmt.Sprintf("Check timeout status for %s", contactName)
+	case "nudge":
+		return fmt.Sprintf("Nudge %s for a reply", contactName)
 	default:
 		return fmt.Sprintf("%s: %s", strings.Title(state), contactName)
 	}
@@ -0,0 +1,151 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// Fields of the postal address form
+const (
+	addressFieldStreet = iota
+	addressFieldCity
+	addressFieldRegion
+	addressFieldPostalCode
+	addressFieldCountry
+	addressFieldCount
+)
+
+var addressFieldLabels = [addressFieldCount]string{
+	addressFieldStreet:     "Street",
+	addressFieldCity:       "City",
+	addressFieldRegion:     "State / region",
+	addressFieldPostalCode: "Postal code",
+	addressFieldCountry:    "Country",
+}
+
+// newAddressInputs creates the inputs of the postal address form
+func newAddressInputs() []textinput.Model {
+	inputs := make([]textinput.Model, addressFieldCount)
+	for i := range inputs {
+		inputs[i] = textinput.New()
+		inputs[i].Width = 50
+		inputs[i].CharLimit = 200
+	}
+	inputs[addressFieldStreet].Placeholder = "12 Oak St, Apt 3"
+	inputs[addressFieldRegion].Placeholder = "OR"
+	inputs[addressFieldPostalCode].CharLimit = 20
+	return inputs
+}
+
+// openAddress starts editing a contact's postal address
+func (m Model) openAddress(contact db.Contact) (Model, tea.Cmd) {
+	m.addressMode = true
+	m.addressContactID = contact.ID
+	m.addressInputs[addressFieldStreet].SetValue(contact.Address.Street)
+	m.addressInputs[addressFieldCity].SetValue(contact.Address.City)
+	m.addressInputs[addressFieldRegion].SetValue(contact.Address.Region)
+	m.addressInputs[addressFieldPostalCode].SetValue(contact.Address.PostalCode)
+	m.addressInputs[addressFieldCountry].SetValue(contact.Address.Country)
+	return m.focusAddressField(addressFieldStreet), textinput.Blink
+}
+
+// focusAddressField moves the form focus to a field
+func (m Model) focusAddressField(field int) Model {
+	m.addressField = field
+	for i := range m.addressInputs {
+		if i == field {
+			m.addressInputs[i].Focus()
+		} else {
+			m.addressInputs[i].Blur()
+		}
+	}
+	return m
+}
+
+// closeAddress leaves the postal address form
+func (m Model) closeAddress() Model {
+	m.addressMode = false
+	for i := range m.addressInputs {
+		m.addressInputs[i].Blur()
+	}
+	return m
+}
+
+// saveAddress stores the address being edited
+func (m Model) saveAddress() Model {
+	address := db.Address{
+		Street:     m.addressInputs[addressFieldStreet].Value(),
+		City:       m.addressInputs[addressFieldCity].Value(),
+		Region:     m.addressInputs[addressFieldRegion].Value(),
+		PostalCode: m.addressInputs[addressFieldPostalCode].Value(),
+		Country:    m.addressInputs[addressFieldCountry].Value(),
+	}
+	if err := m.db.UpdateAddress(m.addressContactID, address); err != nil {
+		m.err = err
+		return m
+	}
+
+	m = m.closeAddress()
+	if contacts, err := m.db.ListContacts(); err == nil {
+		m.setContacts(contacts)
+	}
+	m.invalidateDetailCache()
+	return m.setFlash(FlashSuccess, "✓ Address saved")
+}
+
+// updateAddress handles keys for the postal address form
+func (m Model) updateAddress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		return m.closeAddress(), nil
+	case "enter":
+		return m.saveAddress(), nil
+	case "tab", "down":
+		return m.focusAddressField((m.addressField + 1) % addressFieldCount), nil
+	case "shift+tab", "up":
+		return m.focusAddressField((m.addressField + addressFieldCount - 1) % addressFieldCount), nil
+	}
+
+	var cmd tea.Cmd
+	m.addressInputs[m.addressField], cmd = m.addressInputs[m.addressField].Update(msg)
+	return m, cmd
+}
+
+// renderAddress renders the postal address form overlay
+func (m Model) renderAddress() string {
+	var name string
+	if contact, err := m.db.GetContact(m.addressContactID); err == nil {
+		name = contact.Name
+	}
+
+	var lines []string
+	lines = append(lines, fmt.Sprintf("Postal address for %s", name))
+	lines = append(lines, "")
+	for field, label := range addressFieldLabels {
+		if m.addressField == field {
+			lines = append(lines, selectedStyle.Render(label))
+		} else {
+			lines = append(lines, labelStyle.Render(label))
+		}
+		lines = append(lines, m.addressInputs[field].View())
+	}
+	lines = append(lines, "")
+	lines = append(lines, "Tab: next field • Enter: save • Esc: cancel")
+	lines = append(lines, labelStyle.Render("Clear every field to remove the address"))
+
+	box := borderStyle.
+		Padding(1).
+		Width(70).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(box)
+}
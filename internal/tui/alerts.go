@@ -0,0 +1,242 @@
+package tui
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// followUpAlert is a follow-up or deadline date that has come due
+type followUpAlert struct {
+	Contact db.Contact
+	Kind    string // "Follow-up" or "Deadline"
+	Due     time.Time
+}
+
+// dueAlerts returns the follow-up and deadline dates of active contacts that
+// fall on or before today, oldest first
+func dueAlerts(contacts []db.Contact, now time.Time) []followUpAlert {
+	var alerts []followUpAlert
+	for _, c := range contacts {
+		if c.Archived {
+			continue
+		}
+		if c.FollowUpDate.Valid && isDue(c.FollowUpDate.Time, now) {
+			alerts = append(alerts, followUpAlert{Contact: c, Kind: "Follow-up", Due: c.FollowUpDate.Time})
+		}
+		if c.DeadlineDate.Valid && isDue(c.DeadlineDate.Time, now) {
+			alerts = append(alerts, followUpAlert{Contact: c, Kind: "Deadline", Due: c.DeadlineDate.Time})
+		}
+	}
+	sort.SliceStable(alerts, func(i, j int) bool {
+		return alerts[i].Due.Before(alerts[j].Due)
+	})
+	return alerts
+}
+
+// isDue reports whether a date falls on or before the day of now
+func isDue(date, now time.Time) bool {
+	endOfToday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+	return date.Before(endOfToday)
+}
+
+// hasFollowUpDue reports whether an active contact's follow-up or deadline
+// date falls on or before today
+func hasFollowUpDue(c db.Contact, now time.Time) bool {
+	if c.Archived {
+		return false
+	}
+	return c.FollowUpDate.Valid && isDue(c.FollowUpDate.Time, now) ||
+		c.DeadlineDate.Valid && isDue(c.DeadlineDate.Time, now)
+}
+
+// nextDueDate returns the earlier of a contact's follow-up and deadline
+// dates, and false if it has neither
+func nextDueDate(c db.Contact) (time.Time, bool) {
+	switch {
+	case c.FollowUpDate.Valid && c.DeadlineDate.Valid:
+		if c.DeadlineDate.Time.Before(c.FollowUpDate.Time) {
+			return c.DeadlineDate.Time, true
+		}
+		return c.FollowUpDate.Time, true
+	case c.FollowUpDate.Valid:
+		return c.FollowUpDate.Time, true
+	case c.DeadlineDate.Valid:
+		return c.DeadlineDate.Time, true
+	}
+	return time.Time{}, false
+}
+
+// sortByDueDate moves contacts with a follow-up or deadline date to the top,
+// soonest (or longest overdue) first, keeping the order of the rest
+func sortByDueDate(contacts []db.Contact) {
+	sort.SliceStable(contacts, func(i, j int) bool {
+		a, aOK := nextDueDate(contacts[i])
+		b, bOK := nextDueDate(contacts[j])
+		if aOK != bOK {
+			return aOK
+		}
+		return aOK && a.Before(b)
+	})
+}
+
+// parseDateInput reads a date typed as YYYY-MM-DD, or as a length of time
+// from today such as 10d, 3w or 2m
+func parseDateInput(s string) (time.Time, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if date, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return date, nil
+	}
+	if len(s) > 1 {
+		if n, err := strconv.Atoi(s[:len(s)-1]); err == nil && n > 0 {
+			today := startOfDay(time.Now())
+			switch s[len(s)-1] {
+			case 'd':
+				return today.AddDate(0, 0, n), nil
+			case 'w':
+				return today.AddDate(0, 0, 7*n), nil
+			case 'm':
+				return today.AddDate(0, n, 0), nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD, or e.g. 10d, 3w, 2m)", s)
+}
+
+// parseDueDate reads a follow-up or deadline date field; empty clears it
+func parseDueDate(s string) (sql.NullTime, error) {
+	if strings.TrimSpace(s) == "" {
+		return sql.NullTime{}, nil
+	}
+	date, err := parseDateInput(s)
+	if err != nil {
+		return sql.NullTime{}, err
+	}
+	return sql.NullTime{Time: date, Valid: true}, nil
+}
+
+// formatDueInput shows a follow-up or deadline date in its edit field
+func formatDueInput(date sql.NullTime) string {
+	if !date.Valid {
+		return ""
+	}
+	return date.Time.Format("2006-01-02")
+}
+
+// startOfDay returns midnight at the start of t's day
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// formatDue describes how long ago a date came due
+func formatDue(due time.Time) string {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	day := time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, time.Local)
+	days := int(today.Sub(day).Hours() / 24)
+	switch {
+	case days <= 0:
+		return "due today"
+	case days == 1:
+		return "1 day overdue"
+	default:
+		return fmt.Sprintf("%d days overdue", days)
+	}
+}
+
+// describeDueDate formats a follow-up or deadline date for the detail pane
+func describeDueDate(kind string, date time.Time) string {
+	text := fmt.Sprintf("%s: %s", kind, date.Format("2006-01-02"))
+	if isDue(date, time.Now()) {
+		return overdueStyle.Render(text + " (" + formatDue(date) + ")")
+	}
+	return text + " (" + formatDateWhen(date) + ")"
+}
+
+// openAlerts shows the follow-ups and deadlines that are due, if any
+func (m Model) openAlerts() Model {
+	m.alerts = dueAlerts(m.contacts, time.Now())
+	m.alertsMode = len(m.alerts) > 0
+	m.alertsSelected = 0
+	return m
+}
+
+// closeAlerts dismisses the due follow-ups panel
+func (m Model) closeAlerts() Model {
+	m.alertsMode = false
+	m.alerts = nil
+	return m
+}
+
+// updateAlerts handles keys for the due follow-ups panel
+func (m Model) updateAlerts(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	switch key {
+	case "esc", "q", " ":
+		return m.closeAlerts(), nil
+	case "j", "down":
+		if m.alertsSelected < len(m.alerts)-1 {
+			m.alertsSelected++
+		}
+	case "k", "up":
+		if m.alertsSelected > 0 {
+			m.alertsSelected--
+		}
+	case "enter":
+		return m.jumpToAlert(m.alertsSelected), nil
+	default:
+		if len(key) == 1 && key[0] >= '1' && key[0] <= '9' {
+			return m.jumpToAlert(int(key[0] - '1')), nil
+		}
+	}
+	return m, nil
+}
+
+// jumpToAlert closes the panel and selects the contact of the i'th alert
+func (m Model) jumpToAlert(i int) Model {
+	if i < 0 || i >= len(m.alerts) {
+		return m
+	}
+	contact := m.alerts[i].Contact
+	m = m.closeAlerts()
+	return m.jumpTo(contact)
+}
+
+// renderAlerts renders the due follow-ups panel
+func (m Model) renderAlerts() string {
+	var lines []string
+	lines = append(lines, fmt.Sprintf("Due follow-ups and deadlines (%d)", len(m.alerts)))
+	lines = append(lines, "")
+	for i, a := range m.alerts {
+		key := " "
+		if i < 9 {
+			key = fmt.Sprintf("%d", i+1)
+		}
+		line := fmt.Sprintf("%s  %-9s %-16s %s", key, a.Kind, formatDue(a.Due), a.Contact.Name)
+		if i == m.alertsSelected {
+			lines = append(lines, selectedStyle.Render("▶ "+line))
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	lines = append(lines, "")
+	lines = append(lines, "1-9/Enter: go to contact • Esc: dismiss")
+
+	box := borderStyle.
+		Padding(1).
+		Width(76).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(box)
+}
@@ -6,12 +6,21 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/pdxmph/contacts-tui/internal/avatar"
 	"github.com/pdxmph/contacts-tui/internal/config"
 	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/escalation"
+	"github.com/pdxmph/contacts-tui/internal/importer"
+	"github.com/pdxmph/contacts-tui/internal/report"
+	"github.com/pdxmph/contacts-tui/internal/scripting"
+	"github.com/pdxmph/contacts-tui/internal/syncer"
+	_ "github.com/pdxmph/contacts-tui/internal/syncer/carddav" // Register CardDAV sync backend
+	_ "github.com/pdxmph/contacts-tui/internal/syncer/git"     // Register git sync backend
 	"github.com/pdxmph/contacts-tui/internal/tasks"
 	_ "github.com/pdxmph/contacts-tui/internal/tasks/dstask"     // Register dstask backend
 	_ "github.com/pdxmph/contacts-tui/internal/tasks/taskwarrior" // Register TaskWarrior backend
@@ -41,6 +50,9 @@ type Model struct {
 	noteMode   bool
 	noteInput  textarea.Model
 	noteType   int
+	noteRating int // 0 = not rated, otherwise 1-5
+	noteDuration int // Minutes the interaction took; 0 = not tracked
+	noteWaiting bool // Mark the contact as owing a reply when the note is saved
 	filter     textinput.Model
 	err        error
 	
@@ -48,16 +60,25 @@ type Model struct {
 	flashMessage string
 	flashType    FlashType
 	flashJustSet bool // Track if flash was just set
+	flashSeq     int  // Incremented per message so stale expiry timers are ignored
 	
 	// Smart filters
 	stateFilter   bool // Show only non-ok states
 	overdueFilter bool // Show only overdue contacts
+	neglectedFilter bool // Show only seriously neglected contacts
+	incompleteFilter bool // Show only contacts with incomplete profiles
+	birthdayFilter  bool // Show only upcoming birthdays, soonest first
+	starredFilter   bool // Show only starred contacts
+	scriptFilter    string // Name of the active script filter
 	typeFilter    string // Filter by relationship type
+	tagFilter     string // Filter by tag
+	groupFilter   string // Filter by group name
 	showArchived  bool // Show archived contacts
 	
 	// Relationship type selection mode
 	typeFilterMode bool
-	typeSelected   int
+	typeSelected   int // Relationship types first, then filterTags
+	filterTags     []db.Tag
 	
 	// Edit mode
 	editMode       bool
@@ -74,6 +95,16 @@ type Model struct {
 	deleteContactID   int
 	deleteContactName string
 	
+	// Archive confirmation mode
+	archiveConfirmMode bool
+	archiveContactID   int
+	archiveAskReason   bool // Prompt for a reason instead of y/n
+	archiveReasonInput textinput.Model
+	
+	// Purge confirmation mode
+	purgeConfirmMode bool
+	purgeCandidates  []db.Contact // Archived contacts past the retention period
+	
 	// Help overlay mode
 	showHelp bool
 	helpScrollOffset int
@@ -83,6 +114,9 @@ type Model struct {
 	newContactField  int // Which field is being edited
 	newContactInputs []textinput.Model
 	newContactRelTypeIdx int // Selected relationship type for new contact
+	duplicates       []db.Duplicate // Existing contacts the new contact may duplicate
+	duplicateSeq     int            // Latest duplicate check; older results are ignored
+	duplicateConfirm bool           // Saving was stopped by possible duplicates; Enter again creates anyway
 	
 	// Interaction editing mode
 	interactionEditMode bool
@@ -92,6 +126,8 @@ type Model struct {
 	interactionEditType  int // Selected interaction type
 	interactionDeleteConfirm bool
 	interactionToDelete int // ID of interaction to delete
+	attachmentInputMode bool // Typing a file path or URL to attach to the selected interaction
+	attachmentInput     textinput.Model
 	
 	// Contact style mode
 	styleMode bool
@@ -113,6 +149,9 @@ type Model struct {
 	labelPromptContactID int
 	labelPromptNewState string
 	
+	// States from the config, with their colors and whether they create tasks
+	states []config.StateConfig
+	
 	// Menu hotkeys
 	stateHotkeys []MenuHotkey
 	interactionHotkeys []MenuHotkey
@@ -134,6 +173,217 @@ type Model struct {
 	// Dstask error handling
 	dstaskIncompleteError bool   // Special mode for handling incomplete subtasks error
 	dstaskTaskID          string // Task ID that has incomplete subtasks
+	
+	// Jump picker mode
+	pickerMode     bool
+	pickerInput    textinput.Model
+	pickerSelected int
+	
+	// Command palette mode
+	paletteMode     bool
+	paletteInput    textinput.Model
+	paletteSelected int
+	stashedFilters *filterState // Filters suspended by a jump, restored with Esc
+	
+	// Full-text search of notes
+	noteSearchMode     bool
+	noteSearchInput    textinput.Model
+	noteSearchResults  []db.NoteMatch
+	noteSearchSelected int
+	
+	// Import mode
+	importPromptMode bool
+	importPathInput  textinput.Model
+	importRunning    bool // Import in progress, showing the progress bar
+	importSummary    bool // Import finished, showing the summary screen
+	importPath       string
+	
+	// Export of the filtered contacts to CSV
+	exportPromptMode bool
+	exportPathInput  textinput.Model
+	importProgress   importer.Progress
+	importErr        error
+	importEnriched   int // Companies filled in from email domains after the import
+	importAvatars    avatar.Summary
+	
+	// Text message mode
+	textPromptMode bool
+	textSending    bool // Message is being sent by the messaging command
+	textInput      textinput.Model
+	textContactID  int
+	
+	// Important dates mode
+	datesMode         bool
+	datesContactID    int
+	dates             []db.ImportantDate
+	datesSelected     int
+	dateFormMode      bool
+	dateFormEditID    int // Date being edited (0 = new)
+	dateFormField     int
+	dateFormRecurring bool
+	dateLabelInput    textinput.Model
+	dateDateInput     textinput.Model
+	
+	// Email addresses or phone numbers mode
+	valuesMode      bool
+	valuesKind      *valueKind
+	valuesContactID int
+	values          []db.ContactValue
+	valuesSelected  int
+	valueFormMode   bool
+	valueFormEditID int // Value being edited (0 = new)
+	valueFormType   string
+	valueInput      textinput.Model
+	
+	// Linked contacts mode
+	linksMode      bool
+	linksContactID int
+	links          []db.ContactLink
+	linksSelected  int
+	linkFormMode   bool
+	linkKind       int // Index into db.LinkKinds
+	linkResult     int // Selected match for the contact to link to
+	linkInput      textinput.Model
+	
+	// Journal mode
+	journalMode          bool
+	journal              []db.LogEntry
+	journalSelected      int
+	journalEditing       bool
+	journalEditID        int // Entry being edited (0 = new)
+	journalInput         textarea.Model
+	journalDeleteConfirm bool
+	
+	// Contact history mode
+	historyMode      bool
+	historyContactID int
+	history          []db.HistoryEntry
+	historySelected  int
+	
+	// Snooze overlay
+	snoozeMode     bool
+	snoozeContact  db.Contact
+	snoozeSelected int
+	snoozeCustom   bool // Typing a custom date
+	snoozeInput    textinput.Model
+	
+	// Sort overlay
+	sortMode     bool
+	sortSelected int
+	sortOrder    string // Order the list is sorted in, as in the sort config
+	
+	// Share of the window the contact list takes, as in the list_width config
+	listRatio float64
+	
+	// The selected contact's details replace the list in single-pane layout
+	detailOpen bool
+	
+	// Marked contacts and the bulk action overlay
+	marked            map[int]bool // IDs of the contacts marked with space or V
+	markAnchor        int          // Contact V marks a range from
+	bulkMode          bool
+	bulkAction        string
+	bulkConfirm       bool
+	bulkStateSelected int
+	bulkTagInput      textinput.Model
+	
+	// Postal address form
+	addressMode      bool
+	addressContactID int
+	addressField     int
+	addressInputs    []textinput.Model
+	
+	// Groups overlay
+	groupsMode         bool
+	groups             []db.Group
+	groupsSelected     int
+	groupsContactID    int // Contact space adds to or removes from a group
+	groupInputMode     bool
+	groupRenameID      int // Group being renamed (0 = new)
+	groupInput         textinput.Model
+	groupStateMode     bool // Picking a state for every member of a group
+	groupStateSelected int
+	
+	// Changes u can reverse, oldest first
+	undoStack []undoEntry
+	
+	// Trash of deleted contacts
+	trashMode         bool
+	trash             []db.Contact
+	trashSelected     int
+	trashConfirmPurge bool
+	
+	// Agenda of upcoming important dates
+	agendaMode     bool
+	agenda         []db.UpcomingDate
+	agendaSelected int
+	
+	// Follow-ups and deadlines due, shown at startup
+	alertsMode     bool
+	alerts         []followUpAlert
+	alertsSelected int
+	
+	// Dashboard summarizing what needs attention
+	dashboardMode     bool
+	dashboard         []dashboardItem
+	dashboardSelected int
+	
+	// QR code of the selected contact's card
+	qrMode  bool
+	qrName  string
+	qrLines []string
+	
+	// Editing single fields in the detail pane
+	inlineMode    bool
+	inlineField   int  // Index into inlineFields
+	inlineEditing bool // The field's input is open
+	inlineInput   textinput.Model
+	
+	// Background sync
+	syncBackend  syncer.Backend // nil when sync is disabled
+	syncInterval time.Duration
+	syncSpinner  spinner.Model
+	syncing      bool
+	lastSynced   time.Time
+	syncErr      error
+	
+	// User scripts (nil when there is no script file)
+	scripts *scripting.Engine
+	
+	// Filtered list cache, keyed on the filters and contacts it was built from
+	contactsVersion int      // Incremented whenever contacts are reloaded
+	datesDue        map[int]bool // Contacts with an important date or birthday coming up
+	searchText      []string // Lowercased filter text, parallel to contacts
+	filteredValid   bool
+	filteredKey     filterCacheKey
+	filtered        []db.Contact
+	
+	// Detail pane cache, refreshed after each update instead of on every render
+	detailContactID    int // Contact the cached interactions belong to (0 = stale)
+	detailInteractions []db.Log
+	detailDates        []db.ImportantDate
+	detailEmails       []db.ContactValue
+	detailPhones       []db.ContactValue
+	detailHandles      []db.ContactValue
+	detailLinks        []db.ContactLink
+	detailJournal      []db.LogEntry
+	detailFields       map[string]string // Custom field values by field name
+	detailRatings      db.RatingSummary
+	detailDurations    db.DurationSummary
+	
+	// Detail pane tabs; the Tasks and History tabs load for one contact at a time
+	detailTab          int
+	detailTabContactID int // Contact the tab's data belongs to (0 = stale)
+	detailTabLoaded    int // Tab the data was loaded for
+	detailTabErr       string
+	detailTasks        []tasks.Task
+	detailHistory      []db.HistoryEntry
+	detailAvatar       string   // Avatar file of the contact, if it has one
+	detailAvatarLines  []string // The avatar as drawn in the detail pane
+	pendingGraphics    string   // Image data to send to the terminal
+	avatarMode         string   // How avatars are drawn (see avatar.DetectDisplay)
+	detailScroll       int      // Lines of the detail pane scrolled past with the wheel
+	detailScrollID     int      // Contact detailScroll applies to
 }
 
 // MenuHotkey represents a menu item with its assigned hotkey
@@ -226,6 +476,17 @@ var RelationshipTypes = []string{
 	"recruiters",
 }
 
+// defaultRelTypeIdx returns the index, skipping "all", of the relationship
+// type new contacts start with: "network" when it exists, else the first
+func defaultRelTypeIdx() int {
+	for i, rType := range RelationshipTypes[1:] {
+		if rType == "network" {
+			return i
+		}
+	}
+	return 0
+}
+
 // Available interaction types
 var InteractionTypes = []string{
 	"manual",
@@ -251,52 +512,38 @@ const (
 	EditFieldEmail
 	EditFieldPhone
 	EditFieldCompany
+	EditFieldLocation
 	EditFieldRelType
 	EditFieldNotes
 	EditFieldLabel
+	EditFieldBirthday
+	EditFieldTags
+	EditFieldAvatar
+	EditFieldFollowUp
+	EditFieldDeadline
+	EditFieldTimezone
 	EditFieldCount // Total number of fields
 )
 
-// Styles
+// Styles, set from the theme by applyTheme
 var (
-	// Contact list selection - no background, just bold and bright
-	selectedStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("214")) // Orange
-	
-	// Note type selector style - no background, just bold brackets
-	noteTypeSelectorStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("214")) // Orange
-	
-	overdueStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("196"))
-	
-	stateStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("214")) // Orange for states
-	
-	labelStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241"))
-	
-	borderStyle = lipgloss.NewStyle().
-			BorderStyle(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color("240"))
-	
-	dimmedStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("238")) // Dim gray for archived
-	
-	greenStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("34")) // Green for ambient
-	
-	yellowStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("226")) // Yellow for triggered
+	selectedStyle         lipgloss.Style // Contact list selection
+	noteTypeSelectorStyle lipgloss.Style
+	overdueStyle          lipgloss.Style
+	stateStyle            lipgloss.Style
+	labelStyle            lipgloss.Style
+	borderStyle           lipgloss.Style
+	dimmedStyle           lipgloss.Style // Archived contacts
+	greenStyle            lipgloss.Style // Ambient contacts
+	yellowStyle           lipgloss.Style // Triggered contacts
 )
 
-// setFlash sets a flash message that will be displayed at the top of the screen
+// setFlash sets a flash message that will be displayed in the status bar
 func (m Model) setFlash(flashType FlashType, message string) Model {
 	m.flashMessage = message
 	m.flashType = flashType
 	m.flashJustSet = true
+	m.flashSeq++
 	return m
 }
 
@@ -308,6 +555,19 @@ func (m Model) clearFlash() Model {
 
 // New creates a new application model
 func New(database *db.DB, cfg *config.Config) (*Model, error) {
+	// Colors, before any styles are built from them
+	var themeErr error
+	if cfg != nil {
+		var t Theme
+		t, themeErr = themeFor(cfg.Theme)
+		applyTheme(t)
+	}
+	if _, adaptive := theme.Accent.(lipgloss.AdaptiveColor); adaptive {
+		// Look up the terminal's background now, as asking once the
+		// program has the terminal can hang
+		lipgloss.HasDarkBackground()
+	}
+	
 	// Load initial contacts
 	contacts, err := database.ListContacts()
 	if err != nil {
@@ -320,9 +580,9 @@ func New(database *db.DB, cfg *config.Config) (*Model, error) {
 	ti.Width = 30 // Generous default width
 	ti.CharLimit = 50
 	ti.Prompt = "> " // Explicitly set the prompt
-	ti.TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("230"))
-	ti.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
-	ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("238"))
+	ti.TextStyle = lipgloss.NewStyle().Foreground(theme.Text)
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(theme.Muted)
+	ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(theme.Dim)
 	
 	// Setup note input
 	ta := textarea.New()
@@ -348,12 +608,27 @@ func New(database *db.DB, cfg *config.Config) (*Model, error) {
 			editInputs[i].Placeholder = "Phone"
 		case EditFieldCompany:
 			editInputs[i].Placeholder = "Company"
+		case EditFieldLocation:
+			editInputs[i].Placeholder = "Location (e.g. Seattle, WA)"
 		case EditFieldNotes:
 			editInputs[i].Placeholder = "Notes"
 		case EditFieldLabel:
 			editInputs[i].Placeholder = "Label (e.g. @john)"
+		case EditFieldBirthday:
+			editInputs[i].Placeholder = "Birthday (YYYY-MM-DD, or MM-DD)"
+		case EditFieldTags:
+			editInputs[i].Placeholder = "Tags (e.g. #mentor #neighbor)"
+		case EditFieldAvatar:
+			editInputs[i].Placeholder = "Avatar image (e.g. ~/Pictures/sarah.jpg)"
+		case EditFieldFollowUp:
+			editInputs[i].Placeholder = "Follow up on (YYYY-MM-DD, or e.g. 2w)"
+		case EditFieldDeadline:
+			editInputs[i].Placeholder = "Deadline (YYYY-MM-DD, or e.g. 1m)"
+		case EditFieldTimezone:
+			editInputs[i].Placeholder = "Time zone (e.g. America/New_York)"
 		}
 	}
+	editInputs = append(editInputs, newFieldInputs(cfg)...)
 	
 	// Setup new contact inputs (same as edit inputs)
 	newContactInputs := make([]textinput.Model, EditFieldCount)
@@ -371,12 +646,27 @@ func New(database *db.DB, cfg *config.Config) (*Model, error) {
 			newContactInputs[i].Placeholder = "Phone"
 		case EditFieldCompany:
 			newContactInputs[i].Placeholder = "Company"
+		case EditFieldLocation:
+			newContactInputs[i].Placeholder = "Location (e.g. Seattle, WA)"
 		case EditFieldNotes:
 			newContactInputs[i].Placeholder = "Notes"
 		case EditFieldLabel:
 			newContactInputs[i].Placeholder = "Label (e.g. @john)"
+		case EditFieldBirthday:
+			newContactInputs[i].Placeholder = "Birthday (YYYY-MM-DD, or MM-DD)"
+		case EditFieldTags:
+			newContactInputs[i].Placeholder = "Tags (e.g. #mentor #neighbor)"
+		case EditFieldAvatar:
+			newContactInputs[i].Placeholder = "Avatar image (e.g. ~/Pictures/sarah.jpg)"
+		case EditFieldFollowUp:
+			newContactInputs[i].Placeholder = "Follow up on (YYYY-MM-DD, or e.g. 2w)"
+		case EditFieldDeadline:
+			newContactInputs[i].Placeholder = "Deadline (YYYY-MM-DD, or e.g. 1m)"
+		case EditFieldTimezone:
+			newContactInputs[i].Placeholder = "Time zone (e.g. America/New_York)"
 		}
 	}
+	newContactInputs = append(newContactInputs, newFieldInputs(cfg)...)
 	
 	// Setup interaction edit textarea
 	interactionTA := textarea.New()
@@ -398,6 +688,87 @@ func New(database *db.DB, cfg *config.Config) (*Model, error) {
 	labelPromptInput.Width = 30
 	labelPromptInput.CharLimit = 50
 	
+	// Setup archive reason input
+	archiveReasonInput := textinput.New()
+	archiveReasonInput.Placeholder = "e.g. moved away, left the company"
+	archiveReasonInput.Width = 50
+	archiveReasonInput.CharLimit = 200
+	
+	// Setup jump picker input
+	pickerInput := textinput.New()
+	pickerInput.Placeholder = "Name, label or company"
+	pickerInput.Width = 40
+	pickerInput.CharLimit = 50
+	
+	// Setup bulk tag input
+	bulkTagInput := textinput.New()
+	bulkTagInput.Placeholder = "#conference2024"
+	bulkTagInput.Width = 40
+	bulkTagInput.CharLimit = 50
+	
+	// Setup command palette input
+	paletteInput := textinput.New()
+	paletteInput.Placeholder = "Archive, filter, export..."
+	paletteInput.Width = 40
+	paletteInput.CharLimit = 50
+	
+	attachmentInput := textinput.New()
+	attachmentInput.Placeholder = "~/Documents/resume.pdf or https://..."
+	attachmentInput.Width = 60
+	attachmentInput.CharLimit = 500
+	
+	linkInput := textinput.New()
+	linkInput.Placeholder = "Name, label or company"
+	linkInput.Width = 40
+	linkInput.CharLimit = 50
+	
+	snoozeInput := textinput.New()
+	snoozeInput.Placeholder = "2025-01-31 or 10d, 3w, 2m"
+	snoozeInput.Width = 30
+	snoozeInput.CharLimit = 20
+	
+	noteSearchInput := textinput.New()
+	noteSearchInput.Placeholder = "e.g. kubernetes"
+	noteSearchInput.Width = 50
+	noteSearchInput.CharLimit = 100
+	
+	// Setup import path input
+	importPathInput := textinput.New()
+	importPathInput.Placeholder = "~/Downloads/contacts.csv"
+	importPathInput.Width = 50
+	importPathInput.CharLimit = 256
+	
+	// Setup export path input
+	exportPathInput := textinput.New()
+	exportPathInput.Width = 50
+	exportPathInput.CharLimit = 256
+	
+	// Setup text message input
+	textInput := textinput.New()
+	textInput.Placeholder = "Message"
+	textInput.Width = 58
+	textInput.CharLimit = 320
+	
+	// Setup important date inputs
+	dateLabelInput := textinput.New()
+	dateLabelInput.Placeholder = "e.g. Work anniversary"
+	dateLabelInput.Width = 40
+	dateLabelInput.CharLimit = 80
+	dateDateInput := textinput.New()
+	dateDateInput.Placeholder = "YYYY-MM-DD"
+	dateDateInput.Width = 12
+	dateDateInput.CharLimit = 10
+	
+	// Setup email address and phone number input
+	valueInput := textinput.New()
+	valueInput.Width = 50
+	
+	// Setup group name input
+	groupInput := textinput.New()
+	groupInput.Placeholder = "e.g. book club"
+	groupInput.Width = 50
+	groupInput.CharLimit = 60
+	
 	// Create task manager (use configured backend or auto-detect)
 	taskBackend := ""
 	if cfg != nil && cfg.Tasks.Backend != "" {
@@ -408,32 +779,375 @@ func New(database *db.DB, cfg *config.Config) (*Model, error) {
 		// If task manager creation fails, we can still run without it
 		taskManager, _ = tasks.NewManager("noop")
 	}
+	if cfg != nil {
+		taskManager.MapProjects(cfg.Tasks.Projects, database.RelationshipTypeOf)
+	}
+	
+	// The relationship types come from the config when it lists them. Types
+	// contacts have that the config doesn't list stay selectable, so editing
+	// those contacts doesn't change their type.
+	var unlistedTypes []string
+	if cfg != nil && len(cfg.Relationships.Types) > 0 {
+		unlistedTypes, err = database.AllowRelationshipTypes(cfg.Relationships.Types)
+		if err != nil {
+			return nil, fmt.Errorf("updating relationship types: %w", err)
+		}
+		RelationshipTypes = append([]string{"all"}, cfg.Relationships.Types...)
+		RelationshipTypes = append(RelationshipTypes, unlistedTypes...)
+		db.SetCadences(cfg.Relationships.Cadence, cfg.Relationships.DefaultCadence)
+	}
+	
+	// So do the states, with any that contacts are in that it doesn't list
+	states := config.DefaultStates
+	var unlistedStates []string
+	if cfg != nil {
+		states = cfg.States.Defined()
+		unlistedStates, err = database.AllowStates(cfg.States.Names())
+		if err != nil {
+			return nil, fmt.Errorf("updating states: %w", err)
+		}
+		ContactStates = append(cfg.States.Names(), unlistedStates...)
+	}
 	
-	return &Model{
+	model := &Model{
 		db:         database,
 		cfg:        cfg,
-		contacts:   contacts,
 		filter:     ti,
 		noteInput:  ta,
 		editInputs: editInputs,
 		newContactInputs: newContactInputs,
 		interactionEditInput: interactionTA,
+		journalInput: newJournalInput(),
 		customFreqInput: customFreqInput,
 		labelPromptInput: labelPromptInput,
+		archiveReasonInput: archiveReasonInput,
+		pickerInput: pickerInput,
+		paletteInput: paletteInput,
+		bulkTagInput: bulkTagInput,
+		noteSearchInput: noteSearchInput,
+		attachmentInput: attachmentInput,
+		importPathInput: importPathInput,
+		exportPathInput: exportPathInput,
+		textInput: textInput,
+		dateLabelInput: dateLabelInput,
+		dateDateInput: dateDateInput,
+		valueInput: valueInput,
+		linkInput: linkInput,
+		snoozeInput: snoozeInput,
+		groupInput: groupInput,
+		addressInputs: newAddressInputs(),
 		taskManager: taskManager,
 		stateHotkeys: assignHotkeys(ContactStates),
 		interactionHotkeys: assignHotkeys(InteractionTypes),
 		relationshipHotkeys: assignHotkeys(RelationshipTypes),
-	}, nil
+		avatarMode: avatarDisplay(cfg),
+		states:     states,
+	}
+	if cfg != nil {
+		model.sortOrder = cfg.UI.Sort
+		model.listRatio = clampListRatio(cfg.UI.ListWidth)
+	}
+	model.setContacts(contacts)
+	
+	// Purge contacts in the trash past the retention period
+	*model = model.emptyExpiredTrash()
+	
+	// Remind about important dates coming up
+	if reminder := model.upcomingDatesReminder(); reminder != "" {
+		*model = model.setFlash(FlashInfo, reminder)
+	}
+	
+	// Point out types and states in use that the config doesn't list
+	if len(unlistedTypes) > 0 {
+		*model = model.setFlash(FlashInfo, fmt.Sprintf("Relationship types not in config: %s (add them, or use contacts-tui types merge)",
+			strings.Join(unlistedTypes, ", ")))
+	}
+	if len(unlistedStates) > 0 {
+		*model = model.setFlash(FlashInfo, fmt.Sprintf("States not in config: %s (add them to [[states.list]])",
+			strings.Join(unlistedStates, ", ")))
+	}
+	
+	if themeErr != nil {
+		*model = model.setFlash(FlashError, themeErr.Error())
+	}
+	
+	// List follow-ups and deadlines that have come due
+	if cfg == nil || cfg.UI.FollowUpAlerts {
+		*model = model.openAlerts()
+	}
+	
+	// Start on the dashboard, under the alerts
+	if cfg != nil && cfg.UI.Dashboard {
+		*model = model.openDashboard()
+	}
+	
+	// Load user script filters, score and automations
+	if cfg != nil {
+		scripts, err := scripting.Load(cfg.Scripting.File)
+		if err != nil {
+			*model = model.setFlash(FlashError, fmt.Sprintf("Scripts disabled: %v", err))
+		}
+		model.scripts = scripts
+	}
+	
+	// Set up background sync if configured
+	if cfg != nil && cfg.Sync.Backend != "" {
+		model.syncSpinner = spinner.New(spinner.WithSpinner(spinner.MiniDot), spinner.WithStyle(labelStyle))
+		backend, err := syncer.Create(cfg.Sync.Backend, cfg)
+		if err != nil {
+			*model = model.setFlash(FlashError, fmt.Sprintf("Sync disabled: %v", err))
+		} else {
+			// Backends that replace the database file take turns with this connection
+			if fileBackend, ok := backend.(syncer.FileBackend); ok {
+				fileBackend.SetLocker(database)
+			}
+			model.syncBackend = backend
+			model.syncing = true // Init starts the first sync
+			if cfg.Sync.Interval != "" {
+				interval, err := time.ParseDuration(cfg.Sync.Interval)
+				if err != nil {
+					return nil, fmt.Errorf("parsing sync interval: %w", err)
+				}
+				model.syncInterval = interval
+			}
+		}
+	}
+	
+	return model, nil
 }
 
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
-	return nil
+	return tea.Batch(m.initSync(), m.initEscalation())
 }
 
 // Update handles messages
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	if updated, ok := model.(Model); ok {
+		updated = updated.promoteError()
+		updated.refreshFilteredCache()
+		updated.refreshDetailCache()
+		updated.refreshDetailTab()
+		if updated.pendingGraphics != "" {
+			cmd = tea.Batch(cmd, sendGraphics(updated.pendingGraphics))
+			updated.pendingGraphics = ""
+		}
+		if updated.flashSeq != m.flashSeq {
+			cmd = tea.Batch(cmd, updated.expireFlash())
+		}
+		return updated, cmd
+	}
+	return model, cmd
+}
+
+// refreshDetailCache reloads the selected contact's recent interactions
+// when the selection changed or the cache was invalidated
+func (m *Model) refreshDetailCache() {
+	contacts := m.filteredContacts()
+	if len(contacts) == 0 || m.selected >= len(contacts) {
+		m.detailContactID = 0
+		m.detailInteractions = nil
+		return
+	}
+	
+	contact := contacts[m.selected]
+	contactID := contact.ID
+	if contactID == m.detailContactID {
+		return
+	}
+	
+	interactions, err := m.db.GetContactInteractions(contactID, -1) // -1: no limit
+	if err != nil {
+		m.detailContactID = 0
+		m.detailInteractions = nil
+		return
+	}
+	dates, err := m.db.ListImportantDates(contactID)
+	if err != nil {
+		m.detailContactID = 0
+		m.detailInteractions = nil
+		return
+	}
+	emails, err := m.db.ListEmails(contactID)
+	if err != nil {
+		m.detailContactID = 0
+		m.detailInteractions = nil
+		return
+	}
+	phones, err := m.db.ListPhones(contactID)
+	if err != nil {
+		m.detailContactID = 0
+		m.detailInteractions = nil
+		return
+	}
+	handles, err := m.db.ListHandles(contactID)
+	if err != nil {
+		m.detailContactID = 0
+		m.detailInteractions = nil
+		return
+	}
+	links, err := m.db.ListLinks(contactID)
+	if err != nil {
+		m.detailContactID = 0
+		m.detailInteractions = nil
+		return
+	}
+	fields, err := m.db.FieldValues(contactID)
+	if err != nil {
+		m.detailContactID = 0
+		m.detailInteractions = nil
+		return
+	}
+	journal, err := m.db.ContactLogs(contactID, detailJournalEntries)
+	if err != nil {
+		m.detailContactID = 0
+		m.detailInteractions = nil
+		return
+	}
+	ratings, err := m.db.GetRatingSummary(contactID)
+	if err != nil {
+		m.detailContactID = 0
+		m.detailInteractions = nil
+		return
+	}
+	durations, err := m.db.GetDurationSummary(contactID)
+	if err != nil {
+		m.detailContactID = 0
+		m.detailInteractions = nil
+		return
+	}
+	m.detailContactID = contactID
+	m.detailInteractions = interactions
+	m.detailDates = dates
+	m.detailEmails = emails
+	m.detailPhones = phones
+	m.detailHandles = handles
+	m.detailLinks = links
+	m.detailJournal = journal
+	m.detailFields = fields
+	m.detailRatings = ratings
+	m.detailDurations = durations
+	m.loadAvatar(contact)
+}
+
+// invalidateDetailCache forces the detail pane to reload interactions
+// after data has been changed
+func (m *Model) invalidateDetailCache() {
+	m.detailContactID = 0
+	m.detailTabContactID = 0
+}
+
+// completeTask completes m.taskToComplete with an optional note, records the
+// completion in the contact's history and offers to update the contact state
+func (m Model) completeTask(completionNote string) (tea.Model, tea.Cmd) {
+	// First, complete the task in TaskWarrior
+	err := m.taskManager.Backend().CompleteTask(m.taskToComplete.ID, completionNote)
+	if err != nil {
+		// Check if this is a dstask incomplete subtasks error
+		if strings.Contains(err.Error(), "Refusing to resolve task with incomplete tasklist") {
+			m.dstaskIncompleteError = true
+			m.dstaskTaskID = m.taskToComplete.ID
+			m.err = fmt.Errorf("Task has incomplete subtasks")
+		} else {
+			m.err = fmt.Errorf("completing task: %w", err)
+		}
+		m.taskCompletionMode = false
+		m.taskCompletionInput.Reset()
+		return m, nil
+	}
+	
+	// Add the completion note to contact's interaction history
+	if m.taskViewContactID > 0 {
+		contact, err := m.db.GetContact(m.taskViewContactID)
+		if err == nil && contact != nil {
+			// Create interaction note with task context
+			interactionNote := fmt.Sprintf("Completed task \"%s\"", m.taskToComplete.Description)
+			if completionNote != "" {
+				interactionNote = fmt.Sprintf("Completed task \"%s\": %s", m.taskToComplete.Description, completionNote)
+			}
+			
+			err = m.db.AddInteractionNote(contact.ID, "task", interactionNote, 0, 0)
+			if err != nil {
+				m.err = fmt.Errorf("adding interaction note: %w", err)
+			}
+			m.invalidateDetailCache()
+		}
+	}
+	
+	// Prepare success message but don't show it yet - wait until after state prompt
+	m.pendingSuccessMsg = fmt.Sprintf("✓ Completed: %s", m.taskToComplete.Description)
+	
+	// Refresh task list
+	if m.taskViewContactID > 0 {
+		contact, err := m.db.GetContact(m.taskViewContactID)
+		if err == nil && contact != nil && contact.Label.Valid && contact.Label.String != "" {
+			if tasks, err := m.taskManager.Backend().GetContactTasks(contact.Label.String); err == nil {
+				m.tasks = tasks
+				// Adjust selected task if we're at the end
+				if m.selectedTask >= len(m.tasks) && len(m.tasks) > 0 {
+					m.selectedTask = len(m.tasks) - 1
+				} else if len(m.tasks) == 0 {
+					m.selectedTask = 0
+				}
+			}
+		}
+	}
+	
+	// Clean up and exit task completion mode
+	m.taskCompletionMode = false
+	m.taskCompletionInput.Reset()
+	m.taskToComplete = tasks.Task{}
+	
+	// Check if we should prompt for state update
+	if m.taskViewContactID > 0 {
+		contact, err := m.db.GetContact(m.taskViewContactID)
+		if err == nil && contact != nil {
+			// Check if contact is in a state that created a task
+			stateStr := strings.ToLower(strings.TrimSpace(contact.State.String))
+			if contact.State.Valid && m.stateCreatesTask(stateStr) {
+				// Set up state update prompt
+				m.stateUpdatePromptMode = true
+				m.stateUpdateContactID = contact.ID
+				m.stateUpdateFromState = contact.State.String
+				m.stateUpdateToState = "ok"
+				return m, nil
+			}
+		}
+	}
+	
+	// If no state update needed, show success message immediately
+	if m.pendingSuccessMsg != "" {
+		m = m.setFlash(FlashSuccess, m.pendingSuccessMsg)
+	}
+	m.pendingSuccessMsg = ""
+	
+	// Exit task mode if no more tasks
+	if len(m.tasks) == 0 {
+		m.taskMode = false
+		m.taskViewContactID = 0  // Clear the contact ID
+	}
+	
+	return m, nil
+}
+
+// update handles messages; Update wraps it to refresh cached state
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Background sync messages are handled in every mode
+	if updated, cmd, handled := m.updateSync(msg); handled {
+		return updated, cmd
+	}
+	
+	// Status bar messages expire in every mode
+	if msg, ok := msg.(flashExpireMsg); ok {
+		return m.handleFlashExpire(msg), nil
+	}
+	
+	// Escalation runs in the background at startup
+	if msg, ok := msg.(escalationDoneMsg); ok {
+		return m.handleEscalationDone(msg), nil
+	}
+	
 	// Task completion mode handling - needs to be before main type switch
 	// to handle all message types, not just KeyMsg
 	if m.taskCompletionMode {
@@ -452,97 +1166,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if key.Type == tea.KeyCtrlJ || key.Type == tea.KeyCtrlM {
 				// Complete the task with the note
 				completionNote := strings.TrimSpace(m.taskCompletionInput.Value())
-				
-				// First, complete the task in TaskWarrior
-				err := m.taskManager.Backend().CompleteTask(m.taskToComplete.ID, completionNote)
-				if err != nil {
-					// Check if this is a dstask incomplete subtasks error
-					if strings.Contains(err.Error(), "Refusing to resolve task with incomplete tasklist") {
-						m.dstaskIncompleteError = true
-						m.dstaskTaskID = m.taskToComplete.ID
-						m.err = fmt.Errorf("Task has incomplete subtasks")
-					} else {
-						m.err = fmt.Errorf("completing task: %w", err)
-					}
-					m.taskCompletionMode = false
-					m.taskCompletionInput.Reset()
-					return m, nil
-				}
-				
-				// Add the completion note to contact's interaction history
-				if m.taskViewContactID > 0 {
-					contact, err := m.db.GetContact(m.taskViewContactID)
-					if err == nil && contact != nil {
-						// Create interaction note with task context
-						interactionNote := fmt.Sprintf("Completed task \"%s\"", m.taskToComplete.Description)
-						if completionNote != "" {
-							interactionNote = fmt.Sprintf("Completed task \"%s\": %s", m.taskToComplete.Description, completionNote)
-						}
-						
-						err = m.db.AddInteractionNote(contact.ID, "task", interactionNote)
-						if err != nil {
-							m.err = fmt.Errorf("adding interaction note: %w", err)
-						}
-					}
-				}
-				
-				// Prepare success message but don't show it yet - wait until after state prompt
-				m.pendingSuccessMsg = fmt.Sprintf("✓ Completed: %s", m.taskToComplete.Description)
-				
-				// Refresh task list
-				if m.taskViewContactID > 0 {
-					contact, err := m.db.GetContact(m.taskViewContactID)
-					if err == nil && contact != nil && contact.Label.Valid && contact.Label.String != "" {
-						if tasks, err := m.taskManager.Backend().GetContactTasks(contact.Label.String); err == nil {
-							m.tasks = tasks
-							// Adjust selected task if we're at the end
-							if m.selectedTask >= len(m.tasks) && len(m.tasks) > 0 {
-								m.selectedTask = len(m.tasks) - 1
-							} else if len(m.tasks) == 0 {
-								m.selectedTask = 0
-							}
-						}
-					}
-				}
-				
-				// Clean up and exit task completion mode
-				m.taskCompletionMode = false
-				m.taskCompletionInput.Reset()
-				m.taskToComplete = tasks.Task{}
-				
-				// Check if we should prompt for state update
-				if m.taskViewContactID > 0 {
-					contact, err := m.db.GetContact(m.taskViewContactID)
-					if err == nil && contact != nil {
-						// Check if contact has a state that suggests follow-up was needed
-						stateStr := strings.ToLower(strings.TrimSpace(contact.State.String))
-						if contact.State.Valid && (stateStr == "followup" || 
-							stateStr == "write" || 
-							stateStr == "ping" ||
-							stateStr == "scheduled") {
-							// Set up state update prompt
-							m.stateUpdatePromptMode = true
-							m.stateUpdateContactID = contact.ID
-							m.stateUpdateFromState = contact.State.String
-							m.stateUpdateToState = "ok"
-							return m, nil
-						}
-					}
-				}
-				
-				// If no state update needed, show success message immediately
-				if m.pendingSuccessMsg != "" {
-					m = m.setFlash(FlashSuccess, m.pendingSuccessMsg)
-				}
-				m.pendingSuccessMsg = ""
-				
-				// Exit task mode if no more tasks
-				if len(m.tasks) == 0 {
-					m.taskMode = false
-					m.taskViewContactID = 0  // Clear the contact ID
-				}
-				
-				return m, nil
+				return m.completeTask(completionNote)
 			}
 		}
 		
@@ -558,18 +1182,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch key.String() {
 			case "y", "Y":
 				// Update the contact's state
+				before := m.snapshot(m.stateUpdateContactID)
 				err := m.db.UpdateContactState(m.stateUpdateContactID, m.stateUpdateToState)
 				if err != nil {
 					m.err = fmt.Errorf("updating contact state: %w", err)
 				} else {
+					if contact, err := m.db.GetContact(m.stateUpdateContactID); err == nil && contact != nil {
+						m = m.pushUndo(fmt.Sprintf("state change of %s", contact.Name), before)
+					}
 					// Show the pending success message if we have one
 					if m.pendingSuccessMsg != "" {
 						m = m.setFlash(FlashSuccess, m.pendingSuccessMsg)
 					}
 					// Refresh contacts to show the updated state
 					if contacts, err := m.db.ListContacts(); err == nil {
-						m.contacts = contacts
+						m.setContacts(contacts)
 					}
+					m = m.runAutomations(scripting.EventState, m.stateUpdateContactID)
 				}
 				m.stateUpdatePromptMode = false
 				m.pendingSuccessMsg = ""  // Clear pending message
@@ -603,8 +1232,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		// Update filter width when window size changes
 		if m.width > 0 {
-			listWidth := m.width / 3
-			m.filter.Width = listWidth - 4 // account for borders and padding
+			m.filter.Width = m.listWidth() - 4 // account for borders and padding
 		}
 		return m, nil
 	
@@ -630,6 +1258,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 	
+	case importMsg:
+		return m.handleImportMsg(msg)
+	
+	case emailDoneMsg:
+		return m.handleEmailDone(msg), nil
+	
+	case callDoneMsg:
+		return m.handleCallDone(msg), nil
+	
+	case textSentMsg:
+		return m.handleTextSent(msg), nil
+	
+	case openedMsg:
+		return m.handleOpened(msg), nil
+	
+	case tea.MouseMsg:
+		return m.updateMouse(msg)
+	
+	case duplicateCheckMsg:
+		return m.handleDuplicateCheck(msg), nil
+	
 	case error:
 		// Handle errors returned from commands
 		m.err = msg
@@ -642,25 +1291,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.flashJustSet = false
 		
-		// Error state handling with special dstask handling
-		if m.err != nil {
+		// The dstask incomplete subtasks error offers 'e' from the status bar;
+		// any other key dismisses the offer and is handled normally
+		if m.dstaskIncompleteError {
+			m.dstaskIncompleteError = false
+			taskID := m.dstaskTaskID
+			m.dstaskTaskID = ""
 			switch msg.String() {
 			case "esc":
-				m.err = nil
-				m.dstaskIncompleteError = false
-				m.dstaskTaskID = ""
+				m = m.clearFlash()
 				return m, nil
-			case "q":
-				return m, tea.Quit
 			case "e":
-				// Only handle 'e' if this is a dstask incomplete error
-				if m.dstaskIncompleteError && m.dstaskTaskID != "" {
-					// Clear the error state
-					m.err = nil
-					m.dstaskIncompleteError = false
-					taskID := m.dstaskTaskID
+				if taskID != "" {
+					m = m.clearFlash()
 					contactID := m.taskViewContactID  // Capture this before any state changes
-					m.dstaskTaskID = ""
 					
 					// Create command to edit dstask note
 					c := exec.Command("dstask", taskID, "note")
@@ -675,8 +1319,116 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					})
 				}
 			}
-			// For any other key in error state, do nothing
-			return m, nil
+		}
+		
+		// Jump picker mode handling
+		if m.pickerMode {
+			return m.updatePicker(msg)
+		}
+		
+		// Command palette handling
+		if m.paletteMode {
+			return m.updatePalette(msg)
+		}
+		
+		// Notes search handling
+		if m.noteSearchMode {
+			return m.updateNoteSearch(msg)
+		}
+		
+		// Export prompt handling
+		if m.exportPromptMode {
+			return m.updateExport(msg)
+		}
+		
+		// Import prompt, progress and summary handling
+		if m.importPromptMode || m.importRunning || m.importSummary {
+			return m.updateImport(msg)
+		}
+		
+		// Text message prompt handling
+		if m.textPromptMode || m.textSending {
+			return m.updateText(msg)
+		}
+		
+		// Important dates list and form handling
+		if m.datesMode {
+			return m.updateDates(msg)
+		}
+		
+		// Email addresses or phone numbers list and form handling
+		if m.valuesMode {
+			return m.updateValues(msg)
+		}
+		
+		// Linked contacts list and form handling
+		if m.linksMode {
+			return m.updateLinks(msg)
+		}
+		
+		// Journal list and editor handling
+		if m.journalMode {
+			return m.updateJournal(msg)
+		}
+		
+		// Contact history overlay handling
+		if m.historyMode {
+			return m.updateHistory(msg)
+		}
+		
+		// Snooze overlay handling
+		if m.snoozeMode {
+			return m.updateSnooze(msg)
+		}
+		
+		// Sort overlay handling
+		if m.sortMode {
+			return m.updateSort(msg)
+		}
+		
+		// Bulk action overlay handling
+		if m.bulkMode {
+			return m.updateBulk(msg)
+		}
+		
+		// Postal address form handling
+		if m.addressMode {
+			return m.updateAddress(msg)
+		}
+		
+		// Groups overlay handling
+		if m.groupsMode {
+			return m.updateGroups(msg)
+		}
+		
+		// Trash handling
+		if m.trashMode {
+			return m.updateTrash(msg)
+		}
+		
+		// Agenda handling
+		if m.agendaMode {
+			return m.updateAgenda(msg)
+		}
+		
+		// Due follow-ups panel handling
+		if m.alertsMode {
+			return m.updateAlerts(msg)
+		}
+		
+		// Dashboard handling
+		if m.dashboardMode {
+			return m.updateDashboard(msg)
+		}
+		
+		// QR code handling
+		if m.qrMode {
+			return m.updateQRCode(msg)
+		}
+		
+		// Inline field editing
+		if m.inlineMode {
+			return m.updateInlineEdit(msg)
 		}
 		
 		// Relationship type filter mode handling
@@ -687,6 +1439,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.typeSelected = 0
 				return m, nil
 			case "enter":
+				if m.typeSelected >= len(RelationshipTypes) {
+					// Set the tag filter
+					m = m.selectFilterTag(m.typeSelected - len(RelationshipTypes))
+					m.typeFilterMode = false
+					m.typeSelected = 0
+					m.selected = m.ensureValidSelection()
+					return m, nil
+				}
+				
 				// Set the type filter
 				selected := RelationshipTypes[m.typeSelected]
 				if selected == "all" {
@@ -699,7 +1460,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.selected = m.ensureValidSelection()
 				return m, nil
 			case "j", "down":
-				if m.typeSelected < len(RelationshipTypes)-1 {
+				if m.typeSelected < len(RelationshipTypes)+len(m.filterTags)-1 {
 					m.typeSelected++
 				}
 			case "k", "up":
@@ -735,16 +1496,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch msg.String() {
 			case "y", "Y":
 				// Perform the bump
-				err := m.db.BumpContact(m.bumpContactID)
-				if err != nil {
-					m.err = err
-				} else {
-					// Reload contacts to show updated state
-					if newContacts, err := m.db.ListContacts(); err == nil {
-						m.contacts = newContacts
-						m.selected = m.ensureValidSelection()
-					}
-				}
+				m = m.bumpContact(m.bumpContactID)
 				m.bumpConfirmMode = false
 				m.bumpContactID = 0
 				return m, nil
@@ -761,16 +1513,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch msg.String() {
 			case "y", "Y":
 				// Perform the delete
-				err := m.db.DeleteContact(m.deleteContactID)
-				if err != nil {
-					m.err = err
-				} else {
-					// Reload contacts to show updated state
-					if newContacts, err := m.db.ListContacts(); err == nil {
-						m.contacts = newContacts
-						m.selected = m.ensureValidSelection()
-					}
-				}
+				m = m.deleteContact(m.deleteContactID)
 				m.deleteConfirmMode = false
 				m.deleteContactID = 0
 				m.deleteContactName = ""
@@ -784,6 +1527,46 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		
+		// Archive confirmation mode handling
+		if m.archiveConfirmMode {
+			if m.archiveAskReason {
+				switch msg.String() {
+				case "esc":
+					return m.closeArchiveConfirm(), nil
+				case "enter":
+					reason := m.archiveReasonInput.Value()
+					contact, ok := m.archiveContact()
+					m = m.closeArchiveConfirm()
+					if ok {
+						m = m.toggleArchive(contact, reason)
+					}
+					return m, nil
+				}
+				var cmd tea.Cmd
+				m.archiveReasonInput, cmd = m.archiveReasonInput.Update(msg)
+				return m, cmd
+			}
+			switch msg.String() {
+			case "y", "Y":
+				if contact, ok := m.archiveContact(); ok {
+					m = m.toggleArchive(contact, "")
+				}
+			}
+			// Any other key cancels
+			return m.closeArchiveConfirm(), nil
+		}
+		
+		// Purge confirmation mode handling
+		if m.purgeConfirmMode {
+			if msg.String() == "y" || msg.String() == "Y" {
+				m = m.purgeArchived()
+			}
+			// Any other key cancels
+			m.purgeConfirmMode = false
+			m.purgeCandidates = nil
+			return m, nil
+		}
+		
 		// Task mode handling
 		if m.taskMode {
 			switch msg.String() {
@@ -814,6 +1597,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if len(m.tasks) > 0 && m.selectedTask < len(m.tasks) {
 					task := m.tasks[m.selectedTask]
 					m.taskToComplete = task
+					
+					// Complete straight away when completion prompts are turned off
+					if !m.confirmations().TaskCompletion {
+						return m.completeTask("")
+					}
+					
 					m.taskCompletionMode = true
 					
 					// Initialize the task completion textarea
@@ -872,15 +1661,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					newLabel = "@" + newLabel
 				}
 				
-				// Check for uniqueness
-				for _, contact := range m.contacts {
-					if contact.Label.Valid && contact.Label.String == newLabel {
-						m.err = fmt.Errorf("label %s already exists", newLabel)
-						return m, nil
-					}
-				}
-				
-				// Update contact with new label
+				// Update contact with new label; the database rejects one
+				// another contact already has
 				err := m.db.UpdateContactLabel(m.labelPromptContactID, newLabel)
 				if err != nil {
 					m.err = fmt.Errorf("failed to update label: %w", err)
@@ -897,13 +1679,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if taskErr != nil {
 						m.err = fmt.Errorf("label added but task creation failed: %w", taskErr)
 					} else {
-						m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Added label %s and created task", newLabel))
+						m = m.dropUndo(fmt.Sprintf("state change of %s", contact.Name))
+						m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Added label %s and created task (can't be undone)", newLabel))
 					}
 				}
 				
 				// Reload contacts and exit label prompt mode
 				if newContacts, err := m.db.ListContacts(); err == nil {
-					m.contacts = newContacts
+					m.setContacts(newContacts)
 					m.selected = m.ensureValidSelection()
 				}
 				
@@ -925,50 +1708,52 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch msg.String() {
 			case "esc":
 				// Cancel new contact creation
-				m.newContactMode = false
-				m.newContactField = 0
-				for i := range m.newContactInputs {
-					m.newContactInputs[i].Blur()
-				}
-				return m, nil
+				return m.closeNewContact(), nil
+				
+			case "ctrl+g":
+				// Go to the possible duplicate instead
+				return m.jumpToDuplicate(), nil
+				
+			case "ctrl+o":
+				// Add the details to the possible duplicate instead
+				return m.mergeIntoDuplicate(), nil
 				
 			case "enter":
 				// Save new contact
-				if strings.TrimSpace(m.newContactInputs[EditFieldName].Value()) == "" {
-					// Name is required
-					m.err = fmt.Errorf("name is required")
+				newContact, err := m.newContactFromForm()
+				if err != nil {
+					m.err = err
 					return m, nil
 				}
 				
-				// Create new contact
-				newContact := db.Contact{
-					Name:             strings.TrimSpace(m.newContactInputs[EditFieldName].Value()),
-					Email:            db.NewNullString(strings.TrimSpace(m.newContactInputs[EditFieldEmail].Value())),
-					Phone:            db.NewNullString(strings.TrimSpace(m.newContactInputs[EditFieldPhone].Value())),
-					Company:          db.NewNullString(strings.TrimSpace(m.newContactInputs[EditFieldCompany].Value())),
-					RelationshipType: RelationshipTypes[m.newContactRelTypeIdx+1], // Skip "all"
-					Notes:            db.NewNullString(strings.TrimSpace(m.newContactInputs[EditFieldNotes].Value())),
-					Label:            db.NewNullString(strings.TrimSpace(m.newContactInputs[EditFieldLabel].Value())),
-					State:            db.NewNullString("ok"), // Default state
+				// Stop once if it looks like someone already in the list
+				if !m.duplicateConfirm {
+					if duplicates := db.PossibleDuplicates(m.contacts, newContact); len(duplicates) > 0 {
+						m.duplicates = duplicates
+						m.duplicateConfirm = true
+						return m, nil
+					}
 				}
 				
 				// Save to database
-				_, err := m.db.AddContact(newContact)
+				id, err := m.db.AddContact(newContact)
+				if err == nil {
+					err = m.db.SetContactTags(int(id), newContact.Tags)
+				}
+				if err == nil {
+					err = m.db.SetFieldValues(int(id), m.fieldInputValues(m.newContactInputs))
+				}
 				if err != nil {
 					m.err = err
 					return m, nil
 				}
 				
 				// Exit new contact mode
-				m.newContactMode = false
-				m.newContactField = 0
-				for i := range m.newContactInputs {
-					m.newContactInputs[i].Blur()
-				}
+				m = m.closeNewContact()
 				
 				// Reload contacts
 				if newContacts, err := m.db.ListContacts(); err == nil {
-					m.contacts = newContacts
+					m.setContacts(newContacts)
 					// Try to select the newly created contact
 					for i, c := range m.filteredContacts() {
 						if c.Name == newContact.Name {
@@ -987,7 +1772,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.newContactField == EditFieldRelType {
 					// Skip to notes field after relationship type
 					m.newContactField = EditFieldNotes
-				} else if m.newContactField < EditFieldCount-1 {
+				} else if m.newContactField < len(m.newContactInputs)-1 {
 					m.newContactField++
 					if m.newContactField == EditFieldRelType {
 						m.newContactField++ // Skip relationship type field in tab order
@@ -1015,7 +1800,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.newContactField-- // Skip relationship type field in tab order
 					}
 				} else {
-					m.newContactField = EditFieldCount - 1
+					m.newContactField = len(m.newContactInputs) - 1
 				}
 				
 				if m.newContactField < len(m.newContactInputs) && m.newContactField != EditFieldRelType {
@@ -1045,7 +1830,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "up", "k":
 				if m.newContactField == EditFieldRelType {
 					// Move to previous field when pressing up on relationship type
-					m.newContactField = EditFieldCompany
+					m.newContactField = EditFieldLocation
 					m.newContactInputs[m.newContactField].Focus()
 					return m, textinput.Blink
 				}
@@ -1064,7 +1849,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Pass through to text input if not on relationship type field
 			if m.newContactField != EditFieldRelType {
 				var cmd tea.Cmd
+				before := m.newContactInputs[m.newContactField].Value()
 				m.newContactInputs[m.newContactField], cmd = m.newContactInputs[m.newContactField].Update(msg)
+				if m.newContactInputs[m.newContactField].Value() != before {
+					m.duplicateConfirm = false
+					var checkCmd tea.Cmd
+					m, checkCmd = m.checkDuplicates()
+					cmd = tea.Batch(cmd, checkCmd)
+				}
 				return m, cmd
 			}
 			return m, nil
@@ -1089,25 +1881,64 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if len(contacts) > 0 && m.selected < len(contacts) {
 						contact := contacts[m.selected]
 						
+						birthday, err := db.ParseBirthday(m.editInputs[EditFieldBirthday].Value())
+						if err != nil {
+							m.err = err
+							return m, nil
+						}
+						avatarPath, err := cleanAvatar(m.editInputs[EditFieldAvatar].Value())
+						if err != nil {
+							m.err = err
+							return m, nil
+						}
+						followUp, err := parseDueDate(m.editInputs[EditFieldFollowUp].Value())
+						if err != nil {
+							m.err = err
+							return m, nil
+						}
+						deadline, err := parseDueDate(m.editInputs[EditFieldDeadline].Value())
+						if err != nil {
+							m.err = err
+							return m, nil
+						}
+						timezone, err := parseTimezone(m.editInputs[EditFieldTimezone].Value())
+						if err != nil {
+							m.err = err
+							return m, nil
+						}
+						
 						// Update the contact
 						contact.Name = m.editInputs[EditFieldName].Value()
 						contact.Email = db.NewNullString(m.editInputs[EditFieldEmail].Value())
 						contact.Phone = db.NewNullString(m.editInputs[EditFieldPhone].Value())
 						contact.Company = db.NewNullString(m.editInputs[EditFieldCompany].Value())
+						contact.Location = db.NewNullString(m.editInputs[EditFieldLocation].Value())
 						contact.Notes = db.NewNullString(m.editInputs[EditFieldNotes].Value())
 						contact.Label = db.NewNullString(m.editInputs[EditFieldLabel].Value())
+						contact.Birthday = db.NewNullString(birthday)
+						contact.Avatar = db.NewNullString(avatarPath)
+						contact.FollowUpDate = followUp
+						contact.DeadlineDate = deadline
+						contact.Timezone = db.NewNullString(timezone)
 						
 						// Set relationship type from the selected index
 						contact.RelationshipType = RelationshipTypes[m.editRelTypeIdx+1] // Skip "all"
 						
 						// Save to database
-						err := m.db.UpdateContact(contact)
+						err = m.db.UpdateContact(contact)
+						if err == nil {
+							err = m.db.SetContactTags(contact.ID, db.ParseTags(m.editInputs[EditFieldTags].Value()))
+						}
+						if err == nil {
+							err = m.db.SetFieldValues(contact.ID, m.fieldInputValues(m.editInputs))
+						}
 						if err != nil {
 							m.err = err
 						} else {
 							// Reload contacts
+							m.invalidateDetailCache()
 							if newContacts, err := m.db.ListContacts(); err == nil {
-								m.contacts = newContacts
+								m.setContacts(newContacts)
 							}
 						}
 					}
@@ -1130,7 +1961,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				
 			case "tab", "down":
 				// Move to next field
-				if m.editField < EditFieldCount-1 {
+				if m.editField < len(m.editInputs)-1 {
 					m.editInputs[m.editField].Blur()
 					m.editField++
 					if m.editField != EditFieldRelType {
@@ -1184,15 +2015,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if len(contacts) > 0 && m.selected < len(contacts) {
 					contact := contacts[m.selected]
 					newState := ContactStates[m.stateSelected]
+					if !m.canMoveTo(contact, newState) {
+						return m.refuseTransition(contact, newState), nil
+					}
+					before := m.snapshot(contact.ID)
 					err := m.db.UpdateContactState(contact.ID, newState)
 					if err != nil {
 						m.err = err
 					} else {
+						action := fmt.Sprintf("state change of %s", contact.Name)
+						m = m.pushUndo(action, before)
+						
 						// Set flash message for successful state update
 						m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Updated %s state to %s", contact.Name, newState))
 						
-						// Create TaskWarrior task if state changed from "ok" to something else
-						if newState != "ok" && m.taskManager.IsEnabled() {
+						// Create a task if the new state calls for one
+						if m.stateCreatesTask(newState) && m.taskManager.IsEnabled() {
 							if contact.Label.Valid && contact.Label.String != "" {
 								taskErr := m.taskManager.Backend().CreateContactTask(
 									contact.Name, 
@@ -1204,7 +2042,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 									m.err = fmt.Errorf("state updated but task creation failed: %w", taskErr)
 								} else {
 									// Add flash message for successful task creation
-									m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Updated %s state to %s and created task", contact.Name, newState))
+									m = m.dropUndo(action)
+									m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Updated %s state to %s and created task (can't be undone)", contact.Name, newState))
 								}
 							} else {
 								// Prompt for label instead of showing error
@@ -1214,28 +2053,42 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 								m.labelPromptInput.SetValue("")
 								m.labelPromptInput.Focus()
 								m.stateMode = false // Exit state mode
+								m = m.runAutomations(scripting.EventState, contact.ID)
 								return m, textinput.Blink
 							}
 						}
 						
 						// Reload contacts to show updated state
 						if newContacts, err := m.db.ListContacts(); err == nil {
-							m.contacts = newContacts
+							m.setContacts(newContacts)
 							// Maintain selection within bounds after reload
 							m.selected = m.ensureValidSelection()
 						}
+						m = m.runAutomations(scripting.EventState, contact.ID)
 					}
 				}
 				m.stateMode = false
 				m.stateSelected = 0
 				return m, nil
-			case "j", "down":
-				if m.stateSelected < len(ContactStates)-1 {
-					m.stateSelected++
+			case "W":
+				// Toggle waiting on a reply
+				contacts := m.filteredContacts()
+				if len(contacts) > 0 && m.selected < len(contacts) {
+					contact := contacts[m.selected]
+					m = m.setWaiting(contact, !contact.WaitingSince.Valid)
 				}
-			case "k", "up":
-				if m.stateSelected > 0 {
-					m.stateSelected--
+				m.stateMode = false
+				m.stateSelected = 0
+				return m, nil
+			case "j", "down", "k", "up":
+				// Move over the states the contact can change to
+				contacts := m.filteredContacts()
+				if len(contacts) > 0 && m.selected < len(contacts) {
+					step := 1
+					if msg.String() == "k" || msg.String() == "up" {
+						step = -1
+					}
+					m = m.stepState(contacts[m.selected], step)
 				}
 			default:
 				// Check if it's a hotkey
@@ -1248,15 +2101,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							if len(contacts) > 0 && m.selected < len(contacts) {
 								contact := contacts[m.selected]
 								newState := ContactStates[i]
+								if !m.canMoveTo(contact, newState) {
+									return m.refuseTransition(contact, newState), nil
+								}
+								before := m.snapshot(contact.ID)
 								err := m.db.UpdateContactState(contact.ID, newState)
 								if err != nil {
 									m.err = err
 								} else {
+									action := fmt.Sprintf("state change of %s", contact.Name)
+									m = m.pushUndo(action, before)
+									
 									// Set flash message for successful state update (when no task needed)
 									m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Updated %s state to %s", contact.Name, newState))
 									
-									// Create task if state changed from "ok" to something else
-									if newState != "ok" && m.taskManager.IsEnabled() {
+									// Create a task if the new state calls for one
+									if m.stateCreatesTask(newState) && m.taskManager.IsEnabled() {
 										if contact.Label.Valid && contact.Label.String != "" {
 											taskErr := m.taskManager.Backend().CreateContactTask(
 												contact.Name, 
@@ -1268,7 +2128,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 												m.err = fmt.Errorf("state updated but task creation failed: %w", taskErr)
 											} else {
 												// Add flash message for successful task creation
-												m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Updated %s state to %s and created task", contact.Name, newState))
+												m = m.dropUndo(action)
+												m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Updated %s state to %s and created task (can't be undone)", contact.Name, newState))
 											}
 										} else {
 											// Prompt for label instead of showing error
@@ -1278,15 +2139,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 											m.labelPromptInput.SetValue("")
 											m.labelPromptInput.Focus()
 											m.stateMode = false // Exit state mode
+											m = m.runAutomations(scripting.EventState, contact.ID)
 											return m, textinput.Blink
 										}
 									}
 									
 									// Reload contacts to show updated state
 									if newContacts, err := m.db.ListContacts(); err == nil {
-										m.contacts = newContacts
+										m.setContacts(newContacts)
 										m.selected = m.ensureValidSelection()
 									}
+									m = m.runAutomations(scripting.EventState, contact.ID)
 								}
 							}
 							m.stateMode = false
@@ -1305,6 +2168,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "esc":
 				m.noteMode = false
 				m.noteType = 0
+				m.noteRating = 0
+				m.noteDuration = 0
 				m.noteInput.Reset()
 				return m, nil
 			case "enter":
@@ -1317,17 +2182,26 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						note := m.noteInput.Value()
 						if note != "" {
 							interactionType := InteractionTypes[m.noteType]
-							err := m.db.AddInteractionNote(contact.ID, interactionType, note)
+							err := m.db.AddInteractionNote(contact.ID, interactionType, note, m.noteRating, m.noteDuration)
+							if err == nil && m.noteWaiting != contact.WaitingSince.Valid {
+								if err = m.db.SetWaiting(contact.ID, m.noteWaiting); err == nil {
+									m = m.reloadContacts()
+								}
+							}
 							if err != nil {
 								m.err = err
 							} else {
+								m.invalidateDetailCache()
 								// Set flash message for successful note addition
 								m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Added %s note for %s", interactionType, contact.Name))
+								m = m.runAutomations(scripting.EventNote, contact.ID)
 							}
 						}
 					}
 					m.noteMode = false
 					m.noteType = 0
+					m.noteRating = 0
+					m.noteDuration = 0
 					m.noteInput.Reset()
 					return m, nil
 				}
@@ -1335,6 +2209,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Cycle through interaction types
 				m.noteType = (m.noteType + 1) % len(InteractionTypes)
 				return m, nil
+			case "ctrl+r":
+				// Cycle the energy rating: not rated, then 1-5
+				m.noteRating = (m.noteRating + 1) % 6
+				return m, nil
+			case "ctrl+l":
+				// Cycle the duration through common lengths
+				m.noteDuration = nextDuration(m.noteDuration)
+				return m, nil
+			case "ctrl+y":
+				// Toggle waiting on a reply
+				m.noteWaiting = !m.noteWaiting
+				return m, nil
 			}
 			
 			// Pass other keys to the note input
@@ -1366,7 +2252,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					} else {
 						// Reload contacts
 						if newContacts, err := m.db.ListContacts(); err == nil {
-							m.contacts = newContacts
+							m.setContacts(newContacts)
 						}
 					}
 					
@@ -1413,7 +2299,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					} else {
 						// Reload contacts
 						if newContacts, err := m.db.ListContacts(); err == nil {
-							m.contacts = newContacts
+							m.setContacts(newContacts)
 						}
 					}
 					m.styleMode = false
@@ -1449,6 +2335,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						if err != nil {
 							m.err = err
 						} else {
+							m.invalidateDetailCache()
 							// Reload interactions
 							contacts := m.filteredContacts()
 							if len(contacts) > 0 && m.selected < len(contacts) {
@@ -1478,6 +2365,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 			
+			// Attaching a file or URL to the selected interaction
+			if m.attachmentInputMode {
+				return m.updateAttachmentInput(msg)
+			}
+			
 			// Check if we're editing an interaction
 			if m.interactionEditInput.Focused() {
 				switch msg.String() {
@@ -1502,6 +2394,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							if err != nil {
 								m.err = err
 							} else {
+								m.invalidateDetailCache()
 								// Reload interactions
 								contacts := m.filteredContacts()
 								if len(contacts) > 0 && m.selected < len(contacts) {
@@ -1559,8 +2452,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.interactionEditInput.Focus()
 					// Set width
 					if m.width > 0 {
-						detailWidth := m.width - (m.width / 3) - 3
-						m.interactionEditInput.SetWidth(detailWidth - 10)
+						m.interactionEditInput.SetWidth(m.detailWidth() - 10)
 					}
 					return m, textarea.Blink
 				}
@@ -1572,6 +2464,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.interactionToDelete = m.interactions[m.selectedInteraction].ID
 				}
 				return m, nil
+			case "a":
+				// Attach a file or URL to the selected interaction
+				return m.openAttachmentInput()
+			case "o":
+				// Open the selected interaction's first attachment
+				return m.openNthAttachment(1)
+			case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+				return m.openNthAttachment(int(msg.String()[0] - '0'))
+			case "x":
+				// Remove the selected interaction's last attachment
+				return m.removeLastAttachment(), nil
+			case "w":
+				// Export the contact's whole timeline as Markdown
+				return m.exportTimeline(report.TimelineMarkdown), nil
+			case "W":
+				// Export the contact's whole timeline as JSON
+				return m.exportTimeline(report.TimelineJSON), nil
 			}
 			return m, nil
 		}
@@ -1638,6 +2547,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		
+		// Marked contacts take the keys of actions that apply to all of them
+		if len(m.marked) > 0 {
+			if next, cmd, ok := m.updateMarked(msg); ok {
+				return next, cmd
+			}
+		}
+		
 		// Normal mode handling
 		switch msg.String() {
 		case "?":
@@ -1648,6 +2564,52 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 			
+		case "ctrl+g":
+			// Open the jump picker
+			return m.openPicker()
+			
+		case "ctrl+p":
+			// Open the command palette
+			return m.openPalette()
+			
+		case "[", "]":
+			// Switch detail pane tabs
+			if m.singlePane() {
+				m.detailOpen = true
+			}
+			if msg.String() == "]" {
+				m = m.switchDetailTab(1)
+			} else {
+				m = m.switchDetailTab(-1)
+			}
+			return m, nil
+			
+		case " ":
+			// Mark the contact for a bulk action
+			m = m.toggleMark()
+			return m, nil
+			
+		case "V":
+			// Mark a range of contacts for a bulk action
+			m = m.markRange()
+			return m, nil
+			
+		case "ctrl+f":
+			// Search contact and interaction notes
+			return m.openNoteSearch()
+			
+		case "I":
+			// Import contacts from a file
+			return m.openImportPrompt()
+			
+		case "x":
+			// Export the filtered contacts to CSV
+			return m.openExportPrompt()
+			
+		case "ctrl+s":
+			// Sync now
+			return m.startSync()
+			
 		case "F": // Debug: Test flash message
 			m = m.setFlash(FlashSuccess, "✓ Test flash message - working correctly!")
 			return m, nil
@@ -1656,7 +2618,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Enter new contact mode
 			m.newContactMode = true
 			m.newContactField = 0
-			m.newContactRelTypeIdx = 3 // Default to "network"
+			m.newContactRelTypeIdx = defaultRelTypeIdx()
+			m.duplicates = nil
+			m.duplicateConfirm = false
 			// Reset all inputs
 			for i := range m.newContactInputs {
 				m.newContactInputs[i].Reset()
@@ -1665,7 +2629,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, textinput.Blink
 			
 		case "r":
-			// Enter relationship type filter mode
+			// Enter relationship type and tag filter mode
+			m = m.loadFilterTags()
 			m.typeFilterMode = true
 			m.typeSelected = 0
 			// If a filter is already active, select it
@@ -1692,6 +2657,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.selected--
 			}
 			
+		case "g":
+			// Go to the top of the list
+			m.selected = 0
+			
+		case "G":
+			// Go to the bottom of the list
+			m.selected = max(len(m.filteredContacts())-1, 0)
+			
+		case "ctrl+d":
+			// Half a page down
+			m.selected = min(m.selected+max(m.listVisibleHeight()/2, 1), max(len(m.filteredContacts())-1, 0))
+			
+		case "ctrl+u":
+			// Half a page up
+			m.selected = max(m.selected-max(m.listVisibleHeight()/2, 1), 0)
+			
 		case "/":
 			m.filterMode = true
 			// Reset and configure the textinput
@@ -1701,8 +2682,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.filter.Prompt = "> "
 			// Set filter width
 			if m.width > 0 {
-				listWidth := m.width / 3
-				m.filter.Width = listWidth - 6
+				m.filter.Width = m.listWidth() - 6
 			} else {
 				m.filter.Width = 25
 			}
@@ -1711,11 +2691,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Batch(textinput.Blink, tea.ClearScreen)
 			
 		case "esc":
-			// Clear any error messages and return to normal operation
-			if m.err != nil {
-				m.err = nil
-				m.dstaskIncompleteError = false
-				m.dstaskTaskID = ""
+			// Dismiss an error that just appeared in the status bar
+			if m.flashMessage != "" && m.flashType == FlashError {
+				m = m.clearFlash()
 				return m, nil
 			}
 			// Close help overlay if open
@@ -1723,6 +2701,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.showHelp = false
 				return m, nil
 			}
+			// Back to the list from a contact's details in single-pane layout
+			if m.singlePane() && m.detailOpen {
+				m.detailOpen = false
+				m.detailScroll = 0
+				return m, nil
+			}
+			// Restore filters suspended by a jump
+			if m.stashedFilters != nil {
+				m = m.restoreStashedFilters()
+				return m, nil
+			}
 			// Clear filter and return to full list
 			if m.filter.Value() != "" {
 				m.filter.Reset()
@@ -1768,18 +2757,58 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.selected = m.ensureValidSelection()
 			return m, nil
 			
+		case "!":
+			// Toggle seriously neglected filter
+			m.neglectedFilter = !m.neglectedFilter
+			m.selected = m.ensureValidSelection()
+			return m, nil
+			
+		case "y":
+			// Copy contact as Markdown
+			contacts := m.filteredContacts()
+			if len(contacts) > 0 && m.selected < len(contacts) {
+				return m.copyContact(contacts[m.selected]), nil
+			}
+			return m, nil
+			
+		case "%":
+			// Toggle incomplete profile filter
+			m.incompleteFilter = !m.incompleteFilter
+			m.selected = m.ensureValidSelection()
+			return m, nil
+			
+		case "B":
+			// Toggle upcoming birthdays filter
+			m.birthdayFilter = !m.birthdayFilter
+			m.selected = m.ensureValidSelection()
+			return m, nil
+			
+		case "v":
+			// Toggle starred filter
+			m.starredFilter = !m.starredFilter
+			m.selected = m.ensureValidSelection()
+			return m, nil
+			
+		case "f":
+			// Cycle through script filters
+			m = m.cycleScriptFilter()
+			m.selected = m.ensureValidSelection()
+			return m, nil
+			
 		case "n":
 			// Enter note mode
 			contacts := m.filteredContacts()
 			if len(contacts) > 0 && m.selected < len(contacts) {
 				m.noteMode = true
 				m.noteType = 0 // Default to "manual"
+				m.noteRating = 0
+				m.noteDuration = 0
+				m.noteWaiting = contacts[m.selected].WaitingSince.Valid
 				m.noteInput.Reset()
 				m.noteInput.Focus()
 				// Set note input width based on detail pane width
 				if m.width > 0 {
-					detailWidth := m.width - (m.width / 3) - 3
-					m.noteInput.SetWidth(detailWidth - 10)
+					m.noteInput.SetWidth(m.detailWidth() - 10)
 				}
 				return m, textarea.Blink
 			}
@@ -1788,9 +2817,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Clear all filters
 			m.stateFilter = false
 			m.overdueFilter = false
+			m.neglectedFilter = false
+			m.incompleteFilter = false
+			m.birthdayFilter = false
+			m.starredFilter = false
+			m.scriptFilter = ""
 			m.typeFilter = ""
+			m.tagFilter = ""
+			m.groupFilter = ""
 			m.showArchived = false
 			m.filter.Reset()
+			m.stashedFilters = nil
 			m.selected = m.ensureValidSelection()
 			return m, nil
 			
@@ -1799,6 +2836,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			contacts := m.filteredContacts()
 			if len(contacts) > 0 && m.selected < len(contacts) {
 				contact := contacts[m.selected]
+				if !m.confirmations().Bump {
+					m = m.bumpContact(contact.ID)
+					return m, nil
+				}
 				m.bumpConfirmMode = true
 				m.bumpContactID = contact.ID
 			}
@@ -1809,19 +2850,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			contacts := m.filteredContacts()
 			if len(contacts) > 0 && m.selected < len(contacts) {
 				contact := contacts[m.selected]
+				before := m.snapshot(contact.ID)
 				err := m.db.MarkContacted(contact.ID, "manual", "Marked via TUI")
 				if err != nil {
 					m.err = err
 				} else {
+					m = m.pushUndo(fmt.Sprintf("marking %s contacted", contact.Name), before)
+					m.invalidateDetailCache()
 					// Set flash message for successful contact marking
 					m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Marked %s as contacted", contact.Name))
 					
 					// Reload contacts to show updated state
 					if newContacts, err := m.db.ListContacts(); err == nil {
-						m.contacts = newContacts
+						m.setContacts(newContacts)
 						// Maintain selection within bounds after reload
 						m.selected = m.ensureValidSelection()
 					}
+					m = m.runAutomations(scripting.EventContacted, contact.ID)
 				}
 			}
 			return m, nil
@@ -1835,32 +2880,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 			
+		case "enter":
+			// Show the contact's details in single-pane layout, then edit
+			// single fields in them
+			if m.singlePane() && !m.detailOpen {
+				m.detailOpen = true
+				return m, nil
+			}
+			m = m.openInlineEdit()
+			return m, nil
+			
 		case "a":
 			// Toggle archive status
 			contacts := m.filteredContacts()
 			if len(contacts) > 0 && m.selected < len(contacts) {
-				contact := contacts[m.selected]
-				var err error
-				var flashMsg string
-				if contact.Archived {
-					err = m.db.UnarchiveContact(contact.ID)
-					flashMsg = fmt.Sprintf("✓ Unarchived %s", contact.Name)
-				} else {
-					err = m.db.ArchiveContact(contact.ID)
-					flashMsg = fmt.Sprintf("✓ Archived %s", contact.Name)
-				}
-				if err != nil {
-					m.err = err
-				} else {
-					// Set flash message
-					m = m.setFlash(FlashSuccess, flashMsg)
-					
-					// Reload contacts to show updated state
-					if newContacts, err := m.db.ListContacts(); err == nil {
-						m.contacts = newContacts
-						m.selected = m.ensureValidSelection()
-					}
-				}
+				return m.startArchive(contacts[m.selected])
 			}
 			return m, nil
 			
@@ -1871,16 +2905,38 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 			
 		case "D":
-			// Delete contact with confirmation
+			// Archive contact, or delete it when delete_action = "delete"
+			contacts := m.filteredContacts()
+			if len(contacts) > 0 && m.selected < len(contacts) && m.deleteAction() == "archive" {
+				contact := contacts[m.selected]
+				if contact.Archived {
+					m = m.setFlash(FlashInfo, fmt.Sprintf("%s is already archived • X: move to trash", contact.Name))
+					return m, nil
+				}
+				return m.startArchive(contact)
+			}
+			// With delete_action = "delete", D deletes just like X
+			fallthrough
+			
+		case "X":
+			// Move contact to the trash, with confirmation
 			contacts := m.filteredContacts()
 			if len(contacts) > 0 && m.selected < len(contacts) {
 				contact := contacts[m.selected]
+				if !m.confirmations().Delete {
+					m = m.deleteContact(contact.ID)
+					return m, nil
+				}
 				m.deleteConfirmMode = true
 				m.deleteContactID = contact.ID
 				m.deleteContactName = contact.Name
 			}
 			return m, nil
 			
+		case "P":
+			// Purge archived contacts past the retention period
+			return m.openPurgeConfirm(), nil
+			
 		case "i":
 			// Enter interaction view/edit mode
 			contacts := m.filteredContacts()
@@ -1939,7 +2995,174 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 			
+		case "E":
+			// Draft an email, from the template for the contact's state if any
+			contacts := m.filteredContacts()
+			if len(contacts) > 0 && m.selected < len(contacts) {
+				return m.composeEmail(contacts[m.selected])
+			}
+			return m, nil
+			
+		case "l":
+			// Call the contact's phone number
+			contacts := m.filteredContacts()
+			if len(contacts) > 0 && m.selected < len(contacts) {
+				return m.startCall(contacts[m.selected])
+			}
+			return m, nil
+			
+		case "Q":
+			// Show the contact's card as a QR code
+			contacts := m.filteredContacts()
+			if len(contacts) > 0 && m.selected < len(contacts) {
+				m = m.openQRCode(contacts[m.selected])
+			}
+			return m, nil
+			
+		case "d":
+			// View/edit important dates
+			contacts := m.filteredContacts()
+			if len(contacts) > 0 && m.selected < len(contacts) {
+				m = m.openDates(contacts[m.selected])
+			}
+			return m, nil
+			
+		case "@":
+			// View/edit email addresses
+			contacts := m.filteredContacts()
+			if len(contacts) > 0 && m.selected < len(contacts) {
+				m = m.openValues(emailValues, contacts[m.selected])
+			}
+			return m, nil
+			
+		case "#":
+			// View/edit phone numbers
+			contacts := m.filteredContacts()
+			if len(contacts) > 0 && m.selected < len(contacts) {
+				m = m.openValues(phoneValues, contacts[m.selected])
+			}
+			return m, nil
+			
+		case "H":
+			// View/edit social media handles
+			contacts := m.filteredContacts()
+			if len(contacts) > 0 && m.selected < len(contacts) {
+				m = m.openValues(handleValues, contacts[m.selected])
+			}
+			return m, nil
+			
+		case "K":
+			// View/edit links to other contacts
+			contacts := m.filteredContacts()
+			if len(contacts) > 0 && m.selected < len(contacts) {
+				m = m.openLinks(contacts[m.selected])
+			}
+			return m, nil
+			
+		case "J":
+			// Daily journal
+			return m.openJournal(), nil
+			
+		case "h":
+			// View the history of changes to the contact's fields
+			contacts := m.filteredContacts()
+			if len(contacts) > 0 && m.selected < len(contacts) {
+				m = m.openHistory(contacts[m.selected])
+			}
+			return m, nil
+			
+		case "p":
+			// Edit postal address
+			contacts := m.filteredContacts()
+			if len(contacts) > 0 && m.selected < len(contacts) {
+				return m.openAddress(contacts[m.selected])
+			}
+			return m, nil
+			
+		case "L":
+			// Manage groups and the selected contact's memberships
+			contacts := m.filteredContacts()
+			if len(contacts) > 0 && m.selected < len(contacts) {
+				m = m.openGroups(contacts[m.selected])
+			} else {
+				m = m.openGroups(db.Contact{})
+			}
+			return m, nil
+			
+		case "Z":
+			// Restore or purge deleted contacts
+			return m.openTrash(), nil
+			
+		case "z":
+			// Snooze the contact out of the overdue view
+			contacts := m.filteredContacts()
+			if len(contacts) > 0 && m.selected < len(contacts) {
+				m = m.openSnooze(contacts[m.selected])
+			}
+			return m, nil
+			
+		case "u":
+			// Undo the last state change, delete, archive, bump or contact
+			return m.undo(), nil
+			
+		case "U":
+			// Show upcoming important dates
+			m = m.openAgenda()
+			return m, nil
+			
+		case "w":
+			// Show the dashboard
+			m = m.openDashboard()
+			return m, nil
+			
+		case "ctrl+left":
+			// Narrow the contact list
+			m = m.resizeList(-listRatioStep)
+			return m, nil
+			
+		case "ctrl+right":
+			// Widen the contact list
+			m = m.resizeList(listRatioStep)
+			return m, nil
+			
+		case "T":
+			// Send a text message through the messaging command
+			contacts := m.filteredContacts()
+			if len(contacts) > 0 && m.selected < len(contacts) {
+				return m.openTextPrompt(contacts[m.selected])
+			}
+			return m, nil
+			
+		case "M":
+			// Toggle whether the contact takes part in reminders
+			contacts := m.filteredContacts()
+			if len(contacts) > 0 && m.selected < len(contacts) {
+				m = m.toggleReminders(contacts[m.selected])
+			}
+			return m, nil
+			
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			// Open a link from the contact's notes or recent interactions
+			contacts := m.filteredContacts()
+			if len(contacts) > 0 && m.selected < len(contacts) {
+				return m.openDetailLink(contacts[m.selected], int(msg.String()[0]-'0'))
+			}
+			return m, nil
+			
+		case "*":
+			// Star or unstar the contact
+			contacts := m.filteredContacts()
+			if len(contacts) > 0 && m.selected < len(contacts) {
+				m = m.toggleStar(contacts[m.selected])
+			}
+			return m, nil
+			
 		case "O":
+			// Choose the order of the list
+			m = m.openSort()
+			return m, nil
+			
+		case "R":
 			// Launch notes-tui with contact tag filter (if enabled)
 			if m.cfg != nil && m.cfg.External.NotesTUI {
 				contacts := m.filteredContacts()
@@ -1972,77 +3195,243 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// filteredContacts returns contacts matching the current filter
+// filterCacheKey identifies the inputs a filtered contact list was built from
+type filterCacheKey struct {
+	filters         filterState
+	sortOrder       string
+	contactsVersion int
+}
+
+// setContacts replaces the loaded contacts, invalidating the filtered cache
+func (m *Model) setContacts(contacts []db.Contact) {
+	m.contacts = contacts
+	m.contactsVersion++
+	m.refreshDatesDue()
+	
+	m.searchText = make([]string, len(contacts))
+	for i, c := range contacts {
+		m.searchText[i] = buildSearchText(c)
+	}
+}
+
+// refreshFilteredCache recomputes the filtered list once if the contacts or
+// filters changed since it was last built
+func (m *Model) refreshFilteredCache() {
+	key := filterCacheKey{filters: m.currentFilters(), sortOrder: m.sortOrder, contactsVersion: m.contactsVersion}
+	if m.filteredValid && m.filteredKey == key {
+		return
+	}
+	m.filtered = m.computeFilteredContacts()
+	m.filteredKey = key
+	m.filteredValid = true
+}
+
+// filteredContacts returns contacts matching the current filter, served from
+// the cache unless the contacts or filters changed during this update
 func (m Model) filteredContacts() []db.Contact {
-	var filtered []db.Contact
+	if m.filteredValid && m.filteredKey == (filterCacheKey{filters: m.currentFilters(), sortOrder: m.sortOrder, contactsVersion: m.contactsVersion}) {
+		return m.filtered
+	}
+	return m.computeFilteredContacts()
+}
+
+// computeFilteredContacts applies all active filters to the loaded contacts
+func (m Model) computeFilteredContacts() []db.Contact {
+	search := db.ParseSearch(m.filter.Value())
+	
+	// Single pass over the loaded contacts; with tens of thousands of rows,
+	// chained per-filter slices dominated the cost of each keystroke. The
+	// matches are collected first so the contacts are copied once into a
+	// slice of the right size.
+	matches := make([]int, 0, len(m.contacts))
+	for i := range m.contacts {
+		c := &m.contacts[i]
+		if !m.matchesFilters(c) {
+			continue
+		}
+		if len(search.Words) > 0 && (i >= len(m.searchText) || !matchesWords(m.searchText[i], search.Words)) {
+			continue
+		}
+		if len(search.Terms) > 0 && !m.matchesTerms(*c, search.Terms) {
+			continue
+		}
+		if search.Location != "" && !c.InLocation(search.Location) {
+			continue
+		}
+		if !hasTags(c, search.Tags) {
+			continue
+		}
+		matches = append(matches, i)
+	}
+	filtered := make([]db.Contact, len(matches))
+	for n, i := range matches {
+		filtered[n] = m.contacts[i]
+	}
 	
-	// Start with all contacts
-	contacts := m.contacts
+	if m.birthdayFilter {
+		sortByBirthday(filtered)
+	} else if m.scripts.HasScore() {
+		sortByScore(filtered, m.scripts)
+	} else if m.overdueFilter {
+		sortByDueDate(filtered)
+	} else {
+		sortContacts(filtered, m.currentSort().name)
+		if m.starredFirst() {
+			sortStarredFirst(filtered)
+		}
+	}
 	
+	return filtered
+}
+
+// matchesFilters reports whether a contact passes the archive, type and
+// smart filters (the text filter is applied separately)
+func (m *Model) matchesFilters(c *db.Contact) bool {
 	// Filter archived contacts (unless showing archived)
-	if !m.showArchived {
-		var activeContacts []db.Contact
-		for _, c := range contacts {
-			if !c.Archived {
-				activeContacts = append(activeContacts, c)
-			}
+	if !m.showArchived && c.Archived {
+		return false
+	}
+	
+	// Apply relationship type filter
+	if m.typeFilter != "" && c.RelationshipType != m.typeFilter {
+		return false
+	}
+	
+	if m.tagFilter != "" && !c.HasTag(m.tagFilter) {
+		return false
+	}
+	
+	if m.groupFilter != "" && !c.InGroup(m.groupFilter) {
+		return false
+	}
+	
+	// Include contacts with non-ok states (contacts with no state are skipped)
+	if m.stateFilter && !(c.State.Valid && c.State.String != "ok") {
+		return false
+	}
+	
+	if m.overdueFilter && !m.needsAttention(*c) {
+		return false
+	}
+	
+	if m.neglectedFilter && !escalation.IsNeglected(*c, m.escalationConfig()) {
+		return false
+	}
+	
+	if m.incompleteFilter && c.Completeness() == 100 {
+		return false
+	}
+	
+	if m.starredFilter && !c.Starred {
+		return false
+	}
+	
+	if m.birthdayFilter {
+		if days := c.DaysUntilBirthday(time.Now()); days < 0 || days > m.remindDays() {
+			return false
 		}
-		contacts = activeContacts
 	}
-	
-	// Apply relationship type filter
-	if m.typeFilter != "" {
-		var typeFiltered []db.Contact
-		for _, c := range contacts {
-			if c.RelationshipType == m.typeFilter {
-				typeFiltered = append(typeFiltered, c)
-			}
+	
+	if m.scriptFilter != "" && !m.scripts.Match(m.scriptFilter, *c) {
+		return false
+	}
+	
+	return true
+}
+
+// needsAttention reports whether the overdue filter lists a contact: it is
+// overdue, or has an important date, follow-up or deadline coming due, and
+// isn't snoozed
+func (m *Model) needsAttention(c db.Contact) bool {
+	if c.IsSnoozed() {
+		return false
+	}
+	return c.IsOverdue() || m.hasDateDue(c) || hasFollowUpDue(c, time.Now())
+}
+
+// buildSearchText returns the lowercased text the filter matches against.
+// Fields are separated by NUL so a query can't match across field boundaries.
+func buildSearchText(c db.Contact) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(c.Name))
+	if c.Label.Valid {
+		b.WriteByte(0)
+		b.WriteString(strings.ToLower(c.Label.String))
+	}
+	if c.Company.Valid {
+		b.WriteByte(0)
+		b.WriteString(strings.ToLower(c.Company.String))
+	}
+	if c.Location.Valid {
+		b.WriteByte(0)
+		b.WriteString(strings.ToLower(c.Location.String))
+	}
+	for _, tag := range c.Tags {
+		b.WriteByte(0)
+		b.WriteString(strings.ToLower(tag))
+	}
+	return b.String()
+}
+
+// findContact looks up a contact by label (with or without the @ prefix),
+// then by exact name, then by a unique partial name match
+func (m Model) findContact(query string) (db.Contact, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return db.Contact{}, fmt.Errorf("no contact specified")
+	}
+
+	label := query
+	if !strings.HasPrefix(label, "@") {
+		label = "@" + label
+	}
+	for _, c := range m.contacts {
+		if c.Label.Valid && strings.EqualFold(c.Label.String, label) {
+			return c, nil
 		}
-		contacts = typeFiltered
 	}
-	
-	// Apply smart filters
-	if m.stateFilter {
-		var stateFiltered []db.Contact
-		for _, c := range contacts {
-			// Include contacts with non-ok states or no state set
-			if c.State.Valid && c.State.String != "ok" {
-				stateFiltered = append(stateFiltered, c)
-			}
+
+	for _, c := range m.contacts {
+		if strings.EqualFold(c.Name, query) {
+			return c, nil
 		}
-		contacts = stateFiltered
 	}
-	
-	if m.overdueFilter {
-		var overdueFiltered []db.Contact
-		for _, c := range contacts {
-			if c.IsOverdue() {
-				overdueFiltered = append(overdueFiltered, c)
-			}
+
+	var matches []db.Contact
+	lower := strings.ToLower(query)
+	for _, c := range m.contacts {
+		if strings.Contains(strings.ToLower(c.Name), lower) {
+			matches = append(matches, c)
 		}
-		contacts = overdueFiltered
 	}
-	
-	// Apply text filter if present
-	if m.filter.Value() == "" {
-		return contacts
+	switch len(matches) {
+	case 0:
+		return db.Contact{}, fmt.Errorf("no contact matching %q", query)
+	case 1:
+		return matches[0], nil
+	default:
+		return db.Contact{}, fmt.Errorf("%q matches %d contacts, use a label to be specific", query, len(matches))
 	}
-	
-	filter := strings.ToLower(m.filter.Value())
-	
-	for _, c := range contacts {
-		if strings.Contains(strings.ToLower(c.Name), filter) ||
-		   (c.Label.Valid && strings.Contains(strings.ToLower(c.Label.String), filter)) ||
-		   (c.Company.Valid && strings.Contains(strings.ToLower(c.Company.String), filter)) {
-			filtered = append(filtered, c)
-		}
+}
+
+// GoTo selects the contact matching query and opens it, so the TUI starts
+// on it: overlays shown at startup are closed, filters hiding the contact
+// are suspended as for a jump, and in single-pane layout its details show.
+func (m *Model) GoTo(query string) error {
+	contact, err := m.findContact(query)
+	if err != nil {
+		return err
 	}
-	
-	return filtered
+
+	*m = m.closeAlerts().closeDashboard().jumpTo(contact)
+	m.detailOpen = true
+	return nil
 }
 
-// ensureValidSelection ensures the current selection is within bounds
-func (m Model) ensureValidSelection() int {
+// ensureValidSelection ensures the current selection is within bounds.
+// It rebuilds the filtered cache first since it's called after filter changes.
+func (m *Model) ensureValidSelection() int {
+	m.refreshFilteredCache()
 	contacts := m.filteredContacts()
 	if len(contacts) == 0 {
 		return 0
@@ -2057,28 +3446,38 @@ func (m Model) ensureValidSelection() int {
 }
 // View renders the UI
 func (m Model) View() string {
-	if m.err != nil {
-		if m.dstaskIncompleteError {
-			return fmt.Sprintf("Error: %v\n\nThis task has incomplete subtasks.\n\nPress 'e' to edit task notes and fix subtasks\nPress Esc to cancel\nPress q to quit", m.err)
-		}
-		return fmt.Sprintf("Error: %v\n\nPress Esc to continue or q to quit.", m.err)
-	}
-	
 	if m.width == 0 || m.height == 0 {
 		return "Loading..."
 	}
 	
+	// Overlays are modal, but status bar messages still show over them
+	if overlay := m.renderOverlay(); overlay != "" {
+		return m.withToast(overlay)
+	}
+	
 	// Calculate pane widths and heights
 	// Always reserve space for flash (1 line)
-	listWidth := m.width / 3
-	detailWidth := m.width - listWidth - 3 // account for borders
+	listWidth := m.listWidth()
+	detailWidth := m.detailWidth()
 	contentHeight := m.height - 4 // account for help line and flash area (always present)
 	
+	// One pane at a time in a narrow window
+	if m.singlePane() {
+		var pane string
+		if m.detailOpen {
+			pane = m.scrolledDetail(m.renderDetail(detailWidth, contentHeight))
+		} else {
+			pane = m.renderList(listWidth, contentHeight)
+		}
+		content := borderStyle.Width(listWidth).Height(contentHeight).Render(pane)
+		return lipgloss.JoinVertical(lipgloss.Left, content, m.renderStatusBar(), m.withSyncStatus(m.renderHelp()))
+	}
+	
 	// Build the list view
 	listView := m.renderList(listWidth, contentHeight)
 	
 	// Build the detail view  
-	detailView := m.renderDetail(detailWidth, contentHeight)
+	detailView := m.scrolledDetail(m.renderDetail(detailWidth, contentHeight))
 	
 	// Join horizontally
 	content := lipgloss.JoinHorizontal(
@@ -2087,16 +3486,124 @@ func (m Model) View() string {
 		borderStyle.Width(detailWidth).Height(contentHeight).Render(detailView),
 	)
 	
-	// Always render flash area (even if empty)
-	flash := m.renderFlash()
+	// Always render the status bar, with the latest message or the context
+	flash := m.renderStatusBar()
 	
-	// Add help line
-	help := m.renderHelp()
+	// Add help line with the sync indicator
+	help := m.withSyncStatus(m.renderHelp())
 	
 	// Build main view with permanent flash area
 	mainView := lipgloss.JoinVertical(lipgloss.Left, content, flash, help)
 	
-	// Handle overlays - these still need to be modal
+	return mainView
+}
+
+// renderOverlay renders the active modal overlay, or "" when none is active
+func (m Model) renderOverlay() string {
+	// Overlay jump picker if active
+	if m.pickerMode {
+		return m.renderPicker()
+	}
+	
+	// Overlay command palette if active
+	if m.paletteMode {
+		return m.renderPalette()
+	}
+	
+	// Overlay notes search if active
+	if m.noteSearchMode {
+		return m.renderNoteSearch()
+	}
+	
+	// Overlay export prompt if active
+	if m.exportPromptMode {
+		return m.renderExport()
+	}
+	
+	// Overlay import prompt, progress or summary if active
+	if m.importPromptMode || m.importRunning || m.importSummary {
+		return m.renderImport()
+	}
+	
+	// Overlay text message prompt if active
+	if m.textPromptMode || m.textSending {
+		return m.renderText()
+	}
+	
+	// Overlay important dates if active
+	if m.datesMode {
+		return m.renderDates()
+	}
+	
+	// Overlay email addresses or phone numbers if active
+	if m.valuesMode {
+		return m.renderValues()
+	}
+	
+	// Overlay linked contacts if active
+	if m.linksMode {
+		return m.renderLinks()
+	}
+	
+	// Overlay the journal if active
+	if m.journalMode {
+		return m.renderJournal()
+	}
+	
+	// Overlay contact history if active
+	if m.historyMode {
+		return m.renderHistory()
+	}
+	
+	// Overlay snooze choices if active
+	if m.snoozeMode {
+		return m.renderSnooze()
+	}
+	
+	// Overlay sort orders if active
+	if m.sortMode {
+		return m.renderSort()
+	}
+	
+	// Overlay bulk action if active
+	if m.bulkMode {
+		return m.renderBulk()
+	}
+	
+	// Overlay postal address form if active
+	if m.addressMode {
+		return m.renderAddress()
+	}
+	
+	// Overlay groups if active
+	if m.groupsMode {
+		return m.renderGroups()
+	}
+	
+	// Overlay trash if active
+	if m.trashMode {
+		return m.renderTrash()
+	}
+	
+	// Overlay agenda if active
+	if m.agendaMode {
+		return m.renderAgenda()
+	}
+	
+	// Overlay due follow-ups if active
+	if m.alertsMode {
+		return m.renderAlerts()
+	}
+	
+	// Overlay dashboard if active
+	if m.dashboardMode {
+		return m.renderDashboard()
+	}
+	
+	// Overlay QR code if active
+	if m.qrMode {
+		return m.renderQRCode()
+	}
 	
 	// Overlay relationship type selection if in type filter mode
 	if m.typeFilterMode {
@@ -2133,6 +3640,16 @@ func (m Model) View() string {
 		return m.renderDeleteConfirmation()
 	}
 	
+	// Overlay archive confirmation if active
+	if m.archiveConfirmMode {
+		return m.renderArchiveConfirmation()
+	}
+	
+	// Overlay purge confirmation if active
+	if m.purgeConfirmMode {
+		return m.renderPurgeConfirmation()
+	}
+	
 	// Overlay style mode if active
 	if m.styleMode {
 		return m.renderStyleMode()
@@ -2168,7 +3685,16 @@ func (m Model) View() string {
 		return m.renderInteractionEditMode()
 	}
 	
-	return mainView
+	return ""
+}
+
+// listVisibleHeight returns how many contacts the list shows at once
+func (m Model) listVisibleHeight() int {
+	height := m.height - 4 // Borders, help line and flash area
+	if m.filterMode {
+		height -= 2
+	}
+	return height - 2 // Header
 }
 
 // renderList renders the contact list
@@ -2200,18 +3726,12 @@ func (m Model) renderList(width, height int) string {
 	header := "Contacts (" + fmt.Sprintf("%d", len(contacts)) + ")"
 	
 	// Add filter indicators
-	var filterIndicators []string
-	if m.typeFilter != "" {
-		filterIndicators = append(filterIndicators, "type:"+m.typeFilter)
-	}
-	if m.stateFilter {
-		filterIndicators = append(filterIndicators, "state:non-ok")
-	}
-	if m.overdueFilter {
-		filterIndicators = append(filterIndicators, "overdue")
+	filterIndicators := m.filterIndicators()
+	if order := m.currentSort(); order.name != "name" {
+		filterIndicators = append(filterIndicators, "sort:"+order.short)
 	}
-	if m.showArchived {
-		filterIndicators = append(filterIndicators, "archived")
+	if len(m.marked) > 0 {
+		filterIndicators = append(filterIndicators, fmt.Sprintf("%d marked", len(m.marked)))
 	}
 	if len(filterIndicators) > 0 {
 		header += " [" + strings.Join(filterIndicators, ", ") + "]"
@@ -2220,21 +3740,35 @@ func (m Model) renderList(width, height int) string {
 	lines = append(lines, header)
 	lines = append(lines, strings.Repeat("─", width-2))
 	
-	// Contact list
+	// Contact list. Settings are looked up once per frame rather than per
+	// row, each lookup copying the model.
+	escalationCfg := m.escalationConfig()
+	stateStyles := make(map[string]lipgloss.Style)
 	for i := startIdx; i < len(contacts) && i < startIdx+visibleHeight; i++ {
 		c := contacts[i]
 		
 		// Determine the single most important indicator to show
-		// Priority: non-ok state > overdue > contact style > none
+		// Priority: non-ok state > neglected > overdue > date coming up > contact style > none
 		var indicator string
 		var indicatorStyle func(...string) string
 		
 		if c.State.Valid && c.State.String != "ok" {
+			style, ok := stateStyles[c.State.String]
+			if !ok {
+				style = m.stateStyleFor(c.State.String)
+				stateStyles[c.State.String] = style
+			}
 			indicator = "●"
-			indicatorStyle = stateStyle.Render
-		} else if c.IsOverdue() {
+			indicatorStyle = style.Render
+		} else if escalation.IsNeglected(c, escalationCfg) {
+			indicator = "!"
+			indicatorStyle = overdueStyle.Render
+		} else if c.IsOverdue() || hasFollowUpDue(c, time.Now()) {
 			indicator = "*"
 			indicatorStyle = overdueStyle.Render
+		} else if m.hasDateDue(c) {
+			indicator = "◆"
+			indicatorStyle = yellowStyle.Render
 		} else {
 			switch c.ContactStyle {
 			case "ambient":
@@ -2258,18 +3792,45 @@ func (m Model) renderList(width, height int) string {
 		if c.Archived {
 			nameContent = "[ARCH] " + nameContent
 		}
+		if c.Starred {
+			nameContent = "★ " + nameContent
+		}
+		if m.marked[c.ID] {
+			nameContent = "✓ " + nameContent
+		}
+		var suffix string
+		if days := c.WaitingDays(); days >= 0 {
+			suffix = " (" + formatWaiting(days) + ")"
+		}
+		if c.Archived && c.ArchiveReason.Valid {
+			suffix = " — " + c.ArchiveReason.String
+		}
+		if last := formatLastInteraction(c); suffix == "" && last != "" {
+			// Only when it fits without cutting the name short
+			last = " (last: " + last + ")"
+			if lipgloss.Width(nameContent+last)+4 <= width-2 {
+				suffix = last
+			}
+		}
 		
 		// Build the line with consistent spacing and leading space
 		var line string
 		if i == m.selected {
 			// Selected: style the entire line uniformly with leading space
 			rawLine := fmt.Sprintf("▶ %s %s", indicator, nameContent)
+			rawLine = fitWithSuffix(rawLine, suffix, width-2)
 			line = selectedStyle.Render(rawLine)
 		} else {
 			// Non-selected: leading space + styled indicator + space + name
 			line = "  " + indicatorStyle(indicator) + " "
 			
 			// Add name content with appropriate styling
+			if m.marked[c.ID] {
+				line += greenStyle.Render("✓") + " "
+			}
+			if c.Starred {
+				line += yellowStyle.Render("★") + " "
+			}
 			if c.Archived {
 				if c.Label.Valid {
 					label := strings.TrimSpace(strings.ReplaceAll(c.Label.String, "\n", " "))
@@ -2285,6 +3846,9 @@ func (m Model) renderList(width, height int) string {
 					line += c.Name
 				}
 			}
+			if suffix != "" {
+				line = fitWithSuffix(line, dimmedStyle.Render(suffix), width-2)
+			}
 		}
 		
 		lines = append(lines, line)
@@ -2292,6 +3856,47 @@ func (m Model) renderList(width, height int) string {
 	
 	return strings.Join(lines, "\n")
 }
+
+// filterIndicators names the active filters other than the text filter,
+// as shown in the list header and the status bar
+func (m Model) filterIndicators() []string {
+	var filterIndicators []string
+	if m.typeFilter != "" {
+		filterIndicators = append(filterIndicators, "type:"+m.typeFilter)
+	}
+	if m.tagFilter != "" {
+		filterIndicators = append(filterIndicators, "#"+m.tagFilter)
+	}
+	if m.groupFilter != "" {
+		filterIndicators = append(filterIndicators, "group:"+m.groupFilter)
+	}
+	if m.stateFilter {
+		filterIndicators = append(filterIndicators, "state:non-ok")
+	}
+	if m.overdueFilter {
+		filterIndicators = append(filterIndicators, "overdue")
+	}
+	if m.neglectedFilter {
+		filterIndicators = append(filterIndicators, "neglected")
+	}
+	if m.incompleteFilter {
+		filterIndicators = append(filterIndicators, "incomplete")
+	}
+	if m.birthdayFilter {
+		filterIndicators = append(filterIndicators, "birthdays")
+	}
+	if m.starredFilter {
+		filterIndicators = append(filterIndicators, "starred")
+	}
+	if m.scriptFilter != "" {
+		filterIndicators = append(filterIndicators, "script:"+m.scriptFilter)
+	}
+	if m.showArchived {
+		filterIndicators = append(filterIndicators, "archived")
+	}
+	return filterIndicators
+}
+
 // renderDetail renders the contact detail view
 func (m Model) renderDetail(width, height int) string {
 	contacts := m.filteredContacts()
@@ -2308,27 +3913,97 @@ func (m Model) renderDetail(width, height int) string {
 		header += " (" + c.Label.String + ")"
 	}
 	lines = append(lines, header)
+	lines = append(lines, m.renderDetailTabs())
 	lines = append(lines, strings.Repeat("─", width-2))
 	lines = append(lines, "")
 	
+	switch m.detailTab {
+	case detailTabInteractions:
+		return strings.Join(append(lines, m.detailInteractionLines(c, width)...), "\n")
+	case detailTabTasks:
+		return strings.Join(append(lines, m.detailTaskLines(c, width)...), "\n")
+	case detailTabHistory:
+		return strings.Join(append(lines, m.detailHistoryLines(c, width)...), "\n")
+	}
+	
+	if m.inlineMode {
+		return strings.Join(append(lines, m.inlineEditLines(c, width)...), "\n")
+	}
+	
+	// Avatar
+	if m.detailContactID == c.ID && len(m.detailAvatarLines) > 0 {
+		lines = append(lines, m.detailAvatarLines...)
+		lines = append(lines, "")
+	}
+	
 	// Basic info
 	if c.Company.Valid {
 		lines = append(lines, fmt.Sprintf("Company: %s", c.Company.String))
 	}
+	if c.Location.Valid && c.Location.String != "" {
+		lines = append(lines, fmt.Sprintf("Location: %s", c.Location.String))
+	}
 	lines = append(lines, fmt.Sprintf("Relationship: %s", c.RelationshipType))
+	if len(c.Tags) > 0 {
+		lines = append(lines, "Tags: "+db.FormatTags(c.Tags))
+	}
+	if len(c.Groups) > 0 {
+		lines = append(lines, "Groups: "+strings.Join(c.Groups, ", "))
+	}
+	if m.detailContactID == c.ID {
+		lines = append(lines, m.detailLinkLines()...)
+	}
+	if m.detailContactID == c.ID {
+		lines = append(lines, m.detailFieldLines()...)
+	}
 	
 	if c.State.Valid {
-		lines = append(lines, fmt.Sprintf("State: %s", c.State.String))
+		lines = append(lines, "State: "+m.stateStyleFor(c.State.String).Render(c.State.String))
 	} else {
 		lines = append(lines, "State: ok")
 	}
 	
-	if c.Email.Valid {
+	if m.detailContactID == c.ID && len(m.detailEmails) > 1 {
+		for i, e := range m.detailEmails {
+			prefix := "Email: "
+			if i > 0 {
+				prefix = "       "
+			}
+			lines = append(lines, prefix+describeValue(e))
+		}
+	} else if c.Email.Valid {
 		lines = append(lines, fmt.Sprintf("Email: %s", c.Email.String))
 	}
-	if c.Phone.Valid {
+	if m.detailContactID == c.ID && len(m.detailPhones) > 1 {
+		for i, p := range m.detailPhones {
+			prefix := "Phone: "
+			if i > 0 {
+				prefix = "       "
+			}
+			lines = append(lines, prefix+describeValue(p))
+		}
+	} else if c.Phone.Valid {
 		lines = append(lines, fmt.Sprintf("Phone: %s", c.Phone.String))
 	}
+	if m.detailContactID == c.ID {
+		for i, h := range m.detailHandles {
+			prefix := "Social: "
+			if i > 0 {
+				prefix = "        "
+			}
+			lines = append(lines, prefix+describeValue(h))
+		}
+	}
+	if c.Birthday.Valid {
+		lines = append(lines, "Birthday: "+describeBirthday(c, time.Now()))
+	}
+	for i, line := range c.Address.Lines() {
+		prefix := "Address: "
+		if i > 0 {
+			prefix = "         "
+		}
+		lines = append(lines, prefix+line)
+	}
 	
 	if c.ContactedAt.Valid {
 		days := int(time.Since(c.ContactedAt.Time).Hours() / 24)
@@ -2337,6 +4012,12 @@ func (m Model) renderDetail(width, height int) string {
 	} else {
 		lines = append(lines, "Last Contact: Never")
 	}
+	if last := formatLastInteraction(c); last != "" {
+		lines = append(lines, "Last Interaction: "+last)
+	}
+	if localTime := describeLocalTime(c, time.Now()); localTime != "" {
+		lines = append(lines, localTime)
+	}
 	
 	// Show bump info if contact has been bumped
 	if c.BumpCount > 0 {
@@ -2351,40 +4032,77 @@ func (m Model) renderDetail(width, height int) string {
 		lines = append(lines, bumpInfo)
 	}
 	
+	if c.FollowUpDate.Valid {
+		lines = append(lines, describeDueDate("Follow-up", c.FollowUpDate.Time))
+	}
+	if c.DeadlineDate.Valid {
+		lines = append(lines, describeDueDate("Deadline", c.DeadlineDate.Time))
+	}
+	
 	// Contact style
 	styleInfo := fmt.Sprintf("Style: %s", c.ContactStyle)
 	if c.ContactStyle == "periodic" && c.CustomFrequencyDays.Valid {
 		styleInfo += fmt.Sprintf(" (%d days)", c.CustomFrequencyDays.Int64)
 	}
 	lines = append(lines, styleInfo)
+	if c.RemindersMuted {
+		lines = append(lines, "Reminders: muted (reference only)")
+	}
+	if c.IsSnoozed() {
+		lines = append(lines, "Snoozed until "+c.SnoozeUntil.Time.Local().Format("Jan 2"))
+	}
+	if c.Archived && c.ArchivedAt.Valid {
+		archiveInfo := fmt.Sprintf("Archived: %s", c.ArchivedAt.Time.Local().Format("2006-01-02"))
+		if c.ArchiveReason.Valid {
+			archiveInfo += " (" + c.ArchiveReason.String + ")"
+		}
+		lines = append(lines, archiveInfo)
+	}
+	if days := c.WaitingDays(); days >= 0 {
+		waitInfo := fmt.Sprintf("Waiting on reply since %s (%s)", c.WaitingSince.Time.Local().Format("Jan 2"), formatWaiting(days))
+		if c.WaitingNudged {
+			waitInfo += ", nudged"
+		}
+		lines = append(lines, waitInfo)
+	}
+	if missing := c.MissingFields(); len(missing) > 0 {
+		lines = append(lines, fmt.Sprintf("Profile: %d%% complete (missing %s)", c.Completeness(), strings.Join(missing, ", ")))
+	}
 	
 	lines = append(lines, "")
 	
-	// Notes
+	// Energy ratings (served from the detail cache)
+	if m.detailContactID == c.ID && m.detailRatings.Count > 0 {
+		lines = append(lines, describeRatings(m.detailRatings))
+		lines = append(lines, "")
+	}
+	
+	// Time spent (served from the detail cache)
+	if m.detailContactID == c.ID && m.detailDurations.Count > 0 {
+		lines = append(lines, describeDurations(m.detailDurations))
+		lines = append(lines, "")
+	}
+	
+	// Important dates (served from the detail cache)
+	if m.detailContactID == c.ID && len(m.detailDates) > 0 {
+		lines = append(lines, "Important Dates:")
+		for _, d := range m.detailDates {
+			lines = append(lines, "  "+describeDate(d))
+		}
+		lines = append(lines, "")
+	}
+	
+	// Notes, rendered as Markdown with their links numbered for 1-9
+	md := markdown{width: width - 2}
 	if c.Notes.Valid && c.Notes.String != "" {
 		lines = append(lines, "Notes:")
-		lines = append(lines, c.Notes.String)
+		lines = append(lines, md.render(c.Notes.String)...)
 		lines = append(lines, "")
 	}
 	
-	// Recent Interactions
-	interactions, err := m.db.GetContactInteractions(c.ID, 5)
-	if err == nil && len(interactions) > 0 {
-		lines = append(lines, "Recent Interactions:")
-		lines = append(lines, strings.Repeat("─", width-2))
-		for _, log := range interactions {
-			dateStr := log.InteractionDate.Format("2006-01-02 15:04")
-			typeStr := fmt.Sprintf("[%s]", log.InteractionType)
-			lines = append(lines, fmt.Sprintf("%s %s", dateStr, typeStr))
-			if log.Notes.Valid && log.Notes.String != "" {
-				// Wrap long notes
-				noteLines := wrapText(log.Notes.String, width-4)
-				for _, noteLine := range noteLines {
-					lines = append(lines, "  "+noteLine)
-				}
-			}
-			lines = append(lines, "")
-		}
+	// Journal entries mentioning the contact (served from the detail cache)
+	if m.detailContactID == c.ID {
+		lines = append(lines, m.detailJournalLines(width)...)
 	}
 	
 	return strings.Join(lines, "\n")
@@ -2400,8 +4118,16 @@ func (m Model) renderHelp() string {
 		return " y: confirm bump • any other key: cancel"
 	}
 	
+	if m.archiveConfirmMode {
+		return " y: confirm • any other key: cancel"
+	}
+	
+	if m.purgeConfirmMode {
+		return " y: PURGE CONTACTS • any other key: cancel"
+	}
+	
 	if m.typeFilterMode {
-		return " Press hotkey to select • Esc: cancel"
+		return " Press hotkey to select • j/k, Enter: select a tag • Esc: cancel"
 	}
 	
 	if m.stateMode {
@@ -2428,19 +4154,47 @@ func (m Model) renderHelp() string {
 		return " Type to filter • ↑/↓: navigate • Enter: confirm • Esc: cancel"
 	}
 	
-	help := " j/k: navigate • /: filter • c: contacted • ?: help • q: quit"
+	if m.pickerMode {
+		return " Type to search • ↑/↓: select • Enter: jump • Esc: cancel"
+	}
+	
+	if m.paletteMode {
+		return " Type to search commands • ↑/↓: select • Enter: run • Esc: cancel"
+	}
+	
+	if m.noteSearchMode {
+		return " Type to search notes • ↑/↓: select • Enter: go to contact • Esc: cancel"
+	}
+	
+	if len(m.marked) > 0 && !m.bulkMode {
+		return fmt.Sprintf(" %d marked • space/V: mark • s: state • a: archive • #: tag • X: trash • Esc: unmark", len(m.marked))
+	}
+	
+	help := " j/k: navigate • /: filter • c: contacted • ctrl+p: commands • ?: help • q: quit"
+	
+	// Going between the list and a contact comes first in single-pane
+	// layout, where narrow windows cut the end of the help short
+	if m.singlePane() && m.detailOpen {
+		help = " Esc: back to list •" + help
+	} else if m.singlePane() {
+		help = " Enter: details •" + help
+	}
 	
 	// Add notes-tui integration if enabled
 	if m.cfg != nil && m.cfg.External.NotesTUI {
-		help += " • O: open notes"
+		help += " • R: open notes"
 	}
 	
 	// Show clear option if any filters are active
-	if m.stateFilter || m.overdueFilter || m.typeFilter != "" || m.filter.Value() != "" || m.showArchived {
+	if m.currentFilters() != (filterState{}) {
 		help += " • C: clear filters"
 	}
 	
-	if m.filter.Value() != "" {
+	if m.singlePane() && m.detailOpen {
+		// Esc goes back to the list first
+	} else if m.stashedFilters != nil {
+		help += " • Esc: restore filters"
+	} else if m.filter.Value() != "" {
 		help += " • Esc: clear filter"
 	}
 	
@@ -2458,7 +4212,7 @@ func (m Model) renderFlash() string {
 	// If no flash message, render empty space with neutral background
 	if m.flashMessage == "" {
 		return lipgloss.NewStyle().
-			Background(lipgloss.Color("235")). // Dark gray background
+			Background(theme.StatusBar).
 			Height(1).
 			Width(width).
 			Render("")
@@ -2469,26 +4223,26 @@ func (m Model) renderFlash() string {
 	switch m.flashType {
 	case FlashSuccess:
 		style = lipgloss.NewStyle().
-			Background(lipgloss.Color("#2d7a2d")).
+			Background(theme.FlashSuccess).
 			Foreground(lipgloss.Color("#ffffff")).
 			Padding(0, 1).
 			Width(width)
 	case FlashError:
 		style = lipgloss.NewStyle().
-			Background(lipgloss.Color("#d32f2f")).
+			Background(theme.FlashError).
 			Foreground(lipgloss.Color("#ffffff")).
 			Padding(0, 1).
 			Width(width)
 	case FlashInfo:
 		style = lipgloss.NewStyle().
-			Background(lipgloss.Color("#1976d2")).
+			Background(theme.FlashInfo).
 			Foreground(lipgloss.Color("#ffffff")).
 			Padding(0, 1).
 			Width(width)
 	default:
 		// Fallback style
 		style = lipgloss.NewStyle().
-			Background(lipgloss.Color("#2d7a2d")).
+			Background(theme.FlashSuccess).
 			Foreground(lipgloss.Color("#ffffff")).
 			Padding(0, 1).
 			Width(width)
@@ -2529,12 +4283,22 @@ func (m Model) renderStateSelection() string {
 		}
 		
 		line := fmt.Sprintf("  %s", stateDisplay)
-		if i == m.stateSelected {
+		if !m.canMoveTo(contact, ContactStates[i]) {
+			// Not allowed by the configured transitions
+			line = dimmedStyle.Render(line)
+		} else if i == m.stateSelected {
 			line = selectedStyle.Render(line)
 		}
 		lines = append(lines, line)
 	}
 	
+	lines = append(lines, "")
+	if contact.WaitingSince.Valid {
+		lines = append(lines, fmt.Sprintf("  [W] stop waiting on reply (%s)", formatWaiting(contact.WaitingDays())))
+	} else {
+		lines = append(lines, "  [W] waiting on reply")
+	}
+	
 	lines = append(lines, "")
 	lines = append(lines, "Press hotkey to select, Esc to cancel")
 	
@@ -2580,10 +4344,28 @@ func (m Model) renderNoteInput() string {
 	lines = append(lines, typeSelector)
 	lines = append(lines, "")
 	
+	// Show energy rating
+	if m.noteRating > 0 {
+		lines = append(lines, "Energy: "+formatRating(m.noteRating))
+	} else {
+		lines = append(lines, "Energy: not rated")
+	}
+	if m.noteDuration > 0 {
+		lines = append(lines, "Duration: "+formatDuration(m.noteDuration))
+	} else {
+		lines = append(lines, "Duration: not tracked")
+	}
+	if m.noteWaiting {
+		lines = append(lines, "[x] Waiting on their reply")
+	} else {
+		lines = append(lines, "[ ] Waiting on their reply")
+	}
+	lines = append(lines, "")
+	
 	// Show note input
 	lines = append(lines, m.noteInput.View())
 	lines = append(lines, "")
-	lines = append(lines, "Tab: change type • Ctrl+Enter: save • Esc: cancel")
+	lines = append(lines, "Tab: change type • Ctrl+R: rate energy • Ctrl+L: duration • Ctrl+Y: waiting on reply • Ctrl+Enter: save • Esc: cancel")
 	
 	// Create a bordered box and center it
 	content := strings.Join(lines, "\n")
@@ -2637,6 +4419,8 @@ func (m Model) renderTypeSelection() string {
 		lines = append(lines, line)
 	}
 	
+	lines = append(lines, m.renderFilterTags()...)
+	
 	lines = append(lines, "")
 	lines = append(lines, "Press hotkey to select, Esc to cancel")
 	
@@ -2676,9 +4460,19 @@ func (m Model) renderEditMode() string {
 		"Email:           ",
 		"Phone:           ",
 		"Company:         ",
+		"Location:        ",
 		"Relationship:    ",
 		"Notes:           ",
 		"Label:           ",
+		"Birthday:        ",
+		"Tags:            ",
+		"Avatar:          ",
+		"Follow-up:       ",
+		"Deadline:        ",
+		"Time zone:       ",
+	}
+	for _, name := range customFieldNames(m.cfg) {
+		fieldLabels = append(fieldLabels, fieldLabel(name))
 	}
 	
 	for i, label := range fieldLabels {
@@ -2772,9 +4566,9 @@ func (m Model) renderBumpConfirmation() string {
 	
 	// Build the confirmation prompt
 	width := 60
-	height := 7
+	height := 9
 	
-	prompt := fmt.Sprintf("Bump contact '%s'? (y/n)", contactName)
+	prompt := fmt.Sprintf("Bump contact '%s'? (y/n)\n\n%s", contactName, confirmButtons())
 	
 	content := lipgloss.NewStyle().
 		Width(width-4).
@@ -2784,7 +4578,7 @@ func (m Model) renderBumpConfirmation() string {
 	
 	box := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("63")).
+		BorderForeground(theme.Overlay).
 		Width(width).
 		Height(height).
 		Render(content)
@@ -2800,24 +4594,24 @@ func (m Model) renderBumpConfirmation() string {
 func (m Model) renderDeleteConfirmation() string {
 	// Build the confirmation prompt
 	width := 60
-	height := 10
+	height := 12
 	
 	prompt := fmt.Sprintf("Delete contact '%s'?\n\n"+
-		"This will permanently delete the contact\n"+
-		"and all associated interaction logs.\n\n"+
-		"This action cannot be undone!\n\n"+
-		"Press 'y' to confirm, any other key to cancel.", m.deleteContactName)
+		"The contact and its interaction logs\n"+
+		"will be moved to the trash (Z), where\n"+
+		"they can be restored until purged.\n\n"+
+		"Press 'y' to confirm, any other key to cancel.\n\n%s", m.deleteContactName, confirmButtons())
 	
 	content := lipgloss.NewStyle().
 		Width(width-4).
 		Height(height-4).
 		Align(lipgloss.Center, lipgloss.Center).
-		Foreground(lipgloss.Color("196")). // Red text for warning
+		Foreground(theme.Danger). // Red text for warning
 		Render(prompt)
 	
 	box := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("196")). // Red border for danger
+		BorderForeground(theme.Danger). // Red border for danger
 		Width(width).
 		Height(height).
 		Render(content)
@@ -2852,6 +4646,11 @@ func (m *Model) enterEditMode(contact db.Contact) {
 	} else {
 		m.editInputs[EditFieldCompany].SetValue("")
 	}
+	if contact.Location.Valid {
+		m.editInputs[EditFieldLocation].SetValue(contact.Location.String)
+	} else {
+		m.editInputs[EditFieldLocation].SetValue("")
+	}
 	if contact.Notes.Valid {
 		m.editInputs[EditFieldNotes].SetValue(contact.Notes.String)
 	} else {
@@ -2862,6 +4661,23 @@ func (m *Model) enterEditMode(contact db.Contact) {
 	} else {
 		m.editInputs[EditFieldLabel].SetValue("")
 	}
+	if contact.Birthday.Valid {
+		m.editInputs[EditFieldBirthday].SetValue(contact.Birthday.String)
+	} else {
+		m.editInputs[EditFieldBirthday].SetValue("")
+	}
+	m.editInputs[EditFieldTags].SetValue(db.FormatTags(contact.Tags))
+	m.editInputs[EditFieldAvatar].SetValue(contact.Avatar.String)
+	m.editInputs[EditFieldFollowUp].SetValue(formatDueInput(contact.FollowUpDate))
+	m.editInputs[EditFieldDeadline].SetValue(formatDueInput(contact.DeadlineDate))
+	m.editInputs[EditFieldTimezone].SetValue(contact.Timezone.String)
+	values, err := m.db.FieldValues(contact.ID)
+	if err != nil {
+		m.err = err
+	}
+	for i, name := range customFieldNames(m.cfg) {
+		m.editInputs[EditFieldCount+i].SetValue(values[name])
+	}
 	
 	// Set the relationship type index
 	m.editRelTypeIdx = 0 // Default to first type
@@ -2931,7 +4747,7 @@ func (m Model) renderStyleMode() string {
 		Width(width).
 		Height(height).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(theme.Border).
 		Padding(1, 2)
 	
 	// Center the box
@@ -2956,39 +4772,90 @@ func (m Model) renderHelpOverlay() string {
 		"  j/k, ↓/↑     Navigate contacts",
 		"  g            Go to top",
 		"  G            Go to bottom",
+		"  Ctrl+D/U     Half a page down/up",
+		"  [ / ]        Detail tabs: Info, Interactions, Tasks, History",
+		"  Ctrl+←/→     Narrow/widen the contact list (saved to config)",
+		"  Ctrl+G       Jump to any contact (ignores filters)",
+		"  Ctrl+F       Search contact and interaction notes",
+		"  Ctrl+P       Command palette: search and run any action by name",
 		"  q, Ctrl+C    Quit",
 		"",
 		"Contact Actions:",
-		"  +, N         Create new contact",
+		"  +, N         Create new contact (Ctrl+G in the form goes to a possible duplicate,",
+		"               Ctrl+O merges into it)",
 		"  c            Mark as contacted",
 		"  b            Bump (reset date without contact)",
+		"  Enter        Edit one field at a time in the detail pane",
 		"  e            Edit contact details",
+		"  y            Copy contact and recent interactions as Markdown",
 		"  n            Add note/interaction",
+		"               (Ctrl+R in the note rates its energy 1-5)",
+		"               (Ctrl+L in the note sets how long it took)",
+		"  E            Draft email using the template for contact's state",
+		"  l            Call the contact's phone number",
+		"  Q            Show the contact's card as a QR code to scan",
+		"  T            Send a text message (when messaging is configured)",
 		"  i            View/edit interaction history",
+		"               (a attaches a file or URL, o/1-9 open attachments)",
 		"  t            View/manage tasks",
+		"  d            View/edit important dates",
+		"  @            View/edit email addresses (work/personal)",
+		"  #            View/edit phone numbers (mobile/work/home)",
+		"  H            View/edit social media handles (o opens a profile)",
+		"  K            Linked contacts (spouse, reports to, introduced by...);",
+		"               Enter jumps to a linked contact",
+		"  h            History of changes to the contact's fields",
+		"  J            Journal: write today's entry, @label mentions link contacts",
+		"  p            Edit postal address",
+		"  L            Groups: add/remove contact, filter or set state for a group",
+		"  U            Upcoming important dates (agenda)",
+		"  w            Dashboard of overdue contacts, states and follow-ups",
+		"  1-9          Open a numbered link in the contact's notes",
 	}
 	
 	// Add notes-tui integration if enabled
 	if m.cfg != nil && m.cfg.External.NotesTUI {
-		helpLines = append(helpLines, "  O            Open notes for contact")
+		helpLines = append(helpLines, "  R            Open notes for contact")
 	}
 	
 	// Continue with the rest of the help
 	helpLines = append(helpLines,
-		"  a            Archive/unarchive contact",
+		"  a            Archive (with an optional reason) or unarchive contact",
 		"  m            Change contact style (periodic/ambient/triggered)",
-		"  D            Delete contact (with confirmation)",
+		"  M            Mute/unmute reminders for contact",
+		"  *            Star/unstar contact (starred are listed first)",
+		"  z            Snooze contact out of the overdue view (1w/1m/custom date)",
+		"  D            Archive contact (or delete, see delete_action)",
+		"  X            Move contact to the trash (with confirmation)",
+		"  Z            Trash: restore or purge deleted contacts",
+		"  u            Undo the last state change, delete, archive, bump or contact",
+		"  P            Purge contacts archived past the retention period",
+		"  I            Import contacts from a file (CSV or vCard)",
+		"  x            Export the filtered contacts to CSV",
+		"  Ctrl+S       Sync now (when sync is configured)",
 		"",
 		"State Management:",
 		"  s            Change contact state (ping, write, ok, etc.)",
+		"               (W in the state menu marks waiting on their reply)",
 		"  S            Toggle filter: show only non-ok states",
 		"",
 		"Filtering:",
 		"  /            Search/filter contacts",
-		"  r            Filter by relationship type",
-		"  o            Toggle filter: show only overdue",
+		"               e.g. state:ping type:work company:acme overdue:yes -tag:old",
+		"               (location:city narrows to contacts in a city,",
+		"               #tag to contacts with that tag)",
+		"  r            Filter by relationship type or tag",
+		"  o            Toggle filter: show only overdue or with a date coming up",
+		"  !            Toggle filter: show only seriously neglected",
+		"  %            Toggle filter: show only incomplete profiles",
+		"  B            Toggle filter: upcoming birthdays, soonest first",
+		"  v            Toggle filter: show only starred contacts",
+		"  f            Cycle script filters (from scripts.toml)",
 		"  A            Toggle: show/hide archived contacts",
 		"  C            Clear all active filters",
+		"  Space, V     Mark a contact, or a range from the last marked one; s, a, D,",
+		"               # and X then act on all marked contacts at once (Esc unmarks)",
+		"  O            Sort by name, last contacted, most overdue, recently added or state",
 		"  Esc          Clear search filter / Close help",
 		"",
 		"Help:",
@@ -3039,7 +4906,7 @@ func (m Model) renderHelpOverlay() string {
 	// Add scroll up indicator if needed
 	if scrollOffset > 0 {
 		content += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241")).
+			Foreground(theme.Muted).
 			Render("▲ (more above)") + "\n"
 		visibleLines = visibleLines[1:] // Remove one line to make room
 	}
@@ -3057,7 +4924,7 @@ func (m Model) renderHelpOverlay() string {
 			content = strings.Join(lines[:len(lines)-1], "\n") + "\n"
 		}
 		content += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241")).
+			Foreground(theme.Muted).
 			Render("▼ (more below)")
 	}
 	
@@ -3071,7 +4938,7 @@ func (m Model) renderHelpOverlay() string {
 	// Create the box
 	box := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("63")).
+		BorderForeground(theme.Overlay).
 		Width(width).
 		Height(height).
 		Render(styledContent)
@@ -3090,7 +4957,7 @@ func (m Model) renderTaskMode() string {
 	
 	content := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("32")).
+		Foreground(theme.Title).
 		MarginBottom(1).
 		Render("Tasks") + "\n\n"
 	
@@ -3103,23 +4970,15 @@ func (m Model) renderTaskMode() string {
 			contactInfo += fmt.Sprintf(" (%s)", contact.Label.String)
 		}
 		content += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("214")).
+			Foreground(theme.Accent).
 			MarginBottom(1).
 			Render(contactInfo) + "\n\n"
 	}
 	
-	// Show error if any
-	if m.err != nil {
-		content += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("196")).
-			MarginBottom(1).
-			Render("Error: " + m.err.Error()) + "\n\n"
-	}
-	
 	// Show tasks
 	if len(m.tasks) == 0 {
 		content += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241")).
+			Foreground(theme.Muted).
 			Render("No tasks found for this contact.") + "\n"
 	} else {
 		content += fmt.Sprintf("Tasks (%d):\n\n", len(m.tasks))
@@ -3150,13 +5009,13 @@ func (m Model) renderTaskMode() string {
 	// Add help text at the bottom
 	helpText := " j/k: navigate tasks • Enter/Space: mark task complete • r: refresh • Esc: back to contacts"
 	content += lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(theme.Muted).
 		Render(helpText) + "\n"
 	
 	// Create a box style
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(theme.Border).
 		Padding(1).
 		Width(width).
 		Height(height)
@@ -3177,7 +5036,7 @@ func (m Model) renderTaskCompletionMode() string {
 	
 	content := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("32")).
+		Foreground(theme.Title).
 		MarginBottom(1).
 		Render("Complete Task") + "\n\n"
 	
@@ -3195,7 +5054,7 @@ func (m Model) renderTaskCompletionMode() string {
 				contactInfo += fmt.Sprintf(" (%s)", contact.Label.String)
 			}
 			content += lipgloss.NewStyle().
-				Foreground(lipgloss.Color("214")).
+				Foreground(theme.Accent).
 				MarginBottom(1).
 				Render(contactInfo) + "\n\n"
 		}
@@ -3208,13 +5067,13 @@ func (m Model) renderTaskCompletionMode() string {
 	// Add help text
 	helpText := " Ctrl+Enter: save and complete task • Esc: cancel"
 	content += lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(theme.Muted).
 		Render(helpText) + "\n"
 	
 	// Create a box style
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(theme.Border).
 		Padding(1).
 		Width(width).
 		Height(height)
@@ -3243,7 +5102,7 @@ func (m Model) renderStateUpdatePrompt() string {
 	
 	content := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("32")).
+		Foreground(theme.Title).
 		MarginBottom(1).
 		Render("Update Contact State?") + "\n\n"
 	
@@ -3260,13 +5119,13 @@ func (m Model) renderStateUpdatePrompt() string {
 	// Add help text
 	helpText := " y: update state • n/Esc: keep current state"
 	content += lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(theme.Muted).
 		Render(helpText)
 	
 	// Create a bordered box
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("32")).
+		BorderForeground(theme.Title).
 		Padding(1).
 		Width(width).
 		Height(height)
@@ -3293,7 +5152,7 @@ func (m Model) renderLabelPrompt() string {
 	
 	content := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("32")).
+		Foreground(theme.Title).
 		MarginBottom(1).
 		Render("Add Label for Task") + "\n\n"
 	
@@ -3302,23 +5161,15 @@ func (m Model) renderLabelPrompt() string {
 	content += "This contact needs a label to create tasks.\n"
 	content += "Enter a unique label (will be used as @tag):\n\n"
 	
-	// Show error if any
-	if m.err != nil {
-		content += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("196")).
-			MarginBottom(1).
-			Render("Error: " + m.err.Error()) + "\n\n"
-	}
-	
 	content += "Label: " + m.labelPromptInput.View() + "\n\n"
 	content += lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(theme.Muted).
 		Render("Enter: save • Esc: cancel")
 	
 	// Create a box style
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(theme.Border).
 		Padding(1).
 		Width(width).
 		Height(height)
@@ -3336,22 +5187,14 @@ func (m Model) renderLabelPrompt() string {
 func (m Model) renderNewContactMode() string {
 	width := 60
 	fieldHeight := 3
-	totalHeight := (EditFieldCount-1)*fieldHeight + 12 // account for title, spacing, and buttons
+	totalHeight := (len(m.newContactInputs)-1)*fieldHeight + 12 // account for title, spacing, and buttons
 	
 	content := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("32")).
+		Foreground(theme.Title).
 		MarginBottom(1).
 		Render("Create New Contact") + "\n\n"
 	
-	// Show error if any
-	if m.err != nil {
-		content += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("196")).
-			MarginBottom(1).
-			Render("Error: " + m.err.Error()) + "\n\n"
-	}
-	
 	// Name field
 	nameLabel := "Name: "
 	if m.newContactField == EditFieldName {
@@ -3380,6 +5223,13 @@ func (m Model) renderNewContactMode() string {
 	}
 	content += companyLabel + m.newContactInputs[EditFieldCompany].View() + "\n\n"
 	
+	// Location field
+	locationLabel := "Location: "
+	if m.newContactField == EditFieldLocation {
+		locationLabel = selectedStyle.Render(locationLabel)
+	}
+	content += locationLabel + m.newContactInputs[EditFieldLocation].View() + "\n\n"
+	
 	// Relationship type selector
 	relLabel := "Relationship: "
 	if m.newContactField == EditFieldRelType {
@@ -3411,15 +5261,69 @@ func (m Model) renderNewContactMode() string {
 	}
 	content += labelLabel + m.newContactInputs[EditFieldLabel].View() + "\n\n"
 	
+	// Birthday field
+	birthdayLabel := "Birthday: "
+	if m.newContactField == EditFieldBirthday {
+		birthdayLabel = selectedStyle.Render(birthdayLabel)
+	}
+	content += birthdayLabel + m.newContactInputs[EditFieldBirthday].View() + "\n\n"
+	
+	// Tags field
+	tagsLabel := "Tags: "
+	if m.newContactField == EditFieldTags {
+		tagsLabel = selectedStyle.Render(tagsLabel)
+	}
+	content += tagsLabel + m.newContactInputs[EditFieldTags].View() + "\n\n"
+	
+	// Avatar field
+	avatarLabel := "Avatar: "
+	if m.newContactField == EditFieldAvatar {
+		avatarLabel = selectedStyle.Render(avatarLabel)
+	}
+	content += avatarLabel + m.newContactInputs[EditFieldAvatar].View() + "\n\n"
+	
+	// Follow-up and deadline fields
+	followUpLabel := "Follow-up: "
+	if m.newContactField == EditFieldFollowUp {
+		followUpLabel = selectedStyle.Render(followUpLabel)
+	}
+	content += followUpLabel + m.newContactInputs[EditFieldFollowUp].View() + "\n\n"
+	deadlineLabel := "Deadline: "
+	if m.newContactField == EditFieldDeadline {
+		deadlineLabel = selectedStyle.Render(deadlineLabel)
+	}
+	content += deadlineLabel + m.newContactInputs[EditFieldDeadline].View() + "\n\n"
+	
+	// Time zone field
+	timezoneLabel := "Time zone: "
+	if m.newContactField == EditFieldTimezone {
+		timezoneLabel = selectedStyle.Render(timezoneLabel)
+	}
+	content += timezoneLabel + m.newContactInputs[EditFieldTimezone].View() + "\n\n"
+	
+	// Custom fields from the config
+	for i, name := range customFieldNames(m.cfg) {
+		label := name + ": "
+		if m.newContactField == EditFieldCount+i {
+			label = selectedStyle.Render(label)
+		}
+		content += label + m.newContactInputs[EditFieldCount+i].View() + "\n\n"
+	}
+	
+	// Possible duplicates
+	if len(m.duplicates) > 0 {
+		content += renderDuplicateWarning(m.duplicates, m.duplicateConfirm)
+	}
+	
 	// Instructions
 	content += lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(theme.Muted).
 		Render("Tab/Shift+Tab: Navigate • Enter: Save • Esc: Cancel")
 	
 	// Create the box
 	box := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("63")).
+		BorderForeground(theme.Overlay).
 		Width(width).
 		Height(totalHeight).
 		Padding(1).
@@ -3446,6 +5350,11 @@ func (m Model) renderInteractionEditMode() string {
 	if m.interactionEditInput.Focused() {
 		availableHeight -= 4 // Space for edit mode display
 	}
+	if m.attachmentInputMode {
+		availableHeight -= 3 // Space for the attachment input
+	} else if !m.interactionEditInput.Focused() && !m.interactionDeleteConfirm {
+		availableHeight-- // Second line of instructions
+	}
 	if m.interactionDeleteConfirm {
 		availableHeight -= 2 // Space for delete confirmation
 	}
@@ -3466,6 +5375,12 @@ func (m Model) renderInteractionEditMode() string {
 		// Date and type line
 		dateStr := interaction.InteractionDate.Format("2006-01-02 15:04")
 		typeStr := fmt.Sprintf("[%s]", interaction.InteractionType)
+		if interaction.Rating.Valid {
+			typeStr += " " + formatRating(int(interaction.Rating.Int64))
+		}
+		if interaction.DurationMinutes.Valid {
+			typeStr += " " + formatDuration(int(interaction.DurationMinutes.Int64))
+		}
 		
 		// Selection indicator
 		var prefix string
@@ -3484,6 +5399,9 @@ func (m Model) renderInteractionEditMode() string {
 				display.lines = append(display.lines, "    " + line)
 			}
 		}
+		for _, line := range attachmentLines(interaction.Attachments, i == m.selectedInteraction, width-8) {
+			display.lines = append(display.lines, "    " + line)
+		}
 		
 		// Empty line after each interaction
 		display.lines = append(display.lines, "")
@@ -3529,7 +5447,7 @@ func (m Model) renderInteractionEditMode() string {
 	// Build content for visible portion
 	content := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("32")).
+		Foreground(theme.Title).
 		MarginBottom(1).
 		Render("Interaction History")
 	
@@ -3540,7 +5458,7 @@ func (m Model) renderInteractionEditMode() string {
 			min(viewportEnd, totalLines), 
 			totalLines)
 		content += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241")).
+			Foreground(theme.Muted).
 			Render(scrollInfo)
 	}
 	content += "\n\n"
@@ -3561,13 +5479,13 @@ func (m Model) renderInteractionEditMode() string {
 		content = strings.TrimSuffix(content, "\n")
 		content = strings.TrimSuffix(content, "\n")
 		content += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241")).
+			Foreground(theme.Muted).
 			Render("\n  ↑ More above") + "\n"
 	}
 	if viewportEnd < totalLines {
 		content = strings.TrimSuffix(content, "\n")
 		content += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241")).
+			Foreground(theme.Muted).
 			Render("\n  ↓ More below") + "\n"
 	}
 	
@@ -3579,10 +5497,18 @@ func (m Model) renderInteractionEditMode() string {
 		content += m.interactionEditInput.View() + "\n"
 	}
 	
+	// If attaching, show the path or URL input
+	if m.attachmentInputMode {
+		content += "\n" + lipgloss.NewStyle().
+			Bold(true).
+			Render("Attach a file or URL") + "\n"
+		content += m.attachmentInput.View() + "\n"
+	}
+	
 	// Show delete confirmation if active
 	if m.interactionDeleteConfirm {
 		content += "\n" + lipgloss.NewStyle().
-			Foreground(lipgloss.Color("196")).
+			Foreground(theme.Danger).
 			Bold(true).
 			Render("Delete this interaction? (y/n)")
 	}
@@ -3591,20 +5517,24 @@ func (m Model) renderInteractionEditMode() string {
 	var instructions string
 	if m.interactionEditInput.Focused() {
 		instructions = "Tab: change type • Ctrl+Enter: save • Esc: cancel"
+	} else if m.attachmentInputMode {
+		instructions = "Enter: attach • Esc: cancel"
 	} else if m.interactionDeleteConfirm {
 		instructions = "y: confirm delete • any key: cancel"
 	} else {
-		instructions = "j/k: navigate • e: edit • d: delete • Esc: exit"
+		instructions = "j/k: navigate • e: edit • d: delete • Esc: exit\n" +
+			"a: attach file or URL • o/1-9: open • x: remove last attachment\n" +
+			"w/W: export timeline as Markdown/JSON"
 	}
 	
 	content += "\n" + lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(theme.Muted).
 		Render(instructions)
 	
 	// Create the box
 	box := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("63")).
+		BorderForeground(theme.Overlay).
 		Width(width).
 		Height(height).
 		Padding(1).
@@ -0,0 +1,201 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// openedMsg reports that the opener for a file or URL finished
+type openedMsg struct {
+	target string
+	err    error
+}
+
+// openCommand returns the command that opens attachments and profile URLs
+func (m Model) openCommand() string {
+	if m.cfg == nil || m.cfg.UI.OpenCommand == "" {
+		return config.Default().UI.OpenCommand
+	}
+	return m.cfg.UI.OpenCommand
+}
+
+// isURL reports whether an attachment is a URL rather than a file path
+func isURL(target string) bool {
+	return strings.Contains(target, "://") || strings.HasPrefix(target, "mailto:")
+}
+
+// cleanAttachment checks a typed file path or URL. Paths may start with ~
+// or be relative to the current directory, and are stored absolute; they
+// must exist.
+func cleanAttachment(input string) (string, error) {
+	target := strings.TrimSpace(input)
+	target = strings.Trim(target, `"'`) // Pasted or dropped paths are often quoted
+	if target == "" {
+		return "", fmt.Errorf("a file path or URL is required")
+	}
+	if isURL(target) {
+		return target, nil
+	}
+
+	path, err := filepath.Abs(config.ExpandPath(strings.ReplaceAll(target, `\ `, " ")))
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("no such file: %s", target)
+	}
+	return path, nil
+}
+
+// attachmentName is how an attachment is listed: a file's name, or a URL
+func attachmentName(target string) string {
+	if isURL(target) {
+		return target
+	}
+	return filepath.Base(target)
+}
+
+// openExternal opens a file or URL with the configured opener
+func (m Model) openExternal(target string) tea.Cmd {
+	args := strings.Fields(m.openCommand())
+	if len(args) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		out, err := exec.Command(args[0], append(args[1:], target)...).CombinedOutput()
+		if err != nil && len(out) > 0 {
+			err = fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+		}
+		return openedMsg{target: target, err: err}
+	}
+}
+
+// handleOpened reports a file or URL the opener failed on
+func (m Model) handleOpened(msg openedMsg) Model {
+	if msg.err != nil {
+		return m.setFlash(FlashError, fmt.Sprintf("Opening %s: %v", attachmentName(msg.target), msg.err))
+	}
+	return m
+}
+
+// selectedInteractionLog returns the interaction under the cursor in the
+// interaction view
+func (m Model) selectedInteractionLog() (db.Log, bool) {
+	if m.selectedInteraction < 0 || m.selectedInteraction >= len(m.interactions) {
+		return db.Log{}, false
+	}
+	return m.interactions[m.selectedInteraction], true
+}
+
+// reloadInteractions refreshes the interaction view after a change to one
+// of a contact's interactions
+func (m Model) reloadInteractions(contactID int) Model {
+	m.invalidateDetailCache()
+	if interactions, err := m.db.GetContactInteractions(contactID, 20); err == nil {
+		m.interactions = interactions
+	}
+	return m
+}
+
+// openAttachmentInput starts attaching a file or URL to the selected
+// interaction
+func (m Model) openAttachmentInput() (tea.Model, tea.Cmd) {
+	if _, ok := m.selectedInteractionLog(); !ok {
+		return m, nil
+	}
+	m.attachmentInputMode = true
+	m.attachmentInput.Reset()
+	m.attachmentInput.Focus()
+	return m, textinput.Blink
+}
+
+// updateAttachmentInput handles keys while typing an attachment
+func (m Model) updateAttachmentInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.attachmentInputMode = false
+		m.attachmentInput.Blur()
+		return m, nil
+	case "enter":
+		interaction, ok := m.selectedInteractionLog()
+		if !ok {
+			m.attachmentInputMode = false
+			return m, nil
+		}
+		target, err := cleanAttachment(m.attachmentInput.Value())
+		if err != nil {
+			return m.setFlash(FlashError, err.Error()), nil
+		}
+		if err := m.db.AddAttachment(interaction.ID, target); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.attachmentInputMode = false
+		m.attachmentInput.Blur()
+		m = m.reloadInteractions(interaction.ContactID)
+		return m.setFlash(FlashSuccess, fmt.Sprintf("✓ Attached %s", attachmentName(target))), nil
+	}
+
+	var cmd tea.Cmd
+	m.attachmentInput, cmd = m.attachmentInput.Update(msg)
+	return m, cmd
+}
+
+// openNthAttachment opens the selected interaction's nth attachment,
+// counting from 1
+func (m Model) openNthAttachment(n int) (tea.Model, tea.Cmd) {
+	interaction, ok := m.selectedInteractionLog()
+	if !ok {
+		return m, nil
+	}
+	if n < 1 || n > len(interaction.Attachments) {
+		if len(interaction.Attachments) == 0 {
+			return m.setFlash(FlashInfo, "Nothing attached; press a to attach a file or URL"), nil
+		}
+		return m, nil
+	}
+	target := interaction.Attachments[n-1]
+	m = m.setFlash(FlashInfo, fmt.Sprintf("Opening %s", attachmentName(target)))
+	return m, m.openExternal(target)
+}
+
+// removeLastAttachment takes the most recently added attachment off the
+// selected interaction
+func (m Model) removeLastAttachment() Model {
+	interaction, ok := m.selectedInteractionLog()
+	if !ok || len(interaction.Attachments) == 0 {
+		return m
+	}
+	target := interaction.Attachments[len(interaction.Attachments)-1]
+	if err := m.db.RemoveAttachment(interaction.ID, target); err != nil {
+		m.err = err
+		return m
+	}
+	m = m.reloadInteractions(interaction.ContactID)
+	return m.setFlash(FlashSuccess, fmt.Sprintf("✓ Removed %s", attachmentName(target)))
+}
+
+// attachmentLines lists an interaction's attachments, optionally numbered
+// for opening with 1-9, cutting long names to width
+func attachmentLines(attachments []string, numbered bool, width int) []string {
+	var lines []string
+	for i, target := range attachments {
+		line := "↗ " + attachmentName(target)
+		if numbered && i < 9 {
+			line = fmt.Sprintf("[%d] %s", i+1, line)
+		}
+		if runes := []rune(line); width > 1 && len(runes) > width {
+			line = string(runes[:width-1]) + "…"
+		}
+		lines = append(lines, labelStyle.Render(line))
+	}
+	return lines
+}
@@ -0,0 +1,130 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pdxmph/contacts-tui/internal/avatar"
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// avatarDisplay returns how avatars are drawn, detecting the terminal's
+// support when the config leaves it to "auto"
+func avatarDisplay(cfg *config.Config) string {
+	display := config.Default().Avatars.Display
+	if cfg != nil && cfg.Avatars.Display != "" {
+		display = cfg.Avatars.Display
+	}
+	if display == avatar.DisplayAuto {
+		return avatar.DetectDisplay()
+	}
+	return display
+}
+
+// avatarHeight returns the rows an avatar takes in the detail pane
+func (m Model) avatarHeight() int {
+	if m.cfg == nil || m.cfg.Avatars.Height <= 0 {
+		return config.Default().Avatars.Height
+	}
+	return m.cfg.Avatars.Height
+}
+
+// avatarFile returns the image shown for a contact: the one set on the
+// contact, or else the one fetched for its email address
+func (m Model) avatarFile(contact db.Contact) string {
+	if contact.Avatar.Valid && contact.Avatar.String != "" {
+		return config.ExpandPath(contact.Avatar.String)
+	}
+	if m.cfg != nil && m.cfg.Avatars.Enabled {
+		return avatar.CachedPath(m.cfg.Avatars.CacheDir, contact.Email.String)
+	}
+	return ""
+}
+
+// loadAvatar draws the avatar of the contact in the detail pane. For kitty
+// the image itself is sent to the terminal separately, by the command
+// Update picks up from pendingGraphics.
+func (m *Model) loadAvatar(contact db.Contact) {
+	m.detailAvatar = m.avatarFile(contact)
+	m.detailAvatarLines = nil
+	if m.detailAvatar == "" || m.avatarMode == avatar.DisplayOff {
+		return
+	}
+
+	rows := m.avatarHeight()
+	if m.avatarMode == avatar.DisplayText {
+		m.detailAvatarLines = avatarBadge(contact.Name)
+		return
+	}
+
+	img, err := avatar.Load(m.detailAvatar)
+	if err != nil {
+		m.detailAvatarLines = []string{labelStyle.Render("Avatar: can't read " + filepath.Base(m.detailAvatar))}
+		return
+	}
+	if m.avatarMode == avatar.DisplayKitty {
+		seq, lines, err := avatar.Kitty(img, uint32(contact.ID), rows*2, rows)
+		if err != nil {
+			m.detailAvatarLines = []string{labelStyle.Render("Avatar: " + err.Error())}
+			return
+		}
+		m.pendingGraphics = seq
+		m.detailAvatarLines = lines
+		return
+	}
+	m.detailAvatarLines = avatar.Blocks(img, rows*2, rows)
+}
+
+// avatarBadge draws an avatar as the contact's initials, for terminals
+// that can't show images
+func avatarBadge(name string) []string {
+	initials := avatar.Initials(name)
+	if initials == "" {
+		initials = "?"
+	}
+	badge := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Accent).
+		Bold(true).
+		Padding(0, 1).
+		Render(initials)
+	return strings.Split(badge, "\n")
+}
+
+// sendGraphics writes image data straight to the terminal. It can't go
+// through View, since the renderer counts escape sequences it doesn't know
+// as text.
+func sendGraphics(seq string) tea.Cmd {
+	return func() tea.Msg {
+		os.Stdout.WriteString(seq)
+		return nil
+	}
+}
+
+// cleanAvatar checks the image file typed into the edit form, storing it
+// with ~ kept so the database can move between machines
+func cleanAvatar(input string) (string, error) {
+	path := strings.Trim(strings.TrimSpace(input), `"'`)
+	if path == "" {
+		return "", nil
+	}
+	if !strings.HasPrefix(path, "~") {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return "", err
+		}
+		path = abs
+	}
+	if _, err := avatar.Load(config.ExpandPath(path)); err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("no such avatar file: %s", path)
+		}
+		return "", fmt.Errorf("avatar must be a JPEG, PNG or GIF: %s", path)
+	}
+	return path, nil
+}
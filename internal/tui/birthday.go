@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// describeBirthday formats a contact's birthday with how far away it is,
+// e.g. "Apr 12, 1980 (in 5 days, turning 46)"
+func describeBirthday(c db.Contact, now time.Time) string {
+	text := db.FormatBirthday(c.Birthday.String)
+	next, ok := c.NextBirthday(now)
+	if !ok {
+		return text
+	}
+	when := formatDateWhen(next)
+	if age, ok := c.TurningAge(now); ok {
+		when += fmt.Sprintf(", turning %d", age)
+	}
+	return fmt.Sprintf("%s (%s)", text, when)
+}
+
+// sortByBirthday orders contacts by their next birthday, soonest first,
+// keeping the existing order for the same day and putting contacts with no
+// birthday last
+func sortByBirthday(contacts []db.Contact) {
+	now := time.Now()
+	days := make(map[int]int, len(contacts))
+	for _, c := range contacts {
+		days[c.ID] = c.DaysUntilBirthday(now)
+		if days[c.ID] < 0 {
+			days[c.ID] = 367
+		}
+	}
+	sort.SliceStable(contacts, func(i, j int) bool {
+		return days[contacts[i].ID] < days[contacts[j].ID]
+	})
+}
@@ -0,0 +1,337 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/scripting"
+)
+
+// Bulk actions on marked contacts
+const (
+	bulkState   = "state"
+	bulkArchive = "archive"
+	bulkTag     = "tag"
+	bulkDelete  = "delete"
+)
+
+// toggleMark marks or unmarks the selected contact and moves to the next,
+// so space can be held down to mark a run of contacts
+func (m Model) toggleMark() Model {
+	contacts := m.filteredContacts()
+	if m.selected >= len(contacts) {
+		return m
+	}
+	id := contacts[m.selected].ID
+	if m.marked[id] {
+		delete(m.marked, id)
+	} else {
+		if m.marked == nil {
+			m.marked = make(map[int]bool)
+		}
+		m.marked[id] = true
+		m.markAnchor = id
+	}
+	if m.selected < len(contacts)-1 {
+		m.selected++
+	}
+	return m
+}
+
+// markRange marks every contact from the one last marked with space to
+// the selected one, as listed
+func (m Model) markRange() Model {
+	contacts := m.filteredContacts()
+	if m.selected >= len(contacts) {
+		return m
+	}
+	anchor := -1
+	for i, c := range contacts {
+		if c.ID == m.markAnchor && m.marked[c.ID] {
+			anchor = i
+		}
+	}
+	if anchor < 0 {
+		return m.toggleMark()
+	}
+	from, to := min(anchor, m.selected), max(anchor, m.selected)
+	for _, c := range contacts[from : to+1] {
+		m.marked[c.ID] = true
+	}
+	m.markAnchor = contacts[m.selected].ID
+	return m
+}
+
+// clearMarks unmarks every contact
+func (m Model) clearMarks() Model {
+	m.marked = nil
+	m.markAnchor = 0
+	return m
+}
+
+// markedContacts returns the marked contacts, as listed, including any the
+// filters have hidden since they were marked
+func (m Model) markedContacts() []db.Contact {
+	var marked []db.Contact
+	for _, c := range m.contacts {
+		if m.marked[c.ID] {
+			marked = append(marked, c)
+		}
+	}
+	return marked
+}
+
+// updateMarked handles the keys that act on all marked contacts instead of
+// the selected one. It reports false for keys it leaves to the list.
+func (m Model) updateMarked(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "esc":
+		m = m.clearMarks()
+		return m.setFlash(FlashInfo, "Marks cleared"), nil, true
+	case "s":
+		m.bulkMode = true
+		m.bulkAction = bulkState
+		m.bulkStateSelected = 0
+		return m, nil, true
+	case "#":
+		m.bulkMode = true
+		m.bulkAction = bulkTag
+		m.bulkTagInput.Reset()
+		m.bulkTagInput.Focus()
+		return m, textinput.Blink, true
+	case "a":
+		return m.confirmBulk(bulkArchive), nil, true
+	case "D":
+		if m.deleteAction() == "delete" {
+			return m.confirmBulk(bulkDelete), nil, true
+		}
+		return m.confirmBulk(bulkArchive), nil, true
+	case "X":
+		return m.confirmBulk(bulkDelete), nil, true
+	}
+	return m, nil, false
+}
+
+// confirmBulk asks once before applying an action to the marked contacts
+func (m Model) confirmBulk(action string) Model {
+	m.bulkMode = true
+	m.bulkAction = action
+	m.bulkConfirm = true
+	return m
+}
+
+// closeBulk leaves the bulk action overlay, keeping the marks
+func (m Model) closeBulk() Model {
+	m.bulkMode = false
+	m.bulkConfirm = false
+	m.bulkTagInput.Blur()
+	return m
+}
+
+// updateBulk handles keys in the bulk action overlay: choosing the state
+// or typing the tag, then the confirmation
+func (m Model) updateBulk(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.bulkConfirm {
+		switch msg.String() {
+		case "y", "Y", "enter":
+			return m.applyBulk(), nil
+		case "esc", "n", "N", "q":
+			return m.closeBulk(), nil
+		}
+		return m, nil
+	}
+
+	switch m.bulkAction {
+	case bulkState:
+		switch msg.String() {
+		case "esc":
+			return m.closeBulk(), nil
+		case "j", "down":
+			if m.bulkStateSelected < len(ContactStates)-1 {
+				m.bulkStateSelected++
+			}
+		case "k", "up":
+			if m.bulkStateSelected > 0 {
+				m.bulkStateSelected--
+			}
+		case "enter":
+			m.bulkConfirm = true
+		}
+		return m, nil
+
+	case bulkTag:
+		switch msg.String() {
+		case "esc":
+			return m.closeBulk(), nil
+		case "enter":
+			if tags := db.ParseTags(m.bulkTagInput.Value()); len(tags) != 1 {
+				m.err = fmt.Errorf("type one tag, like #conference2024")
+				return m, nil
+			}
+			m.bulkTagInput.Blur()
+			m.bulkConfirm = true
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.bulkTagInput, cmd = m.bulkTagInput.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// applyBulk applies the confirmed action to the marked contacts and clears
+// the marks
+func (m Model) applyBulk() Model {
+	marked := m.markedContacts()
+	action := m.bulkAction
+	m = m.closeBulk().clearMarks()
+
+	switch action {
+	case bulkState:
+		state := ContactStates[m.bulkStateSelected]
+		return m.setContactsState(marked, state, "marked contacts", fmt.Sprintf("state change of %d contacts", len(marked)))
+
+	case bulkTag:
+		tag := db.ParseTags(m.bulkTagInput.Value())[0]
+		if err := m.db.TagContacts(contactIDs(marked), tag); err != nil {
+			m.err = err
+			return m
+		}
+		m = m.reloadContacts()
+		return m.setFlash(FlashSuccess, fmt.Sprintf("✓ Tagged %d contacts #%s", len(marked), tag))
+
+	case bulkArchive:
+		var archiving []db.Contact
+		for _, c := range marked {
+			if !c.Archived {
+				archiving = append(archiving, c)
+			}
+		}
+		before := m.snapshots(archiving)
+		if err := m.db.ArchiveContacts(contactIDs(archiving), ""); err != nil {
+			m.err = err
+			return m
+		}
+		m = m.reloadContacts()
+		m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Archived %d contacts • u: undo", len(archiving)))
+		m = m.pushUndo(fmt.Sprintf("archive of %d contacts", len(archiving)), before...)
+		for _, c := range archiving {
+			m = m.runAutomations(scripting.EventArchived, c.ID)
+		}
+		return m
+
+	case bulkDelete:
+		before := m.snapshots(marked)
+		if err := m.db.DeleteContacts(contactIDs(marked)); err != nil {
+			m.err = err
+			return m
+		}
+		m = m.reloadContacts()
+		m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Moved %d contacts to the trash • u: undo • Z: open trash", len(marked)))
+		return m.pushUndo(fmt.Sprintf("delete of %d contacts", len(marked)), before...)
+	}
+	return m
+}
+
+// contactIDs returns the IDs of contacts
+func contactIDs(contacts []db.Contact) []int {
+	ids := make([]int, len(contacts))
+	for i, c := range contacts {
+		ids[i] = c.ID
+	}
+	return ids
+}
+
+// snapshots records contacts before an undoable bulk change
+func (m Model) snapshots(contacts []db.Contact) []*db.Snapshot {
+	before := make([]*db.Snapshot, len(contacts))
+	for i, c := range contacts {
+		before[i] = m.snapshot(c.ID)
+	}
+	return before
+}
+
+// bulkQuestion is the confirmation question for the pending bulk action
+func (m Model) bulkQuestion(count int) string {
+	switch m.bulkAction {
+	case bulkState:
+		return fmt.Sprintf("Set %d marked contacts to %s?", count, ContactStates[m.bulkStateSelected])
+	case bulkTag:
+		tags := db.ParseTags(m.bulkTagInput.Value())
+		return fmt.Sprintf("Tag %d marked contacts #%s?", count, strings.Join(tags, " #"))
+	case bulkArchive:
+		return fmt.Sprintf("Archive %d marked contacts?", count)
+	default:
+		return fmt.Sprintf("Move %d marked contacts to the trash?", count)
+	}
+}
+
+// renderBulk renders the bulk action overlay
+func (m Model) renderBulk() string {
+	marked := m.markedContacts()
+	var lines []string
+	switch {
+	case m.bulkConfirm:
+		lines = append(lines, m.bulkQuestion(len(marked)))
+		lines = append(lines, "")
+		for i, c := range marked {
+			if i == 10 {
+				lines = append(lines, fmt.Sprintf("  ...and %d more", len(marked)-i))
+				break
+			}
+			lines = append(lines, "  "+c.Name)
+		}
+		lines = append(lines, "")
+		switch m.bulkAction {
+		case bulkState:
+			lines = append(lines, labelStyle.Render("Contacts that can't move to this state are skipped"))
+		case bulkArchive, bulkDelete:
+			lines = append(lines, labelStyle.Render("u undoes it afterwards"))
+		}
+		lines = append(lines, "Press 'y' to confirm, Esc to cancel.")
+		lines = append(lines, "")
+		lines = append(lines, confirmButtons())
+
+	case m.bulkAction == bulkState:
+		lines = append(lines, fmt.Sprintf("Set state for %d marked contacts", len(marked)))
+		lines = append(lines, "")
+		for i, state := range ContactStates {
+			if i == m.bulkStateSelected {
+				lines = append(lines, selectedStyle.Render("▶ "+state))
+			} else {
+				lines = append(lines, "  "+state)
+			}
+		}
+		lines = append(lines, "")
+		lines = append(lines, "j/k: navigate • Enter: choose • Esc: cancel")
+
+	case m.bulkAction == bulkTag:
+		lines = append(lines, fmt.Sprintf("Tag %d marked contacts", len(marked)))
+		lines = append(lines, "")
+		lines = append(lines, m.bulkTagInput.View())
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render("Their other tags are kept"))
+		lines = append(lines, "Enter: choose • Esc: cancel")
+	}
+
+	borderColor := theme.Overlay
+	if m.bulkConfirm && m.bulkAction == bulkDelete {
+		borderColor = theme.Danger
+	}
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Padding(1, 2).
+		Width(60).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(box)
+}
@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/messaging"
+)
+
+// callDoneMsg reports that the call command exited
+type callDoneMsg struct {
+	contactID int
+	name      string
+	err       error
+}
+
+// callCommand returns the command that starts calls, opening a tel: URL
+// with open_command unless call_command is set
+func (m Model) callCommand() string {
+	if m.cfg != nil && m.cfg.Messaging.CallCommand != "" {
+		return m.cfg.Messaging.CallCommand
+	}
+	return m.openCommand()
+}
+
+// startCall calls the contact's phone number with the call command
+func (m Model) startCall(contact db.Contact) (Model, tea.Cmd) {
+	if !contact.Phone.Valid || messaging.NormalizePhone(contact.Phone.String) == "" {
+		m.err = fmt.Errorf("%s has no phone number", contact.Name)
+		return m, nil
+	}
+	cmd, err := messaging.CallCommand(m.callCommand(), contact.Phone.String)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return callDoneMsg{contactID: contact.ID, name: contact.Name, err: err}
+	})
+}
+
+// handleCallDone reports the outcome of the call command, logging a call
+// interaction when [messaging] log_calls is set
+func (m Model) handleCallDone(msg callDoneMsg) Model {
+	if msg.err != nil {
+		m.err = fmt.Errorf("call command failed: %w", msg.err)
+		return m
+	}
+	if m.cfg == nil || !m.cfg.Messaging.LogCalls {
+		return m.setFlash(FlashSuccess, "✓ Calling "+msg.name)
+	}
+	return m.logInteraction(msg.contactID, msg.name, "call", "✓ Called "+msg.name)
+}
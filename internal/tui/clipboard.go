@@ -0,0 +1,37 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/atotto/clipboard"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/report"
+)
+
+// copyInteractions returns how many recent interactions are copied with a contact
+func (m Model) copyInteractions() int {
+	if m.cfg == nil || m.cfg.UI.CopyInteractions <= 0 {
+		return 5
+	}
+	return m.cfg.UI.CopyInteractions
+}
+
+// copyContact copies a contact and its recent interactions to the clipboard
+// as Markdown
+func (m Model) copyContact(contact db.Contact) Model {
+	logs, err := m.db.GetContactInteractions(contact.ID, m.copyInteractions())
+	if err != nil {
+		m.err = err
+		return m
+	}
+	dates, err := m.db.ListImportantDates(contact.ID)
+	if err != nil {
+		m.err = err
+		return m
+	}
+	if err := clipboard.WriteAll(report.ContactMarkdown(contact, logs, dates)); err != nil {
+		m.err = fmt.Errorf("copying to clipboard: %w", err)
+		return m
+	}
+	return m.setFlash(FlashSuccess, fmt.Sprintf("✓ Copied %s as Markdown", contact.Name))
+}
@@ -0,0 +1,276 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/scripting"
+)
+
+// confirmations returns which actions ask for confirmation first
+func (m Model) confirmations() config.ConfirmConfig {
+	if m.cfg == nil {
+		return config.Default().Confirm
+	}
+	return m.cfg.Confirm
+}
+
+// deleteAction returns what the D key does: "archive" or "delete"
+func (m Model) deleteAction() string {
+	if m.cfg == nil || m.cfg.UI.DeleteAction == "" {
+		return "archive"
+	}
+	return m.cfg.UI.DeleteAction
+}
+
+// reloadContacts reloads the contact list after a change
+func (m Model) reloadContacts() Model {
+	m.invalidateDetailCache()
+	if newContacts, err := m.db.ListContacts(); err == nil {
+		m.setContacts(newContacts)
+		m.selected = m.ensureValidSelection()
+	}
+	return m
+}
+
+// bumpContact bumps a contact and reloads the list
+func (m Model) bumpContact(contactID int) Model {
+	before := m.snapshot(contactID)
+	if err := m.db.BumpContact(contactID); err != nil {
+		m.err = err
+		return m
+	}
+	m = m.reloadContacts()
+	if contact, err := m.db.GetContact(contactID); err == nil && contact != nil {
+		m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Bumped %s", contact.Name))
+		m = m.pushUndo("bump of "+contact.Name, before)
+	}
+	return m.runAutomations(scripting.EventBumped, contactID)
+}
+
+// deleteContact moves a contact to the trash and reloads the list
+func (m Model) deleteContact(contactID int) Model {
+	contact, _ := m.db.GetContact(contactID)
+	before := m.snapshot(contactID)
+	if err := m.db.DeleteContact(contactID); err != nil {
+		m.err = err
+		return m
+	}
+	m = m.reloadContacts()
+	if contact != nil {
+		m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Moved %s to the trash • u: undo • Z: open trash", contact.Name))
+		m = m.pushUndo("delete of "+contact.Name, before)
+	}
+	return m
+}
+
+// startArchive archives or unarchives a contact, first asking for a reason
+// or a confirmation when configured
+func (m Model) startArchive(contact db.Contact) (Model, tea.Cmd) {
+	if !contact.Archived && m.confirmations().ArchiveReason {
+		m.archiveConfirmMode = true
+		m.archiveContactID = contact.ID
+		m.archiveAskReason = true
+		m.archiveReasonInput.Reset()
+		m.archiveReasonInput.Focus()
+		return m, textinput.Blink
+	}
+	if m.confirmations().Archive {
+		m.archiveConfirmMode = true
+		m.archiveContactID = contact.ID
+		return m, nil
+	}
+	return m.toggleArchive(contact, ""), nil
+}
+
+// closeArchiveConfirm leaves the archive confirmation or reason prompt
+func (m Model) closeArchiveConfirm() Model {
+	m.archiveConfirmMode = false
+	m.archiveContactID = 0
+	m.archiveAskReason = false
+	m.archiveReasonInput.Blur()
+	return m
+}
+
+// archiveContact returns the contact awaiting archive confirmation
+func (m Model) archiveContact() (db.Contact, bool) {
+	for _, c := range m.filteredContacts() {
+		if c.ID == m.archiveContactID {
+			return c, true
+		}
+	}
+	return db.Contact{}, false
+}
+
+// toggleArchive archives a contact with an optional reason, or unarchives
+// it, and reloads the list
+func (m Model) toggleArchive(contact db.Contact, reason string) Model {
+	var err error
+	var flashMsg, action string
+	before := m.snapshot(contact.ID)
+	if contact.Archived {
+		err = m.db.UnarchiveContact(contact.ID)
+		flashMsg = fmt.Sprintf("✓ Unarchived %s", contact.Name)
+		action = "unarchive of " + contact.Name
+	} else {
+		err = m.db.ArchiveContact(contact.ID, reason)
+		flashMsg = fmt.Sprintf("✓ Archived %s", contact.Name)
+		action = "archive of " + contact.Name
+	}
+	if err != nil {
+		m.err = err
+		return m
+	}
+	m = m.reloadContacts()
+	m = m.setFlash(FlashSuccess, flashMsg)
+	m = m.pushUndo(action, before)
+	return m.runAutomations(scripting.EventArchived, contact.ID)
+}
+
+// toggleReminders mutes or unmutes reminders for a contact and reloads the list
+func (m Model) toggleReminders(contact db.Contact) Model {
+	if err := m.db.SetRemindersMuted(contact.ID, !contact.RemindersMuted); err != nil {
+		m.err = err
+		return m
+	}
+	m = m.reloadContacts()
+	if contact.RemindersMuted {
+		return m.setFlash(FlashSuccess, fmt.Sprintf("✓ Reminders on for %s", contact.Name))
+	}
+	return m.setFlash(FlashSuccess, fmt.Sprintf("✓ Reminders muted for %s", contact.Name))
+}
+
+// renderArchiveConfirmation renders the archive confirmation or reason prompt
+func (m Model) renderArchiveConfirmation() string {
+	action := "Archive"
+	contact, _ := m.archiveContact()
+	contactName := contact.Name
+	if contact.Archived {
+		action = "Unarchive"
+	}
+
+	if m.archiveAskReason {
+		var lines []string
+		lines = append(lines, fmt.Sprintf("Archive %s", contactName))
+		lines = append(lines, "")
+		lines = append(lines, "Reason (optional):")
+		lines = append(lines, m.archiveReasonInput.View())
+		lines = append(lines, "")
+		lines = append(lines, "Enter: archive • Esc: cancel")
+
+		box := borderStyle.
+			Padding(1).
+			Width(60).
+			Render(strings.Join(lines, "\n"))
+
+		return lipgloss.NewStyle().
+			Width(m.width).
+			Height(m.height).
+			Align(lipgloss.Center, lipgloss.Center).
+			Render(box)
+	}
+
+	width := 60
+	height := 9
+
+	prompt := fmt.Sprintf("%s contact '%s'? (y/n)\n\n%s", action, contactName, confirmButtons())
+
+	content := lipgloss.NewStyle().
+		Width(width-4).
+		Height(height-4).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(prompt)
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Overlay).
+		Width(width).
+		Height(height).
+		Render(content)
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(box)
+}
+
+// openPurgeConfirm asks before purging archived contacts past the retention period
+func (m Model) openPurgeConfirm() Model {
+	days := 0
+	if m.cfg != nil {
+		days = m.cfg.Retention.ArchivedDays
+	}
+	if days <= 0 {
+		return m.setFlash(FlashInfo, "No retention period set • set archived_days under [retention] in config.toml")
+	}
+
+	expired, err := m.db.ListArchivedBefore(time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		m.err = err
+		return m
+	}
+	if len(expired) == 0 {
+		return m.setFlash(FlashInfo, fmt.Sprintf("No contacts archived more than %d days ago", days))
+	}
+
+	m.purgeConfirmMode = true
+	m.purgeCandidates = expired
+	return m
+}
+
+// purgeArchived permanently deletes the contacts awaiting purge confirmation
+func (m Model) purgeArchived() Model {
+	purged, err := m.db.PurgeArchived(m.purgeCandidates)
+	m = m.reloadContacts()
+	if err != nil {
+		m.err = err
+		return m
+	}
+	return m.setFlash(FlashSuccess, fmt.Sprintf("✓ Purged %d archived contacts", purged))
+}
+
+// renderPurgeConfirmation renders the purge confirmation prompt
+func (m Model) renderPurgeConfirmation() string {
+	var lines []string
+	lines = append(lines, fmt.Sprintf("Permanently delete %d archived contacts?", len(m.purgeCandidates)))
+	lines = append(lines, "")
+	for i, c := range m.purgeCandidates {
+		if i == 10 {
+			lines = append(lines, fmt.Sprintf("  ...and %d more", len(m.purgeCandidates)-i))
+			break
+		}
+		line := fmt.Sprintf("  %s (archived %s)", c.Name, c.ArchivedAt.Time.Format("2006-01-02"))
+		if c.ArchiveReason.Valid {
+			line += ": " + c.ArchiveReason.String
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "")
+	lines = append(lines, "Their interaction history is deleted too.")
+	lines = append(lines, "This action cannot be undone!")
+	lines = append(lines, "")
+	lines = append(lines, "Press 'y' to confirm, any other key to cancel.")
+	lines = append(lines, "")
+	lines = append(lines, confirmButtons())
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Danger). // Red border for danger
+		Foreground(theme.Danger).
+		Padding(1, 2).
+		Width(60).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(box)
+}
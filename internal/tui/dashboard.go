@@ -0,0 +1,263 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// Rows in each dashboard section that list single contacts
+const (
+	dashboardFollowUps    = 8
+	dashboardInteractions = 6
+)
+
+// dashboardItem is a row of the dashboard Enter acts on: a filtered list
+// to show, or a contact to go to
+type dashboardItem struct {
+	section string
+	label   string
+	filters *filterState // List to show
+	state   string       // Select the first contact in this state there
+	contact *db.Contact  // Contact to go to
+}
+
+// countMatching counts the contacts a set of filters lists
+func (m Model) countMatching(f filterState) int {
+	filtered := m
+	filtered.restoreFilters(f)
+	count := 0
+	for i := range m.contacts {
+		if filtered.matchesFilters(&m.contacts[i]) {
+			count++
+		}
+	}
+	return count
+}
+
+// dashboardItems summarizes the contacts: overdue ones by relationship
+// type, ones in states other than ok, follow-ups coming up and the latest
+// interactions
+func (m Model) dashboardItems() ([]dashboardItem, error) {
+	var items []dashboardItem
+
+	const overdue = "Overdue"
+	if count := m.countMatching(filterState{overdueFilter: true}); count > 0 {
+		items = append(items, dashboardItem{
+			section: overdue,
+			label:   fmt.Sprintf("%-14s %d", "All", count),
+			filters: &filterState{overdueFilter: true},
+		})
+		for _, rType := range RelationshipTypes {
+			f := filterState{overdueFilter: true, typeFilter: rType}
+			if count := m.countMatching(f); count > 0 {
+				items = append(items, dashboardItem{
+					section: overdue,
+					label:   fmt.Sprintf("%-14s %d", rType, count),
+					filters: &f,
+				})
+			}
+		}
+	}
+
+	const states = "States"
+	stateCounts := make(map[string]int)
+	for _, c := range m.contacts {
+		if !c.Archived && c.State.Valid && c.State.String != "ok" {
+			stateCounts[c.State.String]++
+		}
+	}
+	for _, state := range ContactStates {
+		if stateCounts[state] > 0 {
+			items = append(items, dashboardItem{
+				section: states,
+				label:   fmt.Sprintf("%-14s %d", state, stateCounts[state]),
+				filters: &filterState{stateFilter: true},
+				state:   state,
+			})
+		}
+	}
+
+	const followUps = "Follow-ups"
+	now := time.Now()
+	horizon := startOfDay(now).AddDate(0, 0, m.remindDays()+1)
+	var due []db.Contact
+	for _, c := range m.contacts {
+		if next, ok := nextDueDate(c); ok && !c.Archived && next.Before(horizon) {
+			due = append(due, c)
+		}
+	}
+	sortByDueDate(due)
+	for i := range due {
+		if i == dashboardFollowUps {
+			break
+		}
+		next, _ := nextDueDate(due[i])
+		when := formatDateWhen(next)
+		if isDue(next, now) {
+			when = formatDue(next)
+		}
+		items = append(items, dashboardItem{
+			section: followUps,
+			label:   fmt.Sprintf("%-16s %s", when, due[i].Name),
+			contact: &due[i],
+		})
+	}
+
+	const recent = "Recent interactions"
+	logs, err := m.db.RecentInteractions(dashboardInteractions)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]db.Contact, len(m.contacts))
+	for _, c := range m.contacts {
+		byID[c.ID] = c
+	}
+	for _, log := range logs {
+		c, ok := byID[log.ContactID]
+		if !ok {
+			continue
+		}
+		items = append(items, dashboardItem{
+			section: recent,
+			label:   fmt.Sprintf("%s %-10s %s", log.InteractionDate.Format("2006-01-02"), "["+log.InteractionType+"]", c.Name),
+			contact: &c,
+		})
+	}
+	return items, nil
+}
+
+// openDashboard shows the dashboard
+func (m Model) openDashboard() Model {
+	items, err := m.dashboardItems()
+	if err != nil {
+		m.err = err
+		return m
+	}
+	m.dashboardMode = true
+	m.dashboard = items
+	m.dashboardSelected = 0
+	return m
+}
+
+// closeDashboard leaves the dashboard
+func (m Model) closeDashboard() Model {
+	m.dashboardMode = false
+	m.dashboard = nil
+	return m
+}
+
+// updateDashboard handles keys for the dashboard
+func (m Model) updateDashboard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "w":
+		return m.closeDashboard(), nil
+	case "j", "down":
+		if m.dashboardSelected < len(m.dashboard)-1 {
+			m.dashboardSelected++
+		}
+	case "k", "up":
+		if m.dashboardSelected > 0 {
+			m.dashboardSelected--
+		}
+	case "tab":
+		// Next section
+		for i := m.dashboardSelected + 1; i < len(m.dashboard); i++ {
+			if m.dashboard[i].section != m.dashboard[m.dashboardSelected].section {
+				m.dashboardSelected = i
+				break
+			}
+		}
+	case "enter":
+		if m.dashboardSelected < len(m.dashboard) {
+			return m.openDashboardItem(m.dashboard[m.dashboardSelected]), nil
+		}
+	}
+	return m, nil
+}
+
+// openDashboardItem closes the dashboard and goes to a row's contact, or
+// replaces the list's filters with the row's
+func (m Model) openDashboardItem(item dashboardItem) Model {
+	m = m.closeDashboard()
+	if item.contact != nil {
+		return m.jumpTo(*item.contact)
+	}
+
+	m.stashedFilters = nil
+	m.restoreFilters(*item.filters)
+	m.selected = 0
+	if item.state != "" {
+		for i, c := range m.filteredContacts() {
+			if c.State.String == item.state {
+				m.selected = i
+				break
+			}
+		}
+	}
+	m.selected = m.ensureValidSelection()
+	return m
+}
+
+// renderDashboard renders the dashboard overlay
+func (m Model) renderDashboard() string {
+	sections := []struct{ name, empty string }{
+		{"Overdue", "Nobody is overdue"},
+		{"States", "Every contact is ok"},
+		{"Follow-ups", fmt.Sprintf("No follow-ups or deadlines in the next %d days", m.remindDays())},
+		{"Recent interactions", "No interactions logged yet"},
+	}
+
+	var body []string
+	selectedLine := 0
+	for _, section := range sections {
+		if len(body) > 0 {
+			body = append(body, "")
+		}
+		body = append(body, section.name)
+		found := false
+		for i, item := range m.dashboard {
+			if item.section != section.name {
+				continue
+			}
+			found = true
+			if i == m.dashboardSelected {
+				selectedLine = len(body)
+				body = append(body, selectedStyle.Render("▶ "+item.label))
+			} else {
+				body = append(body, "  "+item.label)
+			}
+		}
+		if !found {
+			body = append(body, labelStyle.Render("  "+section.empty))
+		}
+	}
+
+	// Scroll to keep the selected row in view
+	visible := max(m.height-10, 5)
+	start := 0
+	if selectedLine >= visible {
+		start = selectedLine - visible + 1
+	}
+	end := min(start+visible, len(body))
+
+	lines := []string{"Dashboard", ""}
+	lines = append(lines, body[start:end]...)
+	lines = append(lines, "")
+	lines = append(lines, "j/k: select • Tab: next section • Enter: show • Esc: close")
+
+	box := borderStyle.
+		Padding(1).
+		Width(70).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(box)
+}
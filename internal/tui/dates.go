@@ -0,0 +1,398 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// Fields of the important date form
+const (
+	dateFieldLabel = iota
+	dateFieldDate
+	dateFieldRecurring
+	dateFieldCount
+)
+
+// remindDays returns how many days ahead important dates are surfaced
+func (m Model) remindDays() int {
+	if m.cfg == nil || m.cfg.Dates.RemindDays <= 0 {
+		return 14
+	}
+	return m.cfg.Dates.RemindDays
+}
+
+// formatDateWhen describes how far away a date is
+func formatDateWhen(next time.Time) string {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	days := int(next.Sub(today).Hours() / 24)
+	switch {
+	case days < 0:
+		return "passed"
+	case days == 0:
+		return "today"
+	case days == 1:
+		return "tomorrow"
+	default:
+		return fmt.Sprintf("in %d days", days)
+	}
+}
+
+// describeDate formats an important date with its next occurrence
+func describeDate(d db.ImportantDate) string {
+	next, ok := d.NextOccurrence(time.Now())
+	if !ok {
+		return fmt.Sprintf("%s: %s (passed)", d.Label, d.Date.Format("Jan 2, 2006"))
+	}
+	return dateSummary(d, next) + " " + formatDateWhen(next)
+}
+
+// dateSummary formats an important date's label and next occurrence
+func dateSummary(d db.ImportantDate, next time.Time) string {
+	text := fmt.Sprintf("%s: %s", d.Label, next.Format("Jan 2"))
+	if !d.Recurring {
+		return text + next.Format(", 2006")
+	}
+	if years := next.Year() - d.Date.Year(); years > 0 {
+		text += fmt.Sprintf(" (%d years)", years)
+	}
+	return text
+}
+
+// openDates shows the important dates of a contact
+func (m Model) openDates(contact db.Contact) Model {
+	m.datesMode = true
+	m.datesContactID = contact.ID
+	m.datesSelected = 0
+	return m.loadDates()
+}
+
+// loadDates reloads the important dates being edited
+func (m Model) loadDates() Model {
+	dates, err := m.db.ListImportantDates(m.datesContactID)
+	if err != nil {
+		m.err = err
+		m.datesMode = false
+		return m
+	}
+	m.dates = dates
+	if m.datesSelected >= len(dates) {
+		m.datesSelected = len(dates) - 1
+	}
+	if m.datesSelected < 0 {
+		m.datesSelected = 0
+	}
+	m.invalidateDetailCache()
+	m.refreshDatesDue()
+	return m
+}
+
+// refreshDatesDue records which contacts have an important date or birthday
+// within the reminder window. These count as due in the overdue filter, so
+// the filtered list is rebuilt.
+func (m *Model) refreshDatesDue() {
+	m.contactsVersion++
+	m.datesDue = make(map[int]bool)
+	upcoming, err := m.db.UpcomingDates(m.remindDays())
+	if err != nil {
+		return
+	}
+	for _, u := range upcoming {
+		m.datesDue[u.ContactID] = true
+	}
+}
+
+// hasDateDue reports whether a contact has an important date or birthday
+// coming up. Muted contacts never come due.
+func (m *Model) hasDateDue(c db.Contact) bool {
+	return m.datesDue[c.ID] && !c.RemindersMuted
+}
+
+// openDateForm starts adding a new date, or editing an existing one
+func (m Model) openDateForm(existing *db.ImportantDate) (Model, tea.Cmd) {
+	m.dateFormMode = true
+	m.dateFormField = dateFieldLabel
+	m.dateFormEditID = 0
+	m.dateFormRecurring = true
+	m.dateLabelInput.Reset()
+	m.dateDateInput.Reset()
+	if existing != nil {
+		m.dateFormEditID = existing.ID
+		m.dateFormRecurring = existing.Recurring
+		m.dateLabelInput.SetValue(existing.Label)
+		m.dateDateInput.SetValue(existing.Date.Format("2006-01-02"))
+	}
+	m.dateDateInput.Blur()
+	m.dateLabelInput.Focus()
+	return m, textinput.Blink
+}
+
+// focusDateField moves the form focus to a field
+func (m Model) focusDateField(field int) Model {
+	m.dateFormField = field
+	m.dateLabelInput.Blur()
+	m.dateDateInput.Blur()
+	switch field {
+	case dateFieldLabel:
+		m.dateLabelInput.Focus()
+	case dateFieldDate:
+		m.dateDateInput.Focus()
+	}
+	return m
+}
+
+// saveDateForm validates and stores the date being edited
+func (m Model) saveDateForm() Model {
+	label := strings.TrimSpace(m.dateLabelInput.Value())
+	if label == "" {
+		m.err = fmt.Errorf("important date needs a label")
+		return m
+	}
+	date, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(m.dateDateInput.Value()), time.Local)
+	if err != nil {
+		m.err = fmt.Errorf("invalid date %q (use YYYY-MM-DD)", m.dateDateInput.Value())
+		return m
+	}
+
+	if m.dateFormEditID != 0 {
+		err = m.db.UpdateImportantDate(m.dateFormEditID, label, date, m.dateFormRecurring)
+	} else {
+		err = m.db.AddImportantDate(m.datesContactID, label, date, m.dateFormRecurring)
+	}
+	if err != nil {
+		m.err = err
+		return m
+	}
+
+	m.dateFormMode = false
+	m.dateLabelInput.Blur()
+	m.dateDateInput.Blur()
+	m = m.loadDates()
+	return m.setFlash(FlashSuccess, fmt.Sprintf("✓ Saved %s", label))
+}
+
+// updateDates handles keys for the important dates list and form
+func (m Model) updateDates(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.dateFormMode {
+		switch msg.String() {
+		case "esc":
+			m.dateFormMode = false
+			m.dateLabelInput.Blur()
+			m.dateDateInput.Blur()
+			return m, nil
+		case "enter":
+			return m.saveDateForm(), nil
+		case "tab", "down":
+			return m.focusDateField((m.dateFormField + 1) % dateFieldCount), nil
+		case "shift+tab", "up":
+			return m.focusDateField((m.dateFormField + dateFieldCount - 1) % dateFieldCount), nil
+		}
+
+		var cmd tea.Cmd
+		switch m.dateFormField {
+		case dateFieldLabel:
+			m.dateLabelInput, cmd = m.dateLabelInput.Update(msg)
+		case dateFieldDate:
+			m.dateDateInput, cmd = m.dateDateInput.Update(msg)
+		case dateFieldRecurring:
+			switch msg.String() {
+			case " ":
+				m.dateFormRecurring = !m.dateFormRecurring
+			case "y":
+				m.dateFormRecurring = true
+			case "n":
+				m.dateFormRecurring = false
+			}
+		}
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.datesMode = false
+		m.dates = nil
+	case "j", "down":
+		if m.datesSelected < len(m.dates)-1 {
+			m.datesSelected++
+		}
+	case "k", "up":
+		if m.datesSelected > 0 {
+			m.datesSelected--
+		}
+	case "a", "+":
+		return m.openDateForm(nil)
+	case "e", "enter":
+		if m.datesSelected < len(m.dates) {
+			d := m.dates[m.datesSelected]
+			return m.openDateForm(&d)
+		}
+	case "x", "delete":
+		if m.datesSelected < len(m.dates) {
+			d := m.dates[m.datesSelected]
+			if err := m.db.DeleteImportantDate(d.ID); err != nil {
+				m.err = err
+				return m, nil
+			}
+			m = m.loadDates()
+			m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Deleted %s", d.Label))
+		}
+	}
+	return m, nil
+}
+
+// renderDates renders the important dates list or form overlay
+func (m Model) renderDates() string {
+	var name string
+	if contact, err := m.db.GetContact(m.datesContactID); err == nil {
+		name = contact.Name
+	}
+
+	var lines []string
+	if m.dateFormMode {
+		title := "Add important date"
+		if m.dateFormEditID != 0 {
+			title = "Edit important date"
+		}
+		lines = append(lines, fmt.Sprintf("%s for %s", title, name))
+		lines = append(lines, "")
+
+		fieldLabel := func(field int, text string) string {
+			if m.dateFormField == field {
+				return selectedStyle.Render(text)
+			}
+			return labelStyle.Render(text)
+		}
+		lines = append(lines, fieldLabel(dateFieldLabel, "Label"))
+		lines = append(lines, m.dateLabelInput.View())
+		lines = append(lines, fieldLabel(dateFieldDate, "Date (YYYY-MM-DD)"))
+		lines = append(lines, m.dateDateInput.View())
+		repeat := "[ ] Once"
+		if m.dateFormRecurring {
+			repeat = "[x] Every year"
+		}
+		lines = append(lines, fieldLabel(dateFieldRecurring, "Repeats"))
+		lines = append(lines, "  "+repeat)
+		lines = append(lines, "")
+		lines = append(lines, "Tab: next field • Space: toggle repeat • Enter: save • Esc: cancel")
+	} else {
+		lines = append(lines, fmt.Sprintf("Important dates for %s", name))
+		lines = append(lines, "")
+		if len(m.dates) == 0 {
+			lines = append(lines, labelStyle.Render("No important dates yet"))
+		}
+		for i, d := range m.dates {
+			if i == m.datesSelected {
+				lines = append(lines, selectedStyle.Render("▶ "+describeDate(d)))
+			} else {
+				lines = append(lines, "  "+describeDate(d))
+			}
+		}
+		lines = append(lines, "")
+		lines = append(lines, "a: add • e: edit • x: delete • Esc: close")
+	}
+
+	box := borderStyle.
+		Padding(1).
+		Width(70).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(box)
+}
+
+// openAgenda shows important dates coming up across all contacts
+func (m Model) openAgenda() Model {
+	upcoming, err := m.db.UpcomingDates(m.remindDays())
+	if err != nil {
+		m.err = err
+		return m
+	}
+	m.agendaMode = true
+	m.agenda = upcoming
+	m.agendaSelected = 0
+	return m
+}
+
+// updateAgenda handles keys for the agenda overlay
+func (m Model) updateAgenda(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "U":
+		m.agendaMode = false
+		m.agenda = nil
+	case "j", "down":
+		if m.agendaSelected < len(m.agenda)-1 {
+			m.agendaSelected++
+		}
+	case "k", "up":
+		if m.agendaSelected > 0 {
+			m.agendaSelected--
+		}
+	case "enter":
+		if m.agendaSelected < len(m.agenda) {
+			contact, err := m.db.GetContact(m.agenda[m.agendaSelected].ContactID)
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
+			m.agendaMode = false
+			m.agenda = nil
+			m = m.jumpTo(*contact)
+		}
+	}
+	return m, nil
+}
+
+// renderAgenda renders the upcoming important dates overlay
+func (m Model) renderAgenda() string {
+	var lines []string
+	lines = append(lines, fmt.Sprintf("Coming up in the next %d days", m.remindDays()))
+	lines = append(lines, "")
+	if len(m.agenda) == 0 {
+		lines = append(lines, labelStyle.Render("Nothing coming up"))
+	}
+	for i, u := range m.agenda {
+		line := fmt.Sprintf("%-10s %s • %s", formatDateWhen(u.Next), u.ContactName, dateSummary(u.ImportantDate, u.Next))
+		if i == m.agendaSelected {
+			lines = append(lines, selectedStyle.Render("▶ "+line))
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	lines = append(lines, "")
+	lines = append(lines, "Enter: go to contact • Esc: close")
+
+	box := borderStyle.
+		Padding(1).
+		Width(76).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(box)
+}
+
+// upcomingDatesReminder returns a status bar reminder of dates coming up
+// soon, or "" when there are none
+func (m Model) upcomingDatesReminder() string {
+	upcoming, err := m.db.UpcomingDates(m.remindDays())
+	if err != nil || len(upcoming) == 0 {
+		return ""
+	}
+	first := upcoming[0]
+	reminder := fmt.Sprintf("%s's %s %s", first.ContactName, first.Label, formatDateWhen(first.Next))
+	if len(upcoming) > 1 {
+		reminder += fmt.Sprintf(" (+%d more)", len(upcoming)-1)
+	}
+	return reminder + " • U: agenda"
+}
@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/muesli/reflow/truncate"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// Tabs of the detail pane, switched with [ and ]
+const (
+	detailTabInfo = iota
+	detailTabInteractions
+	detailTabTasks
+	detailTabHistory
+)
+
+// detailTabNames are the tabs' titles, in order
+var detailTabNames = []string{"Info", "Interactions", "Tasks", "History"}
+
+// switchDetailTab moves delta tabs along, wrapping around
+func (m Model) switchDetailTab(delta int) Model {
+	m.detailTab = (m.detailTab + delta + len(detailTabNames)) % len(detailTabNames)
+	m.detailScroll = 0
+	return m
+}
+
+// refreshDetailTab loads what the Tasks and History tabs show for the
+// selected contact, once per contact and only while the tab is open, since
+// listing tasks runs the task backend
+func (m *Model) refreshDetailTab() {
+	if m.detailTab != detailTabTasks && m.detailTab != detailTabHistory {
+		return
+	}
+	contacts := m.filteredContacts()
+	if len(contacts) == 0 || m.selected >= len(contacts) {
+		return
+	}
+	c := contacts[m.selected]
+	if m.detailTabContactID == c.ID && m.detailTabLoaded == m.detailTab {
+		return
+	}
+	m.detailTabContactID = c.ID
+	m.detailTabLoaded = m.detailTab
+	m.detailTabErr = ""
+
+	switch m.detailTab {
+	case detailTabTasks:
+		m.detailTasks = nil
+		switch {
+		case !m.taskManager.IsEnabled():
+			m.detailTabErr = "No task backend available"
+		case !c.Label.Valid || c.Label.String == "":
+			m.detailTabErr = "Give the contact a label to track its tasks"
+		default:
+			tasks, err := m.taskManager.Backend().GetContactTasks(c.Label.String)
+			if err != nil {
+				m.detailTabErr = fmt.Sprintf("Loading tasks: %v", err)
+			}
+			m.detailTasks = tasks
+		}
+	case detailTabHistory:
+		history, err := m.db.ContactHistory(c.ID)
+		if err != nil {
+			m.detailTabErr = err.Error()
+		}
+		m.detailHistory = history
+	}
+}
+
+// renderDetailTabs renders the tab bar, the open tab highlighted
+func (m Model) renderDetailTabs() string {
+	var tabs []string
+	for i, name := range detailTabNames {
+		if i == m.detailTab {
+			tabs = append(tabs, selectedStyle.Render("["+name+"]"))
+		} else {
+			tabs = append(tabs, labelStyle.Render(" "+name+" "))
+		}
+	}
+	return strings.Join(tabs, " ") + labelStyle.Render("  [/]")
+}
+
+// interactionLines lists interactions with their notes and attachments,
+// newest first
+func interactionLines(logs []db.Log, md *markdown, width int) []string {
+	var lines []string
+	for _, log := range logs {
+		dateStr := log.InteractionDate.Format("2006-01-02 15:04")
+		typeStr := fmt.Sprintf("[%s]", log.InteractionType)
+		if log.Rating.Valid {
+			typeStr += " " + formatRating(int(log.Rating.Int64))
+		}
+		if log.DurationMinutes.Valid {
+			typeStr += " " + formatDuration(int(log.DurationMinutes.Int64))
+		}
+		lines = append(lines, fmt.Sprintf("%s %s", dateStr, typeStr))
+		if log.Notes.Valid && log.Notes.String != "" {
+			for _, noteLine := range md.render(log.Notes.String) {
+				lines = append(lines, "  "+noteLine)
+			}
+		}
+		for _, line := range attachmentLines(log.Attachments, false, width-4) {
+			lines = append(lines, "  "+line)
+		}
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// detailInteractionLines renders the Interactions tab
+func (m Model) detailInteractionLines(c db.Contact, width int) []string {
+	logs := m.detailLogs(c)
+	if len(logs) == 0 {
+		return []string{labelStyle.Render("No interactions yet; n adds a note")}
+	}
+	lines := []string{fmt.Sprintf("Interactions (%d)", len(logs)), ""}
+	md := markdown{width: width - 4}
+	return append(lines, interactionLines(logs, &md, width)...)
+}
+
+// detailTaskLines renders the Tasks tab
+func (m Model) detailTaskLines(c db.Contact, width int) []string {
+	if m.detailTabContactID != c.ID || m.detailTabLoaded != detailTabTasks {
+		return []string{labelStyle.Render("Loading tasks…")}
+	}
+	if m.detailTabErr != "" {
+		return []string{labelStyle.Render(m.detailTabErr)}
+	}
+	if len(m.detailTasks) == 0 {
+		return []string{labelStyle.Render("No tasks for this contact")}
+	}
+	lines := []string{fmt.Sprintf("Tasks (%d) • t: manage", len(m.detailTasks)), ""}
+	for _, task := range m.detailTasks {
+		line := "• " + task.Description
+		if task.Priority != "" {
+			line += " [" + task.Priority + "]"
+		}
+		if task.Due != nil {
+			line += labelStyle.Render(" (due " + task.Due.Format("2006-01-02") + ")")
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// detailHistoryLines renders the History tab, newest changes first
+func (m Model) detailHistoryLines(c db.Contact, width int) []string {
+	if m.detailTabContactID != c.ID || m.detailTabLoaded != detailTabHistory {
+		return []string{labelStyle.Render("Loading history…")}
+	}
+	if m.detailTabErr != "" {
+		return []string{labelStyle.Render(m.detailTabErr)}
+	}
+	if len(m.detailHistory) == 0 {
+		return []string{labelStyle.Render("No changes recorded yet")}
+	}
+	lines := []string{fmt.Sprintf("Changes (%d) • h: browse", len(m.detailHistory)), ""}
+	for _, e := range m.detailHistory {
+		lines = append(lines, labelStyle.Render(e.ChangedAt.Local().Format("2006-01-02 15:04"))+" "+historyFieldLabel(e.Field))
+		change := historyValue(e.OldValue.String, e.OldValue.Valid) + " → " + historyValue(e.NewValue.String, e.NewValue.Valid)
+		lines = append(lines, "  "+truncate.StringWithTail(change, uint(max(width-4, 1)), "…"))
+	}
+	return lines
+}
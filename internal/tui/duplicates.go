@@ -0,0 +1,174 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// duplicateCheckMsg carries the possible duplicates of the contact being created
+type duplicateCheckMsg struct {
+	seq     int
+	matches []db.Duplicate
+}
+
+// checkDuplicates looks for existing contacts matching the new contact form
+// in the background. Results from older checks are ignored.
+func (m Model) checkDuplicates() (Model, tea.Cmd) {
+	m.duplicateSeq++
+	seq := m.duplicateSeq
+	contacts := m.contacts
+	candidate := db.Contact{
+		Name:  m.newContactInputs[EditFieldName].Value(),
+		Email: db.NewNullString(m.newContactInputs[EditFieldEmail].Value()),
+		Phone: db.NewNullString(m.newContactInputs[EditFieldPhone].Value()),
+	}
+	return m, func() tea.Msg {
+		return duplicateCheckMsg{seq: seq, matches: db.PossibleDuplicates(contacts, candidate)}
+	}
+}
+
+// handleDuplicateCheck shows the result of the latest duplicate check
+func (m Model) handleDuplicateCheck(msg duplicateCheckMsg) Model {
+	if !m.newContactMode || msg.seq != m.duplicateSeq {
+		return m
+	}
+	m.duplicates = msg.matches
+	return m
+}
+
+// jumpToDuplicate abandons the new contact and selects the first possible duplicate
+func (m Model) jumpToDuplicate() Model {
+	if len(m.duplicates) == 0 {
+		return m
+	}
+	contact := m.duplicates[0].Contact
+	m = m.closeNewContact()
+	return m.jumpTo(contact)
+}
+
+// mergeIntoDuplicate abandons the new contact and adds what was typed to
+// the first possible duplicate instead; custom fields only fill in blanks
+func (m Model) mergeIntoDuplicate() Model {
+	if len(m.duplicates) == 0 {
+		return m
+	}
+	existing := m.duplicates[0].Contact
+	incoming, err := m.newContactFromForm()
+	if err != nil {
+		m.err = err
+		return m
+	}
+	if err := m.db.MergeInto(existing, incoming); err != nil {
+		m.err = err
+		return m
+	}
+
+	values, err := m.db.FieldValues(existing.ID)
+	if err != nil {
+		m.err = err
+		return m
+	}
+	blanks := make(map[string]string)
+	for name, value := range m.fieldInputValues(m.newContactInputs) {
+		if values[name] == "" {
+			blanks[name] = value
+		}
+	}
+	if err := m.db.SetFieldValues(existing.ID, blanks); err != nil {
+		m.err = err
+		return m
+	}
+
+	m = m.closeNewContact().reloadContacts()
+	m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Merged into %s", existing.Name))
+	return m.jumpTo(existing)
+}
+
+// newContactFromForm builds the contact typed into the new contact form
+func (m Model) newContactFromForm() (db.Contact, error) {
+	input := func(field int) string {
+		return strings.TrimSpace(m.newContactInputs[field].Value())
+	}
+	if input(EditFieldName) == "" {
+		return db.Contact{}, fmt.Errorf("name is required")
+	}
+	birthday, err := db.ParseBirthday(input(EditFieldBirthday))
+	if err != nil {
+		return db.Contact{}, err
+	}
+	avatarPath, err := cleanAvatar(input(EditFieldAvatar))
+	if err != nil {
+		return db.Contact{}, err
+	}
+	followUp, err := parseDueDate(input(EditFieldFollowUp))
+	if err != nil {
+		return db.Contact{}, err
+	}
+	deadline, err := parseDueDate(input(EditFieldDeadline))
+	if err != nil {
+		return db.Contact{}, err
+	}
+	timezone, err := parseTimezone(input(EditFieldTimezone))
+	if err != nil {
+		return db.Contact{}, err
+	}
+	return db.Contact{
+		Name:             input(EditFieldName),
+		Email:            db.NewNullString(input(EditFieldEmail)),
+		Phone:            db.NewNullString(input(EditFieldPhone)),
+		Company:          db.NewNullString(input(EditFieldCompany)),
+		Location:         db.NewNullString(input(EditFieldLocation)),
+		RelationshipType: RelationshipTypes[m.newContactRelTypeIdx+1], // Skip "all"
+		Notes:            db.NewNullString(input(EditFieldNotes)),
+		Label:            db.NewNullString(input(EditFieldLabel)),
+		Birthday:         db.NewNullString(birthday),
+		Avatar:           db.NewNullString(avatarPath),
+		FollowUpDate:     followUp,
+		DeadlineDate:     deadline,
+		Timezone:         db.NewNullString(timezone),
+		Tags:             db.ParseTags(input(EditFieldTags)),
+		State:            db.NewNullString("ok"), // Default state
+	}, nil
+}
+
+// closeNewContact leaves new contact mode
+func (m Model) closeNewContact() Model {
+	m.newContactMode = false
+	m.newContactField = 0
+	for i := range m.newContactInputs {
+		m.newContactInputs[i].Blur()
+	}
+	m.duplicates = nil
+	m.duplicateConfirm = false
+	return m
+}
+
+// renderDuplicateWarning describes the possible duplicates of the new
+// contact; confirm is set once saving has been stopped because of them
+func renderDuplicateWarning(duplicates []db.Duplicate, confirm bool) string {
+	first := duplicates[0]
+	name := first.Contact.Name
+	if first.Contact.Label.Valid && first.Contact.Label.String != "" {
+		name += " (" + first.Contact.Label.String + ")"
+	}
+	reason := "same " + first.Reason
+	if first.Reason == "similar name" {
+		reason = first.Reason
+	}
+	warning := fmt.Sprintf("⚠ Possible duplicate: %s, %s", name, reason)
+	if len(duplicates) > 1 {
+		var others []string
+		for _, d := range duplicates[1:] {
+			others = append(others, d.Contact.Name)
+		}
+		warning += fmt.Sprintf(" (also %s)", strings.Join(others, ", "))
+	}
+	keys := "Ctrl+G: go to existing contact • Ctrl+O: merge into it"
+	if confirm {
+		keys = "Enter: create anyway • " + keys
+	}
+	return yellowStyle.Render(warning) + "\n" + dimmedStyle.Render(keys) + "\n\n"
+}
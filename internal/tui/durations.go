@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// durationSteps are the lengths Ctrl+L cycles through in note mode, in minutes
+var durationSteps = []int{0, 15, 30, 45, 60, 90, 120, 180, 240}
+
+// nextDuration returns the duration step after minutes, wrapping to 0
+func nextDuration(minutes int) int {
+	for _, step := range durationSteps {
+		if step > minutes {
+			return step
+		}
+	}
+	return 0
+}
+
+// formatDuration renders minutes as e.g. "45m", "2h" or "1h 30m"
+func formatDuration(minutes int) string {
+	hours, mins := minutes/60, minutes%60
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", mins)
+	case mins == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh %dm", hours, mins)
+	}
+}
+
+// describeDurations summarizes the time spent with a contact
+func describeDurations(s db.DurationSummary) string {
+	text := fmt.Sprintf("Time: %s over %d interactions", formatDuration(s.Minutes), s.Count)
+	if s.MonthMins > 0 {
+		text += fmt.Sprintf(" (%s this month)", formatDuration(s.MonthMins))
+	}
+	return text
+}
@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/email"
+	"github.com/pdxmph/contacts-tui/internal/scripting"
+)
+
+// emailDoneMsg reports that the mail command exited
+type emailDoneMsg struct {
+	contactID int
+	name      string
+	err       error
+}
+
+// emailConfig returns the mail command and templates
+func (m Model) emailConfig() config.EmailConfig {
+	if m.cfg == nil {
+		return config.Default().Email
+	}
+	return m.cfg.Email
+}
+
+// composeEmail drafts an email from the template for the contact's state and
+// opens it in the mail command. Without a template the email starts blank.
+func (m Model) composeEmail(contact db.Contact) (Model, tea.Cmd) {
+	cfg := m.emailConfig()
+	tmpl, _ := email.TemplateFor(cfg, contact.State.String)
+
+	draft, err := email.Compose(contact, tmpl)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	cmd, cleanup, err := email.Command(cfg.Command, draft)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+		cleanup()
+		return emailDoneMsg{contactID: contact.ID, name: contact.Name, err: err}
+	})
+}
+
+// handleEmailDone reports the outcome of the mail command, logging an email
+// interaction when [email] log is set
+func (m Model) handleEmailDone(msg emailDoneMsg) Model {
+	if msg.err != nil {
+		m.err = fmt.Errorf("mail command failed: %w", msg.err)
+		return m
+	}
+	if !m.emailConfig().Log {
+		return m.setFlash(FlashSuccess, fmt.Sprintf("✓ Drafted email to %s", msg.name))
+	}
+	return m.logInteraction(msg.contactID, msg.name, "email", "✓ Emailed "+msg.name)
+}
+
+// logInteraction logs an interaction with no notes after an email or call,
+// marking the contact contacted
+func (m Model) logInteraction(contactID int, name, interactionType, flash string) Model {
+	before := m.snapshot(contactID)
+	if err := m.db.MarkContacted(contactID, interactionType, ""); err != nil {
+		m.err = fmt.Errorf("logging %s: %w", interactionType, err)
+		return m
+	}
+	m = m.reloadContacts()
+	m = m.setFlash(FlashSuccess, fmt.Sprintf("%s • logged %s • u: undo", flash, interactionType))
+	m = m.pushUndo(fmt.Sprintf("marking %s contacted", name), before)
+	return m.runAutomations(scripting.EventContacted, contactID)
+}
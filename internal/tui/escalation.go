@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/escalation"
+	"github.com/pdxmph/contacts-tui/internal/notify"
+)
+
+// escalationDoneMsg reports the outcome of the startup escalation run
+type escalationDoneMsg struct {
+	summary escalation.Summary
+	err     error
+}
+
+// escalationConfig returns the reminder escalation settings
+func (m Model) escalationConfig() config.EscalationConfig {
+	if m.cfg == nil {
+		return config.Default().Escalation
+	}
+	return m.cfg.Escalation
+}
+
+// initEscalation applies escalation steps in the background at startup
+func (m Model) initEscalation() tea.Cmd {
+	cfg := m.escalationConfig()
+	if !cfg.Enabled {
+		return nil
+	}
+	database := m.db
+	backend := m.taskManager.Backend()
+	notifications := config.Default().Notifications
+	waiting := config.Default().Waiting
+	if m.cfg != nil {
+		notifications = m.cfg.Notifications
+		waiting = m.cfg.Waiting
+	}
+	return func() tea.Msg {
+		throttle, err := notify.NewThrottle(database, notifications, time.Now())
+		if err != nil {
+			return escalationDoneMsg{err: err}
+		}
+		summary, err := escalation.Run(database, backend, cfg, throttle)
+		if err == nil {
+			err = escalation.Nudge(database, backend, waiting, throttle, &summary)
+		}
+		return escalationDoneMsg{summary: summary, err: err}
+	}
+}
+
+// handleEscalationDone reloads contacts changed by escalation and reports the result
+func (m Model) handleEscalationDone(msg escalationDoneMsg) Model {
+	if msg.err != nil {
+		m.err = fmt.Errorf("escalating reminders: %w", msg.err)
+		return m
+	}
+
+	s := msg.summary
+	if s.Pinged+s.TasksCreated+s.Neglected+s.Reset+s.Nudged > 0 {
+		m = m.reloadContacts()
+	}
+	if len(s.Errors) > 0 {
+		return m.setFlash(FlashError, fmt.Sprintf("Escalation: %s (%d errors, first: %s)", s, len(s.Errors), s.Errors[0]))
+	}
+	if s.Pinged+s.TasksCreated+s.Neglected+s.Nudged > 0 && !s.Quiet {
+		return m.setFlash(FlashInfo, "Escalation: "+s.String())
+	}
+	return m
+}
@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// openExportPrompt asks where to write the filtered contacts as CSV
+func (m Model) openExportPrompt() (Model, tea.Cmd) {
+	m.exportPromptMode = true
+	m.exportPathInput.SetValue(fmt.Sprintf("~/contacts-%s.csv", time.Now().Format("2006-01-02")))
+	m.exportPathInput.CursorEnd()
+	m.exportPathInput.Focus()
+	return m, textinput.Blink
+}
+
+// updateExport handles keys for the export prompt
+func (m Model) updateExport(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.exportPromptMode = false
+		m.exportPathInput.Blur()
+		return m, nil
+
+	case "enter":
+		path := strings.TrimSpace(m.exportPathInput.Value())
+		if path == "" {
+			return m, nil
+		}
+		m.exportPromptMode = false
+		m.exportPathInput.Blur()
+		return m.exportCSV(path), nil
+	}
+
+	var cmd tea.Cmd
+	m.exportPathInput, cmd = m.exportPathInput.Update(msg)
+	return m, cmd
+}
+
+// exportCSV writes the contacts shown by the active filters to a CSV file
+func (m Model) exportCSV(path string) Model {
+	contacts := m.filteredContacts()
+	var buf bytes.Buffer
+	if err := db.WriteCSV(&buf, contacts); err != nil {
+		m.err = err
+		return m
+	}
+	if err := os.WriteFile(config.ExpandPath(path), buf.Bytes(), 0644); err != nil {
+		m.err = fmt.Errorf("writing export: %w", err)
+		return m
+	}
+	return m.setFlash(FlashSuccess, fmt.Sprintf("✓ Exported %d contacts to %s", len(contacts), path))
+}
+
+// renderExport renders the export prompt overlay
+func (m Model) renderExport() string {
+	var lines []string
+	lines = append(lines, fmt.Sprintf("Export %d contacts to CSV:", len(m.filteredContacts())))
+	lines = append(lines, "")
+	lines = append(lines, m.exportPathInput.View())
+	lines = append(lines, "")
+	lines = append(lines, labelStyle.Render("Only contacts shown by the current filters are exported"))
+	lines = append(lines, "")
+	lines = append(lines, "Enter: export • Esc: cancel")
+
+	box := borderStyle.
+		Padding(1).
+		Width(60).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(box)
+}
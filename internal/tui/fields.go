@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/pdxmph/contacts-tui/internal/config"
+)
+
+// customFieldNames returns the custom fields declared in the config, in the
+// order they are shown. Their inputs follow the built-in ones in the edit
+// and new contact forms.
+func customFieldNames(cfg *config.Config) []string {
+	if cfg == nil {
+		return nil
+	}
+	return cfg.Fields.Custom
+}
+
+// newFieldInputs returns an input for each custom field
+func newFieldInputs(cfg *config.Config) []textinput.Model {
+	var inputs []textinput.Model
+	for _, name := range customFieldNames(cfg) {
+		input := textinput.New()
+		input.Width = 40
+		input.CharLimit = 200
+		input.Placeholder = name
+		inputs = append(inputs, input)
+	}
+	return inputs
+}
+
+// fieldInputValues collects the custom field values typed in a form's inputs
+func (m Model) fieldInputValues(inputs []textinput.Model) map[string]string {
+	values := make(map[string]string)
+	for i, name := range customFieldNames(m.cfg) {
+		if EditFieldCount+i < len(inputs) {
+			values[name] = inputs[EditFieldCount+i].Value()
+		}
+	}
+	return values
+}
+
+// fieldLabel pads a custom field's name to line up with the edit form's
+// built-in labels
+func fieldLabel(name string) string {
+	if len(name) >= 16 {
+		return name + ": "
+	}
+	return fmt.Sprintf("%-17s", name+":")
+}
+
+// detailFieldLines returns the detail pane lines for the custom fields set
+// on the contact whose details are cached
+func (m Model) detailFieldLines() []string {
+	var lines []string
+	for _, name := range customFieldNames(m.cfg) {
+		if value := m.detailFields[name]; value != "" {
+			lines = append(lines, fmt.Sprintf("%s: %s", name, value))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,369 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/scripting"
+)
+
+// openGroups shows the groups, with space adding the contact to or
+// removing it from the selected one
+func (m Model) openGroups(contact db.Contact) Model {
+	m.groupsMode = true
+	m.groupsContactID = contact.ID
+	m.groupsSelected = 0
+	m.groupInputMode = false
+	m.groupStateMode = false
+	return m.loadGroups()
+}
+
+// loadGroups reloads the group list
+func (m Model) loadGroups() Model {
+	groups, err := m.db.ListGroups()
+	if err != nil {
+		m.err = err
+		m.groupsMode = false
+		return m
+	}
+	m.groups = groups
+	if m.groupsSelected >= len(groups) {
+		m.groupsSelected = len(groups) - 1
+	}
+	if m.groupsSelected < 0 {
+		m.groupsSelected = 0
+	}
+	return m
+}
+
+// selectedGroup returns the group under the cursor
+func (m Model) selectedGroup() (db.Group, bool) {
+	if m.groupsSelected < 0 || m.groupsSelected >= len(m.groups) {
+		return db.Group{}, false
+	}
+	return m.groups[m.groupsSelected], true
+}
+
+// groupContact returns the contact the overlay was opened on
+func (m Model) groupContact() (db.Contact, bool) {
+	for _, c := range m.contacts {
+		if c.ID == m.groupsContactID {
+			return c, true
+		}
+	}
+	return db.Contact{}, false
+}
+
+// groupMembers returns the unarchived contacts in a group
+func (m Model) groupMembers(name string) []db.Contact {
+	var members []db.Contact
+	for _, c := range m.contacts {
+		if !c.Archived && c.InGroup(name) {
+			members = append(members, c)
+		}
+	}
+	return members
+}
+
+// openGroupInput starts naming a new group, or renaming one
+func (m Model) openGroupInput(group *db.Group) (tea.Model, tea.Cmd) {
+	m.groupInputMode = true
+	m.groupRenameID = 0
+	m.groupInput.SetValue("")
+	if group != nil {
+		m.groupRenameID = group.ID
+		m.groupInput.SetValue(group.Name)
+	}
+	m.groupInput.CursorEnd()
+	m.groupInput.Focus()
+	return m, textinput.Blink
+}
+
+// saveGroupInput creates or renames a group from the name typed
+func (m Model) saveGroupInput() Model {
+	name := m.groupInput.Value()
+	if m.groupRenameID != 0 {
+		var old string
+		if g, ok := m.selectedGroup(); ok {
+			old = g.Name
+		}
+		if err := m.db.RenameGroup(m.groupRenameID, name); err != nil {
+			return m.setFlash(FlashError, err.Error())
+		}
+		if strings.EqualFold(m.groupFilter, old) {
+			m.groupFilter = strings.Join(strings.Fields(name), " ")
+		}
+		m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Renamed %s", old))
+	} else {
+		if _, err := m.db.CreateGroup(name); err != nil {
+			return m.setFlash(FlashError, err.Error())
+		}
+		m = m.setFlash(FlashSuccess, "✓ Created group")
+	}
+	m.groupInputMode = false
+	m.groupInput.Blur()
+	m = m.loadGroups().reloadContacts()
+	for i, g := range m.groups {
+		if strings.EqualFold(g.Name, strings.Join(strings.Fields(name), " ")) {
+			m.groupsSelected = i
+		}
+	}
+	return m
+}
+
+// toggleGroupMember adds the overlay's contact to the selected group, or
+// takes it out if it is already a member
+func (m Model) toggleGroupMember() Model {
+	g, ok := m.selectedGroup()
+	if !ok {
+		return m
+	}
+	contact, ok := m.groupContact()
+	if !ok {
+		return m
+	}
+	if contact.InGroup(g.Name) {
+		if err := m.db.RemoveFromGroup(g.ID, contact.ID); err != nil {
+			m.err = err
+			return m
+		}
+		m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Removed %s from %s", contact.Name, g.Name))
+	} else {
+		if err := m.db.AddToGroup(g.ID, contact.ID); err != nil {
+			m.err = err
+			return m
+		}
+		m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Added %s to %s", contact.Name, g.Name))
+	}
+	return m.loadGroups().reloadContacts()
+}
+
+// setGroupState moves every member of a group to a state, as if each had
+// been changed in the state menu. Members the configured transitions do not
+// allow to move are skipped.
+func (m Model) setGroupState(g db.Group, state string) Model {
+	return m.setContactsState(m.groupMembers(g.Name), state, "in "+g.Name, "state change of "+g.Name)
+}
+
+// setContactsState moves contacts to a state at once, as if each had been
+// changed in the state menu, skipping those the configured transitions do
+// not allow to move. which describes the contacts in the status bar, as in
+// "in book club"; action names the change for undo. A change that creates
+// tasks can't be undone.
+func (m Model) setContactsState(contacts []db.Contact, state, which, action string) Model {
+	var moving []db.Contact
+	var changed []int
+	var before []*db.Snapshot
+	skipped, tasks := 0, 0
+	for _, c := range contacts {
+		if currentState(c) == state {
+			continue
+		}
+		if !m.canMoveTo(c, state) {
+			skipped++
+			continue
+		}
+		moving = append(moving, c)
+		changed = append(changed, c.ID)
+		before = append(before, m.snapshot(c.ID))
+	}
+	if err := m.db.UpdateContactsState(changed, state); err != nil {
+		m.err = err
+		return m
+	}
+	for _, c := range moving {
+		if m.stateCreatesTask(state) && m.taskManager.IsEnabled() && c.Label.Valid && c.Label.String != "" {
+			if err := m.taskManager.Backend().CreateContactTask(c.Name, state, c.Label.String); err != nil {
+				m.err = fmt.Errorf("state updated but task creation failed: %w", err)
+			} else {
+				tasks++
+			}
+		}
+	}
+	m = m.reloadContacts()
+
+	msg := fmt.Sprintf("✓ Set %d %s to %s", len(changed), which, state)
+	if tasks > 0 {
+		msg += fmt.Sprintf(", created %d tasks (can't be undone)", tasks)
+	}
+	if skipped > 0 {
+		msg += fmt.Sprintf(" (%d can't move to %s)", skipped, state)
+	}
+	m = m.setFlash(FlashSuccess, msg)
+	if tasks == 0 {
+		m = m.pushUndo(action, before...)
+	}
+	for _, id := range changed {
+		m = m.runAutomations(scripting.EventState, id)
+	}
+	return m
+}
+
+// updateGroups handles keys in the groups overlay
+func (m Model) updateGroups(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.groupInputMode {
+		switch msg.String() {
+		case "esc":
+			m.groupInputMode = false
+			m.groupInput.Blur()
+			return m, nil
+		case "enter":
+			return m.saveGroupInput(), nil
+		}
+		var cmd tea.Cmd
+		m.groupInput, cmd = m.groupInput.Update(msg)
+		return m, cmd
+	}
+
+	if m.groupStateMode {
+		switch msg.String() {
+		case "esc":
+			m.groupStateMode = false
+		case "j", "down":
+			if m.groupStateSelected < len(ContactStates)-1 {
+				m.groupStateSelected++
+			}
+		case "k", "up":
+			if m.groupStateSelected > 0 {
+				m.groupStateSelected--
+			}
+		case "enter":
+			m.groupStateMode = false
+			if g, ok := m.selectedGroup(); ok {
+				m = m.setGroupState(g, ContactStates[m.groupStateSelected]).loadGroups()
+			}
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.groupsMode = false
+		m.groups = nil
+	case "j", "down":
+		if m.groupsSelected < len(m.groups)-1 {
+			m.groupsSelected++
+		}
+	case "k", "up":
+		if m.groupsSelected > 0 {
+			m.groupsSelected--
+		}
+	case " ", "m":
+		return m.toggleGroupMember(), nil
+	case "a", "+":
+		return m.openGroupInput(nil)
+	case "e":
+		if g, ok := m.selectedGroup(); ok {
+			return m.openGroupInput(&g)
+		}
+	case "x", "delete":
+		if g, ok := m.selectedGroup(); ok {
+			if err := m.db.DeleteGroup(g.ID); err != nil {
+				m.err = err
+				return m, nil
+			}
+			if strings.EqualFold(m.groupFilter, g.Name) {
+				m.groupFilter = ""
+			}
+			m = m.loadGroups().reloadContacts()
+			m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Deleted group %s (its contacts are kept)", g.Name))
+		}
+	case "s":
+		if _, ok := m.selectedGroup(); ok {
+			m.groupStateMode = true
+			m.groupStateSelected = 0
+		}
+	case "enter", "f":
+		// Filter the list to the group, or clear the filter if it is applied
+		if g, ok := m.selectedGroup(); ok {
+			if strings.EqualFold(m.groupFilter, g.Name) {
+				m.groupFilter = ""
+			} else {
+				m.groupFilter = g.Name
+			}
+			m.groupsMode = false
+			m.groups = nil
+			m.selected = m.ensureValidSelection()
+		}
+	}
+	return m, nil
+}
+
+// renderGroups renders the groups overlay
+func (m Model) renderGroups() string {
+	contact, _ := m.groupContact()
+
+	var lines []string
+	switch {
+	case m.groupInputMode:
+		title := "New group"
+		if m.groupRenameID != 0 {
+			title = "Rename group"
+		}
+		lines = append(lines, title)
+		lines = append(lines, "")
+		lines = append(lines, selectedStyle.Render("Name"))
+		lines = append(lines, m.groupInput.View())
+		lines = append(lines, "")
+		lines = append(lines, "Enter: save • Esc: cancel")
+
+	case m.groupStateMode:
+		g, _ := m.selectedGroup()
+		members := m.groupMembers(g.Name)
+		lines = append(lines, fmt.Sprintf("Set state for everyone in %s (%d)", g.Name, len(members)))
+		lines = append(lines, "")
+		for i, state := range ContactStates {
+			line := state
+			if i == m.groupStateSelected {
+				lines = append(lines, selectedStyle.Render("▶ "+line))
+			} else {
+				lines = append(lines, "  "+line)
+			}
+		}
+		lines = append(lines, "")
+		lines = append(lines, "j/k: navigate • Enter: apply to the group • Esc: cancel")
+
+	default:
+		lines = append(lines, "Groups")
+		lines = append(lines, "")
+		if len(m.groups) == 0 {
+			lines = append(lines, labelStyle.Render("No groups yet; press a to create one"))
+		}
+		for i, g := range m.groups {
+			mark := "[ ]"
+			if contact.InGroup(g.Name) {
+				mark = "[✓]"
+			}
+			line := fmt.Sprintf("%s %s (%d)", mark, g.Name, g.Members)
+			if strings.EqualFold(m.groupFilter, g.Name) {
+				line += " • filtered"
+			}
+			if i == m.groupsSelected {
+				lines = append(lines, selectedStyle.Render("▶ "+line))
+			} else {
+				lines = append(lines, "  "+line)
+			}
+		}
+		lines = append(lines, "")
+		if contact.Name != "" {
+			lines = append(lines, fmt.Sprintf("Space: add/remove %s", contact.Name))
+		}
+		lines = append(lines, "Enter: filter to group • s: set state for group")
+		lines = append(lines, "a: new • e: rename • x: delete • Esc: close")
+	}
+
+	box := borderStyle.
+		Padding(1).
+		Width(70).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(box)
+}
@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// historyWidth is the width of the text in the history overlay
+const historyWidth = 76
+
+// historyDetailLines is the most lines each of the selected change's old
+// and new values take
+const historyDetailLines = 4
+
+// openHistory shows the recorded changes to a contact's fields
+func (m Model) openHistory(contact db.Contact) Model {
+	entries, err := m.db.ContactHistory(contact.ID)
+	if err != nil {
+		m.err = err
+		return m
+	}
+	m.historyMode = true
+	m.historyContactID = contact.ID
+	m.history = entries
+	m.historySelected = 0
+	return m
+}
+
+// updateHistory handles keys for the history overlay
+func (m Model) updateHistory(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "h":
+		m.historyMode = false
+		m.history = nil
+	case "j", "down":
+		if m.historySelected < len(m.history)-1 {
+			m.historySelected++
+		}
+	case "k", "up":
+		if m.historySelected > 0 {
+			m.historySelected--
+		}
+	case "g":
+		m.historySelected = 0
+	case "G":
+		m.historySelected = max(len(m.history)-1, 0)
+	}
+	return m, nil
+}
+
+// historyFieldLabel turns a column name into a label, e.g.
+// follow_up_date into "Follow up date"
+func historyFieldLabel(field string) string {
+	label := strings.ReplaceAll(field, "_", " ")
+	if label == "" {
+		return label
+	}
+	return strings.ToUpper(label[:1]) + label[1:]
+}
+
+// historyValue shows a recorded value on one line
+func historyValue(value string, valid bool) string {
+	if !valid || value == "" {
+		return "(empty)"
+	}
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// renderHistory renders the history overlay
+func (m Model) renderHistory() string {
+	var name string
+	if contact, err := m.db.GetContact(m.historyContactID); err == nil {
+		name = contact.Name
+	}
+
+	lines := []string{"History of " + name, ""}
+	if len(m.history) == 0 {
+		lines = append(lines, labelStyle.Render("No changes recorded yet"))
+	}
+
+	// Leave room for the box, the selected change's values and the help line
+	rows := max(m.height-12-2*historyDetailLines, 3)
+	start := 0
+	if m.historySelected >= rows {
+		start = m.historySelected - rows + 1
+	}
+	for i := start; i < len(m.history) && i < start+rows; i++ {
+		e := m.history[i]
+		line := e.ChangedAt.Local().Format("2006-01-02 15:04") + "  " + historyFieldLabel(e.Field) + ": " +
+			historyValue(e.OldValue.String, e.OldValue.Valid) + " → " + historyValue(e.NewValue.String, e.NewValue.Valid)
+		line = truncate.StringWithTail(line, historyWidth-2, "…")
+		if i == m.historySelected {
+			lines = append(lines, selectedStyle.Render("▶ "+line))
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+
+	if m.historySelected < len(m.history) {
+		e := m.history[m.historySelected]
+		lines = append(lines, "")
+		for _, value := range []struct {
+			label string
+			value string
+			valid bool
+		}{
+			{"Before: ", e.OldValue.String, e.OldValue.Valid},
+			{"After:  ", e.NewValue.String, e.NewValue.Valid},
+		} {
+			wrapped := wrapText(historyValue(value.value, value.valid), historyWidth-len(value.label))
+			if len(wrapped) > historyDetailLines {
+				wrapped = append(wrapped[:historyDetailLines-1], "…")
+			}
+			for i, text := range wrapped {
+				prefix := strings.Repeat(" ", len(value.label))
+				if i == 0 {
+					prefix = value.label
+				}
+				lines = append(lines, labelStyle.Render(prefix)+text)
+			}
+		}
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, "j/k: select change • g/G: newest/oldest • Esc: close")
+
+	box := borderStyle.
+		Padding(1).
+		Width(historyWidth + 4).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(box)
+}
@@ -0,0 +1,232 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pdxmph/contacts-tui/internal/avatar"
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/enrich"
+	"github.com/pdxmph/contacts-tui/internal/importer"
+)
+
+// importSummaryMaxErrors caps the errors listed on the summary screen
+const importSummaryMaxErrors = 8
+
+// importUpdate carries progress from the background import goroutine
+type importUpdate struct {
+	progress importer.Progress
+	enriched int // Companies filled in from email domains after the import
+	avatars  avatar.Summary
+	done     bool
+	err      error
+}
+
+// importMsg delivers an import update along with the channel to keep reading
+type importMsg struct {
+	update importUpdate
+	ch     <-chan importUpdate
+}
+
+// startImport parses and imports a file in the background, streaming
+// progress back to the UI
+func startImport(database *db.DB, path string, cfg config.Config) tea.Cmd {
+	return func() tea.Msg {
+		ch := make(chan importUpdate, 1)
+		go func() {
+			defer close(ch)
+			contacts, err := importer.ParseFile(path)
+			if err != nil {
+				ch <- importUpdate{done: true, err: err}
+				return
+			}
+			progress, err := importer.Import(database, contacts, func(p importer.Progress) {
+				// Drop intermediate updates the UI hasn't caught up with yet
+				select {
+				case ch <- importUpdate{progress: p}:
+				default:
+				}
+			})
+			var enriched int
+			if err == nil {
+				enriched, err = enrich.AfterImport(database, cfg.Enrich)
+			}
+			var avatars avatar.Summary
+			if err == nil {
+				avatars, err = avatar.AfterImport(database, cfg.Avatars)
+			}
+			ch <- importUpdate{progress: progress, enriched: enriched, avatars: avatars, done: true, err: err}
+		}()
+		return waitForImport(ch)()
+	}
+}
+
+// waitForImport waits for the next update from a running import
+func waitForImport(ch <-chan importUpdate) tea.Cmd {
+	return func() tea.Msg {
+		update, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return importMsg{update: update, ch: ch}
+	}
+}
+
+// handleImportMsg records import progress and finishes the run
+func (m Model) handleImportMsg(msg importMsg) (tea.Model, tea.Cmd) {
+	m.importProgress = msg.update.progress
+	if !msg.update.done {
+		return m, waitForImport(msg.ch)
+	}
+
+	m.importRunning = false
+	m.importSummary = true
+	m.importErr = msg.update.err
+	m.importEnriched = msg.update.enriched
+	m.importAvatars = msg.update.avatars
+	if newContacts, err := m.db.ListContacts(); err == nil {
+		m.setContacts(newContacts)
+		m.selected = m.ensureValidSelection()
+	}
+	m.invalidateDetailCache()
+	return m, nil
+}
+
+// openImportPrompt asks for the file to import
+func (m Model) openImportPrompt() (Model, tea.Cmd) {
+	m.importPromptMode = true
+	m.importPathInput.Reset()
+	m.importPathInput.Focus()
+	return m, textinput.Blink
+}
+
+// updateImport handles keys for the import prompt, progress and summary screens
+func (m Model) updateImport(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// The import runs to completion; keys are ignored until it finishes
+	if m.importRunning {
+		return m, nil
+	}
+
+	if m.importSummary {
+		switch msg.String() {
+		case "enter", "esc", "q":
+			m.importSummary = false
+			m.importProgress = importer.Progress{}
+			m.importErr = nil
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.importPromptMode = false
+		m.importPathInput.Blur()
+		return m, nil
+
+	case "enter":
+		path := strings.TrimSpace(m.importPathInput.Value())
+		if path == "" {
+			return m, nil
+		}
+		m.importPromptMode = false
+		m.importPathInput.Blur()
+		m.importRunning = true
+		m.importPath = config.ExpandPath(path)
+		m.importProgress = importer.Progress{}
+		var cfg config.Config
+		if m.cfg != nil {
+			cfg = *m.cfg
+		}
+		return m, startImport(m.db, m.importPath, cfg)
+	}
+
+	var cmd tea.Cmd
+	m.importPathInput, cmd = m.importPathInput.Update(msg)
+	return m, cmd
+}
+
+// renderProgressBar draws a text progress bar
+func renderProgressBar(done, total, width int) string {
+	if total <= 0 {
+		return strings.Repeat("░", width)
+	}
+	filled := done * width / total
+	if filled > width {
+		filled = width
+	}
+	return selectedStyle.Render(strings.Repeat("█", filled)) + labelStyle.Render(strings.Repeat("░", width-filled))
+}
+
+// renderImport renders the import prompt, progress or summary overlay
+func (m Model) renderImport() string {
+	var lines []string
+	p := m.importProgress
+
+	switch {
+	case m.importPromptMode:
+		formats := importer.Formats()
+		lines = append(lines, "Import contacts from file:")
+		lines = append(lines, "")
+		lines = append(lines, m.importPathInput.View())
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render("Formats: "+strings.Join(formats, ", ")))
+		lines = append(lines, "")
+		lines = append(lines, "Enter: import • Esc: cancel")
+
+	case m.importRunning:
+		lines = append(lines, fmt.Sprintf("Importing %s...", filepath.Base(m.importPath)))
+		lines = append(lines, "")
+		lines = append(lines, renderProgressBar(p.Processed(), p.Total, 50))
+		lines = append(lines, fmt.Sprintf("%d of %d records", p.Processed(), p.Total))
+		lines = append(lines, "")
+		lines = append(lines, fmt.Sprintf("Created: %d  Updated: %d  Skipped: %d  Errors: %d",
+			p.Created, p.Updated, p.Skipped, len(p.Errors)))
+
+	default:
+		lines = append(lines, fmt.Sprintf("Import of %s finished", filepath.Base(m.importPath)))
+		lines = append(lines, "")
+		if m.importErr != nil {
+			lines = append(lines, overdueStyle.Render("Import failed: "+m.importErr.Error()))
+			lines = append(lines, "")
+		}
+		lines = append(lines, fmt.Sprintf("Created: %d", p.Created))
+		lines = append(lines, fmt.Sprintf("Updated: %d", p.Updated))
+		lines = append(lines, fmt.Sprintf("Skipped: %d (already up to date)", p.Skipped))
+		lines = append(lines, fmt.Sprintf("Errors:  %d", len(p.Errors)))
+		if m.importEnriched > 0 {
+			lines = append(lines, fmt.Sprintf("Companies filled in from email domains: %d", m.importEnriched))
+		}
+		if m.cfg != nil && m.cfg.Avatars.Enabled {
+			lines = append(lines, "Avatars: "+m.importAvatars.String())
+		}
+		if len(p.Errors) > 0 {
+			lines = append(lines, "")
+			for i, e := range p.Errors {
+				if i == importSummaryMaxErrors {
+					lines = append(lines, labelStyle.Render(fmt.Sprintf("  ...and %d more", len(p.Errors)-i)))
+					break
+				}
+				lines = append(lines, overdueStyle.Render("  "+e))
+			}
+		}
+		lines = append(lines, "")
+		lines = append(lines, "Enter/Esc: close")
+	}
+
+	content := strings.Join(lines, "\n")
+	box := borderStyle.
+		Padding(1).
+		Width(64).
+		Render(content)
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(box)
+}
@@ -0,0 +1,294 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/reflow/truncate"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// inlineHeaderLines is how many lines of the detail pane come before the
+// first field while editing inline: name, tabs, rule, blank, hint, blank
+const inlineHeaderLines = 6
+
+// inlineField is a field that can be edited in the detail pane: one of the
+// edit form's built-in fields, or a custom field
+type inlineField struct {
+	field  int    // EditField index; EditFieldCount for a custom field
+	label  string // As shown
+	custom string // Name of the custom field
+}
+
+// inlineFields lists the fields that can be edited inline, in the order of
+// the edit form
+func (m Model) inlineFields() []inlineField {
+	fields := []inlineField{
+		{field: EditFieldName, label: "Name"},
+		{field: EditFieldEmail, label: "Email"},
+		{field: EditFieldPhone, label: "Phone"},
+		{field: EditFieldCompany, label: "Company"},
+		{field: EditFieldLocation, label: "Location"},
+		{field: EditFieldRelType, label: "Relationship"},
+		{field: EditFieldNotes, label: "Notes"},
+		{field: EditFieldLabel, label: "Label"},
+		{field: EditFieldBirthday, label: "Birthday"},
+		{field: EditFieldTags, label: "Tags"},
+		{field: EditFieldAvatar, label: "Avatar"},
+		{field: EditFieldFollowUp, label: "Follow-up"},
+		{field: EditFieldDeadline, label: "Deadline"},
+		{field: EditFieldTimezone, label: "Time zone"},
+	}
+	for _, name := range customFieldNames(m.cfg) {
+		fields = append(fields, inlineField{field: EditFieldCount, label: name, custom: name})
+	}
+	return fields
+}
+
+// inlineValue returns a field's value as it is typed in the edit form
+func (m Model) inlineValue(c db.Contact, f inlineField) string {
+	switch f.field {
+	case EditFieldName:
+		return c.Name
+	case EditFieldEmail:
+		return c.Email.String
+	case EditFieldPhone:
+		return c.Phone.String
+	case EditFieldCompany:
+		return c.Company.String
+	case EditFieldLocation:
+		return c.Location.String
+	case EditFieldRelType:
+		return c.RelationshipType
+	case EditFieldNotes:
+		return c.Notes.String
+	case EditFieldLabel:
+		return c.Label.String
+	case EditFieldBirthday:
+		return c.Birthday.String
+	case EditFieldTags:
+		return db.FormatTags(c.Tags)
+	case EditFieldAvatar:
+		return c.Avatar.String
+	case EditFieldFollowUp:
+		return formatDueInput(c.FollowUpDate)
+	case EditFieldDeadline:
+		return formatDueInput(c.DeadlineDate)
+	case EditFieldTimezone:
+		return c.Timezone.String
+	}
+	if m.detailContactID == c.ID {
+		return m.detailFields[f.custom]
+	}
+	return ""
+}
+
+// setContactField sets a built-in field of a contact from what was typed,
+// checking it the way the edit form does
+func setContactField(c *db.Contact, field int, value string) error {
+	switch field {
+	case EditFieldName:
+		if value == "" {
+			return fmt.Errorf("name can't be empty")
+		}
+		c.Name = value
+	case EditFieldEmail:
+		c.Email = db.NewNullString(value)
+	case EditFieldPhone:
+		c.Phone = db.NewNullString(value)
+	case EditFieldCompany:
+		c.Company = db.NewNullString(value)
+	case EditFieldLocation:
+		c.Location = db.NewNullString(value)
+	case EditFieldRelType:
+		for _, rType := range RelationshipTypes[1:] { // Skip "all"
+			if strings.EqualFold(rType, value) {
+				c.RelationshipType = rType
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown relationship type %q (use one of %s)", value, strings.Join(RelationshipTypes[1:], ", "))
+	case EditFieldNotes:
+		c.Notes = db.NewNullString(value)
+	case EditFieldLabel:
+		c.Label = db.NewNullString(value)
+	case EditFieldBirthday:
+		birthday, err := db.ParseBirthday(value)
+		if err != nil {
+			return err
+		}
+		c.Birthday = db.NewNullString(birthday)
+	case EditFieldAvatar:
+		avatarPath, err := cleanAvatar(value)
+		if err != nil {
+			return err
+		}
+		c.Avatar = db.NewNullString(avatarPath)
+	case EditFieldFollowUp, EditFieldDeadline:
+		date, err := parseDueDate(value)
+		if err != nil {
+			return err
+		}
+		if field == EditFieldFollowUp {
+			c.FollowUpDate = date
+		} else {
+			c.DeadlineDate = date
+		}
+	case EditFieldTimezone:
+		timezone, err := parseTimezone(value)
+		if err != nil {
+			return err
+		}
+		c.Timezone = db.NewNullString(timezone)
+	}
+	return nil
+}
+
+// openInlineEdit puts a field cursor in the detail pane, on the Info tab
+func (m Model) openInlineEdit() Model {
+	contacts := m.filteredContacts()
+	if len(contacts) == 0 || m.selected >= len(contacts) {
+		return m
+	}
+	m.detailTab = detailTabInfo
+	m.detailOpen = true
+	m.inlineMode = true
+	m.inlineEditing = false
+	m.inlineField = 0
+	m.detailScrollID = contacts[m.selected].ID
+	m.detailScroll = 0
+	return m
+}
+
+// closeInlineEdit takes the field cursor out of the detail pane
+func (m Model) closeInlineEdit() Model {
+	m.inlineMode = false
+	m.inlineEditing = false
+	m.inlineInput.Blur()
+	m.detailScroll = 0
+	return m
+}
+
+// moveInlineField moves the field cursor, scrolling the detail pane to
+// keep it in view
+func (m Model) moveInlineField(delta int) Model {
+	m.inlineField = min(max(m.inlineField+delta, 0), len(m.inlineFields())-1)
+	line := inlineHeaderLines + m.inlineField
+	visible := m.height - 4
+	if line >= m.detailScroll+visible {
+		m.detailScroll = line - visible + 1
+	}
+	if m.inlineField == 0 {
+		m.detailScroll = 0
+	} else if line < m.detailScroll {
+		m.detailScroll = line
+	}
+	return m
+}
+
+// updateInlineEdit handles keys while fields are edited in the detail pane
+func (m Model) updateInlineEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	contacts := m.filteredContacts()
+	if len(contacts) == 0 || m.selected >= len(contacts) {
+		return m.closeInlineEdit(), nil
+	}
+	c := contacts[m.selected]
+
+	if m.inlineEditing {
+		switch msg.String() {
+		case "esc":
+			m.inlineEditing = false
+			m.inlineInput.Blur()
+			return m, nil
+		case "enter":
+			return m.saveInlineField(c), nil
+		}
+		var cmd tea.Cmd
+		m.inlineInput, cmd = m.inlineInput.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		return m.closeInlineEdit(), nil
+	case "j", "down", "tab":
+		return m.moveInlineField(1), nil
+	case "k", "up", "shift+tab":
+		return m.moveInlineField(-1), nil
+	case "e":
+		// The full form, for changing several fields at once
+		m = m.closeInlineEdit()
+		m.enterEditMode(c)
+		return m, nil
+	case "enter":
+		f := m.inlineFields()[m.inlineField]
+		m.inlineInput = textinput.New()
+		m.inlineInput.CharLimit = 500
+		m.inlineInput.Width = max(m.detailWidth()-21, 10)
+		m.inlineInput.SetValue(m.inlineValue(c, f))
+		m.inlineInput.Focus()
+		m.inlineEditing = true
+		return m, textinput.Blink
+	}
+	return m, nil
+}
+
+// saveInlineField saves the field being edited. When the value is rejected
+// the input stays open to fix it.
+func (m Model) saveInlineField(c db.Contact) Model {
+	f := m.inlineFields()[m.inlineField]
+	value := strings.TrimSpace(m.inlineInput.Value())
+
+	var err error
+	switch f.field {
+	case EditFieldTags:
+		err = m.db.SetContactTags(c.ID, db.ParseTags(value))
+	case EditFieldCount:
+		err = m.db.SetFieldValues(c.ID, map[string]string{f.custom: value})
+	default:
+		if err = setContactField(&c, f.field, value); err == nil {
+			err = m.db.UpdateContact(c)
+		}
+	}
+	if err != nil {
+		m.err = err
+		return m
+	}
+
+	m.inlineEditing = false
+	m.inlineInput.Blur()
+	m = m.reloadContacts()
+	// Renaming can move the contact in the list
+	for i, contact := range m.filteredContacts() {
+		if contact.ID == c.ID {
+			m.selected = i
+			break
+		}
+	}
+	return m.setFlash(FlashSuccess, fmt.Sprintf("✓ Updated %s's %s", c.Name, strings.ToLower(f.label)))
+}
+
+// inlineEditLines renders the Info tab as a list of fields to edit
+func (m Model) inlineEditLines(c db.Contact, width int) []string {
+	hint := "j/k: field • Enter: edit • e: full form • Esc: done"
+	if m.inlineEditing {
+		hint = "Enter: save • Esc: cancel"
+	}
+	lines := []string{labelStyle.Render(hint), ""}
+	for i, f := range m.inlineFields() {
+		label := fmt.Sprintf("%-14s", f.label+":")
+		switch {
+		case i == m.inlineField && m.inlineEditing:
+			lines = append(lines, selectedStyle.Render("▶ "+label)+m.inlineInput.View())
+		case i == m.inlineField:
+			value := truncate.StringWithTail(m.inlineValue(c, f), uint(max(width-20, 1)), "…")
+			lines = append(lines, selectedStyle.Render("▶ "+label+value))
+		default:
+			value := truncate.StringWithTail(m.inlineValue(c, f), uint(max(width-20, 1)), "…")
+			lines = append(lines, "  "+label+value)
+		}
+	}
+	return lines
+}
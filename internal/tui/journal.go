@@ -0,0 +1,257 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+)
+
+// journalWidth is the width of the text in the journal overlay
+const journalWidth = 76
+
+// detailJournalEntries is how many journal entries the detail pane shows
+const detailJournalEntries = 3
+
+// newJournalInput sets up the textarea journal entries are written in
+func newJournalInput() textarea.Model {
+	ta := textarea.New()
+	ta.Placeholder = "What happened today? Mention contacts by label, e.g. @sarahc"
+	ta.SetHeight(8)
+	ta.SetWidth(journalWidth)
+	ta.CharLimit = 5000
+	ta.ShowLineNumbers = false
+	return ta
+}
+
+// openJournal shows the journal, newest entries first
+func (m Model) openJournal() Model {
+	m.journalMode = true
+	m.journalSelected = 0
+	return m.loadJournal()
+}
+
+// loadJournal reloads the journal entries
+func (m Model) loadJournal() Model {
+	entries, err := m.db.ListLogs(-1)
+	if err != nil {
+		m.err = err
+		m.journalMode = false
+		return m
+	}
+	m.journal = entries
+	if m.journalSelected >= len(entries) {
+		m.journalSelected = len(entries) - 1
+	}
+	if m.journalSelected < 0 {
+		m.journalSelected = 0
+	}
+	return m
+}
+
+// editJournalEntry opens the editor on an entry, or on a new one for an id
+// of 0
+func (m Model) editJournalEntry(id int, content string) (Model, tea.Cmd) {
+	m.journalEditing = true
+	m.journalEditID = id
+	m.journalInput.Reset()
+	m.journalInput.SetValue(content)
+	m.journalInput.Focus()
+	return m, textarea.Blink
+}
+
+// writeToday opens today's journal entry, starting one if there isn't one
+// yet
+func (m Model) writeToday() (Model, tea.Cmd) {
+	today := time.Now().Format("2006-01-02")
+	for i, e := range m.journal {
+		if e.CreatedAt.Local().Format("2006-01-02") == today {
+			m.journalSelected = i
+			return m.editJournalEntry(e.ID, e.Content)
+		}
+	}
+	return m.editJournalEntry(0, "")
+}
+
+// saveJournalEntry saves the entry being written, linking it to the
+// contacts it mentions
+func (m Model) saveJournalEntry() Model {
+	content := strings.TrimSpace(m.journalInput.Value())
+	m.journalEditing = false
+	m.journalInput.Blur()
+	if content == "" {
+		return m
+	}
+
+	contactIDs, unknown, err := m.db.ResolveMentions(content)
+	if err != nil {
+		m.err = err
+		return m
+	}
+	id, err := m.db.SaveLog(m.journalEditID, content, contactIDs)
+	if err != nil {
+		m.err = err
+		return m
+	}
+	m = m.loadJournal()
+	for i, e := range m.journal {
+		if e.ID == id {
+			m.journalSelected = i
+		}
+	}
+	m.invalidateDetailCache()
+	if len(unknown) > 0 {
+		return m.setFlash(FlashInfo, "✓ Saved; no contact labelled "+strings.Join(unknown, ", "))
+	}
+	return m.setFlash(FlashSuccess, fmt.Sprintf("✓ Saved journal entry (%d contacts mentioned)", len(contactIDs)))
+}
+
+// updateJournal handles keys for the journal list and editor
+func (m Model) updateJournal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.journalEditing {
+		switch msg.String() {
+		case "esc":
+			m.journalEditing = false
+			m.journalInput.Blur()
+			return m, nil
+		case "enter":
+			// Save on ctrl+enter or cmd+enter
+			if msg.Type == tea.KeyCtrlJ || msg.Type == tea.KeyCtrlM {
+				return m.saveJournalEntry(), nil
+			}
+		}
+		var cmd tea.Cmd
+		m.journalInput, cmd = m.journalInput.Update(msg)
+		return m, cmd
+	}
+
+	if m.journalDeleteConfirm {
+		m.journalDeleteConfirm = false
+		if msg.String() != "y" && msg.String() != "Y" {
+			return m, nil
+		}
+		if m.journalSelected < len(m.journal) {
+			if err := m.db.DeleteLog(m.journal[m.journalSelected].ID); err != nil {
+				m.err = err
+				return m, nil
+			}
+			m = m.loadJournal()
+			m.invalidateDetailCache()
+			m = m.setFlash(FlashSuccess, "✓ Deleted journal entry")
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.journalMode = false
+		m.journal = nil
+	case "j", "down":
+		if m.journalSelected < len(m.journal)-1 {
+			m.journalSelected++
+		}
+	case "k", "up":
+		if m.journalSelected > 0 {
+			m.journalSelected--
+		}
+	case "n", "a":
+		return m.writeToday()
+	case "e", "enter":
+		if m.journalSelected < len(m.journal) {
+			e := m.journal[m.journalSelected]
+			return m.editJournalEntry(e.ID, e.Content)
+		}
+	case "x", "delete":
+		if m.journalSelected < len(m.journal) {
+			m.journalDeleteConfirm = true
+		}
+	}
+	return m, nil
+}
+
+// renderJournal renders the journal list or editor overlay
+func (m Model) renderJournal() string {
+	var lines []string
+	if m.journalEditing {
+		title := "Journal: " + time.Now().Format("Monday, January 2")
+		if m.journalEditID != 0 && m.journalSelected < len(m.journal) {
+			title = "Journal: " + m.journal[m.journalSelected].CreatedAt.Local().Format("Monday, January 2")
+		}
+		lines = append(lines, title)
+		lines = append(lines, "")
+		lines = append(lines, m.journalInput.View())
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render("@label links an entry to a contact"))
+		lines = append(lines, "Ctrl+Enter: save • Esc: cancel")
+	} else {
+		lines = append(lines, "Journal")
+		lines = append(lines, "")
+		if len(m.journal) == 0 {
+			lines = append(lines, labelStyle.Render("No entries yet; press n to write today's"))
+		}
+
+		// Leave room for the box, the selected entry and the help line
+		rows := max(m.height-18, 3)
+		start := 0
+		if m.journalSelected >= rows {
+			start = m.journalSelected - rows + 1
+		}
+		for i := start; i < len(m.journal) && i < start+rows; i++ {
+			e := m.journal[i]
+			line := e.CreatedAt.Local().Format("2006-01-02") + "  " + strings.Join(strings.Fields(e.Content), " ")
+			line = truncate.StringWithTail(line, journalWidth-2, "…")
+			if i == m.journalSelected {
+				lines = append(lines, selectedStyle.Render("▶ "+line))
+			} else {
+				lines = append(lines, "  "+line)
+			}
+		}
+
+		if m.journalSelected < len(m.journal) {
+			lines = append(lines, "")
+			wrapped := wrapText(m.journal[m.journalSelected].Content, journalWidth)
+			if len(wrapped) > 6 {
+				wrapped = append(wrapped[:5], "…")
+			}
+			lines = append(lines, wrapped...)
+		}
+
+		lines = append(lines, "")
+		if m.journalDeleteConfirm {
+			lines = append(lines, "Delete this entry? y: delete • any other key: cancel")
+		} else {
+			lines = append(lines, "n: write today's entry • Enter: edit • x: delete • Esc: close")
+		}
+	}
+
+	box := borderStyle.
+		Padding(1).
+		Width(journalWidth + 4).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(box)
+}
+
+// detailJournalLines lists the newest journal entries mentioning the
+// contact, for the detail pane
+func (m Model) detailJournalLines(width int) []string {
+	if len(m.detailJournal) == 0 {
+		return nil
+	}
+	lines := []string{"Journal:"}
+	for _, e := range m.detailJournal {
+		lines = append(lines, e.CreatedAt.Local().Format("2006-01-02"))
+		for _, line := range wrapText(e.Content, width-4) {
+			lines = append(lines, "  "+line)
+		}
+	}
+	return append(lines, "")
+}
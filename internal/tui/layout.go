@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+)
+
+// Limits and step of the share of the window the contact list takes
+const (
+	minListRatio     = 0.2
+	maxListRatio     = 0.8
+	defaultListRatio = 0.33
+	listRatioStep    = 0.05
+)
+
+// narrowWidth is the window width below which the "auto" layout shows one
+// pane at a time, since side by side neither pane has room
+const narrowWidth = 90
+
+// singlePane reports whether the window shows the contact list and the
+// detail pane one at a time, each at full width
+func (m Model) singlePane() bool {
+	layout := "auto"
+	if m.cfg != nil && m.cfg.UI.Layout != "" {
+		layout = m.cfg.UI.Layout
+	}
+	switch layout {
+	case "single":
+		return true
+	case "split":
+		return false
+	}
+	return m.width < narrowWidth
+}
+
+// clampListRatio keeps a list_width setting within its limits; 0, for a
+// config written before the setting existed, means the default
+func clampListRatio(ratio float64) float64 {
+	if ratio == 0 {
+		return defaultListRatio
+	}
+	return min(max(ratio, minListRatio), maxListRatio)
+}
+
+// listWidth is the width of the contact list, borders excluded
+func (m Model) listWidth() int {
+	if m.singlePane() {
+		return m.width - 2
+	}
+	return int(float64(m.width) * clampListRatio(m.listRatio))
+}
+
+// detailWidth is the width of the detail pane, borders excluded
+func (m Model) detailWidth() int {
+	if m.singlePane() {
+		return m.width - 2
+	}
+	return m.width - m.listWidth() - 3
+}
+
+// resizeList widens (positive step) or narrows the contact list and saves
+// the new split to the config file
+func (m Model) resizeList(step float64) Model {
+	if m.singlePane() {
+		return m.setFlash(FlashInfo, "The list takes the whole window in single-pane layout")
+	}
+	ratio := clampListRatio(math.Round((clampListRatio(m.listRatio)+step)*100) / 100)
+	if ratio == m.listRatio {
+		return m
+	}
+	m.listRatio = ratio
+	m.filter.Width = m.listWidth() - 4
+	m.detailScroll = 0 // Lines wrap differently now
+
+	message := fmt.Sprintf("List width %d%%", int(math.Round(ratio*100)))
+	if m.cfg != nil {
+		m.cfg.UI.ListWidth = ratio
+		if err := config.SetNumber("ui", "list_width", ratio); err != nil {
+			return m.setFlash(FlashError, fmt.Sprintf("%s, but couldn't save it: %v", message, err))
+		}
+	}
+	return m.setFlash(FlashInfo, message)
+}
@@ -0,0 +1,235 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// linkMaxResults is the number of contacts offered when adding a link
+const linkMaxResults = 6
+
+// openLinks shows the links between a contact and other contacts
+func (m Model) openLinks(contact db.Contact) Model {
+	m.linksMode = true
+	m.linksContactID = contact.ID
+	m.linksSelected = 0
+	return m.loadLinks()
+}
+
+// loadLinks reloads the links being edited
+func (m Model) loadLinks() Model {
+	links, err := m.db.ListLinks(m.linksContactID)
+	if err != nil {
+		m.err = err
+		m.linksMode = false
+		return m
+	}
+	m.links = links
+	if m.linksSelected >= len(links) {
+		m.linksSelected = len(links) - 1
+	}
+	if m.linksSelected < 0 {
+		m.linksSelected = 0
+	}
+	m.invalidateDetailCache()
+	return m
+}
+
+// linkCandidates returns the contacts matching what has been typed in the
+// link form, other than the contact being linked
+func (m Model) linkCandidates() []pickerResult {
+	var candidates []pickerResult
+	for _, r := range m.matchContacts(m.linkInput.Value(), linkMaxResults+1) {
+		if r.contact.ID != m.linksContactID {
+			candidates = append(candidates, r)
+		}
+	}
+	if len(candidates) > linkMaxResults {
+		candidates = candidates[:linkMaxResults]
+	}
+	return candidates
+}
+
+// openLinkForm starts linking the contact to another one
+func (m Model) openLinkForm() (Model, tea.Cmd) {
+	m.linkFormMode = true
+	m.linkKind = 0
+	m.linkResult = 0
+	m.linkInput.Reset()
+	m.linkInput.Focus()
+	return m, textinput.Blink
+}
+
+// saveLinkForm links the contact to the selected match
+func (m Model) saveLinkForm() Model {
+	candidates := m.linkCandidates()
+	if m.linkResult >= len(candidates) {
+		return m
+	}
+	other := candidates[m.linkResult].contact
+	kind := db.LinkKinds[m.linkKind]
+	if err := m.db.AddLink(m.linksContactID, other.ID, kind.Name); err != nil {
+		m.err = err
+		return m
+	}
+
+	m.linkFormMode = false
+	m.linkInput.Blur()
+	m = m.loadLinks()
+	return m.setFlash(FlashSuccess, fmt.Sprintf("✓ Linked as %s %s", kind.Label, other.Name))
+}
+
+// updateLinks handles keys for the links list and form
+func (m Model) updateLinks(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.linkFormMode {
+		switch msg.String() {
+		case "esc":
+			m.linkFormMode = false
+			m.linkInput.Blur()
+			return m, nil
+		case "enter":
+			return m.saveLinkForm(), nil
+		case "tab":
+			m.linkKind = (m.linkKind + 1) % len(db.LinkKinds)
+			return m, nil
+		case "shift+tab":
+			m.linkKind = (m.linkKind + len(db.LinkKinds) - 1) % len(db.LinkKinds)
+			return m, nil
+		case "down", "ctrl+n":
+			if m.linkResult < len(m.linkCandidates())-1 {
+				m.linkResult++
+			}
+			return m, nil
+		case "up", "ctrl+p":
+			if m.linkResult > 0 {
+				m.linkResult--
+			}
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.linkInput, cmd = m.linkInput.Update(msg)
+		m.linkResult = 0
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.linksMode = false
+		m.links = nil
+	case "j", "down":
+		if m.linksSelected < len(m.links)-1 {
+			m.linksSelected++
+		}
+	case "k", "up":
+		if m.linksSelected > 0 {
+			m.linksSelected--
+		}
+	case "a", "+":
+		return m.openLinkForm()
+	case "enter":
+		// Jump to the linked contact
+		if m.linksSelected < len(m.links) {
+			id := m.links[m.linksSelected].OtherID
+			m.linksMode = false
+			m.links = nil
+			for _, c := range m.contacts {
+				if c.ID == id {
+					return m.jumpTo(c), nil
+				}
+			}
+		}
+	case "x", "delete":
+		if m.linksSelected < len(m.links) {
+			l := m.links[m.linksSelected]
+			if err := m.db.DeleteLink(l.ID); err != nil {
+				m.err = err
+				return m, nil
+			}
+			m = m.loadLinks()
+			m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Unlinked %s", l.OtherName))
+		}
+	}
+	return m, nil
+}
+
+// renderLinks renders the links list or form overlay
+func (m Model) renderLinks() string {
+	var name string
+	if contact, err := m.db.GetContact(m.linksContactID); err == nil {
+		name = contact.Name
+	}
+
+	var lines []string
+	if m.linkFormMode {
+		lines = append(lines, fmt.Sprintf("Link %s", name))
+		lines = append(lines, "")
+		kind := db.LinkKinds[m.linkKind]
+		lines = append(lines, name+" is "+selectedStyle.Render("< "+kind.Label+" >"))
+		lines = append(lines, "")
+		lines = append(lines, m.linkInput.View())
+		lines = append(lines, "")
+		candidates := m.linkCandidates()
+		if len(candidates) == 0 {
+			lines = append(lines, labelStyle.Render("  No matching contacts"))
+		}
+		for i, r := range candidates {
+			line := r.contact.Name
+			if r.contact.Company.Valid {
+				line += labelStyle.Render(" · " + r.contact.Company.String)
+			}
+			if i == m.linkResult {
+				line = selectedStyle.Render("▶ ") + line
+			} else {
+				line = "  " + line
+			}
+			lines = append(lines, line)
+		}
+		lines = append(lines, "")
+		lines = append(lines, "Tab: change kind • ↑/↓: select contact • Enter: link • Esc: cancel")
+	} else {
+		lines = append(lines, fmt.Sprintf("Links for %s", name))
+		lines = append(lines, "")
+		if len(m.links) == 0 {
+			lines = append(lines, labelStyle.Render("No linked contacts yet"))
+		}
+		for i, l := range m.links {
+			line := l.Label + " " + l.OtherName
+			if i == m.linksSelected {
+				lines = append(lines, selectedStyle.Render("▶ "+line))
+			} else {
+				lines = append(lines, "  "+line)
+			}
+		}
+		lines = append(lines, "")
+		lines = append(lines, "a: add • Enter: go to contact • x: unlink • Esc: close")
+	}
+
+	box := borderStyle.
+		Padding(1).
+		Width(70).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(box)
+}
+
+// detailLinkLines lists the contact's links for the detail pane
+func (m Model) detailLinkLines() []string {
+	var lines []string
+	for i, l := range m.detailLinks {
+		prefix := "Links: "
+		if i > 0 {
+			prefix = "       "
+		}
+		lines = append(lines, prefix+l.Label+" "+l.OtherName)
+	}
+	return lines
+}
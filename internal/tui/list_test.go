@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// largeListModel returns a model over n generated contacts, sized like a
+// typical terminal
+func largeListModel(b *testing.B, n int) Model {
+	b.Helper()
+	dir := b.TempDir()
+	b.Setenv("HOME", dir)
+	path := filepath.Join(dir, "contacts.db")
+	if err := db.CreateFixturesDatabase(path); err != nil {
+		b.Fatalf("creating fixtures: %v", err)
+	}
+	database, err := db.Open(path)
+	if err != nil {
+		b.Fatalf("opening fixtures: %v", err)
+	}
+	b.Cleanup(func() { database.Close() })
+
+	cfg := config.Default()
+	cfg.Database.Path = path
+	cfg.Tasks.Backend = "noop"
+	cfg.UI.FollowUpAlerts = false
+	model, err := New(database, cfg)
+	if err != nil {
+		b.Fatalf("creating model: %v", err)
+	}
+
+	first := []string{"Sarah", "Mike", "Priya", "Jonas", "Ana", "Wei", "Fatima", "Liam", "Chloé", "Kenji"}
+	last := []string{"Chen", "Johnson", "Patel", "Müller", "Silva", "Zhang", "Okafor", "Brown", "Dubois", "Sato"}
+	types := []string{"work", "friend", "family", "network"}
+	states := []string{"ok", "ok", "ok", "ping", "write", "followup"}
+	now := time.Now()
+	contacts := make([]db.Contact, n)
+	for i := range contacts {
+		name := fmt.Sprintf("%s %s %d", first[i%len(first)], last[i/len(first)%len(last)], i)
+		contacts[i] = db.Contact{
+			ID:               i + 1,
+			Name:             name,
+			Label:            db.NewNullString(fmt.Sprintf("@c%d", i)),
+			Company:          db.NewNullString(fmt.Sprintf("Company %d", i%250)),
+			RelationshipType: types[i%len(types)],
+			State:            db.NewNullString(states[i%len(states)]),
+			ContactedAt:      sql.NullTime{Time: now.AddDate(0, 0, -i%400), Valid: true},
+			ContactStyle:     "periodic",
+			Tags:             []string{types[i%len(types)]},
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		}
+	}
+	model.setContacts(contacts)
+
+	updated, _ := model.Update(tea.WindowSizeMsg{Width: 160, Height: 50})
+	return updated.(Model)
+}
+
+// BenchmarkFilterContacts filters a large list as each keystroke of a
+// search does
+func BenchmarkFilterContacts(b *testing.B) {
+	for _, n := range []int{5000, 20000} {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			m := largeListModel(b, n)
+			queries := []string{"", "s", "sa", "sar", "sara", "sarah", "chen", "company 1", "type:work state:ping"}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				m.filter.SetValue(queries[i%len(queries)])
+				m.computeFilteredContacts()
+			}
+		})
+	}
+}
+
+// BenchmarkRenderList renders the list while scrolling through a large
+// list, as each frame does
+func BenchmarkRenderList(b *testing.B) {
+	for _, n := range []int{5000, 20000} {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			m := largeListModel(b, n)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				m.selected = i % n
+				m.renderList(80, 50)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+	"github.com/muesli/reflow/wrap"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// Styles for Markdown in notes; the colored ones are set by applyTheme
+var (
+	mdBoldStyle   = lipgloss.NewStyle().Bold(true)
+	mdItalicStyle = lipgloss.NewStyle().Italic(true)
+	mdPlainStyle  = lipgloss.NewStyle()
+	mdCodeStyle   lipgloss.Style
+	mdLinkStyle   lipgloss.Style
+	mdQuoteStyle  lipgloss.Style
+)
+
+var (
+	mdHeading  = regexp.MustCompile(`^#{1,6}\s+`)
+	mdBullet   = regexp.MustCompile(`^(\s*)[-*+]\s+`)
+	mdNumbered = regexp.MustCompile(`^(\s*)(\d+[.)])\s+`)
+)
+
+// mdWord is a styled word and its width on screen. glued words follow the
+// previous one without a space, as in "**bold**,".
+type mdWord struct {
+	text  string
+	width int
+	glued bool
+}
+
+// markdown renders notes written in Markdown for the detail pane: bold,
+// italics, code, headings, lists, quotes and links, wrapped to width. Links
+// are numbered from len(links)+1 and appended to links, so they can be
+// opened with the number keys. It is written here rather than using
+// glamour, which has no hook for numbering links and styles text from its
+// own style sheets instead of the theme.
+type markdown struct {
+	width int
+	links []string
+}
+
+// render renders one note
+func (md *markdown) render(text string) []string {
+	var lines []string
+	inCode := false
+	blank := true // Drop leading and repeated blank lines
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			lines = append(lines, mdCodeStyle.Render(truncate.StringWithTail("  "+line, uint(max(md.width, 1)), "…")))
+			blank = false
+			continue
+		}
+		if trimmed == "" {
+			if !blank {
+				lines = append(lines, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+
+		switch {
+		case mdHeading.MatchString(trimmed):
+			words := md.words(mdHeading.ReplaceAllString(trimmed, ""), mdBoldStyle)
+			lines = append(lines, wrapWords(words, md.width, "", "")...)
+		case strings.HasPrefix(trimmed, ">"):
+			words := md.words(strings.TrimSpace(strings.TrimLeft(trimmed, ">")), mdQuoteStyle)
+			bar := mdQuoteStyle.Render("│ ")
+			lines = append(lines, wrapWords(words, md.width, bar, bar)...)
+		case mdBullet.MatchString(line):
+			m := mdBullet.FindStringSubmatch(line)
+			indent := strings.Repeat(" ", len(m[1]))
+			words := md.words(line[len(m[0]):], mdPlainStyle)
+			lines = append(lines, wrapWords(words, md.width, indent+"• ", indent+"  ")...)
+		case mdNumbered.MatchString(line):
+			m := mdNumbered.FindStringSubmatch(line)
+			indent := strings.Repeat(" ", len(m[1]))
+			words := md.words(line[len(m[0]):], mdPlainStyle)
+			lines = append(lines, wrapWords(words, md.width, indent+m[2]+" ", indent+strings.Repeat(" ", len(m[2])+1))...)
+		default:
+			lines = append(lines, wrapWords(md.words(trimmed, mdPlainStyle), md.width, "", "")...)
+		}
+	}
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// words splits a line into styled words, applying inline Markdown on top
+// of base
+func (md *markdown) words(text string, base lipgloss.Style) []mdWord {
+	var words []mdWord
+	spaced := true // Whether the text added so far ends in a space
+	add := func(s string, style lipgloss.Style) {
+		if s == "" {
+			return
+		}
+		for i, field := range strings.Fields(s) {
+			glued := i == 0 && !spaced && !unicode.IsSpace(rune(s[0]))
+			words = append(words, mdWord{text: style.Render(field), width: lipgloss.Width(field), glued: glued})
+		}
+		spaced = unicode.IsSpace(rune(s[len(s)-1]))
+	}
+	addLink := func(label, url string) {
+		md.links = append(md.links, url)
+		add(label, base.Inherit(mdLinkStyle))
+		marker := fmt.Sprintf("[%d]", len(md.links))
+		words = append(words, mdWord{text: dimmedStyle.Render(marker), width: len(marker), glued: true})
+		spaced = false
+	}
+
+	plain := 0 // Start of text not yet added
+	for i := 0; i < len(text); {
+		start := i
+		var style lipgloss.Style
+		var inner string
+		switch {
+		case strings.HasPrefix(text[i:], "**") || strings.HasPrefix(text[i:], "__"):
+			if end := strings.Index(text[i+2:], text[i:i+2]); end > 0 {
+				inner, style, i = text[i+2:i+2+end], base.Inherit(mdBoldStyle), i+4+end
+			}
+		case text[i] == '`':
+			if end := strings.IndexByte(text[i+1:], '`'); end > 0 {
+				inner, style, i = text[i+1:i+1+end], mdCodeStyle, i+2+end
+			}
+		case (text[i] == '*' || text[i] == '_') && !isWordChar(text, i-1):
+			end := strings.IndexByte(text[i+1:], text[i])
+			if end > 0 && !isWordChar(text, i+2+end) && strings.TrimSpace(text[i+1:i+1+end]) == text[i+1:i+1+end] {
+				inner, style, i = text[i+1:i+1+end], base.Inherit(mdItalicStyle), i+2+end
+			}
+		case text[i] == '[':
+			if mid := strings.Index(text[i:], "]("); mid > 1 {
+				if end := strings.IndexByte(text[i+mid:], ')'); end > 2 {
+					add(text[plain:start], base)
+					addLink(text[i+1:i+mid], text[i+mid+2:i+mid+end])
+					i += mid + end + 1
+					plain = i
+					continue
+				}
+			}
+		case (strings.HasPrefix(text[i:], "http://") || strings.HasPrefix(text[i:], "https://")) && (i == 0 || text[i-1] == ' ' || text[i-1] == '('):
+			end := strings.IndexFunc(text[i:], unicode.IsSpace)
+			if end < 0 {
+				end = len(text) - i
+			}
+			url := strings.TrimRight(text[i:i+end], ".,;:!?)")
+			add(text[plain:start], base)
+			addLink(url, url)
+			i += len(url)
+			plain = i
+			continue
+		}
+		if i == start {
+			i++
+			continue
+		}
+		add(text[plain:start], base)
+		add(inner, style)
+		plain = i
+	}
+	add(text[plain:], base)
+	return words
+}
+
+// isWordChar reports whether the byte at i is a letter or digit, so
+// snake_case and 2*3*4 aren't taken for emphasis
+func isWordChar(s string, i int) bool {
+	if i < 0 || i >= len(s) {
+		return false
+	}
+	c := rune(s[i])
+	return unicode.IsLetter(c) || unicode.IsDigit(c)
+}
+
+// wrapWords lays out styled words in lines no wider than width, starting
+// the first line with first and the rest with rest
+func wrapWords(words []mdWord, width int, first, rest string) []string {
+	var lines []string
+	line, lineWidth := first, lipgloss.Width(first)
+	restWidth := lipgloss.Width(rest)
+	empty := true
+	for _, w := range words {
+		if !w.glued && w.width > width-restWidth {
+			// A word wider than a line, such as a long link, is broken
+			// over lines of its own
+			if !empty {
+				lines = append(lines, line)
+				line = rest
+			}
+			pieces := strings.Split(wrap.String(w.text, max(width-restWidth, 1)), "\n")
+			for _, piece := range pieces[:len(pieces)-1] {
+				lines = append(lines, line+piece)
+				line = rest
+			}
+			line += pieces[len(pieces)-1]
+			lineWidth = lipgloss.Width(line)
+			empty = false
+			continue
+		}
+
+		gap := 1
+		if w.glued || empty {
+			gap = 0
+		}
+		if !empty && !w.glued && lineWidth+gap+w.width > width {
+			lines = append(lines, line)
+			line, lineWidth, gap = rest, lipgloss.Width(rest), 0
+		}
+		line += strings.Repeat(" ", gap) + w.text
+		lineWidth += gap + w.width
+		empty = false
+	}
+	return append(lines, line)
+}
+
+// noteLinks lists the links in the open detail tab's notes: the contact's
+// notes on the Info tab, its interactions' on the Interactions tab, in the
+// order they are numbered
+func (m Model) noteLinks(c db.Contact) []string {
+	md := markdown{width: 80}
+	switch m.detailTab {
+	case detailTabInfo:
+		if c.Notes.Valid {
+			md.render(c.Notes.String)
+		}
+	case detailTabInteractions:
+		for _, log := range m.detailLogs(c) {
+			if log.Notes.Valid {
+				md.render(log.Notes.String)
+			}
+		}
+	}
+	return md.links
+}
+
+// openDetailLink opens the nth link in the open detail tab's notes
+func (m Model) openDetailLink(c db.Contact, n int) (tea.Model, tea.Cmd) {
+	links := m.noteLinks(c)
+	if n < 1 || n > len(links) {
+		return m, nil
+	}
+	m = m.setFlash(FlashInfo, fmt.Sprintf("Opening %s", links[n-1]))
+	return m, m.openExternal(links[n-1])
+}
+
+// detailLogs returns the contact's interactions, newest first, served from
+// the cache kept fresh by Update
+func (m Model) detailLogs(c db.Contact) []db.Log {
+	if m.detailContactID != c.ID {
+		interactions, _ := m.db.GetContactInteractions(c.ID, -1) // -1: no limit
+		return interactions
+	}
+	return m.detailInteractions
+}
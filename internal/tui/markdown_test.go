@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkdownRender(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		text  string
+		width int
+		want  []string
+		links []string
+	}{
+		{
+			name:  "bold, italic and code",
+			text:  "**bold** and *italic* and `code`",
+			width: 80,
+			want:  []string{"bold and italic and code"},
+		},
+		{
+			name:  "emphasis next to punctuation",
+			text:  "Met __Sam__, then _Alex_; said `go test`!",
+			width: 80,
+			want:  []string{"Met Sam, then Alex; said go test!"},
+		},
+		{
+			name:  "underscores and stars inside words",
+			text:  "see snake_case_name and 2*3*4",
+			width: 80,
+			want:  []string{"see snake_case_name and 2*3*4"},
+		},
+		{
+			name:  "unclosed emphasis",
+			text:  "a **dangling and a `tick",
+			width: 80,
+			want:  []string{"a **dangling and a `tick"},
+		},
+		{
+			name:  "heading and quote",
+			text:  "## Kids\n> Loves *chess*",
+			width: 80,
+			want:  []string{"Kids", "│ Loves chess"},
+		},
+		{
+			name:  "nested lists",
+			text:  "- one\n  - two\n    * three\n- four\n1. first\n   2) second",
+			width: 80,
+			want:  []string{"• one", "  • two", "    • three", "• four", "1. first", "   2) second"},
+		},
+		{
+			name:  "wrapped list items keep their indent",
+			text:  "- one two three four\n  - five six seven\n10. eight nine ten",
+			width: 12,
+			want:  []string{"• one two", "  three four", "  • five six", "    seven", "10. eight", "    nine ten"},
+		},
+		{
+			name:  "links with punctuation",
+			text:  "See [the site](https://example.com), then https://example.org/a.",
+			width: 80,
+			want:  []string{"See the site[1], then https://example.org/a[2]."},
+			links: []string{"https://example.com", "https://example.org/a"},
+		},
+		{
+			name:  "bare link in parentheses",
+			text:  "(https://example.com/x?y=1) and (see [docs](https://example.com/docs)).",
+			width: 80,
+			want:  []string{"(https://example.com/x?y=1[1]) and (see docs[2])."},
+			links: []string{"https://example.com/x?y=1", "https://example.com/docs"},
+		},
+		{
+			name:  "blank lines collapse",
+			text:  "\n\none\n\n\n\ntwo\n\n",
+			width: 80,
+			want:  []string{"one", "", "two"},
+		},
+		{
+			name:  "code block",
+			text:  "```\nfunc main() {}\n```\nafter",
+			width: 80,
+			want:  []string{"  func main() {}", "after"},
+		},
+		{
+			name:  "wrapping at a narrow width",
+			text:  "alpha beta gamma delta",
+			width: 10,
+			want:  []string{"alpha beta", "gamma", "delta"},
+		},
+		{
+			name:  "glued punctuation wraps with its word",
+			text:  "one two **three**, four",
+			width: 9,
+			want:  []string{"one two", "three,", "four"},
+		},
+		{
+			name:  "words wider than the line are broken",
+			text:  "see https://example.com/a/long/path now",
+			width: 12,
+			want:  []string{"see", "https://exam", "ple.com/a/lo", "ng/path[1]", "now"},
+			links: []string{"https://example.com/a/long/path"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			md := markdown{width: tt.width}
+			got := md.render(tt.text)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("render(%q) at width %d:\ngot  %q\nwant %q", tt.text, tt.width, got, tt.want)
+			}
+			if strings.Join(md.links, " ") != strings.Join(tt.links, " ") {
+				t.Errorf("links: got %q, want %q", md.links, tt.links)
+			}
+		})
+	}
+}
+
+func TestMarkdownNumbersLinksAcrossNotes(t *testing.T) {
+	md := markdown{width: 80}
+	md.render("[one](https://one.example)")
+	got := md.render("[two](https://two.example)")
+	if want := "two[2]"; len(got) != 1 || got[0] != want {
+		t.Errorf("second note: got %q, want %q", got, want)
+	}
+	if len(md.links) != 2 {
+		t.Errorf("links: got %q, want both notes' links", md.links)
+	}
+}
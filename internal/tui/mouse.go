@@ -0,0 +1,144 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Buttons of confirmation dialogs; clicking one presses y or Esc
+const (
+	yesButton = "[ Yes ]"
+	noButton  = "[ No ]"
+)
+
+// detailScrollStep is how many lines the wheel scrolls the detail pane
+const detailScrollStep = 3
+
+// ansiPattern matches the escape sequences styles add to rendered text
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;:]*[A-Za-z]")
+
+// confirmButtons renders the buttons of a confirmation dialog
+func confirmButtons() string {
+	return selectedStyle.Render(yesButton) + "   " + noButton
+}
+
+// buttonAt returns the dialog button drawn at column x of line y of a
+// rendered view, or ""
+func buttonAt(view string, x, y int) string {
+	lines := strings.Split(view, "\n")
+	if y < 0 || y >= len(lines) {
+		return ""
+	}
+	line := ansiPattern.ReplaceAllString(lines[y], "")
+	for _, button := range []string{yesButton, noButton} {
+		if i := strings.Index(line, button); i >= 0 {
+			start := lipgloss.Width(line[:i])
+			if x >= start && x < start+lipgloss.Width(button) {
+				return button
+			}
+		}
+	}
+	return ""
+}
+
+// listPaneWidth is the width of the contact list pane, borders included
+func (m Model) listPaneWidth() int {
+	return m.listWidth() + 2
+}
+
+// listRowAt returns the index in the filtered contacts of the list row at
+// line y of the screen
+func (m Model) listRowAt(y int) (int, bool) {
+	// Same layout as View and renderList: border, filter, header, separator
+	row := y - 1
+	if m.filterMode {
+		row -= 2
+	}
+	row -= 2
+	visibleHeight := m.listVisibleHeight()
+	if row < 0 || row >= visibleHeight {
+		return 0, false
+	}
+	startIdx := 0
+	if m.selected >= visibleHeight {
+		startIdx = m.selected - visibleHeight + 1
+	}
+	i := startIdx + row
+	if i >= len(m.filteredContacts()) {
+		return 0, false
+	}
+	return i, true
+}
+
+// scrollDetail scrolls the detail pane by delta lines, within the selected
+// contact's details
+func (m Model) scrollDetail(delta int) Model {
+	contacts := m.filteredContacts()
+	if len(contacts) == 0 || m.selected >= len(contacts) {
+		return m
+	}
+	id := contacts[m.selected].ID
+	if m.detailScrollID != id {
+		m.detailScrollID = id
+		m.detailScroll = 0
+	}
+	contentHeight := m.height - 4
+	lines := strings.Count(m.renderDetail(m.detailWidth(), contentHeight), "\n") + 1
+	m.detailScroll = min(max(m.detailScroll+delta, 0), max(lines-contentHeight, 0))
+	return m
+}
+
+// scrolledDetail drops the lines of the detail pane scrolled past
+func (m Model) scrolledDetail(detail string) string {
+	contacts := m.filteredContacts()
+	if m.detailScroll == 0 || len(contacts) == 0 || m.selected >= len(contacts) || contacts[m.selected].ID != m.detailScrollID {
+		return detail
+	}
+	lines := strings.Split(detail, "\n")
+	return strings.Join(lines[min(m.detailScroll, len(lines)):], "\n")
+}
+
+// updateMouse handles clicks and the scroll wheel: clicking a contact
+// selects it, the wheel moves through the list or scrolls the detail pane,
+// and clicking a dialog's Yes or No button answers it
+func (m Model) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if overlay := m.renderOverlay(); overlay != "" {
+		if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+			return m, nil
+		}
+		switch buttonAt(overlay, msg.X, msg.Y) {
+		case yesButton:
+			return m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+		case noButton:
+			return m.update(tea.KeyMsg{Type: tea.KeyEsc})
+		}
+		return m, nil
+	}
+
+	inList := msg.X < m.listPaneWidth()
+	if m.singlePane() {
+		inList = !m.detailOpen
+	}
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp && inList:
+		if m.selected > 0 {
+			m.selected--
+		}
+	case msg.Button == tea.MouseButtonWheelDown && inList:
+		if m.selected < len(m.filteredContacts())-1 {
+			m.selected++
+		}
+	case msg.Button == tea.MouseButtonWheelUp:
+		m = m.scrollDetail(-detailScrollStep)
+	case msg.Button == tea.MouseButtonWheelDown:
+		m = m.scrollDetail(detailScrollStep)
+	case msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress && inList:
+		if i, ok := m.listRowAt(msg.Y); ok {
+			m.selected = i
+		}
+	}
+	return m, nil
+}
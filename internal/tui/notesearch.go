@@ -0,0 +1,179 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// noteSearchMaxResults is the number of matches shown in the notes search
+const noteSearchMaxResults = 8
+
+// noteSearchLimit is the number of matches fetched, to scroll through
+const noteSearchLimit = 100
+
+// openNoteSearch enters the full-text search of contact and interaction
+// notes
+func (m Model) openNoteSearch() (Model, tea.Cmd) {
+	m.noteSearchMode = true
+	m.noteSearchSelected = 0
+	m.noteSearchResults = nil
+	m.noteSearchInput.Reset()
+	m.noteSearchInput.Focus()
+	return m, textinput.Blink
+}
+
+// runNoteSearch searches notes for what has been typed
+func (m Model) runNoteSearch() Model {
+	m.noteSearchSelected = 0
+	results, err := m.db.SearchNotes(m.noteSearchInput.Value(), noteSearchLimit)
+	if err != nil {
+		m.noteSearchResults = nil
+		return m.setFlash(FlashError, fmt.Sprintf("Search failed: %v", err))
+	}
+	m.noteSearchResults = results
+	return m
+}
+
+// updateNoteSearch handles key presses while the notes search is open
+func (m Model) updateNoteSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.noteSearchMode = false
+		m.noteSearchInput.Blur()
+		m.noteSearchResults = nil
+		return m, nil
+
+	case "enter":
+		m.noteSearchMode = false
+		m.noteSearchInput.Blur()
+		if m.noteSearchSelected < len(m.noteSearchResults) {
+			id := m.noteSearchResults[m.noteSearchSelected].ContactID
+			for _, c := range m.contacts {
+				if c.ID == id {
+					m = m.jumpTo(c)
+					break
+				}
+			}
+		}
+		m.noteSearchResults = nil
+		return m, nil
+
+	case "down", "ctrl+n":
+		if m.noteSearchSelected < len(m.noteSearchResults)-1 {
+			m.noteSearchSelected++
+		}
+		return m, nil
+
+	case "up", "ctrl+p":
+		if m.noteSearchSelected > 0 {
+			m.noteSearchSelected--
+		}
+		return m, nil
+	}
+
+	before := m.noteSearchInput.Value()
+	var cmd tea.Cmd
+	m.noteSearchInput, cmd = m.noteSearchInput.Update(msg)
+	if m.noteSearchInput.Value() != before {
+		m = m.runNoteSearch()
+	}
+	return m, cmd
+}
+
+// noteExcerpt returns up to width characters of notes on one line, starting
+// a little before the first word of the query found in them
+func noteExcerpt(notes, query string, width int) string {
+	text := []rune(strings.Join(strings.Fields(notes), " "))
+	lower := strings.ToLower(string(text))
+
+	start := 0
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if i := strings.Index(lower, word); i >= 0 {
+			start = len([]rune(lower[:i]))
+			break
+		}
+	}
+	start -= width / 4
+	if start < 0 || len(text) <= width {
+		start = 0
+	}
+	if start > len(text)-width && len(text) > width {
+		start = len(text) - width
+	}
+
+	end := min(start+width, len(text))
+	excerpt := string(text[start:end])
+	if start > 0 {
+		excerpt = "…" + excerpt
+	}
+	if end < len(text) {
+		excerpt += "…"
+	}
+	return excerpt
+}
+
+// describeNoteMatch describes where a match was found: the contact's notes,
+// or an interaction and when it happened
+func describeNoteMatch(r db.NoteMatch) string {
+	if r.InteractionID == 0 {
+		return "contact notes"
+	}
+	return fmt.Sprintf("%s %s", r.Type, r.Date.Local().Format("2006-01-02"))
+}
+
+// renderNoteSearch renders the notes search overlay
+func (m Model) renderNoteSearch() string {
+	query := m.noteSearchInput.Value()
+
+	var lines []string
+	lines = append(lines, "Search notes and interactions:")
+	lines = append(lines, "")
+	lines = append(lines, m.noteSearchInput.View())
+	lines = append(lines, "")
+
+	results := m.noteSearchResults
+	if strings.TrimSpace(query) != "" && len(results) == 0 {
+		lines = append(lines, labelStyle.Render("  No matching notes"))
+	}
+
+	// Scroll so the selected match stays in view
+	first := 0
+	if m.noteSearchSelected >= noteSearchMaxResults {
+		first = m.noteSearchSelected - noteSearchMaxResults + 1
+	}
+	last := min(first+noteSearchMaxResults, len(results))
+	for i := first; i < last; i++ {
+		r := results[i]
+		line := r.ContactName + labelStyle.Render(" · "+describeNoteMatch(r))
+		if i == m.noteSearchSelected {
+			line = selectedStyle.Render("▶ ") + line
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+		lines = append(lines, dimmedStyle.Render("    "+noteExcerpt(r.Notes, query, 64)))
+	}
+	if len(results) > noteSearchMaxResults {
+		lines = append(lines, labelStyle.Render(fmt.Sprintf("  %d of %d matches", m.noteSearchSelected+1, len(results))))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, "↑/↓: select • Enter: go to contact • Esc: cancel")
+
+	content := strings.Join(lines, "\n")
+	box := borderStyle.
+		Padding(1).
+		Width(76).
+		Render(content)
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(box)
+}
@@ -0,0 +1,233 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// paletteMaxResults is the number of commands shown in the palette at once
+const paletteMaxResults = 12
+
+// paletteCommand is an action listed in the command palette. Running it
+// presses its key in the contact list.
+type paletteCommand struct {
+	name string
+	key  string // As shown, e.g. "Ctrl+G"
+	msg  tea.KeyMsg
+}
+
+// runeKey is the key message for pressing a printable key
+func runeKey(r rune) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+}
+
+// paletteCommands lists every action of the contact list, leaving out the
+// ones the config doesn't enable
+func (m Model) paletteCommands() []paletteCommand {
+	command := func(name string, r rune) paletteCommand {
+		return paletteCommand{name: name, key: string(r), msg: runeKey(r)}
+	}
+
+	commands := []paletteCommand{
+		{name: "Jump to contact", key: "Ctrl+G", msg: tea.KeyMsg{Type: tea.KeyCtrlG}},
+		{name: "Search notes", key: "Ctrl+F", msg: tea.KeyMsg{Type: tea.KeyCtrlF}},
+		command("New contact", '+'),
+		command("Mark contacted", 'c'),
+		command("Bump contact", 'b'),
+		command("Edit contact", 'e'),
+		{name: "Edit a field inline", key: "Enter", msg: tea.KeyMsg{Type: tea.KeyEnter}},
+		command("Add note", 'n'),
+		command("Change state", 's'),
+		command("Change style", 'm'),
+		command("Copy contact as Markdown", 'y'),
+		command("Share contact as QR code", 'Q'),
+		command("Draft email", 'E'),
+		command("Call", 'l'),
+	}
+	if m.messagingCommand() != "" {
+		commands = append(commands, command("Send text message", 'T'))
+	}
+	commands = append(commands,
+		command("Next detail tab", ']'),
+		command("Previous detail tab", '['),
+		paletteCommand{name: "Widen contact list", key: "Ctrl+→", msg: tea.KeyMsg{Type: tea.KeyCtrlRight}},
+		paletteCommand{name: "Narrow contact list", key: "Ctrl+←", msg: tea.KeyMsg{Type: tea.KeyCtrlLeft}},
+		command("Interaction history", 'i'),
+		command("Tasks", 't'),
+		command("Important dates", 'd'),
+		command("Email addresses", '@'),
+		command("Phone numbers", '#'),
+		command("Social media handles", 'H'),
+		command("Linked contacts", 'K'),
+		command("Change history", 'h'),
+		command("Postal address", 'p'),
+		command("Journal", 'J'),
+		command("Groups", 'L'),
+		command("Agenda", 'U'),
+		command("Dashboard", 'w'),
+	)
+	if m.cfg != nil && m.cfg.External.NotesTUI {
+		commands = append(commands, command("Open notes", 'R'))
+	}
+	commands = append(commands,
+		command("Archive or unarchive contact", 'a'),
+		command("Mute or unmute reminders", 'M'),
+		command("Star or unstar contact", '*'),
+		command("Snooze contact", 'z'),
+	)
+	if m.deleteAction() == "delete" {
+		commands = append(commands, command("Delete contact", 'D'))
+	}
+	commands = append(commands,
+		command("Move contact to trash", 'X'),
+		command("Trash", 'Z'),
+		command("Undo", 'u'),
+		command("Purge archived contacts", 'P'),
+		command("Import contacts", 'I'),
+		command("Export CSV", 'x'),
+	)
+	if m.syncBackend != nil {
+		commands = append(commands, paletteCommand{name: "Sync now", key: "Ctrl+S", msg: tea.KeyMsg{Type: tea.KeyCtrlS}})
+	}
+	commands = append(commands,
+		paletteCommand{name: "Mark contact for bulk actions", key: "Space", msg: tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}},
+		command("Mark range from last marked", 'V'),
+		command("Search contacts", '/'),
+		command("Filter by type or tag", 'r'),
+		command("Filter: non-ok states", 'S'),
+		command("Filter: overdue", 'o'),
+		command("Filter: neglected", '!'),
+		command("Filter: incomplete profiles", '%'),
+		command("Filter: upcoming birthdays", 'B'),
+		command("Filter: starred", 'v'),
+		command("Cycle script filters", 'f'),
+		command("Show or hide archived", 'A'),
+		command("Clear filters", 'C'),
+		command("Sort contacts", 'O'),
+		command("Help", '?'),
+		command("Quit", 'q'),
+	)
+	return commands
+}
+
+// paletteResults returns the commands matching the palette's input, best
+// first; with no input, all of them in order
+func (m Model) paletteResults() []paletteCommand {
+	query := strings.TrimSpace(m.paletteInput.Value())
+	type result struct {
+		command paletteCommand
+		score   int
+	}
+	var results []result
+	for _, c := range m.paletteCommands() {
+		if score := fuzzyScore(query, c.name); score >= 0 {
+			results = append(results, result{command: c, score: score})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+
+	commands := make([]paletteCommand, len(results))
+	for i, r := range results {
+		commands[i] = r.command
+	}
+	return commands
+}
+
+// openPalette enters command palette mode
+func (m Model) openPalette() (Model, tea.Cmd) {
+	m.paletteMode = true
+	m.paletteSelected = 0
+	m.paletteInput.Reset()
+	m.paletteInput.Focus()
+	return m, textinput.Blink
+}
+
+// updatePalette handles key presses while the command palette is open
+func (m Model) updatePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.paletteMode = false
+		m.paletteInput.Blur()
+		return m, nil
+
+	case "enter":
+		results := m.paletteResults()
+		m.paletteMode = false
+		m.paletteInput.Blur()
+		if m.paletteSelected < len(results) {
+			return m.update(results[m.paletteSelected].msg)
+		}
+		return m, nil
+
+	case "down", "ctrl+n":
+		if m.paletteSelected < len(m.paletteResults())-1 {
+			m.paletteSelected++
+		}
+		return m, nil
+
+	case "up", "ctrl+p":
+		if m.paletteSelected > 0 {
+			m.paletteSelected--
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.paletteInput, cmd = m.paletteInput.Update(msg)
+	m.paletteSelected = 0
+	return m, cmd
+}
+
+// renderPalette renders the command palette overlay
+func (m Model) renderPalette() string {
+	const width = 60
+	var lines []string
+	lines = append(lines, "Run a command:")
+	lines = append(lines, "")
+	lines = append(lines, m.paletteInput.View())
+	lines = append(lines, "")
+
+	results := m.paletteResults()
+	if len(results) == 0 {
+		lines = append(lines, labelStyle.Render("  No matching commands"))
+	}
+	// Scroll to keep the selected command in view
+	start := 0
+	if m.paletteSelected >= paletteMaxResults {
+		start = m.paletteSelected - paletteMaxResults + 1
+	}
+	for i := start; i < len(results) && i < start+paletteMaxResults; i++ {
+		c := results[i]
+		name := "  " + c.name
+		if i == m.paletteSelected {
+			name = selectedStyle.Render("▶ " + c.name)
+		}
+		// Key hints line up on the right, inside the padding
+		gap := max(width-2-lipgloss.Width(name)-lipgloss.Width(c.key), 1)
+		lines = append(lines, name+strings.Repeat(" ", gap)+labelStyle.Render(c.key))
+	}
+	if len(results) > paletteMaxResults {
+		lines = append(lines, labelStyle.Render("  … ↑/↓ for more"))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, "↑/↓: select • Enter: run • Esc: cancel")
+
+	content := strings.Join(lines, "\n")
+	box := borderStyle.
+		Padding(1).
+		Width(width).
+		Render(content)
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(box)
+}
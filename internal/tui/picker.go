@@ -0,0 +1,292 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// pickerMaxResults is the number of matches shown in the jump picker
+const pickerMaxResults = 12
+
+// pickerResult is a contact matched by the jump picker
+type pickerResult struct {
+	contact db.Contact
+	score   int
+}
+
+// filterState captures the active list filters so they can be restored later
+type filterState struct {
+	text             string
+	typeFilter       string
+	tagFilter        string
+	groupFilter      string
+	stateFilter      bool
+	overdueFilter    bool
+	neglectedFilter  bool
+	incompleteFilter bool
+	birthdayFilter   bool
+	starredFilter    bool
+	scriptFilter     string
+	showArchived     bool
+}
+
+// currentFilters returns a snapshot of the active filters
+func (m Model) currentFilters() filterState {
+	return filterState{
+		text:             m.filter.Value(),
+		typeFilter:       m.typeFilter,
+		tagFilter:        m.tagFilter,
+		groupFilter:      m.groupFilter,
+		stateFilter:      m.stateFilter,
+		overdueFilter:    m.overdueFilter,
+		neglectedFilter:  m.neglectedFilter,
+		incompleteFilter: m.incompleteFilter,
+		birthdayFilter:   m.birthdayFilter,
+		starredFilter:    m.starredFilter,
+		scriptFilter:     m.scriptFilter,
+		showArchived:     m.showArchived,
+	}
+}
+
+// restoreFilters applies a previously captured filter snapshot
+func (m *Model) restoreFilters(f filterState) {
+	m.filter.SetValue(f.text)
+	m.typeFilter = f.typeFilter
+	m.tagFilter = f.tagFilter
+	m.groupFilter = f.groupFilter
+	m.stateFilter = f.stateFilter
+	m.overdueFilter = f.overdueFilter
+	m.neglectedFilter = f.neglectedFilter
+	m.incompleteFilter = f.incompleteFilter
+	m.birthdayFilter = f.birthdayFilter
+	m.starredFilter = f.starredFilter
+	m.scriptFilter = f.scriptFilter
+	m.showArchived = f.showArchived
+}
+
+// fuzzyScore scores how well pattern matches s as a case-insensitive
+// subsequence. It returns -1 when pattern does not match at all.
+// Consecutive characters and matches at word starts score higher.
+func fuzzyScore(pattern, s string) int {
+	if pattern == "" {
+		return 0
+	}
+
+	p := []rune(strings.ToLower(pattern))
+	r := []rune(strings.ToLower(s))
+
+	score := 0
+	pi := 0
+	lastMatch := -1
+	for i := 0; i < len(r) && pi < len(p); i++ {
+		if r[i] != p[pi] {
+			continue
+		}
+		score += 1
+		if lastMatch == i-1 {
+			score += 5 // Consecutive run
+		}
+		if i == 0 || !unicode.IsLetter(r[i-1]) {
+			score += 3 // Start of a word
+		}
+		if lastMatch >= 0 {
+			score -= i - lastMatch - 1 // Gap penalty
+		}
+		lastMatch = i
+		pi++
+	}
+
+	if pi < len(p) {
+		return -1
+	}
+	return score
+}
+
+// pickerResults returns the best fuzzy matches across all contacts,
+// ignoring any active filters
+func (m Model) pickerResults() []pickerResult {
+	return m.matchContacts(m.pickerInput.Value(), pickerMaxResults)
+}
+
+// matchContacts returns up to limit contacts best matching query by name,
+// label or company
+func (m Model) matchContacts(query string, limit int) []pickerResult {
+	query = strings.TrimSpace(query)
+
+	var results []pickerResult
+	for _, c := range m.contacts {
+		best := fuzzyScore(query, c.Name)
+		if c.Label.Valid {
+			if s := fuzzyScore(query, c.Label.String); s > best {
+				best = s
+			}
+		}
+		if c.Company.Valid {
+			// Company matches rank below name and label matches
+			if s := fuzzyScore(query, c.Company.String); s >= 0 && s/2 > best {
+				best = s / 2
+			}
+		}
+		if best < 0 {
+			continue
+		}
+		results = append(results, pickerResult{contact: c, score: best})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// openPicker enters jump picker mode
+func (m Model) openPicker() (Model, tea.Cmd) {
+	m.pickerMode = true
+	m.pickerSelected = 0
+	m.pickerInput.Reset()
+	m.pickerInput.Focus()
+	return m, textinput.Blink
+}
+
+// updatePicker handles key presses while the jump picker is open
+func (m Model) updatePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.pickerMode = false
+		m.pickerInput.Blur()
+		return m, nil
+
+	case "enter":
+		results := m.pickerResults()
+		m.pickerMode = false
+		m.pickerInput.Blur()
+		if m.pickerSelected < len(results) {
+			m = m.jumpTo(results[m.pickerSelected].contact)
+		}
+		return m, nil
+
+	case "down", "ctrl+n":
+		if m.pickerSelected < len(m.pickerResults())-1 {
+			m.pickerSelected++
+		}
+		return m, nil
+
+	case "up", "ctrl+p":
+		if m.pickerSelected > 0 {
+			m.pickerSelected--
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.pickerInput, cmd = m.pickerInput.Update(msg)
+	m.pickerSelected = 0
+	return m, cmd
+}
+
+// jumpTo selects the given contact. If the active filters hide it, they are
+// stashed and cleared so the contact can be shown; Esc restores them.
+func (m Model) jumpTo(contact db.Contact) Model {
+	for i, c := range m.filteredContacts() {
+		if c.ID == contact.ID {
+			m.selected = i
+			return m
+		}
+	}
+
+	if m.stashedFilters == nil {
+		stashed := m.currentFilters()
+		m.stashedFilters = &stashed
+	}
+	m.restoreFilters(filterState{showArchived: contact.Archived})
+
+	for i, c := range m.filteredContacts() {
+		if c.ID == contact.ID {
+			m.selected = i
+			break
+		}
+	}
+
+	return m.setFlash(FlashInfo, fmt.Sprintf("Filters suspended to show %s • Esc: restore filters", contact.Name))
+}
+
+// restoreStashedFilters brings back filters suspended by a jump, keeping the
+// current contact selected if it is still visible
+func (m Model) restoreStashedFilters() Model {
+	var currentID int
+	contacts := m.filteredContacts()
+	if m.selected < len(contacts) {
+		currentID = contacts[m.selected].ID
+	}
+
+	m.restoreFilters(*m.stashedFilters)
+	m.stashedFilters = nil
+
+	for i, c := range m.filteredContacts() {
+		if c.ID == currentID {
+			m.selected = i
+			return m
+		}
+	}
+	m.selected = m.ensureValidSelection()
+	return m
+}
+
+// renderPicker renders the jump picker overlay
+func (m Model) renderPicker() string {
+	var lines []string
+	lines = append(lines, "Jump to contact:")
+	lines = append(lines, "")
+	lines = append(lines, m.pickerInput.View())
+	lines = append(lines, "")
+
+	results := m.pickerResults()
+	if len(results) == 0 {
+		lines = append(lines, labelStyle.Render("  No matching contacts"))
+	}
+	for i, r := range results {
+		line := r.contact.Name
+		if r.contact.Label.Valid {
+			line += " " + labelStyle.Render("["+r.contact.Label.String+"]")
+		}
+		if r.contact.Company.Valid {
+			line += labelStyle.Render(" · " + r.contact.Company.String)
+		}
+		if r.contact.Archived {
+			line = dimmedStyle.Render("[ARCH] ") + line
+		}
+
+		if i == m.pickerSelected {
+			line = selectedStyle.Render("▶ ") + line
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, "↑/↓: select • Enter: jump • Esc: cancel")
+
+	content := strings.Join(lines, "\n")
+	box := borderStyle.
+		Padding(1).
+		Width(60).
+		Render(content)
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(box)
+}
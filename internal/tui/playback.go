@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keyTypes maps key names as bubbletea prints them ("enter", "ctrl+s",
+// "shift+tab") back to key types
+var keyTypes = func() map[string]tea.KeyType {
+	types := make(map[string]tea.KeyType)
+	for k := tea.KeyType(-100); k < 128; k++ {
+		if name := k.String(); name != "" && name != "runes" {
+			types[name] = k
+		}
+	}
+	types["space"] = tea.KeySpace
+	return types
+}()
+
+// ParseKey turns a key name into a key message. Names are those bubbletea
+// uses, such as "enter", "esc", "tab", "ctrl+s" or "up", plus "space";
+// "alt+" may prefix any key, and anything else is typed as text.
+func ParseKey(name string) (tea.KeyMsg, error) {
+	if name == "" {
+		return tea.KeyMsg{}, fmt.Errorf("empty key name")
+	}
+	alt := false
+	if strings.HasPrefix(name, "alt+") && len(name) > len("alt+") {
+		alt = true
+		name = strings.TrimPrefix(name, "alt+")
+	}
+	if k, ok := keyTypes[name]; ok {
+		msg := tea.KeyMsg{Type: k, Alt: alt}
+		if k == tea.KeySpace {
+			msg.Runes = []rune{' '}
+		}
+		return msg, nil
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(name), Alt: alt}, nil
+}
+
+// Frame is the screen rendered after a key was handled
+type Frame struct {
+	Key  string // "" for the frame before any key
+	View string
+}
+
+// Playback feeds a sequence of keys to the model at the given screen size
+// and returns the rendered frame after each one, starting with the initial
+// screen. Commands returned by Update (background work such as syncing,
+// imports and cursor blinking) are not run, so each frame shows the state
+// right after its key was handled; quitting keys do not end playback. m is
+// left in the state after the last key, so playback can go on in steps.
+func Playback(m *Model, keys []string, width, height int) ([]Frame, error) {
+	msgs := make([]tea.KeyMsg, len(keys))
+	for i, key := range keys {
+		msg, err := ParseKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %d: %w", i+1, err)
+		}
+		msgs[i] = msg
+	}
+
+	var model tea.Model = *m
+	model, _ = model.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	frames := []Frame{{View: model.View()}}
+	for i, msg := range msgs {
+		model, _ = model.Update(msg)
+		frames = append(frames, Frame{Key: keys[i], View: model.View()})
+	}
+	*m = model.(Model)
+	return frames, nil
+}
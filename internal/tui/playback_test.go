@@ -0,0 +1,151 @@
+package tui_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/tui"
+)
+
+// newFixtureModel opens a fresh fixtures database with a config that
+// touches nothing outside the test's directories
+func newFixtureModel(t *testing.T) (*tui.Model, *db.DB) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+
+	path := filepath.Join(dir, "contacts.db")
+	if err := db.CreateFixturesDatabase(path); err != nil {
+		t.Fatalf("creating fixtures: %v", err)
+	}
+	database, err := db.Open(path)
+	if err != nil {
+		t.Fatalf("opening fixtures: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	cfg := config.Default()
+	cfg.Database.Path = path
+	cfg.Tasks.Backend = "noop"
+	cfg.UI.FollowUpAlerts = false
+	cfg.Retention.DeletedDir = filepath.Join(dir, "deleted")
+	cfg.UI.ExportDir = dir
+	config.SetWritePath(filepath.Join(dir, "config.toml"))
+	t.Cleanup(func() { config.SetWritePath("") })
+
+	model, err := tui.New(database, cfg)
+	if err != nil {
+		t.Fatalf("creating model: %v", err)
+	}
+	return model, database
+}
+
+// play replays keys on a 100x30 screen and returns the frames
+func play(t *testing.T, model *tui.Model, keys ...string) []tui.Frame {
+	t.Helper()
+	frames, err := tui.Playback(model, keys, 100, 30)
+	if err != nil {
+		t.Fatalf("playing %q: %v", keys, err)
+	}
+	if len(frames) != len(keys)+1 {
+		t.Fatalf("got %d frames for %d keys", len(frames), len(keys))
+	}
+	return frames
+}
+
+// assertShows fails unless the frame contains every one of want
+func assertShows(t *testing.T, frame tui.Frame, want ...string) {
+	t.Helper()
+	for _, w := range want {
+		if !strings.Contains(frame.View, w) {
+			t.Errorf("after %q the screen doesn't show %q:\n%s", frame.Key, w, frame.View)
+		}
+	}
+}
+
+func TestPlaybackFilter(t *testing.T) {
+	model, _ := newFixtureModel(t)
+	frames := play(t, model, "/", "sarah", "enter")
+
+	assertShows(t, frames[0], "Alex Thompson", "Sarah Chen")
+	last := frames[len(frames)-1]
+	assertShows(t, last, "Contacts (1)", "Sarah Chen")
+	if strings.Contains(last.View, "Alex Thompson") {
+		t.Errorf("filtering for sarah still lists Alex Thompson:\n%s", last.View)
+	}
+}
+
+func TestPlaybackStateChangeAndUndo(t *testing.T) {
+	model, database := newFixtureModel(t)
+	frames := play(t, model, "s", "t", "u")
+
+	assertShows(t, frames[0], "State: followup")
+	assertShows(t, frames[1], "Set state for Alex Thompson:", "[t]imeout")
+	assertShows(t, frames[2], "State: timeout", "Updated Alex Thompson state to timeout")
+	assertShows(t, frames[3], "State: followup", "Undid state change of Alex Thompson")
+
+	contact, err := database.GetContactByLabel("@alext")
+	if err != nil {
+		t.Fatalf("reading contact: %v", err)
+	}
+	if contact.State.String != "followup" {
+		t.Errorf("state after undo is %q in the database, want followup", contact.State.String)
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"enter", "enter"},
+		{"esc", "esc"},
+		{"ctrl+s", "ctrl+s"},
+		{"space", " "},
+		{"alt+j", "alt+j"},
+		{"sarah", "sarah"},
+	}
+	for _, tt := range tests {
+		msg, err := tui.ParseKey(tt.name)
+		if err != nil {
+			t.Errorf("ParseKey(%q): %v", tt.name, err)
+			continue
+		}
+		if got := msg.String(); got != tt.want {
+			t.Errorf("ParseKey(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if _, err := tui.ParseKey(""); err == nil {
+		t.Error("ParseKey accepted an empty key name")
+	}
+}
+
+func TestGoToOpensContact(t *testing.T) {
+	model, _ := newFixtureModel(t)
+	if err := model.GoTo("@sarahc"); err != nil {
+		t.Fatalf("going to @sarahc: %v", err)
+	}
+	// A narrow window shows one pane; it should be Sarah's details
+	frames, err := tui.Playback(model, nil, 80, 30)
+	if err != nil {
+		t.Fatalf("playing: %v", err)
+	}
+	assertShows(t, frames[0], "Sarah Chen", "Esc: back to list")
+}
+
+func TestGoToPastStartupOverlaysAndFilters(t *testing.T) {
+	model, _ := newFixtureModel(t)
+	// Hide Sarah behind a filter, then open the dashboard as at startup
+	frames := play(t, model, "/", "alex", "enter", "w")
+	if last := frames[len(frames)-1]; strings.Contains(last.View, "Sarah Chen") {
+		t.Fatalf("Sarah Chen isn't hidden to begin with:\n%s", last.View)
+	}
+	if err := model.GoTo("@sarahc"); err != nil {
+		t.Fatalf("going to @sarahc: %v", err)
+	}
+	frames = play(t, model)
+	assertShows(t, frames[0], "Sarah Chen", "Filters suspended to show Sarah Chen")
+}
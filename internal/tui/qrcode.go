@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"rsc.io/qr"
+)
+
+// qrQuietZone is the light border, in modules, scanners need around a code
+const qrQuietZone = 2
+
+// qrStyle draws codes dark on light whatever the terminal's colors, since
+// scanners can't read inverted codes
+var qrStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#000000")).Background(lipgloss.Color("#ffffff"))
+
+// openQRCode shows the contact's card as a QR code to scan with a phone
+func (m Model) openQRCode(contact db.Contact) Model {
+	code, err := encodeQR(db.ShareVCard(contact))
+	if err != nil {
+		m.err = fmt.Errorf("sharing %s: %w", contact.Name, err)
+		return m
+	}
+	m.qrMode = true
+	m.qrName = contact.Name
+	m.qrLines = qrLines(code, qrQuietZone)
+	return m
+}
+
+// encodeQR encodes text in the smallest QR code that holds it, with medium
+// error correction when that fits in the same size
+func encodeQR(text string) (*qr.Code, error) {
+	code, err := qr.Encode(text, qr.L)
+	if err != nil {
+		return nil, err
+	}
+	if medium, err := qr.Encode(text, qr.M); err == nil && medium.Size == code.Size {
+		code = medium
+	}
+	return code, nil
+}
+
+// qrLines draws a code with half blocks, two rows of modules to a line,
+// inside a quiet zone of light modules. Blocks are dark modules, so the
+// lines must be shown in a dark color on a light background.
+func qrLines(code *qr.Code, quiet int) []string {
+	var lines []string
+	for y := -quiet; y < code.Size+quiet; y += 2 {
+		var b strings.Builder
+		for x := -quiet; x < code.Size+quiet; x++ {
+			top, bottom := code.Black(x, y), code.Black(x, y+1)
+			switch {
+			case top && bottom:
+				b.WriteString("█")
+			case top:
+				b.WriteString("▀")
+			case bottom:
+				b.WriteString("▄")
+			default:
+				b.WriteString(" ")
+			}
+		}
+		lines = append(lines, b.String())
+	}
+	return lines
+}
+
+// updateQRCode handles keys for the QR code overlay
+func (m Model) updateQRCode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "Q", "enter":
+		m.qrMode = false
+		m.qrLines = nil
+	}
+	return m, nil
+}
+
+// renderQRCode renders the QR code overlay
+func (m Model) renderQRCode() string {
+	var lines []string
+	lines = append(lines, "Scan to add "+m.qrName)
+	lines = append(lines, "")
+
+	width := lipgloss.Width(m.qrLines[0])
+	if width+4 > m.width || len(m.qrLines)+8 > m.height {
+		lines = append(lines, fmt.Sprintf("Make the terminal at least %d×%d to show the code", width+4, len(m.qrLines)+8))
+	} else {
+		for _, line := range m.qrLines {
+			lines = append(lines, qrStyle.Render(line))
+		}
+	}
+	lines = append(lines, "")
+	lines = append(lines, labelStyle.Render("Name, phone, email and company only"))
+	lines = append(lines, "Esc: close")
+
+	// A style of its own, so the box fits the code
+	box := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(theme.Border).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(box)
+}
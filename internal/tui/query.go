@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// queryFields match a contact against the value of a search term, one for
+// each of db.SearchFields. Yes/no fields ignore the value, which the parser
+// has folded into the term's Negate.
+var queryFields = map[string]func(m *Model, c db.Contact, value string) bool{
+	"name":    func(m *Model, c db.Contact, v string) bool { return containsFold(c.Name, v) },
+	"label":   func(m *Model, c db.Contact, v string) bool { return containsFold(c.Label.String, v) },
+	"company": func(m *Model, c db.Contact, v string) bool { return containsFold(c.Company.String, v) },
+	"email":   func(m *Model, c db.Contact, v string) bool { return containsFold(c.Email.String, v) },
+	"phone":   func(m *Model, c db.Contact, v string) bool { return containsFold(c.Phone.String, v) },
+	"notes":   func(m *Model, c db.Contact, v string) bool { return containsFold(c.Notes.String, v) },
+	"state": func(m *Model, c db.Contact, v string) bool {
+		state := "ok"
+		if c.State.Valid && c.State.String != "" {
+			state = c.State.String
+		}
+		return hasPrefixFold(state, v)
+	},
+	"type":  func(m *Model, c db.Contact, v string) bool { return hasPrefixFold(c.RelationshipType, v) },
+	"style": func(m *Model, c db.Contact, v string) bool { return hasPrefixFold(c.ContactStyle, v) },
+	"group": func(m *Model, c db.Contact, v string) bool {
+		for _, group := range c.Groups {
+			if hasPrefixFold(group, v) {
+				return true
+			}
+		}
+		return false
+	},
+	"tag":     func(m *Model, c db.Contact, v string) bool { return hasTags(&c, []string{v}) },
+	"overdue": func(m *Model, c db.Contact, v string) bool { return m.needsAttention(c) },
+	"starred": func(m *Model, c db.Contact, v string) bool { return c.Starred },
+	"waiting": func(m *Model, c db.Contact, v string) bool { return c.WaitingSince.Valid },
+	"muted":   func(m *Model, c db.Contact, v string) bool { return c.RemindersMuted },
+	"snoozed": func(m *Model, c db.Contact, v string) bool { return c.IsSnoozed() },
+}
+
+// matchesTerms reports whether a contact matches every field:value term
+func (m *Model) matchesTerms(c db.Contact, terms []db.SearchTerm) bool {
+	for _, term := range terms {
+		if queryFields[term.Field](m, c, term.Value) == term.Negate {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesWords reports whether a contact's search text contains every word
+func matchesWords(searchText string, words []string) bool {
+	for _, word := range words {
+		if !strings.Contains(searchText, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsFold reports whether s contains the lowercased substr, ignoring
+// case
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), substr)
+}
+
+// hasPrefixFold reports whether s starts with the lowercased prefix,
+// ignoring case
+func hasPrefixFold(s, prefix string) bool {
+	return strings.HasPrefix(strings.ToLower(s), prefix)
+}
@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+func TestQueryFieldsCoverSearchFields(t *testing.T) {
+	for _, field := range db.SearchFields() {
+		if queryFields[field] == nil {
+			t.Errorf("the filter can't match %s: terms", field)
+		}
+	}
+}
+
+// TestFilterMatchesDatabaseSearch checks that the filter lists the same
+// contacts as a FilterContacts search for the same text
+func TestFilterMatchesDatabaseSearch(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	path := filepath.Join(dir, "contacts.db")
+	if err := db.CreateFixturesDatabase(path); err != nil {
+		t.Fatalf("creating fixtures: %v", err)
+	}
+	database, err := db.Open(path)
+	if err != nil {
+		t.Fatalf("opening fixtures: %v", err)
+	}
+	defer database.Close()
+
+	// Tags and a location to search for, none of which the fixtures have
+	sarah, err := database.GetContactByLabel("@sarahc")
+	if err != nil {
+		t.Fatalf("reading contact: %v", err)
+	}
+	sarah.Location = db.NewNullString("Portland, OR")
+	if err := database.UpdateContact(*sarah); err != nil {
+		t.Fatalf("updating contact: %v", err)
+	}
+	if err := database.SetContactTags(sarah.ID, []string{"Mentor", "École"}); err != nil {
+		t.Fatalf("tagging contact: %v", err)
+	}
+
+	cfg := config.Default()
+	cfg.Database.Path = path
+	cfg.Tasks.Backend = "noop"
+	cfg.UI.FollowUpAlerts = false
+	model, err := New(database, cfg)
+	if err != nil {
+		t.Fatalf("creating model: %v", err)
+	}
+
+	for _, search := range []string{
+		"sarah",
+		"SARAH chen",
+		"tech",
+		"state:ping",
+		"-type:work",
+		`company:"ai startup"`,
+		"type:fam starred:no",
+		"waiting:no muted:no snoozed:no",
+		"#ment",
+		"tag:ment",
+		"loc:portland",
+		"ÉCOLE",
+		"notes:SUNDAY",
+		"nobody-matches-this",
+	} {
+		m := *model
+		m.filter.SetValue(search)
+		var got []int
+		for _, c := range m.computeFilteredContacts() {
+			got = append(got, c.ID)
+		}
+
+		contacts, err := database.FilterContacts(db.ContactFilter{Text: search})
+		if err != nil {
+			t.Errorf("searching for %q: %v", search, err)
+			continue
+		}
+		var want []int
+		for _, c := range contacts {
+			want = append(want, c.ID)
+		}
+
+		sort.Ints(got)
+		sort.Ints(want)
+		if len(got) != len(want) {
+			t.Errorf("%q: the filter lists contacts %v, the database %v", search, got, want)
+			continue
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Errorf("%q: the filter lists contacts %v, the database %v", search, got, want)
+				break
+			}
+		}
+	}
+}
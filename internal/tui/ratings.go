@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// formatRating renders a 1-5 energy rating as stars
+func formatRating(rating int) string {
+	if rating < 0 {
+		rating = 0
+	}
+	if rating > 5 {
+		rating = 5
+	}
+	return strings.Repeat("★", rating) + strings.Repeat("☆", 5-rating)
+}
+
+// ratingTone describes an average rating
+func ratingTone(avg float64) string {
+	switch {
+	case avg >= 3.5:
+		return "energizing"
+	case avg <= 2.5:
+		return "draining"
+	default:
+		return "neutral"
+	}
+}
+
+// describeRatings summarizes a contact's energy ratings, comparing the last
+// 90 days with the overall average when both are available
+func describeRatings(s db.RatingSummary) string {
+	text := fmt.Sprintf("Energy: %.1f/5 %s over %d rated", s.Average, ratingTone(s.Average), s.Count)
+	if s.RecentCount > 0 && s.RecentCount < s.Count {
+		trend := "steady"
+		switch {
+		case s.RecentAverage-s.Average >= 0.5:
+			trend = "rising"
+		case s.Average-s.RecentAverage >= 0.5:
+			trend = "falling"
+		}
+		text += fmt.Sprintf(" (recently %.1f, %s)", s.RecentAverage, trend)
+	}
+	return text
+}
@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/scripting"
+)
+
+// cycleScriptFilter moves to the next script filter, then back to none
+func (m Model) cycleScriptFilter() Model {
+	names := m.scripts.FilterNames()
+	if len(names) == 0 {
+		return m.setFlash(FlashInfo, "No script filters • define them under [filters] in scripts.toml")
+	}
+
+	next := names[0]
+	for i, name := range names {
+		if name == m.scriptFilter {
+			next = ""
+			if i+1 < len(names) {
+				next = names[i+1]
+			}
+			break
+		}
+	}
+	m.scriptFilter = next
+	return m
+}
+
+// sortByScore orders contacts by the script score, highest first, keeping
+// the existing order for equal scores
+func sortByScore(contacts []db.Contact, scripts *scripting.Engine) {
+	scores := make(map[int]float64, len(contacts))
+	for _, c := range contacts {
+		scores[c.ID] = scripts.Score(c)
+	}
+	sort.SliceStable(contacts, func(i, j int) bool {
+		return scores[contacts[i].ID] > scores[contacts[j].ID]
+	})
+}
+
+// runAutomations runs the script automations for an event on a contact,
+// adding their messages to the status bar
+func (m Model) runAutomations(event string, contactID int) Model {
+	if m.scripts == nil {
+		return m
+	}
+
+	messages, err := m.scripts.Run(event, m.db, m.taskManager.Backend(), contactID)
+	m = m.reloadContacts()
+	if err != nil {
+		m.err = fmt.Errorf("script automation: %w", err)
+		return m
+	}
+	if len(messages) > 0 {
+		if m.flashMessage != "" {
+			messages = append([]string{m.flashMessage}, messages...)
+		}
+		m = m.setFlash(FlashSuccess, strings.Join(messages, " • "))
+	}
+	return m
+}
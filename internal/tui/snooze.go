@@ -0,0 +1,169 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// snoozeOption is a choice in the snooze overlay
+type snoozeOption struct {
+	key   string
+	label string
+	days  int // 0 for the custom date and unsnooze choices
+}
+
+// snoozeOptions lists the snooze choices; unsnooze only shows for snoozed
+// contacts
+var snoozeOptions = []snoozeOption{
+	{"w", "1 week", 7},
+	{"m", "1 month", 30},
+	{"c", "Custom date…", 0},
+	{"u", "Unsnooze", 0},
+}
+
+// openSnooze shows the snooze choices for a contact
+func (m Model) openSnooze(contact db.Contact) Model {
+	m.snoozeMode = true
+	m.snoozeContact = contact
+	m.snoozeSelected = 0
+	m.snoozeCustom = false
+	return m
+}
+
+// snoozeChoices returns the options that apply to the contact being snoozed
+func (m Model) snoozeChoices() []snoozeOption {
+	if m.snoozeContact.IsSnoozed() {
+		return snoozeOptions
+	}
+	return snoozeOptions[:len(snoozeOptions)-1]
+}
+
+// snooze keeps the contact out of the overdue view until a date, or ends
+// its snooze for the zero time, and reloads the list
+func (m Model) snooze(until time.Time) Model {
+	if !until.IsZero() && !until.After(time.Now()) {
+		m.err = fmt.Errorf("snooze date %s has already passed", until.Format("2006-01-02"))
+		return m
+	}
+	contact := m.snoozeContact
+	m.snoozeMode = false
+	m.snoozeCustom = false
+	m.snoozeInput.Blur()
+	if err := m.db.SnoozeContact(contact.ID, until); err != nil {
+		m.err = err
+		return m
+	}
+	m = m.reloadContacts()
+	if until.IsZero() {
+		return m.setFlash(FlashSuccess, fmt.Sprintf("✓ %s is no longer snoozed", contact.Name))
+	}
+	return m.setFlash(FlashSuccess, fmt.Sprintf("✓ Snoozed %s until %s", contact.Name, until.Format("Jan 2")))
+}
+
+// chooseSnooze acts on a snooze option
+func (m Model) chooseSnooze(option snoozeOption) (Model, tea.Cmd) {
+	switch {
+	case option.days > 0:
+		return m.snooze(startOfDay(time.Now()).AddDate(0, 0, option.days)), nil
+	case option.key == "c":
+		m.snoozeCustom = true
+		m.snoozeInput.SetValue("")
+		m.snoozeInput.Focus()
+		return m, textinput.Blink
+	default:
+		return m.snooze(time.Time{}), nil
+	}
+}
+
+// updateSnooze handles keys for the snooze overlay
+func (m Model) updateSnooze(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.snoozeCustom {
+		switch msg.String() {
+		case "esc":
+			m.snoozeCustom = false
+			m.snoozeInput.Blur()
+			return m, nil
+		case "enter":
+			until, err := parseDateInput(m.snoozeInput.Value())
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
+			return m.snooze(until), nil
+		}
+		var cmd tea.Cmd
+		m.snoozeInput, cmd = m.snoozeInput.Update(msg)
+		return m, cmd
+	}
+
+	choices := m.snoozeChoices()
+	switch key := msg.String(); key {
+	case "esc", "q", "z":
+		m.snoozeMode = false
+	case "j", "down":
+		if m.snoozeSelected < len(choices)-1 {
+			m.snoozeSelected++
+		}
+	case "k", "up":
+		if m.snoozeSelected > 0 {
+			m.snoozeSelected--
+		}
+	case "enter":
+		return m.chooseSnooze(choices[m.snoozeSelected])
+	default:
+		for _, option := range choices {
+			if option.key == key {
+				return m.chooseSnooze(option)
+			}
+		}
+	}
+	return m, nil
+}
+
+// renderSnooze renders the snooze overlay
+func (m Model) renderSnooze() string {
+	lines := []string{"Snooze " + m.snoozeContact.Name, ""}
+	if m.snoozeContact.IsSnoozed() {
+		lines = append(lines, labelStyle.Render("Snoozed until "+m.snoozeContact.SnoozeUntil.Time.Local().Format("Mon Jan 2, 2006")), "")
+	}
+
+	if m.snoozeCustom {
+		lines = append(lines, "Snooze until:")
+		lines = append(lines, m.snoozeInput.View())
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render("YYYY-MM-DD, or a length of time such as 10d, 3w or 2m"))
+		lines = append(lines, "Enter: snooze • Esc: back")
+	} else {
+		for i, option := range m.snoozeChoices() {
+			line := fmt.Sprintf("[%s] %s", option.key, option.label)
+			if option.days > 0 {
+				line += labelStyle.Render(" (until " + startOfDay(time.Now()).AddDate(0, 0, option.days).Format("Jan 2") + ")")
+			}
+			if i == m.snoozeSelected {
+				lines = append(lines, selectedStyle.Render("▶ ")+line)
+			} else {
+				lines = append(lines, "  "+line)
+			}
+		}
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render("Snoozed contacts aren't overdue until the date"))
+		lines = append(lines, "j/k: select • Enter: choose • Esc: cancel")
+	}
+
+	box := borderStyle.
+		Padding(1).
+		Width(56).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(box)
+}
@@ -0,0 +1,175 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// sortOrder is an order the contact list can be sorted in
+type sortOrder struct {
+	key   string
+	name  string // As written in the config
+	label string
+	short string // Shown in the list header
+}
+
+// sortOrders lists the orders the O menu offers
+var sortOrders = []sortOrder{
+	{"n", "name", "Name", "name"},
+	{"l", "last_contacted", "Last contacted, longest ago first", "contacted"},
+	{"o", "overdue", "Most overdue first", "overdue"},
+	{"r", "recent", "Recently added first", "added"},
+	{"s", "state", "State, in the order of the state menu", "state"},
+}
+
+// currentSort returns the order the list is sorted in; unknown orders in
+// the config sort by name
+func (m Model) currentSort() sortOrder {
+	for _, order := range sortOrders {
+		if order.name == m.sortOrder {
+			return order
+		}
+	}
+	return sortOrders[0]
+}
+
+// sortContacts sorts contacts in an order. Contacts that tie keep their
+// order, which is by name.
+func sortContacts(contacts []db.Contact, order string) {
+	switch order {
+	case "last_contacted":
+		// Never contacted first: they are the longest ago of all
+		sort.SliceStable(contacts, func(i, j int) bool {
+			a, b := contacts[i].LastInteraction(), contacts[j].LastInteraction()
+			if a.Valid != b.Valid {
+				return !a.Valid
+			}
+			return a.Valid && a.Time.Before(b.Time)
+		})
+	case "overdue":
+		sort.SliceStable(contacts, func(i, j int) bool {
+			return contacts[i].OverdueRatio() > contacts[j].OverdueRatio()
+		})
+	case "recent":
+		sort.SliceStable(contacts, func(i, j int) bool {
+			return contacts[i].CreatedAt.After(contacts[j].CreatedAt)
+		})
+	case "state":
+		rank := make(map[string]int, len(ContactStates))
+		for i, state := range ContactStates {
+			rank[state] = i
+		}
+		stateRank := func(c db.Contact) int {
+			if r, ok := rank[c.State.String]; ok && c.State.Valid {
+				return r
+			}
+			return len(ContactStates)
+		}
+		sort.SliceStable(contacts, func(i, j int) bool {
+			return stateRank(contacts[i]) < stateRank(contacts[j])
+		})
+	}
+}
+
+// openSort shows the sort orders, with the current one selected
+func (m Model) openSort() Model {
+	m.sortMode = true
+	m.sortSelected = 0
+	for i, order := range sortOrders {
+		if order.name == m.currentSort().name {
+			m.sortSelected = i
+		}
+	}
+	return m
+}
+
+// chooseSort sorts the list in an order and saves it in the config, keeping
+// the selected contact selected
+func (m Model) chooseSort(order sortOrder) Model {
+	m.sortMode = false
+	var currentID int
+	if contacts := m.filteredContacts(); m.selected < len(contacts) {
+		currentID = contacts[m.selected].ID
+	}
+
+	m.sortOrder = order.name
+	for i, c := range m.filteredContacts() {
+		if c.ID == currentID {
+			m.selected = i
+			break
+		}
+	}
+
+	if m.cfg != nil {
+		m.cfg.UI.Sort = order.name
+		if err := config.SetValue("ui", "sort", order.name); err != nil {
+			return m.setFlash(FlashError, fmt.Sprintf("Sorted by %s, but couldn't save it: %v", strings.ToLower(order.label), err))
+		}
+	}
+	return m.setFlash(FlashSuccess, "✓ Sorted by "+strings.ToLower(order.label))
+}
+
+// updateSort handles keys for the sort overlay
+func (m Model) updateSort(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key := msg.String(); key {
+	case "esc", "q", "O":
+		m.sortMode = false
+	case "j", "down":
+		if m.sortSelected < len(sortOrders)-1 {
+			m.sortSelected++
+		}
+	case "k", "up":
+		if m.sortSelected > 0 {
+			m.sortSelected--
+		}
+	case "enter":
+		return m.chooseSort(sortOrders[m.sortSelected]), nil
+	default:
+		for _, order := range sortOrders {
+			if order.key == key {
+				return m.chooseSort(order), nil
+			}
+		}
+	}
+	return m, nil
+}
+
+// renderSort renders the sort overlay
+func (m Model) renderSort() string {
+	lines := []string{"Sort contacts by", ""}
+	for i, order := range sortOrders {
+		line := fmt.Sprintf("[%s] %s", order.key, order.label)
+		if order.name == m.currentSort().name {
+			line += labelStyle.Render(" (current)")
+		}
+		if i == m.sortSelected {
+			lines = append(lines, selectedStyle.Render("▶ ")+line)
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	lines = append(lines, "")
+	if m.birthdayFilter || m.overdueFilter || m.scripts.HasScore() {
+		lines = append(lines, labelStyle.Render("The active filter or score script sorts the list for now"))
+	} else if m.starredFirst() {
+		lines = append(lines, labelStyle.Render("Starred contacts stay at the top"))
+	}
+	lines = append(lines, "j/k: select • Enter: sort • Esc: cancel")
+
+	box := borderStyle.
+		Padding(1).
+		Width(60).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(box)
+}
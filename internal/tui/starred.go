@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// starredFirst reports whether starred contacts are listed before the rest
+func (m Model) starredFirst() bool {
+	return m.cfg == nil || m.cfg.UI.StarredFirst
+}
+
+// sortStarredFirst moves starred contacts to the top, keeping the order
+// within starred and unstarred contacts
+func sortStarredFirst(contacts []db.Contact) {
+	sort.SliceStable(contacts, func(i, j int) bool {
+		return contacts[i].Starred && !contacts[j].Starred
+	})
+}
+
+// toggleStar stars or unstars a contact, keeping it selected when it is
+// still listed
+func (m Model) toggleStar(contact db.Contact) Model {
+	if err := m.db.SetStarred(contact.ID, !contact.Starred); err != nil {
+		m.err = err
+		return m
+	}
+	m = m.reloadContacts()
+	for i, c := range m.filteredContacts() {
+		if c.ID == contact.ID {
+			m.selected = i
+			break
+		}
+	}
+	if contact.Starred {
+		return m.setFlash(FlashSuccess, fmt.Sprintf("✓ Unstarred %s", contact.Name))
+	}
+	return m.setFlash(FlashSuccess, fmt.Sprintf("★ Starred %s", contact.Name))
+}
@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// currentState returns a contact's state, counting no state as "ok"
+func currentState(contact db.Contact) string {
+	if contact.State.Valid && contact.State.String != "" {
+		return contact.State.String
+	}
+	return "ok"
+}
+
+// stateDef returns how a state is defined in the config. States contacts are
+// in that the config doesn't list create tasks, as the built-in ones do.
+func (m Model) stateDef(state string) config.StateConfig {
+	for _, s := range m.states {
+		if s.Name == state {
+			return s
+		}
+	}
+	return config.StateConfig{Name: state, Task: state != "ok"}
+}
+
+// stateCreatesTask reports whether moving a contact to a state creates a task
+func (m Model) stateCreatesTask(state string) bool {
+	return m.stateDef(state).Task
+}
+
+// stateStyleFor returns the style a state is drawn in
+func (m Model) stateStyleFor(state string) lipgloss.Style {
+	if color := m.stateDef(state).Color; color != "" {
+		return stateStyle.Copy().Foreground(lipgloss.Color(color))
+	}
+	if state == "ok" {
+		return lipgloss.NewStyle()
+	}
+	return stateStyle
+}
+
+// canMoveTo reports whether the configured state transitions allow a
+// contact to move to a state. Staying in the same state is always allowed.
+func (m Model) canMoveTo(contact db.Contact, state string) bool {
+	from := currentState(contact)
+	if m.cfg == nil || state == from {
+		return true
+	}
+	allowed, ok := m.cfg.States.Transitions[from]
+	if !ok {
+		return true
+	}
+	for _, s := range allowed {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// refuseTransition flashes why a contact cannot move to a state
+func (m Model) refuseTransition(contact db.Contact, state string) Model {
+	return m.setFlash(FlashError, fmt.Sprintf("%s can't move from %s to %s", contact.Name, currentState(contact), state))
+}
+
+// stepState moves the state menu selection by step, skipping states the
+// contact cannot move to
+func (m Model) stepState(contact db.Contact, step int) Model {
+	for i := m.stateSelected + step; i >= 0 && i < len(ContactStates); i += step {
+		if m.canMoveTo(contact, ContactStates[i]) {
+			m.stateSelected = i
+			break
+		}
+	}
+	return m
+}
@@ -0,0 +1,140 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+)
+
+// How long status bar messages stay up before dismissing themselves
+const (
+	flashDuration      = 4 * time.Second
+	flashErrorDuration = 8 * time.Second
+)
+
+// flashExpireMsg dismisses the status bar message it was scheduled for
+type flashExpireMsg struct {
+	seq int
+}
+
+// expireFlash schedules the current status bar message to be dismissed
+func (m Model) expireFlash() tea.Cmd {
+	if m.flashMessage == "" {
+		return nil
+	}
+	duration := flashDuration
+	if m.flashType == FlashError {
+		duration = flashErrorDuration
+	}
+	seq := m.flashSeq
+	return tea.Tick(duration, func(time.Time) tea.Msg {
+		return flashExpireMsg{seq: seq}
+	})
+}
+
+// handleFlashExpire dismisses the status bar message unless a newer one replaced it
+func (m Model) handleFlashExpire(msg flashExpireMsg) Model {
+	if msg.seq != m.flashSeq {
+		return m
+	}
+	m.dstaskIncompleteError = false
+	m.dstaskTaskID = ""
+	return m.clearFlash()
+}
+
+// promoteError moves an error into the status bar so it never hides the
+// screen the user is working in
+func (m Model) promoteError() Model {
+	if m.err == nil {
+		return m
+	}
+	message := "Error: " + m.err.Error()
+	if m.dstaskIncompleteError {
+		message += " • e: edit task notes • Esc: dismiss"
+	}
+	m.err = nil
+	return m.setFlash(FlashError, message)
+}
+
+// withToast draws the status bar message over the bottom line of a
+// full-screen overlay
+func (m Model) withToast(view string) string {
+	if m.flashMessage == "" {
+		return view
+	}
+	lines := strings.Split(view, "\n")
+	lines[len(lines)-1] = m.renderFlash()
+	return strings.Join(lines, "\n")
+}
+
+// renderStatusBar renders the line above the key help: the latest message
+// while it is up, otherwise how many contacts are overdue and the active
+// filters, with the task backend and the database on the right
+func (m Model) renderStatusBar() string {
+	if m.flashMessage != "" || m.width <= 0 {
+		return m.renderFlash()
+	}
+
+	var left []string
+	if overdue := m.countMatching(filterState{overdueFilter: true}); overdue > 0 {
+		left = append(left, statusSegment(theme.Danger, fmt.Sprintf("%d overdue", overdue)))
+	} else {
+		left = append(left, statusSegment(theme.Success, "nothing overdue"))
+	}
+	filters := m.filterIndicators()
+	if text := strings.TrimSpace(m.filter.Value()); text != "" {
+		filters = append([]string{`"` + text + `"`}, filters...)
+	}
+	if len(filters) > 0 {
+		left = append(left, statusSegment(theme.Accent, "filter: "+strings.Join(filters, ", ")))
+	}
+
+	var right []string
+	if m.taskManager != nil && m.taskManager.Name() != "noop" {
+		right = append(right, statusSegment(theme.Muted, "tasks: "+m.taskManager.Name()))
+	}
+	if m.db != nil {
+		right = append(right, statusSegment(theme.Muted, homeRelative(m.db.Path())))
+	}
+
+	separator := statusSegment(theme.Muted, " • ")
+	leftText := statusSegment(theme.Muted, " ") + strings.Join(left, separator)
+	rightText := strings.Join(right, separator) + statusSegment(theme.Muted, " ")
+	// Context on the right goes first when there isn't room for everything
+	for len(right) > 0 && lipgloss.Width(leftText)+lipgloss.Width(rightText)+1 > m.width {
+		right = right[1:]
+		rightText = strings.Join(right, separator) + statusSegment(theme.Muted, " ")
+	}
+	if len(right) == 0 {
+		rightText = ""
+	}
+	if lipgloss.Width(leftText)+lipgloss.Width(rightText) > m.width {
+		leftText = truncate.StringWithTail(leftText, uint(m.width), "…")
+	}
+
+	gap := max(m.width-lipgloss.Width(leftText)-lipgloss.Width(rightText), 0)
+	return leftText + statusSegment(theme.Muted, strings.Repeat(" ", gap)) + rightText
+}
+
+// statusSegment renders text in the status bar's background
+func statusSegment(color lipgloss.TerminalColor, text string) string {
+	return lipgloss.NewStyle().Background(theme.StatusBar).Foreground(color).Render(text)
+}
+
+// homeRelative shortens a path in the home directory to start with ~
+func homeRelative(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.Join("~", rel)
+	}
+	return path
+}
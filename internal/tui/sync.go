@@ -0,0 +1,177 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+	"github.com/pdxmph/contacts-tui/internal/syncer"
+)
+
+// syncTimeout bounds a single sync run so a hung remote can't stall syncing forever
+const syncTimeout = 2 * time.Minute
+
+// syncDoneMsg reports the outcome of a background sync run
+type syncDoneMsg struct {
+	result syncer.Result
+	err    error
+	at     time.Time
+}
+
+// syncTickMsg triggers a scheduled sync
+type syncTickMsg struct{}
+
+// syncClockMsg refreshes the "last synced" indicator
+type syncClockMsg struct{}
+
+// runSync runs a sync in the background
+func runSync(backend syncer.Backend) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), syncTimeout)
+		defer cancel()
+		result, err := backend.Sync(ctx)
+		return syncDoneMsg{result: result, err: err, at: time.Now()}
+	}
+}
+
+// scheduleSync waits for the configured interval before syncing again
+func (m Model) scheduleSync() tea.Cmd {
+	if m.syncInterval <= 0 {
+		return nil
+	}
+	return tea.Tick(m.syncInterval, func(time.Time) tea.Msg {
+		return syncTickMsg{}
+	})
+}
+
+// syncClock keeps the "last synced" age current while idle
+func syncClock() tea.Cmd {
+	return tea.Tick(time.Minute, func(time.Time) tea.Msg {
+		return syncClockMsg{}
+	})
+}
+
+// initSync returns the commands that start syncing when the program starts
+func (m Model) initSync() tea.Cmd {
+	if m.syncBackend == nil {
+		return nil
+	}
+	return tea.Batch(runSync(m.syncBackend), m.syncSpinner.Tick, syncClock())
+}
+
+// startSync begins a sync unless one is already running
+func (m Model) startSync() (Model, tea.Cmd) {
+	if m.syncBackend == nil {
+		return m.setFlash(FlashInfo, "Sync is not configured (see [sync] in config.toml)"), nil
+	}
+	if m.syncing {
+		return m, nil
+	}
+	m.syncing = true
+	return m, tea.Batch(runSync(m.syncBackend), m.syncSpinner.Tick)
+}
+
+// updateSync handles background sync messages. They are processed in every
+// mode so a sync finishing behind an overlay is never lost.
+func (m Model) updateSync(msg tea.Msg) (Model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case spinner.TickMsg:
+		if msg.ID != m.syncSpinner.ID() {
+			return m, nil, false
+		}
+		if !m.syncing {
+			return m, nil, true
+		}
+		var cmd tea.Cmd
+		m.syncSpinner, cmd = m.syncSpinner.Update(msg)
+		return m, cmd, true
+
+	case syncTickMsg:
+		m, cmd := m.startSync()
+		return m, cmd, true
+
+	case syncClockMsg:
+		return m, syncClock(), true
+
+	case syncDoneMsg:
+		m.syncing = false
+		if msg.err != nil {
+			m.syncErr = msg.err
+			m = m.setFlash(FlashError, fmt.Sprintf("Sync failed: %v", msg.err))
+			return m, m.scheduleSync(), true
+		}
+
+		m.syncErr = nil
+		m.lastSynced = msg.at
+		// A backend that replaced the database file has reopened it already
+		if msg.result.Changed {
+			if newContacts, err := m.db.ListContacts(); err == nil {
+				m.setContacts(newContacts)
+				m.selected = m.ensureValidSelection()
+				m.invalidateDetailCache()
+				m = m.setFlash(FlashInfo, "Synced: "+msg.result.Summary)
+			}
+		}
+		return m, m.scheduleSync(), true
+	}
+
+	return m, nil, false
+}
+
+// renderSyncStatus renders the sync indicator shown at the end of the help line
+func (m Model) renderSyncStatus() string {
+	if m.syncBackend == nil {
+		return ""
+	}
+
+	switch {
+	case m.syncing:
+		return m.syncSpinner.View() + labelStyle.Render("syncing")
+	case m.syncErr != nil:
+		return overdueStyle.Render("⚠ sync failed")
+	case m.lastSynced.IsZero():
+		return labelStyle.Render("not synced")
+	default:
+		return labelStyle.Render("synced " + formatAgo(time.Since(m.lastSynced)))
+	}
+}
+
+// withSyncStatus right-aligns the sync indicator on the help line, cutting
+// the help short when the window is too narrow for both
+func (m Model) withSyncStatus(help string) string {
+	status := m.renderSyncStatus()
+	room := m.width
+	if status != "" {
+		room -= lipgloss.Width(status) + 2
+	}
+	if m.width > 0 && lipgloss.Width(help) > room {
+		help = truncate.StringWithTail(help, uint(max(room, 1)), "…")
+	}
+	if status == "" {
+		return help
+	}
+
+	gap := m.width - lipgloss.Width(help) - lipgloss.Width(status) - 1
+	if gap < 1 {
+		gap = 1
+	}
+	return help + lipgloss.NewStyle().Width(gap).Render("") + status
+}
+
+// formatAgo formats a duration as a short relative age, e.g. "5m ago"
+func formatAgo(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
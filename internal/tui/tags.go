@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// hasTags reports whether a contact carries a tag starting with each of
+// the lowercased prefixes, so results narrow as a tag is typed
+func hasTags(c *db.Contact, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		found := false
+		for _, tag := range c.Tags {
+			if strings.HasPrefix(strings.ToLower(tag), prefix) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// loadFilterTags loads the tags offered in the type filter overlay
+func (m Model) loadFilterTags() Model {
+	tags, err := m.db.ListTags()
+	if err != nil {
+		m.err = err
+		return m
+	}
+	m.filterTags = tags
+	return m
+}
+
+// selectFilterTag applies the tag at index i of the type filter overlay's
+// tag list, or clears the tag filter if that tag is already applied
+func (m Model) selectFilterTag(i int) Model {
+	if i < 0 || i >= len(m.filterTags) {
+		return m
+	}
+	if strings.EqualFold(m.tagFilter, m.filterTags[i].Name) {
+		m.tagFilter = ""
+	} else {
+		m.tagFilter = m.filterTags[i].Name
+	}
+	return m
+}
+
+// renderFilterTags renders the tag list below the relationship types in the
+// type filter overlay
+func (m Model) renderFilterTags() []string {
+	if len(m.filterTags) == 0 {
+		return nil
+	}
+	lines := []string{"", "Filter by tag:"}
+	for i, t := range m.filterTags {
+		line := fmt.Sprintf("  #%s (%d)", t.Name, t.Count)
+		if strings.EqualFold(m.tagFilter, t.Name) {
+			line += " ✓"
+		}
+		if len(RelationshipTypes)+i == m.typeSelected {
+			line = selectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,144 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/messaging"
+	"github.com/pdxmph/contacts-tui/internal/scripting"
+)
+
+// textSendTimeout bounds how long the messaging command may run
+const textSendTimeout = 30 * time.Second
+
+// textSentMsg reports the outcome of sending a text message
+type textSentMsg struct {
+	contactID int
+	name      string
+	message   string
+	err       error
+}
+
+// messagingCommand returns the configured messaging command, or "" if texting is disabled
+func (m Model) messagingCommand() string {
+	if m.cfg == nil {
+		return ""
+	}
+	return m.cfg.Messaging.Command
+}
+
+// openTextPrompt asks for a text message to send to a contact
+func (m Model) openTextPrompt(contact db.Contact) (Model, tea.Cmd) {
+	if m.messagingCommand() == "" {
+		return m.setFlash(FlashInfo, "Texting is not set up • set command under [messaging] in config.toml"), nil
+	}
+	if !contact.Phone.Valid || messaging.NormalizePhone(contact.Phone.String) == "" {
+		m.err = fmt.Errorf("%s has no phone number", contact.Name)
+		return m, nil
+	}
+
+	m.textPromptMode = true
+	m.textContactID = contact.ID
+	m.textInput.Reset()
+	m.textInput.Focus()
+	return m, textinput.Blink
+}
+
+// sendText sends a text message in the background
+func sendText(command string, contact db.Contact, message string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), textSendTimeout)
+		defer cancel()
+		err := messaging.Send(ctx, command, contact.Phone.String, message)
+		return textSentMsg{contactID: contact.ID, name: contact.Name, message: message, err: err}
+	}
+}
+
+// handleTextSent logs a text interaction once the message was sent
+func (m Model) handleTextSent(msg textSentMsg) Model {
+	m.textSending = false
+	if msg.err != nil {
+		m.err = fmt.Errorf("sending text to %s: %w", msg.name, msg.err)
+		return m
+	}
+	if err := m.db.MarkContacted(msg.contactID, "text", msg.message); err != nil {
+		m.err = fmt.Errorf("text sent but logging failed: %w", err)
+		return m
+	}
+	m = m.reloadContacts()
+	m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Sent text to %s", msg.name))
+	return m.runAutomations(scripting.EventContacted, msg.contactID)
+}
+
+// updateText handles keys for the text message prompt
+func (m Model) updateText(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Keys are ignored until the message is sent
+	if m.textSending {
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.textPromptMode = false
+		m.textInput.Blur()
+		return m, nil
+
+	case "enter":
+		message := strings.TrimSpace(m.textInput.Value())
+		if message == "" {
+			return m, nil
+		}
+		contact, err := m.db.GetContact(m.textContactID)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.textPromptMode = false
+		m.textInput.Blur()
+		m.textSending = true
+		return m, sendText(m.messagingCommand(), *contact, message)
+	}
+
+	var cmd tea.Cmd
+	m.textInput, cmd = m.textInput.Update(msg)
+	return m, cmd
+}
+
+// renderText renders the text message prompt
+func (m Model) renderText() string {
+	var name, phone string
+	if contact, err := m.db.GetContact(m.textContactID); err == nil {
+		name = contact.Name
+		phone = contact.Phone.String
+	}
+
+	var lines []string
+	if m.textSending {
+		lines = append(lines, fmt.Sprintf("Sending text to %s...", name))
+	} else {
+		lines = append(lines, fmt.Sprintf("Text %s (%s):", name, phone))
+		lines = append(lines, "")
+		lines = append(lines, m.textInput.View())
+		lines = append(lines, "")
+		lines = append(lines, labelStyle.Render(fmt.Sprintf("%d/%d", len(m.textInput.Value()), m.textInput.CharLimit)))
+		lines = append(lines, "")
+		lines = append(lines, "Enter: send • Esc: cancel")
+	}
+
+	box := borderStyle.
+		Padding(1).
+		Width(64).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(box)
+}
@@ -0,0 +1,202 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pdxmph/contacts-tui/internal/config"
+)
+
+// Theme is the palette the TUI is drawn in
+type Theme struct {
+	Accent    lipgloss.TerminalColor // Selected contact, states and highlights
+	Title     lipgloss.TerminalColor // Titles of forms and dialogs
+	Border    lipgloss.TerminalColor // Pane borders
+	Overlay   lipgloss.TerminalColor // Borders of forms and dialogs
+	Muted     lipgloss.TerminalColor // Labels, hints and help text
+	Dim       lipgloss.TerminalColor // Archived contacts and placeholders
+	Text      lipgloss.TerminalColor // Text typed into inputs
+	Danger    lipgloss.TerminalColor // Overdue contacts and deletions
+	Success   lipgloss.TerminalColor // Ambient contacts
+	Warning   lipgloss.TerminalColor // Triggered contacts and dates coming up
+	Link      lipgloss.TerminalColor // Links in notes
+	Code      lipgloss.TerminalColor // Code in notes
+	StatusBar lipgloss.TerminalColor // Background of the status bar
+
+	// Backgrounds of status bar messages, which are written in white
+	FlashSuccess lipgloss.TerminalColor
+	FlashError   lipgloss.TerminalColor
+	FlashInfo    lipgloss.TerminalColor
+}
+
+// themes are the built-in themes, by name
+var themes = map[string]Theme{
+	"dark": {
+		Accent:       lipgloss.Color("214"), // Orange
+		Title:        lipgloss.Color("32"),
+		Border:       lipgloss.Color("240"),
+		Overlay:      lipgloss.Color("63"),
+		Muted:        lipgloss.Color("241"),
+		Dim:          lipgloss.Color("238"),
+		Text:         lipgloss.Color("230"),
+		Danger:       lipgloss.Color("196"),
+		Success:      lipgloss.Color("34"),
+		Warning:      lipgloss.Color("226"),
+		Link:         lipgloss.Color("39"),
+		Code:         lipgloss.Color("180"),
+		StatusBar:    lipgloss.Color("235"),
+		FlashSuccess: lipgloss.Color("#2d7a2d"),
+		FlashError:   lipgloss.Color("#d32f2f"),
+		FlashInfo:    lipgloss.Color("#1976d2"),
+	},
+	"light": {
+		Accent:       lipgloss.Color("166"), // Dark orange
+		Title:        lipgloss.Color("25"),
+		Border:       lipgloss.Color("246"),
+		Overlay:      lipgloss.Color("61"),
+		Muted:        lipgloss.Color("242"),
+		Dim:          lipgloss.Color("246"),
+		Text:         lipgloss.Color("235"),
+		Danger:       lipgloss.Color("160"),
+		Success:      lipgloss.Color("28"),
+		Warning:      lipgloss.Color("136"),
+		Link:         lipgloss.Color("25"),
+		Code:         lipgloss.Color("94"),
+		StatusBar:    lipgloss.Color("254"),
+		FlashSuccess: lipgloss.Color("#2d7a2d"),
+		FlashError:   lipgloss.Color("#c62828"),
+		FlashInfo:    lipgloss.Color("#1565c0"),
+	},
+	"solarized": {
+		Accent:       lipgloss.Color("#cb4b16"),
+		Title:        lipgloss.Color("#268bd2"),
+		Border:       lipgloss.Color("#586e75"),
+		Overlay:      lipgloss.Color("#6c71c4"),
+		Muted:        lipgloss.Color("#839496"),
+		Dim:          lipgloss.Color("#586e75"),
+		Text:         lipgloss.Color("#93a1a1"),
+		Danger:       lipgloss.Color("#dc322f"),
+		Success:      lipgloss.Color("#859900"),
+		Warning:      lipgloss.Color("#b58900"),
+		Link:         lipgloss.Color("#2aa198"),
+		Code:         lipgloss.Color("#d33682"),
+		StatusBar:    lipgloss.Color("#073642"),
+		FlashSuccess: lipgloss.Color("#859900"),
+		FlashError:   lipgloss.Color("#dc322f"),
+		FlashInfo:    lipgloss.Color("#268bd2"),
+	},
+}
+
+// adaptiveTheme picks each color from the light or the dark theme,
+// depending on the terminal's background
+func adaptiveTheme(light, dark Theme) Theme {
+	pick := func(l, d lipgloss.TerminalColor) lipgloss.TerminalColor {
+		lc, lok := l.(lipgloss.Color)
+		dc, dok := d.(lipgloss.Color)
+		if !lok || !dok {
+			return d
+		}
+		return lipgloss.AdaptiveColor{Light: string(lc), Dark: string(dc)}
+	}
+	return Theme{
+		Accent:       pick(light.Accent, dark.Accent),
+		Title:        pick(light.Title, dark.Title),
+		Border:       pick(light.Border, dark.Border),
+		Overlay:      pick(light.Overlay, dark.Overlay),
+		Muted:        pick(light.Muted, dark.Muted),
+		Dim:          pick(light.Dim, dark.Dim),
+		Text:         pick(light.Text, dark.Text),
+		Danger:       pick(light.Danger, dark.Danger),
+		Success:      pick(light.Success, dark.Success),
+		Warning:      pick(light.Warning, dark.Warning),
+		Link:         pick(light.Link, dark.Link),
+		Code:         pick(light.Code, dark.Code),
+		StatusBar:    pick(light.StatusBar, dark.StatusBar),
+		FlashSuccess: pick(light.FlashSuccess, dark.FlashSuccess),
+		FlashError:   pick(light.FlashError, dark.FlashError),
+		FlashInfo:    pick(light.FlashInfo, dark.FlashInfo),
+	}
+}
+
+// theme is the palette in use
+var theme Theme
+
+func init() {
+	themes["auto"] = adaptiveTheme(themes["light"], themes["dark"])
+	applyTheme(themes["auto"])
+}
+
+// themeFor builds the configured theme: the named built-in theme with the
+// colors the config sets replaced. An unknown name falls back to auto.
+func themeFor(cfg config.ThemeConfig) (Theme, error) {
+	name := strings.ToLower(cfg.Name)
+	if name == "" {
+		name = "auto"
+	}
+	t, ok := themes[name]
+	if !ok {
+		t = themes["auto"]
+	}
+	for _, c := range []struct {
+		color *lipgloss.TerminalColor
+		value string
+	}{
+		{&t.Accent, cfg.Accent},
+		{&t.Title, cfg.Title},
+		{&t.Border, cfg.Border},
+		{&t.Overlay, cfg.Overlay},
+		{&t.Muted, cfg.Muted},
+		{&t.Dim, cfg.Dim},
+		{&t.Text, cfg.Text},
+		{&t.Danger, cfg.Danger},
+		{&t.Success, cfg.Success},
+		{&t.Warning, cfg.Warning},
+		{&t.Link, cfg.Link},
+		{&t.Code, cfg.Code},
+		{&t.StatusBar, cfg.StatusBar},
+		{&t.FlashSuccess, cfg.FlashSuccess},
+		{&t.FlashError, cfg.FlashError},
+		{&t.FlashInfo, cfg.FlashInfo},
+	} {
+		if c.value != "" {
+			*c.color = lipgloss.Color(c.value)
+		}
+	}
+	if !ok {
+		return t, fmt.Errorf("unknown theme %q (use %s)", cfg.Name, strings.Join(themeNames(), ", "))
+	}
+	return t, nil
+}
+
+// themeNames lists the built-in themes
+func themeNames() []string {
+	var names []string
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyTheme switches the shared styles to a theme's colors
+func applyTheme(t Theme) {
+	theme = t
+
+	// Contact list selection - no background, just bold and bright
+	selectedStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Accent)
+	// Note type selector style - no background, just bold brackets
+	noteTypeSelectorStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Accent)
+	overdueStyle = lipgloss.NewStyle().Foreground(t.Danger)
+	stateStyle = lipgloss.NewStyle().Foreground(t.Accent)
+	labelStyle = lipgloss.NewStyle().Foreground(t.Muted)
+	borderStyle = lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()).BorderForeground(t.Border)
+	dimmedStyle = lipgloss.NewStyle().Foreground(t.Dim)
+	greenStyle = lipgloss.NewStyle().Foreground(t.Success)
+	yellowStyle = lipgloss.NewStyle().Foreground(t.Warning)
+
+	mdCodeStyle = lipgloss.NewStyle().Foreground(t.Code)
+	mdLinkStyle = lipgloss.NewStyle().Underline(true).Foreground(t.Link)
+	mdQuoteStyle = lipgloss.NewStyle().Foreground(t.Muted)
+}
@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/report"
+)
+
+// exportDir returns where contact timelines are written
+func (m Model) exportDir() string {
+	if m.cfg == nil || m.cfg.UI.ExportDir == "" {
+		return config.Default().UI.ExportDir
+	}
+	return m.cfg.UI.ExportDir
+}
+
+// exportTimeline writes the selected contact's full interaction timeline
+// to the export directory as Markdown or JSON
+func (m Model) exportTimeline(format string) Model {
+	contacts := m.filteredContacts()
+	if len(contacts) == 0 || m.selected >= len(contacts) {
+		return m
+	}
+	contact := contacts[m.selected]
+
+	data, err := report.Timeline(m.db, contact, format)
+	if err != nil {
+		m.err = err
+		return m
+	}
+	dir := m.exportDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		m.err = fmt.Errorf("creating export directory: %w", err)
+		return m
+	}
+	path := filepath.Join(dir, report.TimelineFileName(contact, format))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		m.err = fmt.Errorf("writing %s: %w", path, err)
+		return m
+	}
+	return m.setFlash(FlashSuccess, "✓ Exported "+contact.Name+"'s interactions to "+path)
+}
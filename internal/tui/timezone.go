@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// parseTimezone checks a time zone field is an IANA name, e.g.
+// Europe/Berlin; empty clears it
+func parseTimezone(input string) (string, error) {
+	name := strings.TrimSpace(input)
+	if name == "" {
+		return "", nil
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return "", fmt.Errorf("unknown time zone %q (use e.g. America/New_York)", name)
+	}
+	return name, nil
+}
+
+// isNightHour reports whether an hour is too late or too early to ping
+// someone
+func isNightHour(hour int) bool {
+	return hour < 8 || hour >= 22
+}
+
+// describeLocalTime shows a contact's current local time for the detail
+// pane, e.g. "It's 22:40 Tue for Sarah (Europe/Berlin)", highlighted when
+// it is night there. It returns "" for contacts without a known time zone.
+func describeLocalTime(c db.Contact, now time.Time) string {
+	if !c.Timezone.Valid || c.Timezone.String == "" {
+		return ""
+	}
+	loc, err := time.LoadLocation(c.Timezone.String)
+	if err != nil {
+		return ""
+	}
+	local := now.In(loc)
+	// First names only, but not "Dr." and the like
+	name := c.Name
+	if fields := strings.Fields(name); len(fields) > 0 && !strings.HasSuffix(fields[0], ".") {
+		name = fields[0]
+	}
+	text := fmt.Sprintf("It's %s for %s (%s)", local.Format("15:04 Mon"), name, c.Timezone.String)
+	if isNightHour(local.Hour()) {
+		return yellowStyle.Render(text + ", night there")
+	}
+	return text
+}
@@ -0,0 +1,161 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// trashDays returns how long deleted contacts stay in the trash; 0 keeps
+// them until purged by hand
+func (m Model) trashDays() int {
+	if m.cfg == nil {
+		return config.Default().Retention.TrashDays
+	}
+	return m.cfg.Retention.TrashDays
+}
+
+// emptyExpiredTrash purges contacts that have been in the trash longer than
+// the retention period
+func (m Model) emptyExpiredTrash() Model {
+	days := m.trashDays()
+	if days <= 0 {
+		return m
+	}
+	purged, err := m.db.PurgeTrash(time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return m.setFlash(FlashError, fmt.Sprintf("Emptying trash: %v", err))
+	}
+	if purged > 0 {
+		return m.setFlash(FlashInfo, fmt.Sprintf("Purged %d contacts in the trash longer than %d days", purged, days))
+	}
+	return m
+}
+
+// openTrash shows the contacts in the trash
+func (m Model) openTrash() Model {
+	m.trashMode = true
+	m.trashSelected = 0
+	m.trashConfirmPurge = false
+	return m.loadTrash()
+}
+
+// loadTrash reloads the contacts in the trash
+func (m Model) loadTrash() Model {
+	trashed, err := m.db.ListTrashed()
+	if err != nil {
+		m.err = err
+		m.trashMode = false
+		return m
+	}
+	m.trash = trashed
+	if m.trashSelected >= len(trashed) {
+		m.trashSelected = len(trashed) - 1
+	}
+	if m.trashSelected < 0 {
+		m.trashSelected = 0
+	}
+	return m
+}
+
+// updateTrash handles keys in the trash view
+func (m Model) updateTrash(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.trashConfirmPurge {
+		m.trashConfirmPurge = false
+		if msg.String() != "y" || m.trashSelected >= len(m.trash) {
+			return m, nil
+		}
+		c := m.trash[m.trashSelected]
+		if err := m.db.PurgeContact(c.ID); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m = m.loadTrash()
+		return m.setFlash(FlashSuccess, fmt.Sprintf("✓ Purged %s permanently", c.Name)), nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.trashMode = false
+		m.trash = nil
+	case "j", "down":
+		if m.trashSelected < len(m.trash)-1 {
+			m.trashSelected++
+		}
+	case "k", "up":
+		if m.trashSelected > 0 {
+			m.trashSelected--
+		}
+	case "r", "enter":
+		if m.trashSelected < len(m.trash) {
+			c := m.trash[m.trashSelected]
+			if err := m.db.RestoreContact(c.ID); err != nil {
+				m.err = err
+				return m, nil
+			}
+			m = m.loadTrash().reloadContacts()
+			m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Restored %s", c.Name))
+		}
+	case "x", "delete":
+		if m.trashSelected < len(m.trash) {
+			m.trashConfirmPurge = true
+		}
+	}
+	return m, nil
+}
+
+// describeTrashed describes when a contact was deleted and when it will be
+// purged
+func (m Model) describeTrashed(c db.Contact, now time.Time) string {
+	deleted := c.TrashedAt.Time
+	line := fmt.Sprintf("%s • deleted %s", c.Name, deleted.Local().Format("2006-01-02"))
+	if days := m.trashDays(); days > 0 {
+		left := days - int(now.Sub(deleted).Hours()/24)
+		if left < 0 {
+			left = 0
+		}
+		line += fmt.Sprintf(", purged in %d days", left)
+	}
+	return line
+}
+
+// renderTrash renders the trash view
+func (m Model) renderTrash() string {
+	var lines []string
+	lines = append(lines, fmt.Sprintf("Trash (%d)", len(m.trash)))
+	lines = append(lines, "")
+	if len(m.trash) == 0 {
+		lines = append(lines, labelStyle.Render("The trash is empty"))
+	}
+	now := time.Now()
+	for i, c := range m.trash {
+		line := m.describeTrashed(c, now)
+		if i == m.trashSelected {
+			lines = append(lines, selectedStyle.Render("▶ "+line))
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	lines = append(lines, "")
+	if m.trashConfirmPurge && m.trashSelected < len(m.trash) {
+		lines = append(lines, yellowStyle.Render(fmt.Sprintf("Purge %s and its interaction history permanently? y/n", m.trash[m.trashSelected].Name)))
+	} else {
+		lines = append(lines, "r/Enter: restore • x: purge permanently • Esc: close")
+	}
+
+	box := borderStyle.
+		Padding(1).
+		Width(70).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(box)
+}
@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// maxUndo is how many changes u can step back through
+const maxUndo = 20
+
+// undoEntry is a change u can reverse: the contacts it touched, as they were
+// before and right after it
+type undoEntry struct {
+	action string // What was done, e.g. "archive of Sarah Chen"
+	before []db.Snapshot
+	after  []db.Snapshot
+}
+
+// snapshot records a contact before an undoable change. It returns nil if
+// the contact can't be read, and the change then can't be undone.
+func (m Model) snapshot(contactID int) *db.Snapshot {
+	s, err := m.db.TakeSnapshot(contactID)
+	if err != nil {
+		return nil
+	}
+	return &s
+}
+
+// pushUndo records a finished change so u can reverse it. Call it before
+// running automations, so interactions they log are kept on undo.
+func (m Model) pushUndo(action string, before ...*db.Snapshot) Model {
+	entry := undoEntry{action: action}
+	for _, b := range before {
+		if b == nil {
+			continue
+		}
+		after, err := m.db.TakeSnapshot(b.ContactID)
+		if err != nil {
+			continue
+		}
+		entry.before = append(entry.before, *b)
+		entry.after = append(entry.after, after)
+	}
+	if len(entry.before) == 0 {
+		return m
+	}
+	m.undoStack = append(m.undoStack, entry)
+	if len(m.undoStack) > maxUndo {
+		m.undoStack = append([]undoEntry(nil), m.undoStack[len(m.undoStack)-maxUndo:]...)
+	}
+	return m
+}
+
+// dropUndo takes action back off the undo stack once the change has created
+// a task. Task backends can't delete tasks, so undoing the change would
+// leave its task behind.
+func (m Model) dropUndo(action string) Model {
+	if n := len(m.undoStack); n > 0 && m.undoStack[n-1].action == action {
+		m.undoStack = m.undoStack[:n-1]
+	}
+	return m
+}
+
+// undo reverses the most recent change on the undo stack
+func (m Model) undo() Model {
+	if len(m.undoStack) == 0 {
+		return m.setFlash(FlashInfo, "Nothing to undo")
+	}
+	entry := m.undoStack[len(m.undoStack)-1]
+	m.undoStack = m.undoStack[:len(m.undoStack)-1]
+
+	for i := range entry.before {
+		if err := m.db.RestoreSnapshot(entry.before[i], entry.after[i]); err != nil {
+			m = m.reloadContacts()
+			return m.setFlash(FlashError, fmt.Sprintf("Can't undo %s: %v", entry.action, err))
+		}
+	}
+	m = m.reloadContacts()
+	if len(entry.before) == 1 {
+		if contact, err := m.db.GetContact(entry.before[0].ContactID); err == nil && contact != nil {
+			m = m.jumpTo(*contact)
+		}
+	}
+	msg := fmt.Sprintf("↶ Undid %s", entry.action)
+	if len(m.undoStack) > 0 {
+		msg += fmt.Sprintf(" • u: undo %s", m.undoStack[len(m.undoStack)-1].action)
+	}
+	return m.setFlash(FlashSuccess, msg)
+}
@@ -0,0 +1,306 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// valueKind describes a list of contact values edited in the values overlay
+type valueKind struct {
+	title       string // e.g. "Email addresses"
+	noun        string // e.g. "email address"
+	field       string // Form label for the value
+	typeName    string // Form label for the type
+	placeholder string
+	charLimit   int
+	types       []string
+
+	list    func(d *db.DB, contactID int) ([]db.ContactValue, error)
+	add     func(d *db.DB, contactID int, value, valueType string) error
+	update  func(d *db.DB, id int, value, valueType string) error
+	remove  func(d *db.DB, id int) error
+	primary func(d *db.DB, id int) error   // nil when the list has no primary entry
+	open    func(v db.ContactValue) string // URL to open for a value, if any
+}
+
+var emailValues = &valueKind{
+	title:       "Email addresses",
+	noun:        "email address",
+	field:       "Address",
+	typeName:    "Type",
+	placeholder: "name@example.com",
+	charLimit:   254,
+	types:       db.EmailTypes,
+	list:        (*db.DB).ListEmails,
+	add:         (*db.DB).AddEmail,
+	update:      (*db.DB).UpdateEmail,
+	remove:      (*db.DB).DeleteEmail,
+	primary:     (*db.DB).MakePrimaryEmail,
+}
+
+var phoneValues = &valueKind{
+	title:       "Phone numbers",
+	noun:        "phone number",
+	field:       "Number",
+	typeName:    "Label",
+	placeholder: "+1 555 0100",
+	charLimit:   40,
+	types:       db.PhoneTypes,
+	list:        (*db.DB).ListPhones,
+	add:         (*db.DB).AddPhone,
+	update:      (*db.DB).UpdatePhone,
+	remove:      (*db.DB).DeletePhone,
+	primary:     (*db.DB).MakePrimaryPhone,
+}
+
+var handleValues = &valueKind{
+	title:       "Social media handles",
+	noun:        "handle",
+	field:       "Handle",
+	typeName:    "Platform",
+	placeholder: "@name@example.social",
+	charLimit:   200,
+	types:       db.HandlePlatforms,
+	list:        (*db.DB).ListHandles,
+	add:         (*db.DB).AddHandle,
+	update:      (*db.DB).UpdateHandle,
+	remove:      (*db.DB).DeleteHandle,
+	open:        func(v db.ContactValue) string { return db.ProfileURL(v.Type, v.Value) },
+}
+
+// openValues shows a contact's email addresses, phone numbers or handles
+func (m Model) openValues(kind *valueKind, contact db.Contact) Model {
+	m.valuesMode = true
+	m.valuesKind = kind
+	m.valuesContactID = contact.ID
+	m.valuesSelected = 0
+	return m.loadValues()
+}
+
+// loadValues reloads the values being edited
+func (m Model) loadValues() Model {
+	values, err := m.valuesKind.list(m.db, m.valuesContactID)
+	if err != nil {
+		m.err = err
+		m.valuesMode = false
+		return m
+	}
+	m.values = values
+	if m.valuesSelected >= len(values) {
+		m.valuesSelected = len(values) - 1
+	}
+	if m.valuesSelected < 0 {
+		m.valuesSelected = 0
+	}
+	m.invalidateDetailCache()
+	return m
+}
+
+// reloadValueContact picks up the contact's primary value after the list
+// changed
+func (m Model) reloadValueContact() Model {
+	if contacts, err := m.db.ListContacts(); err == nil {
+		m.setContacts(contacts)
+	}
+	return m
+}
+
+// openValueForm starts adding a new value, or editing an existing one
+func (m Model) openValueForm(existing *db.ContactValue) (Model, tea.Cmd) {
+	m.valueFormMode = true
+	m.valueFormEditID = 0
+	m.valueFormType = m.valuesKind.types[0]
+	m.valueInput.Reset()
+	m.valueInput.Placeholder = m.valuesKind.placeholder
+	m.valueInput.CharLimit = m.valuesKind.charLimit
+	if existing != nil {
+		m.valueFormEditID = existing.ID
+		m.valueFormType = existing.Type
+		m.valueInput.SetValue(existing.Value)
+	}
+	m.valueInput.Focus()
+	return m, textinput.Blink
+}
+
+// nextValueType cycles through a list of types
+func nextValueType(types []string, current string) string {
+	for i, t := range types {
+		if t == current {
+			return types[(i+1)%len(types)]
+		}
+	}
+	return types[0]
+}
+
+// saveValueForm validates and stores the value being edited
+func (m Model) saveValueForm() Model {
+	value := strings.TrimSpace(m.valueInput.Value())
+	var err error
+	if m.valueFormEditID != 0 {
+		err = m.valuesKind.update(m.db, m.valueFormEditID, value, m.valueFormType)
+	} else {
+		err = m.valuesKind.add(m.db, m.valuesContactID, value, m.valueFormType)
+	}
+	if err != nil {
+		m.err = err
+		return m
+	}
+
+	m.valueFormMode = false
+	m.valueInput.Blur()
+	m = m.loadValues().reloadValueContact()
+	return m.setFlash(FlashSuccess, fmt.Sprintf("✓ Saved %s", value))
+}
+
+// updateValues handles keys for the values list and form
+func (m Model) updateValues(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.valueFormMode {
+		switch msg.String() {
+		case "esc":
+			m.valueFormMode = false
+			m.valueInput.Blur()
+			return m, nil
+		case "enter":
+			return m.saveValueForm(), nil
+		case "tab", "shift+tab":
+			m.valueFormType = nextValueType(m.valuesKind.types, m.valueFormType)
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.valueInput, cmd = m.valueInput.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.valuesMode = false
+		m.values = nil
+	case "j", "down":
+		if m.valuesSelected < len(m.values)-1 {
+			m.valuesSelected++
+		}
+	case "k", "up":
+		if m.valuesSelected > 0 {
+			m.valuesSelected--
+		}
+	case "a", "+":
+		return m.openValueForm(nil)
+	case "e", "enter":
+		if m.valuesSelected < len(m.values) {
+			v := m.values[m.valuesSelected]
+			return m.openValueForm(&v)
+		}
+	case "p":
+		if m.valuesKind.primary != nil && m.valuesSelected < len(m.values) {
+			v := m.values[m.valuesSelected]
+			if err := m.valuesKind.primary(m.db, v.ID); err != nil {
+				m.err = err
+				return m, nil
+			}
+			m.valuesSelected = 0
+			m = m.loadValues().reloadValueContact()
+			m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ %s is now the primary %s", v.Value, m.valuesKind.noun))
+		}
+	case "o":
+		if m.valuesKind.open != nil && m.valuesSelected < len(m.values) {
+			v := m.values[m.valuesSelected]
+			target := m.valuesKind.open(v)
+			if target == "" {
+				return m.setFlash(FlashError, fmt.Sprintf("No profile address for %s", describeValue(v))), nil
+			}
+			m = m.setFlash(FlashInfo, fmt.Sprintf("Opening %s", target))
+			return m, m.openExternal(target)
+		}
+	case "x", "delete":
+		if m.valuesSelected < len(m.values) {
+			v := m.values[m.valuesSelected]
+			if err := m.valuesKind.remove(m.db, v.ID); err != nil {
+				m.err = err
+				return m, nil
+			}
+			m = m.loadValues().reloadValueContact()
+			m = m.setFlash(FlashSuccess, fmt.Sprintf("✓ Deleted %s", v.Value))
+		}
+	}
+	return m, nil
+}
+
+// describeValue formats a value with its type
+func describeValue(v db.ContactValue) string {
+	return fmt.Sprintf("%s (%s)", v.Value, v.Type)
+}
+
+// renderValues renders the values list or form overlay
+func (m Model) renderValues() string {
+	kind := m.valuesKind
+	var name string
+	if contact, err := m.db.GetContact(m.valuesContactID); err == nil {
+		name = contact.Name
+	}
+
+	var lines []string
+	if m.valueFormMode {
+		title := "Add " + kind.noun
+		if m.valueFormEditID != 0 {
+			title = "Edit " + kind.noun
+		}
+		lines = append(lines, fmt.Sprintf("%s for %s", title, name))
+		lines = append(lines, "")
+		lines = append(lines, selectedStyle.Render(kind.field))
+		lines = append(lines, m.valueInput.View())
+		var types []string
+		for _, t := range kind.types {
+			if t == m.valueFormType {
+				types = append(types, selectedStyle.Render("["+t+"]"))
+			} else {
+				types = append(types, labelStyle.Render(" "+t+" "))
+			}
+		}
+		lines = append(lines, labelStyle.Render(kind.typeName))
+		lines = append(lines, "  "+strings.Join(types, " "))
+		lines = append(lines, "")
+		lines = append(lines, fmt.Sprintf("Tab: change %s • Enter: save • Esc: cancel", strings.ToLower(kind.typeName)))
+	} else {
+		lines = append(lines, fmt.Sprintf("%s for %s", kind.title, name))
+		lines = append(lines, "")
+		if len(m.values) == 0 {
+			lines = append(lines, labelStyle.Render(fmt.Sprintf("No %ss yet", kind.noun)))
+		}
+		for i, v := range m.values {
+			line := describeValue(v)
+			if i == 0 && kind.primary != nil {
+				line += " • primary"
+			}
+			if i == m.valuesSelected {
+				lines = append(lines, selectedStyle.Render("▶ "+line))
+			} else {
+				lines = append(lines, "  "+line)
+			}
+		}
+		lines = append(lines, "")
+		switch {
+		case kind.primary != nil:
+			lines = append(lines, "a: add • e: edit • p: make primary • x: delete • Esc: close")
+		case kind.open != nil:
+			lines = append(lines, "a: add • e: edit • o: open profile • x: delete • Esc: close")
+		default:
+			lines = append(lines, "a: add • e: edit • x: delete • Esc: close")
+		}
+	}
+
+	box := borderStyle.
+		Padding(1).
+		Width(70).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(box)
+}
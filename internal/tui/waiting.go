@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// formatWaiting describes how long a contact has owed a reply
+func formatWaiting(days int) string {
+	switch days {
+	case 0:
+		return "waiting today"
+	case 1:
+		return "waiting 1 day"
+	default:
+		return fmt.Sprintf("waiting %d days", days)
+	}
+}
+
+// setWaiting marks or clears a contact as owing a reply and reloads the list
+func (m Model) setWaiting(contact db.Contact, waiting bool) Model {
+	if err := m.db.SetWaiting(contact.ID, waiting); err != nil {
+		m.err = err
+		return m
+	}
+	m = m.reloadContacts()
+	if waiting {
+		return m.setFlash(FlashSuccess, fmt.Sprintf("✓ Waiting on a reply from %s", contact.Name))
+	}
+	return m.setFlash(FlashSuccess, fmt.Sprintf("✓ No longer waiting on %s", contact.Name))
+}
+
+// formatLastInteraction describes a contact's most recent logged
+// interaction, e.g. "call, 12d ago", or "" if none was logged
+func formatLastInteraction(c db.Contact) string {
+	if !c.LastInteractionType.Valid || !c.LastInteractionAt.Valid {
+		return ""
+	}
+	return c.LastInteractionType.String + ", " + formatAgo(time.Since(c.LastInteractionAt.Time))
+}
+
+// fitWithSuffix appends suffix to line, truncating line so the result fits
+// in width columns and the suffix stays visible
+func fitWithSuffix(line, suffix string, width int) string {
+	room := width - lipgloss.Width(suffix)
+	if room < 1 {
+		return line
+	}
+	if lipgloss.Width(line) <= room {
+		return line + suffix
+	}
+	return truncate.StringWithTail(line, uint(room), "…") + suffix
+}
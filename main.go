@@ -13,7 +13,63 @@ import (
 	"github.com/pdxmph/contacts-tui/internal/tui"
 )
 
+// subcommands run instead of the TUI when named first on the command line,
+// before any flags, with the message logged when they fail. They take
+// precedence over a contact given as a bare argument.
+var subcommands = map[string]struct {
+	run     func(args []string) error
+	failure string
+}{
+	"purge":               {runPurge, "Error purging"},
+	"batch":               {runBatch, "Error applying batch"},
+	"escalate":            {runEscalate, "Error escalating"},
+	"export":              {runExport, "Error exporting contacts"},
+	"graph":               {runGraph, "Error exporting graph"},
+	"import":              {runImport, "Error importing contacts"},
+	"import-interactions": {runImportInteractions, "Error importing interactions"},
+	"import-mapped":       {runImportMapped, "Error importing export"},
+	"replay":              {runReplay, "Error replaying keys"},
+	"sheet":               {runSheet, "Error writing contact sheet"},
+	"avatars":             {runAvatars, "Error fetching avatars"},
+	"enrich":              {runEnrich, "Error enriching contacts"},
+	"serve":               {runServe, "Error serving metrics"},
+	"stats":               {runStats, "Error exporting statistics"},
+	"time":                {runTime, "Error summing interaction time"},
+	"encrypt":             {runEncrypt, "Error encrypting database"},
+	"backup":              {runBackup, "Error backing up"},
+	"restore":             {runRestore, "Error restoring"},
+	"doctor":              {runDoctor, "Error checking database"},
+	"types":               {runTypes, "Error updating relationship types"},
+}
+
 func main() {
+	// Every command backs up the database before migrating it, and asks for
+	// the passphrase of an encrypted one when opening it
+	if cfg, err := config.Load(); err == nil {
+		db.SetBackups(cfg.Retention.BackupDir, cfg.Retention.Backups)
+		// Git sync commits only the database file, not its write-ahead log
+		if cfg.Sync.Backend == "git" {
+			db.SetJournalMode("delete")
+		} else {
+			db.SetJournalMode(cfg.Database.JournalMode)
+		}
+		if cfg.Database.Encrypted {
+			db.SetKeySource(func() (string, error) {
+				return databaseKey(cfg.Database)
+			})
+		}
+	}
+	
+	// Subcommands take their own flags
+	if len(os.Args) > 1 {
+		if cmd, ok := subcommands[os.Args[1]]; ok {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				log.Fatal(cmd.failure+":", err)
+			}
+			return
+		}
+	}
+	
 	// Parse command line flags
 	var (
 		writeConfig    = flag.Bool("write-config", false, "Write default configuration file")
@@ -22,9 +78,31 @@ func main() {
 		databasePath   = flag.String("database", "", "Path to database file (overrides config)")
 		createFixtures = flag.Bool("create-fixtures", false, "Create fixtures database for testing")
 		fixturesPath   = flag.String("fixtures-path", "", "Path for fixtures database (default: ./fixtures.db)")
+		gotoContact    = flag.String("goto", "", "Open on the contact matching this label or name (also given as a bare argument, unless it names a subcommand)")
+		syncOnce       = flag.Bool("sync", false, "Sync once with the configured [sync] backend and exit")
+		exportJSON     = flag.String("export-json", "", "Back up the whole database to a JSON file (\"-\" for stdout)")
+		importJSON     = flag.String("import-json", "", "Restore a JSON backup into a new or empty database")
 	)
 	flag.Parse()
 	
+	// A bare argument (e.g. contacts-tui @sarahc) is shorthand for -goto.
+	// A subcommand's name is never taken for a contact: it was meant as the
+	// subcommand, given after flags. Flags after the contact would be left
+	// unparsed, so anything after it is refused.
+	if flag.NArg() > 0 {
+		arg := flag.Arg(0)
+		if _, ok := subcommands[arg]; ok {
+			log.Fatalf("Error: %s is a subcommand and must come first, before any flags; use -goto %q to open a contact with that name", arg, arg)
+		}
+		if flag.NArg() > 1 {
+			log.Fatalf("Error: unexpected arguments after %s: %v (flags go before the contact)", arg, flag.Args()[1:])
+		}
+		if *gotoContact != "" {
+			log.Fatalf("Error: give the contact to open either with -goto or as an argument, not both")
+		}
+		*gotoContact = arg
+	}
+	
 	// Handle create-fixtures command
 	if *createFixtures {
 		fixturesDB := "./fixtures.db"
@@ -74,6 +152,28 @@ func main() {
 		return
 	}
 	
+	// Handle JSON backup commands
+	if *exportJSON != "" {
+		if err := exportBackup(cfg, *exportJSON); err != nil {
+			log.Fatal("Error exporting backup:", err)
+		}
+		return
+	}
+	if *importJSON != "" {
+		if err := importBackup(cfg, *importJSON); err != nil {
+			log.Fatal("Error restoring backup:", err)
+		}
+		return
+	}
+	
+	// Handle sync command
+	if *syncOnce {
+		if err := runSyncOnce(cfg); err != nil {
+			log.Fatal("Error syncing:", err)
+		}
+		return
+	}
+	
 	// Check if database exists
 	if _, err := os.Stat(cfg.Database.Path); os.IsNotExist(err) {
 		fmt.Printf("Database not found at %s\n", cfg.Database.Path)
@@ -88,20 +188,37 @@ func main() {
 		log.Fatal(err)
 	}
 	defer database.Close()
+	database.SetDeletedDir(cfg.Retention.DeletedDir)
 	
 	// Run migrations
 	if err := database.RunMigrations(); err != nil {
 		log.Fatal("Error running migrations:", err)
 	}
 	
+	// Back up the database when the last copy is old enough
+	if err := scheduledBackup(database, cfg); err != nil {
+		log.Println("Error backing up:", err)
+	}
+	
 	// Create model
 	model, err := tui.New(database, cfg)
 	if err != nil {
 		log.Fatal(err)
 	}
 	
+	// Jump straight to a contact if one was requested
+	if *gotoContact != "" {
+		if err := model.GoTo(*gotoContact); err != nil {
+			log.Fatal(err)
+		}
+	}
+	
 	// Start the program
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	options := []tea.ProgramOption{tea.WithAltScreen()}
+	if cfg.UI.Mouse {
+		options = append(options, tea.WithMouseCellMotion())
+	}
+	p := tea.NewProgram(model, options...)
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// runPurge permanently removes contacts archived longer than the retention
+// period, and contacts in the trash longer than trash_days or
+// -trash-older-than. It never prompts, so it can run from cron.
+func runPurge(args []string) error {
+	fs := flag.NewFlagSet("purge", flag.ExitOnError)
+	databasePath := fs.String("database", "", "Path to database file (overrides config)")
+	olderThan := fs.Int("older-than", -1, "Purge contacts archived more than this many days ago (default: retention.archived_days)")
+	trashOlderThan := fs.Int("trash-older-than", -1, "Purge contacts in the trash longer than this many days, 0 for all of them (default: retention.trash_days)")
+	dryRun := fs.Bool("dry-run", false, "List the contacts that would be purged without removing them")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: contacts-tui purge [options]")
+		fmt.Fprintln(fs.Output(), "\nPermanently delete archived and trashed contacts past the retention period.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if *databasePath != "" {
+		cfg.Database.Path = *databasePath
+	}
+
+	database, err := db.Open(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+	database.SetDeletedDir(cfg.Retention.DeletedDir)
+
+	// trash_days = 0 keeps the trash until it is emptied by hand
+	trashDays := *trashOlderThan
+	if trashDays < 0 && cfg.Retention.TrashDays > 0 {
+		trashDays = cfg.Retention.TrashDays
+	}
+	if err := purgeTrash(database, trashDays, *dryRun); err != nil {
+		return err
+	}
+
+	days := cfg.Retention.ArchivedDays
+	if *olderThan >= 0 {
+		days = *olderThan
+	}
+	if days <= 0 {
+		fmt.Println("No retention period set; archived contacts are kept forever.")
+		fmt.Println("Set archived_days under [retention] in config.toml, or pass -older-than N.")
+		return nil
+	}
+
+	expired, err := database.ListArchivedBefore(time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return err
+	}
+	if len(expired) == 0 {
+		fmt.Printf("No contacts archived more than %d days ago.\n", days)
+		return nil
+	}
+
+	for _, c := range expired {
+		line := fmt.Sprintf("  %s (archived %s)", c.Name, c.ArchivedAt.Time.Format("2006-01-02"))
+		if c.ArchiveReason.Valid {
+			line += ": " + c.ArchiveReason.String
+		}
+		fmt.Println(line)
+	}
+	if *dryRun {
+		fmt.Printf("Would purge %d contacts archived more than %d days ago.\n", len(expired), days)
+		return nil
+	}
+
+	purged, err := database.PurgeArchived(expired)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Purged %d contacts archived more than %d days ago.\n", purged, days)
+	if cfg.Retention.DeletedDir != "" {
+		fmt.Printf("  Saved as JSON in %s before deleting.\n", cfg.Retention.DeletedDir)
+	}
+	return nil
+}
+
+// purgeTrash permanently removes contacts in the trash longer than days;
+// a negative number keeps them all
+func purgeTrash(database *db.DB, days int, dryRun bool) error {
+	if days < 0 {
+		return nil
+	}
+	cutoff := time.Now().AddDate(0, 0, -days)
+	which := fmt.Sprintf("contacts in the trash longer than %d days", days)
+	if days == 0 {
+		which = "contacts in the trash"
+	}
+	if dryRun {
+		trashed, err := database.ListTrashed()
+		if err != nil {
+			return err
+		}
+		expired := 0
+		for _, c := range trashed {
+			if c.TrashedAt.Time.Before(cutoff) {
+				fmt.Printf("  %s (deleted %s)\n", c.Name, c.TrashedAt.Time.Format("2006-01-02"))
+				expired++
+			}
+		}
+		if expired > 0 {
+			fmt.Printf("Would purge %d %s.\n", expired, which)
+		}
+		return nil
+	}
+
+	purged, err := database.PurgeTrash(cutoff)
+	if err != nil {
+		return err
+	}
+	if purged > 0 {
+		fmt.Printf("✓ Purged %d %s.\n", purged, which)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/tui"
+)
+
+// runReplay plays keystrokes into the TUI without a terminal and prints the
+// screens it renders, for scripted checks and bug reports
+func runReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	databasePath := fs.String("database", "", "Path to database file (overrides config)")
+	script := fs.String("f", "", "Read keys from this file, one per line (- for stdin)")
+	width := fs.Int("width", 100, "Screen width")
+	height := fs.Int("height", 30, "Screen height")
+	last := fs.Bool("last", false, "Print only the final screen")
+	write := fs.Bool("write", false, "Apply changes to the database instead of a scratch copy")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: contacts-tui replay [options] [key...]")
+		fmt.Fprintln(fs.Output(), "\nFeed keys to the TUI and print the screen after each one. Keys use")
+		fmt.Fprintln(fs.Output(), "bubbletea names (enter, esc, tab, space, up, ctrl+s); anything else is")
+		fmt.Fprintln(fs.Output(), "typed as text. Runs against a scratch copy of the database, with tasks,")
+		fmt.Fprintln(fs.Output(), "sync, texting and settings kept out of your own, unless -write is given.")
+		fmt.Fprintln(fs.Output(), "\nExample: contacts-tui replay -last / alice enter")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *width < 20 || *height < 5 {
+		return fmt.Errorf("screen must be at least 20x5")
+	}
+
+	keys := fs.Args()
+	if *script != "" {
+		scripted, err := readKeys(*script)
+		if err != nil {
+			return err
+		}
+		keys = append(scripted, keys...)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if *databasePath != "" {
+		cfg.Database.Path = *databasePath
+	}
+
+	path := cfg.Database.Path
+	if !*write {
+		// The scratch copy needs no backups
+		db.SetBackups("", 0)
+		dir, err := os.MkdirTemp("", "contacts-replay-*")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+		path = filepath.Join(dir, "contacts.db")
+		if err := db.CopyFile(cfg.Database.Path, path); err != nil {
+			return err
+		}
+
+		// Nothing outside the scratch directory changes either: no tasks
+		// are created, nothing is synced, texted or called, and deleted
+		// contacts, exports and settings are written next to the copy
+		cfg.Tasks.Backend = "noop"
+		cfg.Sync.Backend = ""
+		cfg.Messaging = config.MessagingConfig{}
+		cfg.Retention.DeletedDir = filepath.Join(dir, "deleted")
+		cfg.UI.ExportDir = dir
+		config.SetWritePath(filepath.Join(dir, "config.toml"))
+	}
+
+	database, err := db.Open(path)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+	database.SetDeletedDir(cfg.Retention.DeletedDir)
+
+	model, err := tui.New(database, cfg)
+	if err != nil {
+		return err
+	}
+	frames, err := tui.Playback(model, keys, *width, *height)
+	if err != nil {
+		return err
+	}
+
+	if *last {
+		frames = frames[len(frames)-1:]
+	}
+	for i, frame := range frames {
+		if !*last {
+			key := frame.Key
+			if i == 0 {
+				key = "start"
+			}
+			fmt.Printf("--- frame %d: %s ---\n", i, key)
+		}
+		fmt.Println(frame.View)
+	}
+	return nil
+}
+
+// readKeys reads a key script, one key per line. Blank lines and lines
+// starting with # are skipped.
+func readKeys(path string) ([]string, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(config.ExpandPath(path))
+		if err != nil {
+			return nil, fmt.Errorf("opening key script: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var keys []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keys = append(keys, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading key script: %w", err)
+	}
+	return keys, nil
+}
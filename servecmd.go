@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/metrics"
+)
+
+// runServe serves Prometheus metrics about the contact list over HTTP
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	databasePath := fs.String("database", "", "Path to database file (overrides config)")
+	addr := fs.String("addr", "127.0.0.1:9188", "Address to listen on")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: contacts-tui serve [options]")
+		fmt.Fprintln(fs.Output(), "\nServe Prometheus metrics at /metrics: contacts by type, overdue contacts,")
+		fmt.Fprintln(fs.Output(), "contacts in non-ok states and interactions logged.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	db.SetCadences(cfg.Relationships.Cadence, cfg.Relationships.DefaultCadence)
+	if *databasePath != "" {
+		cfg.Database.Path = *databasePath
+	}
+
+	database, err := db.Open(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		if err := metrics.Write(&buf, database); err != nil {
+			log.Println("Error collecting metrics:", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", metrics.ContentType)
+		w.Write(buf.Bytes())
+	})
+
+	log.Printf("Serving metrics on http://%s/metrics", *addr)
+	return http.ListenAndServe(*addr, mux)
+}
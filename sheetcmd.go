@@ -0,0 +1,124 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/report"
+)
+
+// runSheet writes a contact sheet of a filtered set of contacts
+func runSheet(args []string) error {
+	fs := flag.NewFlagSet("sheet", flag.ExitOnError)
+	databasePath := fs.String("database", "", "Path to database file (overrides config)")
+	filter := addContactFilterFlags(fs)
+	format := fs.String("format", report.FormatText, "Output format: text or markdown")
+	title := fs.String("title", "", "Sheet title (default: based on the filters)")
+	output := fs.String("o", "", "Write the sheet to this file instead of stdout")
+	limit := fs.Int("limit", 0, "Only include this many contacts, for printing a long sheet in parts")
+	offset := fs.Int("offset", 0, "Skip this many contacts first, with -limit")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: contacts-tui sheet [options]")
+		fmt.Fprintln(fs.Output(), "\nPrint a contact sheet for offline reference or sharing. The markdown")
+		fmt.Fprintln(fs.Output(), "format can be turned into a PDF with a tool like pandoc.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if *databasePath != "" {
+		cfg.Database.Path = *databasePath
+	}
+
+	database, err := db.Open(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	contactFilter := filter.contactFilter()
+	contactFilter.Limit = *limit
+	contactFilter.Offset = *offset
+	contacts, err := database.FilterContacts(contactFilter)
+	if err != nil {
+		return err
+	}
+
+	if *title == "" {
+		*title = "Contacts"
+		if *filter.relType != "" {
+			*title = strings.Title(*filter.relType) + " contacts"
+		}
+		if *filter.location != "" {
+			*title += " in " + *filter.location
+		}
+		if (*limit > 0 || *offset > 0) && len(contacts) > 0 {
+			total, err := database.CountContacts(contactFilter)
+			if err != nil {
+				return err
+			}
+			*title += fmt.Sprintf(" (%d–%d of %d)", *offset+1, *offset+len(contacts), total)
+		}
+	}
+
+	sheet, err := report.Sheet(contacts, *title, *format, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		fmt.Print(sheet)
+		return nil
+	}
+	if err := os.WriteFile(config.ExpandPath(*output), []byte(sheet), 0644); err != nil {
+		return fmt.Errorf("writing sheet: %w", err)
+	}
+	fmt.Printf("✓ Wrote %d contacts to %s\n", len(contacts), *output)
+	return nil
+}
+
+// contactFilter selects contacts for commands that export a subset of them
+type contactFilter struct {
+	relType  *string
+	state    *string
+	location *string
+	search   *string
+	archived *bool
+}
+
+// addContactFilterFlags adds the -type, -state, -location, -search and
+// -archived flags to a command
+func addContactFilterFlags(fs *flag.FlagSet) contactFilter {
+	return contactFilter{
+		relType:  fs.String("type", "", "Only include contacts of this relationship type"),
+		state:    fs.String("state", "", "Only include contacts in this state"),
+		location: fs.String("location", "", "Only include contacts whose location or address contains this text"),
+		search:   fs.String("search", "", "Only include contacts matching this search, written as in the TUI's / filter"),
+		archived: fs.Bool("archived", false, "Include archived contacts"),
+	}
+}
+
+// contactFilter returns the database filter for the flags
+func (f contactFilter) contactFilter() db.ContactFilter {
+	return db.ContactFilter{
+		RelationshipType: *f.relType,
+		State:            *f.state,
+		Location:         *f.location,
+		Text:             *f.search,
+		IncludeArchived:  *f.archived,
+	}
+}
+
+// query returns the contacts matching the filter
+func (f contactFilter) query(database *db.DB) ([]db.Contact, error) {
+	return database.FilterContacts(f.contactFilter())
+}
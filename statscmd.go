@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+	"github.com/pdxmph/contacts-tui/internal/report"
+)
+
+// runStats exports aggregate statistics for charting elsewhere
+func runStats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	databasePath := fs.String("database", "", "Path to database file (overrides config)")
+	months := fs.Int("months", 12, "Number of months to include, counting this one")
+	format := fs.String("format", report.FormatCSV, "Output format: csv or json")
+	output := fs.String("o", "", "Write the statistics to this file instead of stdout")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: contacts-tui stats [options]")
+		fmt.Fprintln(fs.Output(), "\nExport statistics per month: interactions by type, time recorded, and")
+		fmt.Fprintln(fs.Output(), "how many contacts were overdue at the end of the month.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *months < 1 {
+		return fmt.Errorf("-months must be at least 1")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	db.SetCadences(cfg.Relationships.Cadence, cfg.Relationships.DefaultCadence)
+	if *databasePath != "" {
+		cfg.Database.Path = *databasePath
+	}
+
+	database, err := db.Open(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	contacts, err := database.ListContacts()
+	if err != nil {
+		return err
+	}
+	logs, err := database.ListInteractions()
+	if err != nil {
+		return err
+	}
+
+	data, err := report.BuildStats(contacts, logs, *months, time.Now()).Encode(*format)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		os.Stdout.Write(data)
+		return nil
+	}
+	if err := os.WriteFile(config.ExpandPath(*output), data, 0644); err != nil {
+		return fmt.Errorf("writing statistics: %w", err)
+	}
+	fmt.Printf("✓ Wrote %d months of statistics to %s\n", *months, *output)
+	return nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/syncer"
+)
+
+// syncOnceTimeout bounds a sync run from the command line
+const syncOnceTimeout = 5 * time.Minute
+
+// runSyncOnce runs the configured sync backend once and prints what it did
+func runSyncOnce(cfg *config.Config) error {
+	if cfg.Sync.Backend == "" {
+		return fmt.Errorf("no sync backend configured (set backend in [sync] of config.toml)")
+	}
+	if _, err := os.Stat(cfg.Database.Path); err != nil {
+		return fmt.Errorf("database not found at %s", cfg.Database.Path)
+	}
+
+	backend, err := syncer.Create(cfg.Sync.Backend, cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), syncOnceTimeout)
+	defer cancel()
+	result, err := backend.Sync(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Synced with %s: %s\n", backend.Name(), result.Summary)
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// runTime prints the time spent in interactions per month and per contact
+func runTime(args []string) error {
+	fs := flag.NewFlagSet("time", flag.ExitOnError)
+	databasePath := fs.String("database", "", "Path to database file (overrides config)")
+	months := fs.Int("months", 6, "Number of months to include, counting this one")
+	top := fs.Int("top", 10, "Number of contacts to list; 0 lists all")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: contacts-tui time [options]")
+		fmt.Fprintln(fs.Output(), "\nSum the durations recorded on interactions (Ctrl+L in note mode) per")
+		fmt.Fprintln(fs.Output(), "month and per contact.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *months < 1 {
+		return fmt.Errorf("-months must be at least 1")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if *databasePath != "" {
+		cfg.Database.Path = *databasePath
+	}
+
+	database, err := db.Open(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	now := time.Now().UTC()
+	since := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1-*months, 0)
+
+	byMonth, err := database.DurationsByMonth(since)
+	if err != nil {
+		return err
+	}
+	byContact, err := database.DurationsByContact(since)
+	if err != nil {
+		return err
+	}
+	if len(byMonth) == 0 {
+		fmt.Printf("No interaction time recorded since %s\n", since.Format("January 2006"))
+		return nil
+	}
+
+	total := 0
+	fmt.Println("By month:")
+	for _, md := range byMonth {
+		month := md.Month
+		if t, err := time.Parse("2006-01", md.Month); err == nil {
+			month = t.Format("Jan 2006")
+		}
+		fmt.Printf("  %-10s %8s  (%d interactions)\n", month, formatMinutes(md.Minutes), md.Interactions)
+		total += md.Minutes
+	}
+	fmt.Printf("  %-10s %8s\n", "Total", formatMinutes(total))
+
+	fmt.Println("\nBy contact:")
+	for i, cd := range byContact {
+		if *top > 0 && i == *top {
+			fmt.Printf("  ... and %d more\n", len(byContact)-*top)
+			break
+		}
+		fmt.Printf("  %-30s %8s  (%d interactions)\n", cd.Name, formatMinutes(cd.Minutes), cd.Interactions)
+	}
+	return nil
+}
+
+// formatMinutes renders minutes as hours and minutes, e.g. "3h 15m"
+func formatMinutes(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pdxmph/contacts-tui/internal/config"
+	"github.com/pdxmph/contacts-tui/internal/db"
+)
+
+// runTypes lists, renames or merges relationship types
+func runTypes(args []string) error {
+	fs := flag.NewFlagSet("types", flag.ExitOnError)
+	databasePath := fs.String("database", "", "Path to database file (overrides config)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: contacts-tui types [options] [list]")
+		fmt.Fprintln(fs.Output(), "       contacts-tui types [options] rename <old> <new>")
+		fmt.Fprintln(fs.Output(), "       contacts-tui types [options] merge <from> <into>")
+		fmt.Fprintln(fs.Output(), "\nRename a relationship type, or merge one into another, across all")
+		fmt.Fprintln(fs.Output(), "contacts. The database constraint and the config file are updated to")
+		fmt.Fprintln(fs.Output(), "match; the old config file is kept with a .bak suffix.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if *databasePath != "" {
+		cfg.Database.Path = *databasePath
+	}
+
+	database, err := db.Open(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	counts, err := database.RelationshipTypeCounts()
+	if err != nil {
+		return err
+	}
+
+	action := fs.Arg(0)
+	switch action {
+	case "", "list":
+		printTypes(cfg.Relationships.Types, counts)
+		return nil
+	case "rename", "merge":
+		if fs.NArg() != 3 {
+			fs.Usage()
+			os.Exit(2)
+		}
+	default:
+		return fmt.Errorf("unknown action %q (use list, rename or merge)", action)
+	}
+
+	from, to := fs.Arg(1), fs.Arg(2)
+	if from == to {
+		return fmt.Errorf("%q and %q are the same type", from, to)
+	}
+	if err := db.ValidateRelationshipType(to); err != nil {
+		return err
+	}
+	if !hasType(cfg.Relationships.Types, from) && counts[from] == 0 {
+		return fmt.Errorf("no relationship type %q", from)
+	}
+	exists := hasType(cfg.Relationships.Types, to) || counts[to] > 0
+	if action == "rename" && exists {
+		return fmt.Errorf("%q already exists; use merge to move %s contacts into it", to, from)
+	}
+	if action == "merge" && !exists {
+		return fmt.Errorf("no relationship type %q; use rename to give %s a new name", to, from)
+	}
+
+	allowed := renamedTypes(cfg.Relationships.Types, from, to)
+	for name := range counts {
+		if name != from && !hasType(allowed, name) {
+			allowed = append(allowed, name)
+		}
+	}
+
+	moved, err := database.RenameRelationshipType(from, to, allowed)
+	if err != nil {
+		return err
+	}
+
+	cfg.Relationships.Types = allowed
+	if rule, ok := cfg.Escalation.Types[from]; ok {
+		if _, taken := cfg.Escalation.Types[to]; !taken {
+			cfg.Escalation.Types[to] = rule
+		}
+		delete(cfg.Escalation.Types, from)
+	}
+	if err := saveConfigWithBackup(cfg); err != nil {
+		return err
+	}
+
+	verb := "Renamed"
+	if action == "merge" {
+		verb = "Merged"
+	}
+	fmt.Printf("✓ %s %s into %s (%d contacts)\n", verb, from, to, moved)
+	return nil
+}
+
+// printTypes lists the configured relationship types with their contact counts
+func printTypes(types []string, counts map[string]int) {
+	for _, name := range types {
+		fmt.Printf("%-15s %d\n", name, counts[name])
+	}
+	var unlisted []string
+	for name := range counts {
+		if !hasType(types, name) {
+			unlisted = append(unlisted, name)
+		}
+	}
+	sort.Strings(unlisted)
+	for _, name := range unlisted {
+		fmt.Printf("%-15s %d (not in config)\n", name, counts[name])
+	}
+}
+
+// renamedTypes returns types with from replaced by to, or dropped when to is
+// already present
+func renamedTypes(types []string, from, to string) []string {
+	var out []string
+	for _, name := range types {
+		if name == from {
+			name = to
+		}
+		if !hasType(out, name) {
+			out = append(out, name)
+		}
+	}
+	if !hasType(out, to) {
+		out = append(out, to)
+	}
+	return out
+}
+
+// hasType reports whether name is in types
+func hasType(types []string, name string) bool {
+	for _, t := range types {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// saveConfigWithBackup writes the config file, keeping the previous one with
+// a .bak suffix
+func saveConfigWithBackup(cfg *config.Config) error {
+	path, err := config.Path()
+	if err != nil {
+		return err
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := os.WriteFile(path+".bak", data, 0644); err != nil {
+			return fmt.Errorf("backing up config: %w", err)
+		}
+		fmt.Printf("Previous config saved as %s.bak\n", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+	return cfg.SaveTo(path)
+}